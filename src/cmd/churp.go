@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+import (
+	"github.com/bl4ck5un/ChuRP/src/networking/cli"
+	"github.com/bl4ck5un/ChuRP/src/networking/devnet"
+)
+
+const usage = `usage: churp <command> [flags]
+
+commands:
+  node      serve a node of the committee
+  board     serve the bulletinboard
+  clock     start epochs on the bulletinboard
+  replica   serve a replica of the content of the bulletinboard
+  devnet    run a committee on this machine and stop it again
+  keygen    write the keys of the nodes and the operator
+  ctl       carry out an operator task, churp ctl -h lists them
+  status    show the status of the nodes and the bulletinboard
+  audit     check the bulletinboard log against what the nodes read
+  sim       run a scenario on a simulated network
+  bench     benchmark a grid of committee configurations
+
+churp <command> -h lists the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+	if command == "devnet" {
+		runDevnet(args)
+		return
+	}
+	run, ok := cli.Commands[command]
+	if !ok {
+		names := make([]string, 0, len(cli.Commands))
+		for name := range cli.Commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "unknown command %q, want devnet or one of %s\n\n", command, strings.Join(names, ", "))
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	run(args)
+}
+
+func runDevnet(args []string) {
+	flags := flag.NewFlagSet("devnet", flag.ExitOnError)
+	counter := flags.Int("n", 4, "Enter number of nodes")
+	degree := flags.Int("t", 1, "Enter the polynomial degree, at most (n-1)/2")
+	epochs := flags.Int64("k", 1, "Enter the number of epochs to run, 0 runs until interrupted")
+	period := flags.Duration("period", 0, "Enter the epoch duration, 0 starts every epoch as soon as the one before has completed")
+	dir := flags.String("path", "devnet", "directory for the metadata, the shares and the output of every process")
+	port := flags.Int("port", 11000, "the bulletinboard listens on this port, node i on the port i above it")
+	replicas := flags.Int("replicas", 0, "number of replicas keeping the content of the bulletinboard, 0 keeps it in memory")
+	chain := flags.String("chain", "", "gas config of a simulated chain keeping the content of the bulletinboard, default for the default gas model")
+	timeout := flags.Duration("timeout", 30*time.Second, "how long the processes have to become ready")
+	logLevel := flags.String("log-level", "info", "lowest level the processes log: debug, info, warning or error")
+	quiet := flags.Bool("q", false, "only write the output of the processes to their files in -path")
+	keep := flags.Bool("keep", false, "keep the committee running after the epochs until interrupted")
+	flags.Parse(args)
+
+	bin, err := os.Executable()
+	if err != nil {
+		log.Fatalf("devnet cannot find the churp binary: %v", err)
+	}
+	passphrase := os.Getenv("CHURP_PASSPHRASE")
+	if passphrase == "" {
+		passphrase = "churp-devnet"
+	}
+	config := devnet.Config{
+		Bin:        bin,
+		Nodes:      *counter,
+		Degree:     *degree,
+		Dir:        *dir,
+		BasePort:   *port,
+		Replicas:   *replicas,
+		Chain:      *chain,
+		Passphrase: passphrase,
+		Timeout:    *timeout,
+		Flags:      []string{"-log-level", *logLevel},
+	}
+	if !*quiet {
+		config.Log = os.Stdout
+	}
+
+	committee, err := devnet.Start(config)
+	if err != nil {
+		log.Fatalf("devnet failed to start: %v", err)
+	}
+	log.Printf("devnet of %d nodes with degree %d is ready at %s", *counter, *degree, strings.Join(committee.Addrs(), " "))
+
+	// an interrupt stops the processes, which ends a clock that runs forever
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	down := make(chan struct{})
+	go func() {
+		<-interrupted
+		close(stopped)
+		committee.Stop()
+		close(down)
+	}()
+
+	err = committee.Run(*epochs, *period)
+	select {
+	case <-stopped:
+		<-down
+		log.Print("devnet interrupted")
+		return
+	default:
+	}
+	if err == nil && *keep {
+		log.Print("epochs done, the committee keeps running until interrupted")
+		<-down
+		return
+	}
+	committee.Stop()
+	if err != nil {
+		log.Fatalf("devnet failed: %v", err)
+	}
+	log.Printf("devnet ran %d epochs", *epochs)
+}
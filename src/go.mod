@@ -8,5 +8,6 @@ require (
 	github.com/ncw/gmp v1.0.3
 	github.com/sirupsen/logrus v1.3.0
 	github.com/stretchr/testify v1.2.2
+	golang.org/x/crypto v0.0.0-20180904163835-0709b304e793
 	google.golang.org/grpc v1.19.0
 )
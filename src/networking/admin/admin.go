@@ -0,0 +1,164 @@
+// Package admin asks nodes and the bulletinboard what they are doing through their AdminService, and prints the answer for operators.
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/jsonpb"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Timeout of a status call, a node or bulletinboard that does not answer in time is reported as down
+const Timeout = 5 * time.Second
+
+// Query returns the status of the node or bulletinboard at addr
+func Query(tr transport.Transport, addr string) (*pb.StatusMsg, error) {
+	conn, err := tr.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	return conn.Admin().Status(ctx, &pb.StatusRequestMsg{})
+}
+
+// Ready returns nil once the node or bulletinboard at addr passes its readiness check, and otherwise why it does not
+func Ready(tr transport.Transport, addr string) error {
+	conn, err := tr.Dial(addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	resp, err := conn.Health().Check(ctx, &healthpb.HealthCheckRequest{Service: pb.HealthReady})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+		return nil
+	}
+	// the check only says whether, the status says why
+	msg, err := conn.Admin().Status(ctx, &pb.StatusRequestMsg{})
+	if err != nil || msg.GetNotReady() == "" {
+		return errors.New(fmt.Sprintf("%s is not ready", addr))
+	}
+	return errors.New(fmt.Sprintf("%s is not ready: %s", addr, msg.GetNotReady()))
+}
+
+// WriteText prints a status the way an operator reads it, one fact per line
+func WriteText(w io.Writer, msg *pb.StatusMsg) {
+	if msg.GetBoard() != nil {
+		fmt.Fprintf(w, "bulletinboard of committee %s, %d nodes, degree %d\n", short(msg.GetCommittee()), msg.GetCounter(), msg.GetDegree())
+	} else {
+		fmt.Fprintf(w, "node %d of committee %s, %d nodes, degree %d\n", msg.GetLabel(), short(msg.GetCommittee()), msg.GetCounter(), msg.GetDegree())
+	}
+	fmt.Fprintf(w, "epoch\t%d\n", msg.GetEpoch())
+	fmt.Fprintf(w, "completed\t%d\n", msg.GetCompleted())
+	if msg.GetReady() {
+		fmt.Fprintf(w, "ready\tyes\n")
+	} else {
+		fmt.Fprintf(w, "ready\tno, %s\n", msg.GetNotReady())
+	}
+	if node := msg.GetNode(); node != nil {
+		phase := node.GetPhase()
+		if node.GetRecovering() {
+			phase += ", recovering"
+		}
+		fmt.Fprintf(w, "phase\t%s\n", phase)
+		fmt.Fprintf(w, "received\tpoints %d, zero shares %d, new shares %d\n", node.GetRecCnt(), node.GetZeroCnt(), node.GetShareCnt())
+		if len(node.GetWaiting()) > 0 {
+			fmt.Fprintf(w, "waiting on\t%s\n", labels(node.GetWaiting()))
+		} else if node.GetPhase() != "idle" {
+			fmt.Fprintf(w, "waiting on\tbulletinboard\n")
+		}
+		if node.GetFaults() > 0 {
+			fmt.Fprintf(w, "faults\t%d\n", node.GetFaults())
+		}
+		if label := int(msg.GetLabel()); label >= 1 && label <= len(node.GetPolyCmts()) {
+			fmt.Fprintf(w, "commitment\t%x\n", node.GetPolyCmts()[label-1])
+		}
+		for _, timing := range node.GetTimings() {
+			fmt.Fprintf(w, "took\t%s %v\n", timing.GetPhase(), time.Duration(timing.GetTook()))
+		}
+	}
+	if board := msg.GetBoard(); board != nil {
+		state := strings.ToLower(board.GetStatus().GetState().String())
+		if board.GetStatus().GetDealt() {
+			state += ", dealt"
+		}
+		fmt.Fprintf(w, "state\t%s\n", state)
+		for _, phase := range board.GetPhases() {
+			fmt.Fprintf(w, "written\tphase %d by %d/%d: %s\n", phase.GetPhase(), len(phase.GetWritten()), msg.GetCounter(), labels(phase.GetWritten()))
+		}
+	}
+	for _, conn := range msg.GetConns() {
+		line := fmt.Sprintf("conn\t%s %s %s", conn.GetPeer(), conn.GetAddress(), conn.GetState())
+		if conn.GetDown() {
+			line += ", down"
+		}
+		switch failures := conn.GetFailures(); {
+		case failures == 1:
+			line += fmt.Sprintf(", the last call failed: %s", conn.GetError())
+		case failures > 1:
+			line += fmt.Sprintf(", the last %d calls failed: %s", failures, conn.GetError())
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// Report asks every address in turn and prints each status as text, or as JSON if asJSON is set. It reports on addresses that do not answer and returns false if any did not.
+func Report(w io.Writer, tr transport.Transport, addrs []string, asJSON bool) bool {
+	ok := true
+	marshaler := jsonpb.Marshaler{Indent: "  "}
+	for i, addr := range addrs {
+		if i > 0 && !asJSON {
+			fmt.Fprintln(w)
+		}
+		msg, err := Query(tr, addr)
+		if err != nil {
+			log.Printf("%s did not answer: %v", addr, err)
+			ok = false
+			continue
+		}
+		if !asJSON {
+			WriteText(w, msg)
+			continue
+		}
+		if err := marshaler.Marshal(w, msg); err != nil {
+			log.Printf("failed to print the status of %s: %v", addr, err)
+			ok = false
+			continue
+		}
+		fmt.Fprintln(w)
+	}
+	return ok
+}
+
+func labels(labels []int32) string {
+	if len(labels) == 0 {
+		return "none"
+	}
+	s := make([]string, len(labels))
+	for i, label := range labels {
+		s[i] = fmt.Sprintf("node%d", label)
+	}
+	return strings.Join(s, " ")
+}
+
+// Committee IDs are long hashes, the first bytes tell them apart
+func short(committee string) string {
+	if len(committee) > 16 {
+		return committee[:16]
+	}
+	return committee
+}
@@ -0,0 +1,233 @@
+package adversary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/nodes"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Adversary makes a node Byzantine. It stands between the node and its transport and rewrites the messages the node sends, so the node itself runs the protocol unchanged.
+// What it changes it signs again with the key of the node, as a corrupted node would, so only the checks of the protocol can tell the messages from honest ones.
+type Adversary struct {
+	label        int
+	metadataPath string
+	behaviors    []Behavior
+
+	// Key of the node and the label behind every address, loaded when the node first dials
+	load  sync.Once
+	err   error
+	id    *identity.Identity
+	peers map[string]int
+
+	mutex   sync.Mutex
+	refused []error
+}
+
+// Message is a message the node sends, as a Behavior sees it
+type Message struct {
+	// Label of the node it goes to, 0 for the bulletinboard
+	To int
+	// Method of the call, like SharePhase1 or WritePhase2
+	Method string
+	Msg    proto.Message
+}
+
+// Behavior is a way to depart from the protocol. It returns the messages to send in place of one the node sends: none to stay silent, more than one to equivocate, the message itself to leave it alone.
+// A Behavior may change the messages it returns, but not the one it is given.
+type Behavior func(m Message) []proto.Message
+
+// New returns the adversary of node label of the committee in metadataPath, which applies the behaviors in turn to everything the node sends
+func New(label int, metadataPath string, behaviors ...Behavior) *Adversary {
+	return &Adversary{
+		label:        label,
+		metadataPath: metadataPath,
+		behaviors:    behaviors,
+	}
+}
+
+// Transport returns tr as the node sees it through the adversary
+func (a *Adversary) Transport(tr transport.Transport) transport.Transport {
+	return &adversaryTransport{adversary: a, tr: tr}
+}
+
+// Refused returns the errors the receivers answered to messages the adversary forged, like the bulletinboard turning down an equivocation
+func (a *Adversary) Refused() []error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]error{}, a.refused...)
+}
+
+// Network returns network with every node that has an adversary talking through it
+func Network(network localnet.Network, adversaries map[int]*Adversary) localnet.Network {
+	byName := make(map[string]*Adversary)
+	for label, a := range adversaries {
+		byName[localnet.NodeAddr(label)] = a
+	}
+	return adversaryNetwork{network: network, adversaries: byName}
+}
+
+type adversaryNetwork struct {
+	network     localnet.Network
+	adversaries map[string]*Adversary
+}
+
+func (n adversaryNetwork) Endpoint(name string) transport.Transport {
+	tr := n.network.Endpoint(name)
+	if a, ok := n.adversaries[name]; ok {
+		return a.Transport(tr)
+	}
+	return tr
+}
+
+// Read the key of the node and the ip_list, once the committee has written them
+func (a *Adversary) setup() error {
+	a.load.Do(func() {
+		a.id, a.err = identity.Load(identity.KeyPath(a.metadataPath, a.label))
+		a.peers = make(map[string]int)
+		for i, addr := range nodes.ReadIpList(a.metadataPath) {
+			if _, ok := a.peers[addr]; !ok && addr != "" {
+				a.peers[addr] = i
+			}
+		}
+	})
+	return a.err
+}
+
+// Run a call of the node through the behaviors. The node gets the answer to the first message sent, or an acknowledgement if none is.
+func (a *Adversary) send(to int, method string, msg proto.Message, call func(msg proto.Message) (*pb.AckMsg, error)) (*pb.AckMsg, error) {
+	msgs := []proto.Message{msg}
+	for _, behavior := range a.behaviors {
+		next := make([]proto.Message, 0, len(msgs))
+		for _, m := range msgs {
+			next = append(next, behavior(Message{To: to, Method: method, Msg: m})...)
+		}
+		msgs = next
+	}
+	if len(msgs) == 0 {
+		return &pb.AckMsg{}, nil
+	}
+	var ack *pb.AckMsg
+	var err error
+	for i, m := range msgs {
+		forged := !proto.Equal(m, msg)
+		if forged {
+			signed, ok := m.(pb.Signed)
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("%s carries no signature", method))
+			}
+			resign(a.id, signed)
+		}
+		out, callErr := call(m)
+		// the node retries a call that is unavailable, a refusal is final
+		if callErr != nil && forged && status.Code(callErr) != codes.Unavailable {
+			a.mutex.Lock()
+			a.refused = append(a.refused, callErr)
+			a.mutex.Unlock()
+		}
+		if i == 0 {
+			ack, err = out, callErr
+		}
+	}
+	return ack, err
+}
+
+// Sign a forged message with the key of the node
+func resign(id *identity.Identity, msg pb.Signed) {
+	switch m := msg.(type) {
+	case *pb.PointMsg:
+		m.Signature = pb.Sign(id, m)
+	case *pb.ZeroMsg:
+		m.Signature = pb.Sign(id, m)
+	case *pb.Cmt1Msg:
+		m.Signature = pb.Sign(id, m)
+	case *pb.Cmt2Msg:
+		m.Signature = pb.Sign(id, m)
+	}
+}
+
+type adversaryTransport struct {
+	adversary *Adversary
+	tr        transport.Transport
+}
+
+func (t *adversaryTransport) Listen(addr string) (transport.Server, error) {
+	return t.tr.Listen(addr)
+}
+
+func (t *adversaryTransport) Dial(addr string) (transport.Conn, error) {
+	if err := t.adversary.setup(); err != nil {
+		return nil, err
+	}
+	conn, err := t.tr.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &adversaryConn{Conn: conn, adversary: t.adversary, to: t.adversary.peers[addr]}, nil
+}
+
+type adversaryConn struct {
+	transport.Conn
+	adversary *Adversary
+	to        int
+}
+
+func (c *adversaryConn) Node() pb.NodeServiceClient {
+	return nodeClient{NodeServiceClient: c.Conn.Node(), adversary: c.adversary, to: c.to}
+}
+
+func (c *adversaryConn) BulletinBoard() pb.BulletinBoardServiceClient {
+	return boardClient{BulletinBoardServiceClient: c.Conn.BulletinBoard(), adversary: c.adversary}
+}
+
+// The calls that carry the messages of the protocol go through the adversary, the others are passed on
+type nodeClient struct {
+	pb.NodeServiceClient
+	adversary *Adversary
+	to        int
+}
+
+func (c nodeClient) SharePhase1(ctx context.Context, in *pb.PointMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	return c.adversary.send(c.to, "SharePhase1", in, func(msg proto.Message) (*pb.AckMsg, error) {
+		return c.NodeServiceClient.SharePhase1(ctx, msg.(*pb.PointMsg), opts...)
+	})
+}
+
+func (c nodeClient) SharePhase2(ctx context.Context, in *pb.ZeroMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	return c.adversary.send(c.to, "SharePhase2", in, func(msg proto.Message) (*pb.AckMsg, error) {
+		return c.NodeServiceClient.SharePhase2(ctx, msg.(*pb.ZeroMsg), opts...)
+	})
+}
+
+func (c nodeClient) SharePhase3(ctx context.Context, in *pb.PointMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	return c.adversary.send(c.to, "SharePhase3", in, func(msg proto.Message) (*pb.AckMsg, error) {
+		return c.NodeServiceClient.SharePhase3(ctx, msg.(*pb.PointMsg), opts...)
+	})
+}
+
+type boardClient struct {
+	pb.BulletinBoardServiceClient
+	adversary *Adversary
+}
+
+func (c boardClient) WritePhase2(ctx context.Context, in *pb.Cmt2Msg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	return c.adversary.send(0, "WritePhase2", in, func(msg proto.Message) (*pb.AckMsg, error) {
+		return c.BulletinBoardServiceClient.WritePhase2(ctx, msg.(*pb.Cmt2Msg), opts...)
+	})
+}
+
+func (c boardClient) WritePhase3(ctx context.Context, in *pb.Cmt1Msg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	return c.adversary.send(0, "WritePhase3", in, func(msg proto.Message) (*pb.AckMsg, error) {
+		return c.BulletinBoardServiceClient.WritePhase3(ctx, msg.(*pb.Cmt1Msg), opts...)
+	})
+}
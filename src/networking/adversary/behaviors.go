@@ -0,0 +1,218 @@
+package adversary
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/ecparam"
+	"github.com/golang/protobuf/proto"
+)
+
+// The behaviors a scenario can pick by name. Only silent takes arguments, see Parse.
+var behaviors = map[string]func(args []string) (Behavior, error){
+	"inconsistent-shares": noArgs(InconsistentShares),
+	"bad-zero-shares":     noArgs(BadZeroShares),
+	"bad-zero-witness":    noArgs(BadZeroWitness),
+	"bad-new-shares":      noArgs(BadNewShares),
+	"bad-commitment":      noArgs(BadCommitment),
+	"equivocate":          noArgs(Equivocate),
+	"silent":              silentTo,
+}
+
+// Names lists the behaviors Parse knows
+func Names() []string {
+	names := make([]string, 0, len(behaviors))
+	for name := range behaviors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse returns the behavior named by spec, written name or name:arg,arg like "silent:node1,bulletinboard".
+// A suffix @N or @N-M limits the behavior to epoch N or to epochs N to M, like "bad-new-shares@1".
+func Parse(spec string) (Behavior, error) {
+	if at := strings.LastIndex(spec, "@"); at >= 0 {
+		first, last, err := parseEpochs(spec[at+1:])
+		if err != nil {
+			return nil, err
+		}
+		behavior, err := Parse(spec[:at])
+		if err != nil {
+			return nil, err
+		}
+		return During(first, last, behavior), nil
+	}
+	parts := strings.SplitN(spec, ":", 2)
+	build, ok := behaviors[parts[0]]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unknown behavior %q, known ones are %s", parts[0], strings.Join(Names(), ", ")))
+	}
+	var args []string
+	if len(parts) == 2 {
+		args = strings.Split(parts[1], ",")
+	}
+	return build(args)
+}
+
+// Epochs written N or N-M
+func parseEpochs(epochs string) (int64, int64, error) {
+	bounds := strings.SplitN(epochs, "-", 2)
+	first, err := strconv.ParseInt(bounds[0], 10, 64)
+	last := first
+	if err == nil && len(bounds) == 2 {
+		last, err = strconv.ParseInt(bounds[1], 10, 64)
+	}
+	if err != nil || first < 1 || last < first {
+		return 0, 0, errors.New(fmt.Sprintf("epochs are N or N-M with 1 <= N <= M, got %q", epochs))
+	}
+	return first, last, nil
+}
+
+func noArgs(behavior func() Behavior) func(args []string) (Behavior, error) {
+	return func(args []string) (Behavior, error) {
+		if len(args) > 0 {
+			return nil, errors.New(fmt.Sprintf("the behavior takes no arguments, got %v", args))
+		}
+		return behavior(), nil
+	}
+}
+
+// InconsistentShares sends every peer a different wrong point in phase 1, each off by the label of the peer
+func InconsistentShares() Behavior {
+	return func(m Message) []proto.Message {
+		if m.Method != "SharePhase1" {
+			return []proto.Message{m.Msg}
+		}
+		msg := proto.Clone(m.Msg).(*pb.PointMsg)
+		msg.Y = shift(msg.Y, int64(m.To))
+		return []proto.Message{msg}
+	}
+}
+
+// BadZeroShares sends zero shares in phase 2 that no longer sum to zero
+func BadZeroShares() Behavior {
+	return func(m Message) []proto.Message {
+		if m.Method != "SharePhase2" {
+			return []proto.Message{m.Msg}
+		}
+		msg := proto.Clone(m.Msg).(*pb.ZeroMsg)
+		msg.Share = shift(msg.Share, 1)
+		return []proto.Message{msg}
+	}
+}
+
+// BadZeroWitness writes a witness in phase 2 that does not open the zero polynomial at 0
+func BadZeroWitness() Behavior {
+	return func(m Message) []proto.Message {
+		if m.Method != "WritePhase2" {
+			return []proto.Message{m.Msg}
+		}
+		msg := proto.Clone(m.Msg).(*pb.Cmt2Msg)
+		msg.Zerowitness = move(msg.Zerowitness)
+		return []proto.Message{msg}
+	}
+}
+
+// BadNewShares sends every peer a new share in phase 3 that does not match the commitment the node writes
+func BadNewShares() Behavior {
+	return func(m Message) []proto.Message {
+		if m.Method != "SharePhase3" {
+			return []proto.Message{m.Msg}
+		}
+		msg := proto.Clone(m.Msg).(*pb.PointMsg)
+		msg.Y = shift(msg.Y, 1)
+		return []proto.Message{msg}
+	}
+}
+
+// BadCommitment writes a commitment in phase 3 that matches neither the shares the node sent nor its commitments of phase 2
+func BadCommitment() Behavior {
+	return func(m Message) []proto.Message {
+		if m.Method != "WritePhase3" {
+			return []proto.Message{m.Msg}
+		}
+		msg := proto.Clone(m.Msg).(*pb.Cmt1Msg)
+		msg.Polycmt = move(msg.Polycmt)
+		return []proto.Message{msg}
+	}
+}
+
+// Equivocate writes a second, different commitment to the bulletinboard after each one the node writes
+func Equivocate() Behavior {
+	return func(m Message) []proto.Message {
+		switch msg := m.Msg.(type) {
+		case *pb.Cmt2Msg:
+			other := proto.Clone(msg).(*pb.Cmt2Msg)
+			other.Polycmt = move(other.Polycmt)
+			return []proto.Message{msg, other}
+		case *pb.Cmt1Msg:
+			other := proto.Clone(msg).(*pb.Cmt1Msg)
+			other.Polycmt = move(other.Polycmt)
+			return []proto.Message{msg, other}
+		}
+		return []proto.Message{m.Msg}
+	}
+}
+
+// Silent sends nothing to the given labels, 0 being the bulletinboard, or to anyone if none are given
+func Silent(labels ...int) Behavior {
+	to := make(map[int]bool)
+	for _, label := range labels {
+		to[label] = true
+	}
+	return func(m Message) []proto.Message {
+		if len(to) == 0 || to[m.To] {
+			return nil
+		}
+		return []proto.Message{m.Msg}
+	}
+}
+
+// Arguments of silent are the names of the endpoints, nodeN or bulletinboard
+func silentTo(args []string) (Behavior, error) {
+	labels := make([]int, 0, len(args))
+	for _, arg := range args {
+		if arg == "bulletinboard" {
+			labels = append(labels, 0)
+			continue
+		}
+		var label int
+		if _, err := fmt.Sscanf(arg, "node%d", &label); err != nil || label < 1 {
+			return nil, errors.New(fmt.Sprintf("silent takes nodeN or bulletinboard, got %q", arg))
+		}
+		labels = append(labels, label)
+	}
+	return Silent(labels...), nil
+}
+
+// During applies behavior to the messages of epochs first to last, the node follows the protocol in the other epochs
+func During(first int64, last int64, behavior Behavior) Behavior {
+	return func(m Message) []proto.Message {
+		if scoped, ok := m.Msg.(pb.EpochScoped); ok && (scoped.GetEpoch() < first || scoped.GetEpoch() > last) {
+			return []proto.Message{m.Msg}
+		}
+		return behavior(m)
+	}
+}
+
+// A field element off by delta
+func shift(value []byte, delta int64) []byte {
+	v := new(big.Int).SetBytes(value)
+	v.Add(v, big.NewInt(delta))
+	v.Mod(v, ecparam.PBC256.Nbig)
+	return v.Bytes()
+}
+
+// A group element moved by the generator, still a valid element but not the committed one
+func move(element []byte) []byte {
+	e := ecparam.PBC256.Pairing.NewG1()
+	e.SetCompressedBytes(element)
+	e.Mul(e, ecparam.PBC256.G)
+	return e.CompressedBytes()
+}
@@ -0,0 +1,235 @@
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/merkle"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Phase 2 of an epoch in the bulletinboard log, its entries come first in the Merkle tree of the epoch
+const phaseProactivization int32 = 2
+
+// Auditor checks the hash-chained log of the bulletinboard and compares it with the log every node read
+type Auditor struct {
+	// Metadata Directory Path
+	metadataPath string
+	// Counter
+	counter int
+	// BulletinBoard IP
+	bip string
+	// Node IP Addresses
+	ipList []string
+	// Committee ID
+	committee string
+	// Transport and Clients
+	transport transport.Transport
+	bConn     transport.Conn
+	bClient   pb.BulletinBoardServiceClient
+	nConn     []transport.Conn
+	nClient   []pb.NodeServiceClient
+
+	// The last entry of the log checked so far
+	last *pb.EntryMsg
+}
+
+// Report is the outcome of the audit of an epoch
+type Report struct {
+	Epoch int64
+	// Entries of the epoch and the Merkle root over them
+	Size int
+	Root []byte
+	// Labels of the nodes whose log matches the one of the bulletinboard, and of those whose log does not. Nodes that did not read the epoch are in neither.
+	Agree    []int
+	Disagree []int
+}
+
+func (a *Auditor) Connect() {
+	bConn, err := a.transport.Dial(a.bip)
+	if err != nil {
+		log.Fatalf("auditor did not connect: %v", err)
+	}
+	a.bConn = bConn
+	a.bClient = bConn.BulletinBoard()
+	for i := 0; i < a.counter; i++ {
+		nConn, err := a.transport.Dial(a.ipList[i])
+		if err != nil {
+			log.Fatalf("auditor did not connect: %v", err)
+		}
+		a.nConn[i] = nConn
+		a.nClient[i] = nConn.Node()
+	}
+}
+
+func (a *Auditor) Disconnect() {
+	a.bConn.Close()
+	for i := 0; i < a.counter; i++ {
+		a.nConn[i].Close()
+	}
+}
+
+// Audit checks the log of an epoch: every entry is chained to the one before, the roots the bulletinboard reports are the roots over its entries, and each node read the same roots.
+// Epochs are expected in order, the first entry of an epoch is checked against the last entry of the epoch audited before.
+func (a *Auditor) Audit(epoch int64) (*Report, error) {
+	entries, err := a.ClientReadLog(epoch)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([][]byte, len(entries))
+	proactivization := 0
+	for i, entry := range entries {
+		if !bytes.Equal(entry.GetHash(), pb.EntryHash(entry)) {
+			return nil, errors.New(fmt.Sprintf("entry %d does not match its hash", entry.GetSeq()))
+		}
+		leaves[i] = entry.GetHash()
+		if entry.GetPhase() == phaseProactivization {
+			proactivization++
+		}
+	}
+	if err := a.checkChain(entries); err != nil {
+		return nil, err
+	}
+	msg, err := a.ClientAudit(epoch)
+	if err != nil {
+		return nil, err
+	}
+	if msg.GetProactivizationRoot() != nil && !bytes.Equal(msg.GetProactivizationRoot(), merkle.Root(leaves[:proactivization])) {
+		return nil, errors.New(fmt.Sprintf("bulletinboard reports a root of phase 2 of epoch %d that is not the root of its log", epoch))
+	}
+	if msg.GetRoot() != nil && !bytes.Equal(msg.GetRoot(), merkle.Root(leaves)) {
+		return nil, errors.New(fmt.Sprintf("bulletinboard reports a root of epoch %d that is not the root of its log", epoch))
+	}
+	report := &Report{
+		Epoch: epoch,
+		Size:  len(entries),
+		Root:  msg.GetRoot(),
+	}
+	for i := 0; i < a.counter; i++ {
+		view, err := a.ClientNodeAudit(i, epoch)
+		if status.Code(err) == codes.NotFound || status.Code(err) == codes.Unavailable {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sameView(msg, view) {
+			report.Agree = append(report.Agree, i+1)
+		} else {
+			report.Disagree = append(report.Disagree, i+1)
+		}
+	}
+	return report, nil
+}
+
+// Entries must follow each other in the log, each chained to the hash of the one before
+func (a *Auditor) checkChain(entries []*pb.EntryMsg) error {
+	sorted := make([]*pb.EntryMsg, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetSeq() < sorted[j].GetSeq()
+	})
+	for _, entry := range sorted {
+		if a.last == nil && entry.GetSeq() == 1 && entry.GetPrev() != nil {
+			return errors.New("the first entry of the log is chained to another")
+		}
+		if a.last != nil {
+			if entry.GetSeq() != a.last.GetSeq()+1 {
+				return errors.New(fmt.Sprintf("log has a gap between entries %d and %d", a.last.GetSeq(), entry.GetSeq()))
+			}
+			if !bytes.Equal(entry.GetPrev(), a.last.GetHash()) {
+				return errors.New(fmt.Sprintf("entry %d is not chained to entry %d", entry.GetSeq(), a.last.GetSeq()))
+			}
+		}
+		a.last = entry
+	}
+	return nil
+}
+
+// A node agrees with the bulletinboard on the roots it read
+func sameView(board *pb.AuditMsg, node *pb.AuditMsg) bool {
+	if node.GetProactivizationRoot() != nil && !bytes.Equal(node.GetProactivizationRoot(), board.GetProactivizationRoot()) {
+		return false
+	}
+	if node.GetRoot() != nil {
+		return bytes.Equal(node.GetRoot(), board.GetRoot()) && bytes.Equal(node.GetHead(), board.GetHead()) &&
+			node.GetSeq() == board.GetSeq() && node.GetSize() == board.GetSize()
+	}
+	return true
+}
+
+func (a *Auditor) ClientReadLog(epoch int64) ([]*pb.EntryMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
+	defer cancel()
+	stream, err := a.bClient.ReadLog(ctx, a.epochMsg(epoch))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*pb.EntryMsg, 0)
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func (a *Auditor) ClientAudit(epoch int64) (*pb.AuditMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
+	defer cancel()
+	return a.bClient.Audit(ctx, a.epochMsg(epoch))
+}
+
+func (a *Auditor) ClientNodeAudit(i int, epoch int64) (*pb.AuditMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
+	defer cancel()
+	return a.nClient[i].Audit(ctx, a.epochMsg(epoch))
+}
+
+func (a *Auditor) epochMsg(epoch int64) *pb.EpochMsg {
+	return &pb.EpochMsg{
+		Epoch:     epoch,
+		Committee: a.committee,
+	}
+}
+
+func ReadIpList(metadataPath string) []string {
+	ipData, err := ioutil.ReadFile(metadataPath + "/ip_list")
+	if err != nil {
+		log.Fatalf("auditor failed to read iplist %v\n", err)
+	}
+	return strings.Split(string(ipData), "\n")
+}
+
+// New returns an auditor of the bulletinboard and the nodes in the metadata path, reached over tr
+func New(counter int, metadataPath string, tr transport.Transport) (Auditor, error) {
+	ipRaw := ReadIpList(metadataPath)[0 : counter+1]
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return Auditor{}, err
+	}
+	return Auditor{
+		metadataPath: metadataPath,
+		counter:      counter,
+		bip:          ipRaw[0],
+		ipList:       ipRaw[1 : counter+1],
+		committee:    identity.CommitteeID(pks),
+		transport:    tr,
+		nConn:        make([]transport.Conn, counter),
+		nClient:      make([]pb.NodeServiceClient, counter),
+	}, nil
+}
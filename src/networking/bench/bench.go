@@ -0,0 +1,193 @@
+package bench
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/utils/frost"
+)
+
+// Scheme is how the nodes commit to what they hand off every epoch, the commitment scheme with its curve
+type Scheme string
+
+const (
+	// KZG commitments on the PBC curve to the polynomials of the default secret
+	KZG Scheme = "kzg-pbc"
+	// Feldman commitments on P-521 as well, to the polynomial of a Schnorr key the operator deals before the first epoch. Every epoch refreshes the key next to the default secret, which keeps its KZG commitments.
+	Feldman Scheme = "feldman-p521"
+)
+
+// Schemes are the schemes a benchmark may sweep
+var Schemes = []Scheme{KZG, Feldman}
+
+// Config is one point of the grid a benchmark sweeps
+type Config struct {
+	Nodes  int
+	Degree int
+	Scheme Scheme
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("n=%d t=%d %s", c.Nodes, c.Degree, c.Scheme)
+}
+
+// Check tells whether the nodes can run the configuration
+func (c Config) Check() error {
+	if c.Degree < 1 || c.Degree >= c.Nodes {
+		return errors.New(fmt.Sprintf("degree must be between 1 and %d, got %d", c.Nodes-1, c.Degree))
+	}
+	if c.Scheme != KZG && c.Scheme != Feldman {
+		return errors.New(fmt.Sprintf("unknown scheme %q, use %s or %s", c.Scheme, KZG, Feldman))
+	}
+	return nil
+}
+
+// Grid returns every configuration of the given values in which the degree is below the number of nodes
+func Grid(nodes []int, degrees []int, schemes []Scheme) []Config {
+	grid := make([]Config, 0)
+	for _, n := range nodes {
+		for _, t := range degrees {
+			if t < 1 || t >= n {
+				continue
+			}
+			for _, scheme := range schemes {
+				grid = append(grid, Config{Nodes: n, Degree: t, Scheme: scheme})
+			}
+		}
+	}
+	return grid
+}
+
+// Runner runs a configuration for some epochs in dir, where the nodes and the bulletinboard leave the metric logs of every epoch.
+// It returns the CPU time each epoch took, or a single total for all of them if it cannot tell the epochs apart.
+type Runner interface {
+	Run(config Config, epochs int64, dir string) ([]time.Duration, error)
+}
+
+// Benchmark runs every configuration repeat times and aggregates what the epochs cost
+func Benchmark(runner Runner, grid []Config, repeat int, epochs int64) ([]Row, error) {
+	rows := make([]Row, 0)
+	for _, config := range grid {
+		if err := config.Check(); err != nil {
+			return nil, errors.New(fmt.Sprintf("%v: %v", config, err))
+		}
+		samples := make(map[string][]float64)
+		for i := 0; i < repeat; i++ {
+			log.Printf("[bench] %v, run %d of %d", config, i+1, repeat)
+			if err := runOnce(runner, config, epochs, samples); err != nil {
+				return nil, errors.New(fmt.Sprintf("%v, run %d: %v", config, i+1, err))
+			}
+		}
+		rows = append(rows, aggregate(config, samples)...)
+	}
+	return rows, nil
+}
+
+// Run a configuration in a directory of its own and add the samples of its epochs. The directory is kept when the run fails.
+func runOnce(runner Runner, config Config, epochs int64, samples map[string][]float64) error {
+	dir, err := ioutil.TempDir("", "churp-bench")
+	if err != nil {
+		return err
+	}
+	cpu, err := runner.Run(config, epochs, dir)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%v, the run is left in %s", err, dir))
+	}
+	board, err := readBoardLog(dir, epochs)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%v, the run is left in %s", err, dir))
+	}
+	node, err := readNodeLogs(dir, config.Nodes)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%v, the run is left in %s", err, dir))
+	}
+	if int64(len(node)) != epochs {
+		return errors.New(fmt.Sprintf("the nodes logged %d epochs, ran %d, the run is left in %s", len(node), epochs, dir))
+	}
+	os.RemoveAll(dir)
+
+	// a runner that cannot tell the epochs apart spreads its CPU time evenly
+	share := float64(epochs) / float64(len(cpu))
+	for _, d := range cpu {
+		samples[MetricCPU] = append(samples[MetricCPU], float64(d)/float64(time.Millisecond)/share)
+	}
+	for e := range node {
+		wire := 0.0
+		for _, record := range node[e] {
+			for metric, key := range latencies {
+				samples[metric] = append(samples[metric], record[key]/float64(time.Millisecond))
+			}
+			wire += record[keyMsgSize]
+		}
+		samples[MetricWire] = append(samples[MetricWire], wire)
+		samples[MetricBoard] = append(samples[MetricBoard], board[e][keyMsgSize])
+	}
+	return nil
+}
+
+// The bulletinboard logs an epoch just after it has told the clock the epoch completed, so its log may still be a record short when the run ends
+func readBoardLog(dir string, epochs int64) ([]map[string]float64, error) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		records, err := readLog(logPath(dir, 0))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(records)) >= epochs {
+			return records, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New(fmt.Sprintf("the bulletinboard logged %d epochs, ran %d", len(records), epochs))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// InProcess runs the committee inside this process over the local transport
+type InProcess struct{}
+
+func (InProcess) Run(config Config, epochs int64, dir string) ([]time.Duration, error) {
+	committee, err := localnet.Start(config.Degree, config.Nodes, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer committee.Stop()
+	if config.Scheme == Feldman {
+		op, err := committee.Operator(committee.Network.Endpoint("operator"))
+		if err != nil {
+			return nil, err
+		}
+		key, err := frost.RandomScalar(crand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := op.DealKey(key); err != nil {
+			return nil, err
+		}
+	}
+	cpu := make([]time.Duration, 0, epochs)
+	for e := int64(0); e < epochs; e++ {
+		before := cpuTime()
+		if err := committee.Run(1); err != nil {
+			return nil, err
+		}
+		cpu = append(cpu, cpuTime()-before)
+	}
+	return cpu, nil
+}
+
+// CPU time this process has used so far, the whole committee when it runs in process
+func cpuTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -0,0 +1,118 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid(t *testing.T) {
+	grid := Grid([]int{2, 4}, []int{1, 2, 3}, []Scheme{KZG})
+	assert.Equal(t, []Config{
+		{Nodes: 2, Degree: 1, Scheme: KZG},
+		{Nodes: 4, Degree: 1, Scheme: KZG},
+		{Nodes: 4, Degree: 2, Scheme: KZG},
+		{Nodes: 4, Degree: 3, Scheme: KZG},
+	}, grid)
+	assert.Equal(t, []Config{
+		{Nodes: 3, Degree: 1, Scheme: KZG},
+		{Nodes: 3, Degree: 1, Scheme: Feldman},
+	}, Grid([]int{3}, []int{1}, Schemes))
+
+	assert.Nil(t, grid[0].Check())
+	assert.NotNil(t, Config{Nodes: 4, Degree: 4, Scheme: KZG}.Check())
+	assert.NotNil(t, Config{Nodes: 4, Degree: 0, Scheme: KZG}.Check())
+	assert.NotNil(t, Config{Nodes: 4, Degree: 1, Scheme: "bls12"}.Check())
+}
+
+func TestReadLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bench")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	content := "totMsgSize,100\nepochLatency,2000000\ntotMsgSize,300\nepochLatency,4000000\n"
+	assert.Nil(t, ioutil.WriteFile(logPath(dir, 1), []byte(content), 0644))
+	records, err := readLog(logPath(dir, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]float64{
+		{keyMsgSize: 100, keyEpoch: 2000000},
+		{keyMsgSize: 300, keyEpoch: 4000000},
+	}, records)
+
+	assert.Nil(t, ioutil.WriteFile(logPath(dir, 2), []byte("totMsgSize,100\n"), 0644))
+	_, err = readNodeLogs(dir, 2)
+	assert.NotNil(t, err, "nodes that logged a different number of epochs")
+
+	assert.Nil(t, ioutil.WriteFile(logPath(dir, 2), []byte("totMsgSize\n"), 0644))
+	_, err = readLog(logPath(dir, 2))
+	assert.NotNil(t, err)
+}
+
+func TestReport(t *testing.T) {
+	config := Config{Nodes: 4, Degree: 1, Scheme: Feldman}
+	rows := aggregate(config, map[string][]float64{
+		MetricWire:  {1, 2, 3, 4},
+		MetricEpoch: {10},
+	})
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, MetricEpoch, rows[0].Metric)
+	assert.Equal(t, "ms", rows[0].Unit)
+	assert.Equal(t, 10.0, rows[0].P95)
+	assert.Equal(t, MetricWire, rows[1].Metric)
+	assert.Equal(t, 4, rows[1].Count)
+	assert.Equal(t, 2.5, rows[1].Mean)
+	assert.Equal(t, 2.5, rows[1].Median)
+	assert.Equal(t, 1.0, rows[1].Min)
+	assert.Equal(t, 4.0, rows[1].Max)
+
+	var out bytes.Buffer
+	assert.Nil(t, WriteCSV(&out, rows))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, strings.Join(header, ","), lines[0])
+	assert.True(t, strings.HasPrefix(lines[2], "4,1,feldman-p521,wire_bytes,bytes,4,2.5,2.5,"))
+
+	out.Reset()
+	assert.Nil(t, WriteJSON(&out, rows))
+	var decoded []Row
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, rows, decoded)
+}
+
+func TestBenchmarkInProcess(t *testing.T) {
+	rows, err := Benchmark(InProcess{}, Grid([]int{3}, []int{1}, Schemes), 2, 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+	counts := make(map[Scheme]map[string]int)
+	board := make(map[Scheme]float64)
+	for _, row := range rows {
+		if counts[row.Scheme] == nil {
+			counts[row.Scheme] = make(map[string]int)
+		}
+		counts[row.Scheme][row.Metric] = row.Count
+		if row.Metric == MetricBoard {
+			board[row.Scheme] = row.Mean
+		}
+		assert.True(t, row.Min > 0, "%s is positive", row.Metric)
+	}
+	// the epochs of the Feldman scheme write the commitments to the P-521 key as well
+	assert.True(t, board[Feldman] > board[KZG], "%v bytes on the bulletinboard with the key, %v without", board[Feldman], board[KZG])
+	// 2 runs of 2 epochs for each scheme, every node logs its latencies in every epoch
+	for _, scheme := range Schemes {
+		assert.Equal(t, map[string]int{
+			MetricEpoch:           12,
+			MetricReconstruction:  12,
+			MetricProactivization: 12,
+			MetricShareDist:       12,
+			MetricCPU:             4,
+			MetricWire:            4,
+			MetricBoard:           4,
+		}, counts[scheme], "%s", scheme)
+	}
+}
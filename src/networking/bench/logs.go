@@ -0,0 +1,104 @@
+package bench
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Keys of the lines the nodes and the bulletinboard append to their log at the end of every epoch
+const (
+	keyMsgSize = "totMsgSize"
+	keyEpoch   = "epochLatency"
+)
+
+// Metrics the reports carry
+const (
+	MetricEpoch           = "epoch_latency"
+	MetricReconstruction  = "reconstruction_latency"
+	MetricProactivization = "proactivization_latency"
+	MetricShareDist       = "sharedist_latency"
+	MetricCPU             = "cpu_time"
+	MetricWire            = "wire_bytes"
+	MetricBoard           = "board_bytes"
+)
+
+// The latency metrics and the keys the nodes log them under, in nanoseconds
+var latencies = map[string]string{
+	MetricEpoch:           keyEpoch,
+	MetricReconstruction:  "reconstructionLatency",
+	MetricProactivization: "proactivizationLatency",
+	MetricShareDist:       "sharedistLatency",
+}
+
+// Units of the metrics
+var units = map[string]string{
+	MetricEpoch:           "ms",
+	MetricReconstruction:  "ms",
+	MetricProactivization: "ms",
+	MetricShareDist:       "ms",
+	MetricCPU:             "ms",
+	MetricWire:            "bytes",
+	MetricBoard:           "bytes",
+}
+
+// The log of the bulletinboard for label 0, of node label otherwise
+func logPath(dir string, label int) string {
+	return dir + "/log" + strconv.Itoa(label)
+}
+
+// Read a log of key,value lines into one record per epoch. A record ends where a key comes again.
+func readLog(path string) ([]map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records := make([]map[string]float64, 0)
+	var record map[string]float64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ",", 2)
+		if len(parts) != 2 {
+			return nil, errors.New(fmt.Sprintf("%s: line %q is not key,value", path, line))
+		}
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%s: %v", path, err))
+		}
+		if _, ok := record[parts[0]]; ok || record == nil {
+			record = make(map[string]float64)
+			records = append(records, record)
+		}
+		record[parts[0]] = value
+	}
+	return records, scanner.Err()
+}
+
+// Read the logs of the nodes of a committee, the records of every node for every epoch
+func readNodeLogs(dir string, counter int) ([][]map[string]float64, error) {
+	var epochs [][]map[string]float64
+	for label := 1; label <= counter; label++ {
+		records, err := readLog(logPath(dir, label))
+		if err != nil {
+			return nil, err
+		}
+		if epochs == nil {
+			epochs = make([][]map[string]float64, len(records))
+		}
+		if len(records) != len(epochs) {
+			return nil, errors.New(fmt.Sprintf("node %d logged %d epochs, node 1 logged %d", label, len(records), len(epochs)))
+		}
+		for e, record := range records {
+			epochs[e] = append(epochs[e], record)
+		}
+	}
+	return epochs, nil
+}
@@ -0,0 +1,139 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+)
+
+// Processes runs the committee as local processes that talk gRPC over the loopback, from the churp binary the Makefile builds
+type Processes struct {
+	// Directory holding churp.exe
+	Bin string
+	// The bulletinboard listens on BasePort, node i on BasePort+i
+	BasePort int
+	// How long to wait for the committee to listen, and for the clock to run the epochs
+	Timeout time.Duration
+}
+
+// The passphrase the nodes store their shares under
+const passphrase = "bench"
+
+func (p Processes) Run(config Config, epochs int64, dir string) ([]time.Duration, error) {
+	addrs := make([]string, 0, config.Nodes+1)
+	for i := 0; i <= config.Nodes; i++ {
+		addrs = append(addrs, fmt.Sprintf("127.0.0.1:%d", p.BasePort+i))
+	}
+	if err := ioutil.WriteFile(dir+"/ip_list", []byte(strings.Join(addrs, "\n")+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := identity.GenerateAll(config.Nodes, dir); err != nil {
+		return nil, err
+	}
+
+	n := strconv.Itoa(config.Nodes)
+	t := strconv.Itoa(config.Degree)
+	servers := make([]*exec.Cmd, 0, config.Nodes+1)
+	defer func() {
+		for _, cmd := range servers {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}
+	}()
+	board, err := p.start(dir, "board", "bb.out", "-c", n, "-d", t, "-path", dir)
+	if err != nil {
+		return nil, err
+	}
+	servers = append(servers, board)
+	for i := 1; i <= config.Nodes; i++ {
+		node, err := p.start(dir, "node", fmt.Sprintf("node%d.out", i), "-l", strconv.Itoa(i), "-c", n, "-d", t, "-path", dir)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, node)
+	}
+	deadline := time.Now().Add(p.Timeout)
+	for _, addr := range addrs {
+		if err := waitListening(addr, deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Scheme == Feldman {
+		// the operator deals the key before the first epoch, its CPU time is not part of the epochs
+		ctl, err := p.start(dir, "ctl", "ctl.out", "-c", n, "-path", dir, "deal-key")
+		if err != nil {
+			return nil, err
+		}
+		if err := ctl.Wait(); err != nil {
+			return nil, errors.New(fmt.Sprintf("dealing the P-521 key failed: %v", err))
+		}
+	}
+
+	clock, err := p.start(dir, "clock", "clock.out", "-c", n, "-e", "1", "-n", strconv.FormatInt(epochs, 10), "-t", "0", "-path", dir)
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() { done <- clock.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("clock failed: %v", err))
+		}
+	case <-time.After(time.Until(deadline)):
+		clock.Process.Kill()
+		<-done
+		return nil, errors.New(fmt.Sprintf("the committee did not run %d epochs within %v", epochs, p.Timeout))
+	}
+
+	// the servers only stop when they are killed, their CPU time is read once they have
+	cpu := clock.ProcessState.UserTime() + clock.ProcessState.SystemTime()
+	for _, cmd := range servers {
+		cmd.Process.Kill()
+		cmd.Wait()
+		cpu += cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+	}
+	servers = nil
+	return []time.Duration{cpu}, nil
+}
+
+// Start a subcommand of the churp binary that writes its output to the file out in dir
+func (p Processes) start(dir string, name string, out string, args ...string) (*exec.Cmd, error) {
+	f, err := os.Create(filepath.Join(dir, out))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cmd := exec.Command(filepath.Join(p.Bin, "churp.exe"), append([]string{name}, args...)...)
+	cmd.Env = append(os.Environ(), "CHURP_PASSPHRASE="+passphrase)
+	cmd.Stdout = f
+	cmd.Stderr = f
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+func waitListening(addr string, deadline time.Time) error {
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New(fmt.Sprintf("nothing listens on %s: %v", addr, err))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,102 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Row sums up the samples of one metric over every run of a configuration
+type Row struct {
+	Nodes  int     `json:"n"`
+	Degree int     `json:"t"`
+	Scheme Scheme  `json:"scheme"`
+	Metric string  `json:"metric"`
+	Unit   string  `json:"unit"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	Stddev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	P95    float64 `json:"p95"`
+}
+
+var header = []string{"n", "t", "scheme", "metric", "unit", "count", "mean", "median", "stddev", "min", "max", "p95"}
+
+// One row for every metric that has samples, in the order of their names
+func aggregate(config Config, samples map[string][]float64) []Row {
+	metrics := make([]string, 0, len(samples))
+	for metric := range samples {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	rows := make([]Row, 0, len(metrics))
+	for _, metric := range metrics {
+		data := stats.Float64Data(samples[metric])
+		if len(data) == 0 {
+			continue
+		}
+		row := Row{
+			Nodes:  config.Nodes,
+			Degree: config.Degree,
+			Scheme: config.Scheme,
+			Metric: metric,
+			Unit:   units[metric],
+			Count:  len(data),
+		}
+		// the statistics only fail on empty data
+		row.Mean, _ = stats.Mean(data)
+		row.Median, _ = stats.Median(data)
+		row.Stddev, _ = stats.StandardDeviation(data)
+		row.Min, _ = stats.Min(data)
+		row.Max, _ = stats.Max(data)
+		row.P95, _ = stats.PercentileNearestRank(data, 95)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteCSV writes the rows as CSV with a header line
+func WriteCSV(w io.Writer, rows []Row) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			strconv.Itoa(row.Nodes),
+			strconv.Itoa(row.Degree),
+			string(row.Scheme),
+			row.Metric,
+			row.Unit,
+			strconv.Itoa(row.Count),
+			formatFloat(row.Mean),
+			formatFloat(row.Median),
+			formatFloat(row.Stddev),
+			formatFloat(row.Min),
+			formatFloat(row.Max),
+			formatFloat(row.P95),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes the rows as a JSON array
+func WriteJSON(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,79 @@
+package bulletinboard
+
+import (
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Checks a write must pass before it is stored. A rejected write fails with the code of its reason:
+//
+//	Unauthenticated     the signature does not come from the node named by the index
+//	PermissionDenied    the index names no member of the committee, or the write is for another committee
+//	NotFound            the committee holds no secret with the ID of the write
+//	InvalidArgument     the commitment is missing a part
+//	FailedPrecondition  no epoch is running, the write is for an earlier epoch, or its phase is not open yet
+//	Unavailable         the write is for a later epoch, the writer may retry once the board caught up
+//	AlreadyExists       the node already wrote a different commitment in this phase, see rewrite
+func (bb *BulletinBoard) authorizeWrite(phase int32, msg commitMsg) error {
+	// the writer is the node whose key signed the commitment
+	if index := msg.GetIndex(); index < 1 || int(index) > bb.counter {
+		return status.Errorf(codes.PermissionDenied, "node %d is not a member of the committee", index)
+	}
+	if err := pb.VerifySigned(bb.pks, msg); err != nil {
+		return err
+	}
+	if err := checkParts(msg); err != nil {
+		return err
+	}
+	bb.mutex.Lock()
+	err := bb.checkWrite(msg)
+	if err == nil {
+		err = bb.holds(*bb.epoch, msg.GetSecret())
+	}
+	need := bb.counter * len(bb.history[*bb.epoch].HeldSecrets())
+	bb.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if phase == phaseShareDist {
+		// share distribution opens once every node wrote its proactivization commitment for every secret
+		entries, err := bb.backend.Read(msg.GetEpoch(), phaseProactivization)
+		if err != nil {
+			return err
+		}
+		if len(entries) < need {
+			return status.Errorf(codes.FailedPrecondition, "phase 3 of epoch %d is not open, %d of %d commitments of phase 2 are written", msg.GetEpoch(), len(entries), need)
+		}
+	}
+	return nil
+}
+
+// Writes must belong to the epoch in progress. The caller holds bb.mutex.
+func (bb *BulletinBoard) checkWrite(msg pb.EpochScoped) error {
+	if *bb.epoch < 1 || bb.history[*bb.epoch].GetState() != pb.EpochStatusMsg_RUNNING {
+		return status.Error(codes.FailedPrecondition, "no epoch in progress")
+	}
+	return pb.CheckEpoch(msg, *bb.epoch, bb.committee)
+}
+
+// Every part of a commitment must be present
+func checkParts(msg commitMsg) error {
+	var parts map[string][]byte
+	switch m := msg.(type) {
+	case *pb.Cmt1Msg:
+		parts = map[string][]byte{"polynomial commitment": m.GetPolycmt()}
+	case *pb.Cmt2Msg:
+		parts = map[string][]byte{
+			"share commitment":      m.GetSharecmt(),
+			"polynomial commitment": m.GetPolycmt(),
+			"zero witness":          m.GetZerowitness(),
+		}
+	}
+	for name, part := range parts {
+		if len(part) == 0 {
+			return status.Errorf(codes.InvalidArgument, "the %s of node %d is empty", name, msg.GetIndex())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package bulletinboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+)
+
+// Status reports the current epoch and which nodes have written in each of its phases, for operators
+func (bb *BulletinBoard) Status(ctx context.Context, in *pb.StatusRequestMsg) (*pb.StatusMsg, error) {
+	notReady := bb.notReady()
+	bb.mutex.Lock()
+	epoch := *bb.epoch
+	current := proto.Clone(bb.history[epoch]).(*pb.EpochStatusMsg)
+	completed := int64(0)
+	for _, msg := range bb.history {
+		if msg.GetState() == pb.EpochStatusMsg_COMPLETED {
+			completed = msg.GetEpoch()
+		}
+	}
+	conns := make([]*pb.ConnStatusMsg, bb.counter)
+	for i := 0; i < bb.counter; i++ {
+		state := transport.NotDialed
+		if bb.nConn[i] != nil {
+			state = bb.nConn[i].State()
+		}
+		peer := fmt.Sprintf("node%d", i+1)
+		conns[i] = &pb.ConnStatusMsg{
+			Peer:    peer,
+			Address: bb.ipList[i],
+			State:   state,
+		}
+		bb.health[peer].Report(conns[i])
+	}
+	bb.mutex.Unlock()
+
+	// a node has written a phase once it wrote for every secret, a dealing writes for the one it deals
+	secrets := len(current.HeldSecrets())
+	if current.GetDealt() {
+		secrets = 1
+	}
+	phases := make([]*pb.PhaseWritersMsg, 0, 2)
+	for _, phase := range []int32{phaseProactivization, phaseShareDist} {
+		entries, err := bb.backend.Read(epoch, phase)
+		if err != nil {
+			return nil, err
+		}
+		written := make([]int32, 0, len(entries))
+		seen := make(map[int32]int)
+		for _, entry := range entries {
+			index := entry.GetIndex()
+			if seen[index]++; seen[index] == secrets {
+				written = append(written, index)
+			}
+		}
+		sort.Slice(written, func(i, j int) bool { return written[i] < written[j] })
+		phases = append(phases, &pb.PhaseWritersMsg{Phase: phase, Written: written})
+	}
+	return &pb.StatusMsg{
+		Committee: bb.committee,
+		Counter:   int32(bb.counter),
+		Degree:    int32(bb.degree),
+		Epoch:     epoch,
+		Completed: completed,
+		Conns:     conns,
+		Board: &pb.BoardStatusMsg{
+			Status: current,
+			Phases: phases,
+		},
+		Ready:    notReady == "",
+		NotReady: notReady,
+	}, nil
+}
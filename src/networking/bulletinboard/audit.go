@@ -0,0 +1,142 @@
+package bulletinboard
+
+import (
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/merkle"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Audit returns the Merkle roots over the complete phases of an epoch and the last entry of the epoch in the log
+func (bb *BulletinBoard) Audit(ctx context.Context, in *pb.EpochMsg) (*pb.AuditMsg, error) {
+	if err := bb.checkAudit(in); err != nil {
+		return nil, err
+	}
+	epoch := in.GetEpoch()
+	msg := &pb.AuditMsg{
+		Epoch:     epoch,
+		Committee: bb.committee,
+	}
+	// every node writes once for each secret the epoch hands off
+	need := bb.counter * bb.secretCount(epoch)
+	pro, err := bb.backend.Read(epoch, phaseProactivization)
+	if err != nil {
+		return nil, err
+	}
+	if len(pro) == need {
+		msg.ProactivizationRoot = merkle.Root(hashes(pro))
+	}
+	entries, err := bb.epochLog(epoch, phaseShareDist)
+	if err != nil {
+		return nil, err
+	}
+	// genesis and dealings have no phase 2 and write for one secret
+	if len(entries) == 2*need || (len(pro) == 0 && len(entries) == bb.counter) {
+		msg.Root = merkle.Root(hashes(entries))
+		msg.Size = int32(len(entries))
+		for _, entry := range entries {
+			if entry.GetSeq() > msg.Seq {
+				msg.Seq = entry.GetSeq()
+				msg.Head = entry.GetHash()
+			}
+		}
+	}
+	return msg, nil
+}
+
+// ReadLog returns the entries of an epoch so far in the order of the leaves of its Merkle tree
+func (bb *BulletinBoard) ReadLog(in *pb.EpochMsg, stream pb.BulletinBoardService_ReadLogServer) error {
+	if err := bb.checkAudit(in); err != nil {
+		return err
+	}
+	entries, err := bb.epochLog(in.GetEpoch(), phaseShareDist)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := stream.Send(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Audits may address genesis and any epoch that has started
+func (bb *BulletinBoard) checkAudit(in *pb.EpochMsg) error {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if in.GetCommittee() != bb.committee {
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	if in.GetEpoch() > *bb.epoch {
+		return status.Errorf(codes.Unavailable, "future epoch %d, current epoch is %d", in.GetEpoch(), *bb.epoch)
+	}
+	if in.GetEpoch() < 0 {
+		return status.Errorf(codes.InvalidArgument, "negative epoch %d", in.GetEpoch())
+	}
+	return nil
+}
+
+// Number of secrets an epoch with phases hands off, it starts and ends with the same ones
+func (bb *BulletinBoard) secretCount(epoch int64) int {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	return len(bb.history[epoch].HeldSecrets())
+}
+
+// Entries of an epoch from phase 2 up to the given phase, in the order of the leaves of its Merkle tree: phase by phase, in the order of the log within a phase
+func (bb *BulletinBoard) epochLog(epoch int64, phase int32) ([]*pb.EntryMsg, error) {
+	entries := make([]*pb.EntryMsg, 0)
+	for p := phaseProactivization; p <= phase; p++ {
+		read, err := bb.backend.Read(epoch, p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, read...)
+	}
+	return entries, nil
+}
+
+// Prove that each entry of content is in the Merkle tree over its epoch up to the given phase
+func (bb *BulletinBoard) prove(epoch int64, phase int32, content []*pb.EntryMsg) ([]*pb.InclusionMsg, error) {
+	entries, err := bb.epochLog(epoch, phase)
+	if err != nil {
+		return nil, err
+	}
+	leaves := hashes(entries)
+	position := make(map[int64]int)
+	for i, entry := range entries {
+		position[entry.GetSeq()] = i
+	}
+	root := merkle.Root(leaves)
+	proofs := make([]*pb.InclusionMsg, len(content))
+	for i, entry := range content {
+		leaf, ok := position[entry.GetSeq()]
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "entry %d of epoch %d is missing from the log", entry.GetSeq(), epoch)
+		}
+		path, err := merkle.Proof(leaves, leaf)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot prove entry %d: %v", entry.GetSeq(), err)
+		}
+		proofs[i] = &pb.InclusionMsg{
+			Seq:  entry.GetSeq(),
+			Prev: entry.GetPrev(),
+			Leaf: int32(leaf),
+			Size: int32(len(leaves)),
+			Path: path,
+			Root: root,
+		}
+	}
+	return proofs, nil
+}
+
+func hashes(entries []*pb.EntryMsg) [][]byte {
+	leaves := make([][]byte, len(entries))
+	for i, entry := range entries {
+		leaves[i] = entry.GetHash()
+	}
+	return leaves
+}
@@ -0,0 +1,60 @@
+package bulletinboard
+
+import (
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Phases of an epoch whose commitments are kept on the bulletinboard
+const (
+	// Proactivization, entries hold a Cmt2Msg
+	phaseProactivization int32 = 2
+	// Share distribution, entries hold a Cmt1Msg. Phase 1 of an epoch reads the ones of the previous epoch.
+	phaseShareDist int32 = 3
+	// Beacon of the round of a completed epoch, entries hold a BeaconShareMsg. It is not part of the log of the epoch an audit covers.
+	phaseBeacon int32 = 4
+)
+
+// Backend keeps the content of the bulletinboard.
+// The BulletinBoard server in front of it checks who may write what and drives the epochs, a backend only stores entries and hands them out again.
+type Backend interface {
+	// Append stores an entry at the end of the log and chains it to the entry before, setting its Seq, Prev and Hash.
+	// Every node has at most one entry for a secret in a phase of an epoch, a second one fails with ErrTaken and the first is kept.
+	Append(entry *pb.EntryMsg) error
+	// Read returns the entries of a phase of an epoch in the order they were appended, those of every secret
+	Read(epoch int64, phase int32) ([]*pb.EntryMsg, error)
+	// Subscribe delivers the stored entries of the given epoch and all later ones, then every entry appended afterwards, until cancel is called.
+	// An entry may be delivered more than once.
+	Subscribe(epoch int64) (entries <-chan *pb.EntryMsg, cancel func())
+	// Close releases the backend
+	Close() error
+}
+
+// EpochRecorder is a backend that also keeps the status of every epoch, so that a restarted bulletinboard carries on with the epochs it had
+type EpochRecorder interface {
+	// RecordEpoch stores the status of an epoch, replacing the one stored before
+	RecordEpoch(msg *pb.EpochStatusMsg) error
+	// Epochs returns the stored statuses in the order of their epochs
+	Epochs() []*pb.EpochStatusMsg
+}
+
+// ErrTaken is returned by Append when the node already has an entry for that secret in that phase of that epoch
+var ErrTaken = status.Error(codes.AlreadyExists, "the node already has an entry in this phase")
+
+// Identifies the entry of a node for a secret in a phase of an epoch
+type slot struct {
+	epoch  int64
+	phase  int32
+	secret string
+	index  int32
+}
+
+func slotOf(entry *pb.EntryMsg) slot {
+	return slot{
+		epoch:  entry.GetEpoch(),
+		phase:  entry.GetPhase(),
+		secret: entry.GetSecret(),
+		index:  entry.GetIndex(),
+	}
+}
@@ -0,0 +1,187 @@
+package bulletinboard
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Nik-U/pbc"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/beacon"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WriteBeacon stores the partial evaluation of a node for the beacon of the round of a completed epoch.
+// The board takes it only if it verifies under the public share of the node, whose values must open the commitments the epoch ended with, so every evaluation on the board counts towards the beacon. The same evaluation written again is acknowledged.
+func (bb *BulletinBoard) WriteBeacon(ctx context.Context, msg *pb.BeaconShareMsg) (*pb.AckMsg, error) {
+	ack, err := bb.storeBeacon(msg)
+	bb.countReceived("WriteBeacon", msg.GetIndex(), msg, err)
+	return ack, err
+}
+
+func (bb *BulletinBoard) storeBeacon(msg *pb.BeaconShareMsg) (*pb.AckMsg, error) {
+	index := msg.GetIndex()
+	entry := epochEntry(msg.GetEpoch()).WithFields(logrus.Fields{"rpc": "WriteBeacon", "peer": bb.peer(index)})
+	if err := bb.checkBeacon(msg); err != nil {
+		entry.WithError(err).Warn("reject partial evaluation")
+		return nil, err
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot encode the partial evaluation: %v", err)
+	}
+	err = bb.backend.Append(&pb.EntryMsg{
+		Epoch: msg.GetEpoch(),
+		Phase: phaseBeacon,
+		Index: index,
+		Data:  data,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return bb.rewrite(phaseBeacon, msg)
+	}
+	if err != nil {
+		entry.WithError(err).Error("failed to store the partial evaluation")
+		return nil, err
+	}
+	entry.Debug("store partial evaluation")
+	return bb.ack(), nil
+}
+
+// A partial evaluation must come from a member of the committee, for a completed epoch, and verify under a public share that opens the commitments of the beacon secret the epoch ended with
+func (bb *BulletinBoard) checkBeacon(msg *pb.BeaconShareMsg) error {
+	index := msg.GetIndex()
+	if index < 1 || int(index) > bb.counter {
+		return status.Errorf(codes.PermissionDenied, "node %d is not a member of the committee", index)
+	}
+	if err := pb.VerifySigned(bb.pks, msg); err != nil {
+		return err
+	}
+	bb.mutex.Lock()
+	err := bb.checkRound(msg)
+	bb.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	epoch := msg.GetEpoch()
+	share := msg.GetShare()
+	if share.GetIndex() != index || share.GetEpoch() != epoch || share.GetCommittee() != bb.committee {
+		return status.Errorf(codes.InvalidArgument, "the public share is the one of node %d in epoch %d", share.GetIndex(), share.GetEpoch())
+	}
+	cmts, err := bb.cmt1(epoch, pb.BeaconSecret)
+	if err != nil {
+		return err
+	}
+	polyCmts := make([][]byte, len(cmts))
+	for i, cmt := range cmts {
+		polyCmts[i] = cmt.GetPolycmt()
+	}
+	public, err := tbls.PublicShare(bb.dpc, int(index), polyCmts, share.GetEvals(), share.GetWitnesses(), bb.lambda)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !bytes.Equal(public.CompressedBytes(), share.GetPublicShare()) {
+		return status.Error(codes.InvalidArgument, "the public share does not match the commitments")
+	}
+	partial, err := tbls.Parse(msg.GetPartial())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !beacon.Verify(public, epoch, partial) {
+		return status.Error(codes.InvalidArgument, "the partial evaluation does not verify under the public share")
+	}
+	return nil
+}
+
+// ReadBeacon returns the beacon of the round of an epoch, combined from the first degree+1 partial evaluations written for it, along with all the evaluations written.
+// It fails with Unavailable while fewer have been written.
+func (bb *BulletinBoard) ReadBeacon(ctx context.Context, in *pb.EpochMsg) (*pb.BeaconMsg, error) {
+	bb.mutex.Lock()
+	err := bb.checkRound(in)
+	bb.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	epoch := in.GetEpoch()
+	entries, err := bb.backend.Read(epoch, phaseBeacon)
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.BeaconMsg{
+		Epoch:     epoch,
+		Committee: bb.committee,
+	}
+	signers := make([]int, 0, bb.degree+1)
+	partials := make([]*pbc.Element, 0, bb.degree+1)
+	publics := make([]*pbc.Element, 0, bb.degree+1)
+	for _, entry := range entries {
+		msg := &pb.BeaconShareMsg{}
+		if err := proto.Unmarshal(entry.GetData(), msg); err != nil {
+			return nil, status.Errorf(codes.DataLoss, "corrupted entry of [node %d]: %v", entry.GetIndex(), err)
+		}
+		out.Shares = append(out.Shares, msg)
+		if len(signers) > bb.degree {
+			continue
+		}
+		// the board checked the evaluation before it took it
+		partial, err := tbls.Parse(msg.GetPartial())
+		if err != nil {
+			return nil, status.Errorf(codes.DataLoss, "corrupted evaluation of [node %d]: %v", entry.GetIndex(), err)
+		}
+		public, err := tbls.Parse(msg.GetShare().GetPublicShare())
+		if err != nil {
+			return nil, status.Errorf(codes.DataLoss, "corrupted public share of [node %d]: %v", entry.GetIndex(), err)
+		}
+		signers = append(signers, int(msg.GetIndex()))
+		partials = append(partials, partial)
+		publics = append(publics, public)
+	}
+	if len(signers) <= bb.degree {
+		return nil, status.Errorf(codes.Unavailable, "the beacon of epoch %d has %d of the %d partial evaluations it needs", epoch, len(signers), bb.degree+1)
+	}
+	proof, err := tbls.Combine(signers, partials)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pub, err := tbls.Combine(signers, publics)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !beacon.Verify(pub, epoch, proof) {
+		return nil, status.Errorf(codes.Internal, "the partial evaluations of epoch %d do not combine into a valid proof", epoch)
+	}
+	for _, signer := range signers {
+		out.Signers = append(out.Signers, int32(signer))
+	}
+	out.Output = beacon.Output(epoch, proof)
+	out.Proof = proof.CompressedBytes()
+	out.PublicKey = pub.CompressedBytes()
+	return out, nil
+}
+
+// The round of a beacon is an epoch of this committee that completed and ran its phases, which hand the beacon secret off. The caller holds bb.mutex.
+func (bb *BulletinBoard) checkRound(msg pb.EpochScoped) error {
+	if msg.GetCommittee() != bb.committee {
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), bb.committee)
+	}
+	epoch := msg.GetEpoch()
+	if epoch < 1 {
+		return status.Errorf(codes.InvalidArgument, "epoch %d has no beacon", epoch)
+	}
+	if epoch > *bb.epoch {
+		return status.Errorf(codes.Unavailable, "future epoch %d, current epoch is %d", epoch, *bb.epoch)
+	}
+	switch bb.history[epoch].GetState() {
+	case pb.EpochStatusMsg_RUNNING:
+		return status.Errorf(codes.Unavailable, "epoch %d has not completed", epoch)
+	case pb.EpochStatusMsg_FAILED:
+		return status.Errorf(codes.FailedPrecondition, "epoch %d failed", epoch)
+	}
+	if bb.history[epoch].GetDealt() || bb.history[epoch].GetDeleted() {
+		// the operator knows a secret it just dealt
+		return status.Errorf(codes.FailedPrecondition, "epoch %d ran no phases and has no beacon", epoch)
+	}
+	return nil
+}
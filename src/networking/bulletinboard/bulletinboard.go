@@ -1,94 +1,294 @@
 package bulletinboard
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
 	pb "github.com/bl4ck5un/ChuRP/src/services"
 	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
 	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
+	"github.com/golang/protobuf/proto"
+	"github.com/ncw/gmp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io/ioutil"
-	"log"
 	"math/rand"
-	"net"
 	"os"
 	"strings"
 	"sync"
-	// "time"
-	"github.com/golang/protobuf/proto"
-	"github.com/ncw/gmp"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
+	"time"
 )
 
+// DefaultEpochTimeout is how long an epoch may run before the board fails it, see SetEpochTimeout
+const DefaultEpochTimeout = 10 * time.Minute
+
 // BulletinBoard Simulator Structure
 type BulletinBoard struct {
 	// Metadata Directory Path
 	metadataPath string
 	// Counter
 	counter int
+	// Polynomial Degree
+	degree int
 	// BulletinBoard IP Address
 	bip string
 	// IP
 	ipList []string
+	// Identity Keys of All Nodes
+	pks []*ecdsa.PublicKey
+	// Key of the operator who deals secrets, nil if there is none
+	operator *ecdsa.PublicKey
+	// Commitment, to check the partial evaluations of the beacon
+	dpc *commitment.DLPolyCommit
+	// Lagrange Coefficients at 0 of the Labels of the Committee
+	lambda []*gmp.Int
 	// Rand
 	randState *rand.Rand
-	// Reconstruction BulletinBoard
-	reconstructionContent []*pb.Cmt1Msg
-	// Proactivization BulletinBoard
-	proCnt                 *int
-	proactivizationContent []*pb.Cmt2Msg
-	// Share Distribution BulletinBoard
-	shaCnt *int
+	// Current Epoch
+	epoch *int64
+	// Committee ID
+	committee string
+	// Status of every epoch so far, indexed by epoch
+	history []*pb.EpochStatusMsg
+	// How long an epoch may run before the board fails it, 0 to wait forever. Set before serving.
+	epochTimeout time.Duration
+	// Content of the board, the commitments of phase 2 and 3 of every epoch
+	backend Backend
 
 	// Mutexes
 	mutex sync.Mutex
 
-	nConn   []*grpc.ClientConn
-	nClient []pb.NodeServiceClient
+	transport transport.Transport
+	nConn     []transport.Conn
+	nClient   []pb.NodeServiceClient
+	// Outcome of the latest calls to each node
+	health map[string]*transport.Health
+
+	// Cancelled once the board stops, ending the calls it still makes
+	ctx    context.Context
+	cancel context.CancelFunc
+	// Set once the board refuses new epochs to stop after the current one
+	draining *bool
+	// Server the board serves on, nil until it serves
+	server transport.Server
 
 	// Metrics
-	totMsgSize *int
+	metrics *boardMetrics
+	// Spans of the stretches of the current epoch
+	steps *trace.Steps
+}
+
+func (bb *BulletinBoard) StartEpoch(ctx context.Context, in *pb.EpochMsg) (*pb.AckMsg, error) {
+	bb.mutex.Lock()
+	if in.GetCommittee() != bb.committee {
+		bb.mutex.Unlock()
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	if *bb.draining {
+		bb.mutex.Unlock()
+		return nil, errDraining
+	}
+	if in.GetEpoch() != *bb.epoch+1 {
+		bb.mutex.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "epoch %d cannot follow epoch %d", in.GetEpoch(), *bb.epoch)
+	}
+	if bb.history[*bb.epoch].GetState() == pb.EpochStatusMsg_RUNNING {
+		bb.mutex.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "epoch %d is still running", *bb.epoch)
+	}
+	// the epoch hands off every secret the committee holds
+	msg := &pb.EpochStatusMsg{
+		Epoch:     in.GetEpoch(),
+		Committee: bb.committee,
+		State:     pb.EpochStatusMsg_RUNNING,
+		Start:     time.Now().UnixNano(),
+		Secrets:   append([]string{}, bb.history[*bb.epoch].HeldSecrets()...),
+	}
+	if err := bb.recordEpoch(msg); err != nil {
+		bb.mutex.Unlock()
+		return nil, status.Errorf(codes.Internal, "cannot record the start of epoch %d: %v", in.GetEpoch(), err)
+	}
+	*bb.epoch = in.GetEpoch()
+	bb.history = append(bb.history, msg)
+	bb.mutex.Unlock()
+	bb.startEpochMetrics(msg)
+	epochEntry(in.GetEpoch()).Info("start epoch")
+	// the epoch runs on after the clock is acknowledged, the clock follows it through EpochStatus
+	go bb.ClientStartPhase1()
+	go bb.expire(msg)
+	return bb.ack(), nil
 }
 
-func (bb *BulletinBoard) StartEpoch(ctx context.Context, in *pb.EmptyMsg) (*pb.AckMsg, error) {
-	log.Print("[bulletinboard] start epoch")
-	bb.ClientStartPhase1()
-	return &pb.AckMsg{}, nil
+// SetEpochTimeout sets how long an epoch may run before the board fails it, 0 to let it run forever
+func (bb *BulletinBoard) SetEpochTimeout(timeout time.Duration) {
+	bb.epochTimeout = timeout
 }
 
-func (bb *BulletinBoard) ReadPhase1(in *pb.EmptyMsg, stream pb.BulletinBoardService_ReadPhase1Server) error {
-	log.Print("[bulletinboard] is being read in phase 1")
-	for i := 0; i < bb.counter; i++ {
-		if err := stream.Send(bb.reconstructionContent[i]); err != nil {
-			log.Fatalf("bulletinboard failed to read phase1: %v", err)
+// Fail the epoch once it ran for the epoch timeout without an outcome, so that a committee stuck on an unresponsive node or a lost message moves on to the next epoch
+func (bb *BulletinBoard) expire(msg *pb.EpochStatusMsg) {
+	if bb.epochTimeout <= 0 {
+		return
+	}
+	timer := time.NewTimer(time.Until(time.Unix(0, msg.GetStart()).Add(bb.epochTimeout)))
+	defer timer.Stop()
+	select {
+	case <-bb.ctx.Done():
+		return
+	case <-timer.C:
+	}
+	bb.mutex.Lock()
+	running := bb.history[msg.GetEpoch()].GetState() == pb.EpochStatusMsg_RUNNING
+	bb.mutex.Unlock()
+	if running {
+		epochEntry(msg.GetEpoch()).Warnf("no outcome after %v", bb.epochTimeout)
+		bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_FAILED)
+	}
+}
+
+// Report the status of a single epoch
+func (bb *BulletinBoard) EpochStatus(ctx context.Context, in *pb.EpochMsg) (*pb.EpochStatusMsg, error) {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if in.GetCommittee() != bb.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	if in.GetEpoch() < 0 || in.GetEpoch() >= int64(len(bb.history)) {
+		return nil, status.Errorf(codes.NotFound, "epoch %d has not started", in.GetEpoch())
+	}
+	return proto.Clone(bb.history[in.GetEpoch()]).(*pb.EpochStatusMsg), nil
+}
+
+// Stream the status of every epoch from genesis on
+func (bb *BulletinBoard) EpochHistory(in *pb.EpochMsg, stream pb.BulletinBoardService_EpochHistoryServer) error {
+	bb.mutex.Lock()
+	if in.GetCommittee() != bb.committee {
+		bb.mutex.Unlock()
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	history := make([]*pb.EpochStatusMsg, len(bb.history))
+	for i := range bb.history {
+		history[i] = proto.Clone(bb.history[i]).(*pb.EpochStatusMsg)
+	}
+	bb.mutex.Unlock()
+	for _, msg := range history {
+		if err := stream.Send(msg); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (bb *BulletinBoard) WritePhase2(ctx context.Context, msg *pb.Cmt2Msg) (*pb.AckMsg, error) {
-	*bb.totMsgSize = *bb.totMsgSize + proto.Size(msg)
-	log.Print("[bulletinboard] is being written in phase 2")
-	index := msg.GetIndex()
-	bb.proactivizationContent[index-1] = msg
+// Close the record of an epoch. A failure is final, a later completion does not override it.
+func (bb *BulletinBoard) endEpoch(epoch int64, state pb.EpochStatusMsg_State) {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if bb.history[epoch].GetState() != pb.EpochStatusMsg_RUNNING {
+		return
+	}
+	bb.history[epoch].State = state
+	bb.history[epoch].End = time.Now().UnixNano()
+	bb.endEpochMetrics(bb.history[epoch])
+	if state == pb.EpochStatusMsg_COMPLETED {
+		epochEntry(epoch).Info("epoch completed")
+	} else {
+		epochEntry(epoch).Warn("epoch " + strings.ToLower(state.String()))
+	}
+	if err := bb.recordEpoch(bb.history[epoch]); err != nil {
+		epochEntry(epoch).WithError(err).Error("failed to record the end of the epoch")
+	}
+}
+
+// Store the status of an epoch if the backend keeps them. The caller holds bb.mutex.
+func (bb *BulletinBoard) recordEpoch(msg *pb.EpochStatusMsg) error {
+	if recorder, ok := bb.backend.(EpochRecorder); ok {
+		return recorder.RecordEpoch(msg)
+	}
+	return nil
+}
+
+// ReadCommitments returns the commitments to the sharing polynomials of a secret a completed epoch ended with, genesis included, with the proofs that they are in the log.
+// A node joining the committee reads them for the latest completed epoch of EpochHistory.
+func (bb *BulletinBoard) ReadCommitments(in *pb.EpochMsg, stream pb.BulletinBoardService_ReadCommitmentsServer) error {
 	bb.mutex.Lock()
-	*bb.proCnt = *bb.proCnt + 1
-	flag := (*bb.proCnt == bb.counter)
+	if in.GetCommittee() != bb.committee {
+		bb.mutex.Unlock()
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	if in.GetEpoch() < 0 || in.GetEpoch() >= int64(len(bb.history)) {
+		bb.mutex.Unlock()
+		return status.Errorf(codes.NotFound, "epoch %d has not started", in.GetEpoch())
+	}
+	state := bb.history[in.GetEpoch()].GetState()
 	bb.mutex.Unlock()
-	if flag {
-		*bb.proCnt = 0
-		bb.ClientStartVerifPhase2()
+	if state != pb.EpochStatusMsg_COMPLETED {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d is %s", in.GetEpoch(), strings.ToLower(state.String()))
+	}
+	content, err := bb.cmt1(in.GetEpoch(), in.GetSecret())
+	if err != nil {
+		return err
+	}
+	for _, msg := range content {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
 	}
-	return &pb.AckMsg{}, nil
+	return nil
 }
 
-func (bb *BulletinBoard) ReadPhase2(in *pb.EmptyMsg, stream pb.BulletinBoardService_ReadPhase2Server) error {
-	log.Print("[bulletinboard] is beting read in phase 2")
+func (bb *BulletinBoard) ReadPhase1(in *pb.EpochMsg, stream pb.BulletinBoardService_ReadPhase1Server) error {
+	epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 1, "rpc": "ReadPhase1"}).Debug("is being read")
+	// phase 1 reconstructs from the commitments written in phase 3 of the previous epoch
+	content, err := bb.readCmt1(in, in.GetEpoch()-1)
+	if err != nil {
+		return err
+	}
 	for i := 0; i < bb.counter; i++ {
-		if err := stream.Send(bb.proactivizationContent[i]); err != nil {
-			log.Fatalf("bulletinboard failed to read phase2: %v", err)
+		bb.countSent("ReadPhase1", "", content[i])
+		if err := stream.Send(content[i]); err != nil {
+			epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 1, "rpc": "ReadPhase1"}).WithError(err).Warn("failed to send")
+			return err
+		}
+	}
+	return nil
+}
+
+func (bb *BulletinBoard) WritePhase2(ctx context.Context, msg *pb.Cmt2Msg) (*pb.AckMsg, error) {
+	return bb.write(phaseProactivization, msg)
+}
+
+func (bb *BulletinBoard) ReadPhase2(in *pb.EpochMsg, stream pb.BulletinBoardService_ReadPhase2Server) error {
+	epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 2, "rpc": "ReadPhase2"}).Debug("is being read")
+	if err := bb.checkRead(in); err != nil {
+		return err
+	}
+	if err := bb.checkSecret(in.GetEpoch(), in.GetSecret()); err != nil {
+		return err
+	}
+	entries, err := bb.readPhase(in.GetEpoch(), phaseProactivization, in.GetSecret())
+	if err != nil {
+		return err
+	}
+	proofs, err := bb.prove(in.GetEpoch(), phaseProactivization, entries)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < bb.counter; i++ {
+		msg := &pb.Cmt2Msg{}
+		if err := proto.Unmarshal(entries[i].GetData(), msg); err != nil {
+			return status.Errorf(codes.DataLoss, "corrupted entry of [node %d]: %v", i+1, err)
+		}
+		msg.Inclusion = proofs[i]
+		bb.countSent("ReadPhase2", "", msg)
+		if err := stream.Send(msg); err != nil {
+			epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 2, "rpc": "ReadPhase2"}).WithError(err).Warn("failed to send")
 			return err
 		}
 	}
@@ -96,46 +296,239 @@ func (bb *BulletinBoard) ReadPhase2(in *pb.EmptyMsg, stream pb.BulletinBoardServ
 }
 
 func (bb *BulletinBoard) WritePhase3(ctx context.Context, msg *pb.Cmt1Msg) (*pb.AckMsg, error) {
-	*bb.totMsgSize = *bb.totMsgSize + proto.Size(msg)
-	log.Print("[bulletinboard] is being written in phase 3")
+	return bb.write(phaseShareDist, msg)
+}
+
+func (bb *BulletinBoard) ReadPhase3(in *pb.EpochMsg, stream pb.BulletinBoardService_ReadPhase3Server) error {
+	epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 3, "rpc": "ReadPhase3"}).Debug("is being read")
+	content, err := bb.readCmt1(in, in.GetEpoch())
+	if err != nil {
+		return err
+	}
+	for i := 0; i < bb.counter; i++ {
+		bb.countSent("ReadPhase3", "", content[i])
+		if err := stream.Send(content[i]); err != nil {
+			epochEntry(in.GetEpoch()).WithFields(logrus.Fields{"phase": 3, "rpc": "ReadPhase3"}).WithError(err).Warn("failed to send")
+			return err
+		}
+	}
+	return nil
+}
+
+// A message a node writes on the board under its signature
+type writtenMsg interface {
+	proto.Message
+	pb.Signed
+	pb.EpochScoped
+}
+
+// A commitment a node writes on the board
+type commitMsg interface {
+	writtenMsg
+	pb.Committed
+}
+
+// Store the commitment of a node in a phase of the current epoch
+func (bb *BulletinBoard) write(phase int32, msg commitMsg) (*pb.AckMsg, error) {
+	ack, err := bb.store(phase, msg)
+	bb.countWrite(phase, msg.GetIndex(), msg, err)
+	return ack, err
+}
+
+func (bb *BulletinBoard) store(phase int32, msg commitMsg) (*pb.AckMsg, error) {
 	index := msg.GetIndex()
-	bb.reconstructionContent[index-1] = msg
+	entry := epochEntry(msg.GetEpoch()).WithFields(logrus.Fields{"phase": phase, "rpc": fmt.Sprintf("WritePhase%d", phase), "peer": bb.peer(index)})
+	if err := bb.authorizeWrite(phase, msg); err != nil {
+		entry.WithError(err).Warn("reject write")
+		return nil, err
+	}
+	entry.Debug("is being written")
+	err := bb.backend.Append(&pb.EntryMsg{
+		Epoch:  msg.GetEpoch(),
+		Phase:  phase,
+		Index:  index,
+		Data:   msg.EntryData(),
+		Secret: msg.GetSecret(),
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return bb.rewrite(phase, msg)
+	}
+	if err != nil {
+		entry.WithError(err).Error("failed to store the write")
+		return nil, err
+	}
+	return bb.ack(), nil
+}
+
+// A node recovering from a crash writes its commitment again. An identical rewrite is acknowledged, the commitment on the board is kept either way.
+func (bb *BulletinBoard) rewrite(phase int32, msg writtenMsg) (*pb.AckMsg, error) {
+	entries, err := bb.backend.Read(msg.GetEpoch(), phase)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.GetIndex() != msg.GetIndex() || entry.GetSecret() != secretOf(msg) {
+			continue
+		}
+		old := proto.Clone(msg).(writtenMsg)
+		old.Reset()
+		if err := proto.Unmarshal(entry.GetData(), old); err != nil {
+			return nil, status.Errorf(codes.DataLoss, "corrupted entry of [node %d]: %v", msg.GetIndex(), err)
+		}
+		if !bytes.Equal(old.SigningBytes(), msg.SigningBytes()) {
+			return nil, status.Errorf(codes.AlreadyExists, "node %d already wrote a different commitment", msg.GetIndex())
+		}
+		return bb.ack(), nil
+	}
+	return nil, status.Errorf(codes.Internal, "the entry of node %d in phase %d is taken but missing", msg.GetIndex(), phase)
+}
+
+// The secret a message on the board is about, an evaluation of the beacon is about none
+func secretOf(msg writtenMsg) string {
+	if cmt, ok := msg.(pb.Committed); ok {
+		return cmt.GetSecret()
+	}
+	return ""
+}
+
+// Follow the entries appended to the board and start the verification of a phase once every node wrote its commitment for every secret
+func (bb *BulletinBoard) watch() {
+	entries, cancel := bb.backend.Subscribe(1)
+	defer cancel()
+	seen := make(map[slot]bool)
+	written := make(map[slot]int)
+	for entry := range entries {
+		key := slotOf(entry)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		// a phase is counted under its slot without a secret and an index
+		phase := slot{epoch: entry.GetEpoch(), phase: entry.GetPhase()}
+		written[phase]++
+		bb.mutex.Lock()
+		running := entry.GetEpoch() == *bb.epoch && bb.history[*bb.epoch].GetState() == pb.EpochStatusMsg_RUNNING
+		need := bb.counter * len(bb.history[*bb.epoch].HeldSecrets())
+		bb.mutex.Unlock()
+		if !running || written[phase] != need {
+			continue
+		}
+		switch entry.GetPhase() {
+		case phaseProactivization:
+			go bb.ClientStartVerifPhase2()
+		case phaseShareDist:
+			go bb.ClientStartVerifPhase3()
+		}
+	}
+}
+
+// Reads may address the current or any earlier epoch of this committee, but not one that has not started.
+func (bb *BulletinBoard) checkRead(in *pb.EpochMsg) error {
 	bb.mutex.Lock()
-	*bb.shaCnt = *bb.shaCnt + 1
-	flag := (*bb.shaCnt == bb.counter)
-	bb.mutex.Unlock()
-	if flag {
-		*bb.shaCnt = 0
-		bb.ClientStartVerifPhase3()
+	defer bb.mutex.Unlock()
+	if in.GetCommittee() != bb.committee {
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), bb.committee)
+	}
+	if in.GetEpoch() > *bb.epoch {
+		return status.Errorf(codes.Unavailable, "future epoch %d, current epoch is %d", in.GetEpoch(), *bb.epoch)
+	}
+	if in.GetEpoch() < 1 {
+		return status.Errorf(codes.InvalidArgument, "epoch %d has no protocol phases", in.GetEpoch())
 	}
-	return &pb.AckMsg{}, nil
+	return nil
 }
 
-func (bb *BulletinBoard) ReadPhase3(in *pb.EmptyMsg, stream pb.BulletinBoardService_ReadPhase3Server) error {
-	log.Print("[bulletinboard] is being read in phase 3")
+// Return the entries of a phase of an epoch for a secret indexed by label - 1, once every node has written
+func (bb *BulletinBoard) readPhase(epoch int64, phase int32, secret string) ([]*pb.EntryMsg, error) {
+	entries, err := bb.backend.Read(epoch, phase)
+	if err != nil {
+		return nil, err
+	}
+	content := make([]*pb.EntryMsg, bb.counter)
+	for _, entry := range entries {
+		if index := entry.GetIndex(); index >= 1 && int(index) <= bb.counter && entry.GetSecret() == secret {
+			content[index-1] = entry
+		}
+	}
 	for i := 0; i < bb.counter; i++ {
-		if err := stream.Send(bb.reconstructionContent[i]); err != nil {
-			log.Fatalf("bulletinboard failed to read phase2: %v", err)
-			return err
+		if content[i] == nil {
+			return nil, status.Errorf(codes.Unavailable, "phase %d of epoch %d is incomplete for %s", phase, epoch, pb.SecretName(secret))
 		}
 	}
-	return nil
+	return content, nil
+}
+
+// Return the complete phase 3 content for the secret of in that the given epoch ends with, with the proofs that it is in the log
+func (bb *BulletinBoard) readCmt1(in *pb.EpochMsg, epoch int64) ([]*pb.Cmt1Msg, error) {
+	if err := bb.checkRead(in); err != nil {
+		return nil, err
+	}
+	return bb.cmt1(epoch, in.GetSecret())
+}
+
+// The commitments to the polynomials of a secret at the end of an epoch. They are in the share distribution of the latest epoch up to it that wrote any for the secret, see sharedIn.
+func (bb *BulletinBoard) cmt1(epoch int64, secret string) ([]*pb.Cmt1Msg, error) {
+	epoch, err := bb.sharedIn(epoch, secret)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := bb.readPhase(epoch, phaseShareDist, secret)
+	if err != nil {
+		return nil, err
+	}
+	proofs, err := bb.prove(epoch, phaseShareDist, entries)
+	if err != nil {
+		return nil, err
+	}
+	content := make([]*pb.Cmt1Msg, bb.counter)
+	for i, entry := range entries {
+		content[i] = &pb.Cmt1Msg{}
+		if err := proto.Unmarshal(entry.GetData(), content[i]); err != nil {
+			return nil, status.Errorf(codes.DataLoss, "corrupted entry of [node %d]: %v", i+1, err)
+		}
+		content[i].Inclusion = proofs[i]
+	}
+	return content, nil
+}
+
+func (bb *BulletinBoard) epochMsg() *pb.EpochMsg {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	return &pb.EpochMsg{
+		Epoch:     *bb.epoch,
+		Committee: bb.committee,
+	}
+}
+
+func (bb *BulletinBoard) ack() *pb.AckMsg {
+	msg := bb.epochMsg()
+	return &pb.AckMsg{
+		Epoch:     msg.Epoch,
+		Committee: msg.Committee,
+	}
 }
 
-func (bb *BulletinBoard) Connect() {
+func (bb *BulletinBoard) Connect() error {
 	for i := 0; i < bb.counter; i++ {
-		nConn, err := grpc.Dial(bb.ipList[i], grpc.WithInsecure())
+		nConn, err := bb.transport.Dial(bb.ipList[i])
 		if err != nil {
-			log.Fatalf("bulletinboard did not connect: %v", err)
+			return errors.New(fmt.Sprintf("bulletinboard did not connect to node %d: %v", i+1, err))
 		}
+		bb.mutex.Lock()
 		bb.nConn[i] = nConn
-		bb.nClient[i] = pb.NewNodeServiceClient(nConn)
+		bb.nClient[i] = nConn.Node()
+		bb.mutex.Unlock()
 	}
+	return nil
 }
 
 func (bb *BulletinBoard) Disconnect() {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
 	for i := 0; i < bb.counter; i++ {
-		bb.nConn[i].Close()
+		if bb.nConn[i] != nil {
+			bb.nConn[i].Close()
+		}
 	}
 }
 
@@ -144,83 +537,142 @@ func (bb *BulletinBoard) Serve(aws bool) {
 	if aws {
 		port = "0.0.0.0:12001"
 	}
-	lis, err := net.Listen("tcp", port)
+	s, err := bb.transport.Listen(port)
 	if err != nil {
-		log.Fatalf("bulletinboard failed to listen %v", err)
+		logger.Fatalf("bulletinboard failed to listen %v", err)
 	}
-	s := grpc.NewServer()
-	pb.RegisterBulletinBoardServiceServer(s, bb)
-	reflection.Register(s)
-	log.Printf("bulletinboard serve on " + bb.bip)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("bulletinboard failed to serve %v", err)
+	s.RegisterBulletinBoard(bb)
+	s.RegisterAdmin(bb)
+	s.RegisterHealth(bb)
+	bb.mutex.Lock()
+	bb.server = s
+	bb.mutex.Unlock()
+	logger.Infof("serve on %s", bb.bip)
+	if err := bb.Connect(); err != nil {
+		logger.Fatal(err)
+	}
+	go bb.watch()
+	// a board restarted during an epoch starts it again, nodes that are in it already ignore the repeated start
+	bb.mutex.Lock()
+	current := bb.history[*bb.epoch]
+	bb.mutex.Unlock()
+	if current.GetState() == pb.EpochStatusMsg_RUNNING {
+		epochEntry(*bb.epoch).Info("resume epoch")
+		go bb.ClientStartPhase1()
+		go bb.expire(current)
+	}
+	if err := s.Serve(); err != nil {
+		logger.Fatalf("bulletinboard failed to serve %v", err)
 	}
 }
 
 func (bb *BulletinBoard) ClientStartPhase1() {
+	msg := bb.epochMsg()
 	if bb.nConn[0] == nil {
-		bb.Connect()
+		if err := bb.Connect(); err != nil {
+			epochEntry(msg.GetEpoch()).WithError(err).Error("cannot start phase 1")
+			bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_FAILED)
+			return
+		}
 	}
+	epochEntry(msg.GetEpoch()).WithField("phase", 1).Info("start phase 1")
 	var wg sync.WaitGroup
 	for i := 0; i < bb.counter; i++ {
-		log.Print("[bulletinboard] start phase 1")
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-			bb.nClient[i].StartPhase1(ctx, &pb.EmptyMsg{})
+			if err := bb.callNode(msg, "StartPhase1", i, bb.nClient[i].StartPhase1); err != nil {
+				bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_FAILED)
+			}
 		}(i)
 	}
 	wg.Wait()
 }
 
 func (bb *BulletinBoard) ClientStartVerifPhase2() {
+	bb.endStretch(timedProactivization)
+	bb.startStretch(timedVerification2)
+	msg := bb.epochMsg()
+	epochEntry(msg.GetEpoch()).WithField("phase", 2).Info("start verification")
 	var wg sync.WaitGroup
 	for i := 0; i < bb.counter; i++ {
-		log.Print("[bulletinboard] start verification in phase 2")
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-			bb.nClient[i].StartVerifPhase2(ctx, &pb.EmptyMsg{})
+			if err := bb.callNode(msg, "StartVerifPhase2", i, bb.nClient[i].StartVerifPhase2); err != nil {
+				if status.Code(err) == codes.Aborted {
+					bb.metrics.failures.Inc(timedVerification2)
+				}
+				bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_FAILED)
+			}
 		}(i)
 	}
 	wg.Wait()
+	bb.endStretch(timedVerification2)
+	bb.startStretch(timedShareDist)
 }
 
 func (bb *BulletinBoard) ClientStartVerifPhase3() {
+	bb.endStretch(timedShareDist)
+	bb.startStretch(timedVerification3)
+	msg := bb.epochMsg()
+	epochEntry(msg.GetEpoch()).WithField("phase", 3).Info("start verification")
 	var wg sync.WaitGroup
 	for i := 0; i < bb.counter; i++ {
-		log.Print("[bulletinboard] start verification in phase 3")
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-			bb.nClient[i].StartVerifPhase3(ctx, &pb.EmptyMsg{})
+			if err := bb.callNode(msg, "StartVerifPhase3", i, bb.nClient[i].StartVerifPhase3); err != nil {
+				if status.Code(err) == codes.Aborted {
+					bb.metrics.failures.Inc(timedVerification3)
+				}
+				bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_FAILED)
+			}
 		}(i)
 	}
 	wg.Wait()
-	*bb.proCnt = 0
-	*bb.shaCnt = 0
+	bb.endStretch(timedVerification3)
+	// every node has verified its new share once StartVerifPhase3 returns
+	bb.endEpoch(msg.GetEpoch(), pb.EpochStatusMsg_COMPLETED)
 	f, _ := os.OpenFile(bb.metadataPath+"/log0", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	defer f.Close()
-	fmt.Fprintf(f, "totMsgSize,%d\n", *bb.totMsgSize)
-	*bb.totMsgSize = 0
+	fmt.Fprintf(f, "totMsgSize,%d\n", bb.epochBytes())
+	if reporter, ok := bb.backend.(GasReporter); ok {
+		gas := reporter.EpochGas(msg.GetEpoch())
+		epochEntry(msg.GetEpoch()).Infof("used %d gas in %d transactions, %d for verification", gas.Total(), gas.Transactions, gas.Verification)
+		fmt.Fprintf(f, "gas,%d\n", gas.Total())
+		fmt.Fprintf(f, "verificationGas,%d\n", gas.Verification)
+	}
+}
+
+// Make an RPC that moves node i+1 on in the epoch of msg, retried while the node is unavailable
+func (bb *BulletinBoard) callNode(msg *pb.EpochMsg, rpc string, i int, call func(context.Context, *pb.EpochMsg, ...grpc.CallOption) (*pb.AckMsg, error)) error {
+	peer := fmt.Sprintf("node%d", i+1)
+	ctx, cancel := context.WithTimeout(bb.ctx, pb.CallTimeout)
+	defer cancel()
+	err := bb.traceCall(rpc, peer, func() error {
+		return pb.Retry(func() error {
+			bb.countSent(rpc, peer, msg)
+			_, err := call(ctx, msg)
+			return err
+		})
+	})
+	if err != nil {
+		nodeEntry(msg.GetEpoch(), rpc, peer).WithError(err).Error("node failed the call")
+	}
+	return err
 }
 
 func ReadIpList(metadataPath string) []string {
 	ipData, err := ioutil.ReadFile(metadataPath + "/ip_list")
 	if err != nil {
-		log.Fatalf("bulletinboard failed to read iplist: %v", err)
+		logger.Fatalf("bulletinboard failed to read iplist: %v", err)
 	}
 	return strings.Split(string(ipData), "\n")
 }
 
-// New returns a network node structure
-func New(degree int, counter int, metadataPath string) (BulletinBoard, error) {
+// New returns a network node structure whose content is kept by backend and that talks to the nodes over tr
+func New(degree int, counter int, metadataPath string, backend Backend, tr transport.Transport) (BulletinBoard, error) {
 	f, _ := os.Create(metadataPath + "/log0")
 	defer f.Close()
 	if counter < 0 {
@@ -237,42 +689,94 @@ func New(degree int, counter int, metadataPath string) (BulletinBoard, error) {
 	bip := ipRaw[0]
 	ipList := ipRaw[1 : counter+1]
 
-	proCnt := 0
-	shaCnt := 0
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return BulletinBoard{}, err
+	}
 
-	reconstructionContent := make([]*pb.Cmt1Msg, counter)
+	operator, err := identity.ReadOperatorPk(metadataPath)
+	if err != nil {
+		return BulletinBoard{}, err
+	}
+
+	committee := identity.CommitteeID(pks)
+	epoch := int64(0)
+	labels := make([]int, counter)
+	for i := range labels {
+		labels[i] = i + 1
+	}
+	lambda, err := tbls.Lagrange(labels)
+	if err != nil {
+		return BulletinBoard{}, err
+	}
+
+	// epoch 0 holds the genesis commitments derived from the fixed seed, a backend that outlives the board has them already
 	poly, err := polyring.NewRand(degree, fixedRandState, p)
 	if err != nil {
-		log.Fatal("Error initializing random poly")
+		logger.Fatal("Error initializing random poly")
 	}
 	c := dpc.NewG1()
 	dpc.Commit(c, poly)
-	cBytes := c.CompressedBytes()
-	for i := 0; i < counter; i++ {
-		msg := &pb.Cmt1Msg{
-			Index:   int32(i + 1),
-			Polycmt: cBytes,
+	if err := appendShared(backend, committee, counter, epoch, "", c.CompressedBytes()); err != nil {
+		return BulletinBoard{}, err
+	}
+	now := time.Now().UnixNano()
+	history := []*pb.EpochStatusMsg{{
+		Epoch:     epoch,
+		Committee: committee,
+		State:     pb.EpochStatusMsg_COMPLETED,
+		Start:     now,
+		End:       now,
+		Secrets:   []string{""},
+	}}
+	if recorder, ok := backend.(EpochRecorder); ok {
+		// carry on with the epochs the backend kept
+		if recorded := recorder.Epochs(); len(recorded) > 0 {
+			for i, msg := range recorded {
+				if msg.GetEpoch() != int64(i) || msg.GetCommittee() != committee {
+					return BulletinBoard{}, errors.New(fmt.Sprintf("stored epoch %d of committee %q does not follow the history of committee %q", msg.GetEpoch(), msg.GetCommittee(), committee))
+				}
+			}
+			history = recorded
+			epoch = recorded[len(recorded)-1].GetEpoch()
+			epochEntry(epoch).Info("carry on from the stored epochs")
+		} else if err := recorder.RecordEpoch(history[0]); err != nil {
+			return BulletinBoard{}, err
 		}
-		reconstructionContent[i] = msg
 	}
-	proactivizationContent := make([]*pb.Cmt2Msg, counter)
 
-	nConn := make([]*grpc.ClientConn, counter)
+	nConn := make([]transport.Conn, counter)
 	nClient := make([]pb.NodeServiceClient, counter)
 
-	totMsgSize := 0
-
+	health := make(map[string]*transport.Health)
+	for i := 1; i <= counter; i++ {
+		health[fmt.Sprintf("node%d", i)] = &transport.Health{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	draining := false
 	return BulletinBoard{
-		metadataPath:           metadataPath,
-		counter:                counter,
-		bip:                    bip,
-		ipList:                 ipList,
-		proCnt:                 &proCnt,
-		shaCnt:                 &shaCnt,
-		reconstructionContent:  reconstructionContent,
-		proactivizationContent: proactivizationContent,
-		nConn:                  nConn,
-		nClient:                nClient,
-		totMsgSize:             &totMsgSize,
+		metadataPath: metadataPath,
+		counter:      counter,
+		degree:       degree,
+		bip:          bip,
+		ipList:       ipList,
+		pks:          pks,
+		operator:     operator,
+		dpc:          &dpc,
+		lambda:       lambda,
+		epoch:        &epoch,
+		committee:    committee,
+		history:      history,
+		epochTimeout: DefaultEpochTimeout,
+		backend:      backend,
+		transport:    tr,
+		nConn:        nConn,
+		nClient:      nClient,
+		health:       health,
+		ctx:          ctx,
+		cancel:       cancel,
+		draining:     &draining,
+		metrics:      newBoardMetrics(counter, degree, committee, history),
+		steps:        trace.NewSteps(map[string]interface{}{"component": "bulletinboard"}),
 	}, nil
 }
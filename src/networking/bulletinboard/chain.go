@@ -0,0 +1,438 @@
+package bulletinboard
+
+import (
+	"crypto/ecdsa"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GasModel prices what the bulletinboard contract does. The defaults follow Ethereum since Istanbul.
+type GasModel struct {
+	// Paid by every transaction
+	Transaction uint64 `toml:"transaction"`
+	// Per byte of calldata, zero and non-zero
+	ZeroByte    uint64 `toml:"zero_byte"`
+	NonZeroByte uint64 `toml:"nonzero_byte"`
+	// Per 32-byte word written to contract storage
+	StorageWord uint64 `toml:"storage_word"`
+	// Per signature the contract checks
+	Signature uint64 `toml:"signature"`
+	// Per commitment the contract verifies when the last write of a phase closes it
+	Verification uint64 `toml:"verification"`
+}
+
+// ChainConfig sets up the simulated chain
+type ChainConfig struct {
+	Gas GasModel `toml:"gas"`
+	// Time between two blocks
+	BlockInterval duration `toml:"block_interval"`
+	// Most gas the transactions of one block may use
+	BlockGasLimit uint64 `toml:"block_gas_limit"`
+}
+
+// A time.Duration written like "2s" in the config file
+type duration struct {
+	time.Duration
+}
+
+func (d *duration) UnmarshalText(text []byte) error {
+	var err error
+	d.Duration, err = time.ParseDuration(string(text))
+	return err
+}
+
+// DefaultChainConfig returns Ethereum-like prices with a block every second
+func DefaultChainConfig() ChainConfig {
+	return ChainConfig{
+		Gas: GasModel{
+			Transaction: 21000,
+			ZeroByte:    4,
+			NonZeroByte: 16,
+			StorageWord: 20000,
+			// ecrecover
+			Signature: 3000,
+			// a multiplication and an addition on the alt_bn128 precompiles
+			Verification: 6150,
+		},
+		BlockInterval: duration{time.Second},
+		BlockGasLimit: 30000000,
+	}
+}
+
+// ReadChainConfig reads a TOML config file, anything it leaves out keeps its default
+func ReadChainConfig(path string) (ChainConfig, error) {
+	config := DefaultChainConfig()
+	if _, err := toml.DecodeFile(path, &config); err != nil {
+		return ChainConfig{}, err
+	}
+	return config, nil
+}
+
+// EpochGas is the gas the transactions of an epoch used
+type EpochGas struct {
+	// Transactions mined, the reverted ones included
+	Transactions int
+	Reverted     int
+	// Gas of the writes, and of the verifications that closed a phase
+	Write        uint64
+	Verification uint64
+}
+
+func (g EpochGas) Total() uint64 {
+	return g.Write + g.Verification
+}
+
+// GasReporter is a backend that charges gas
+type GasReporter interface {
+	EpochGas(epoch int64) EpochGas
+}
+
+// A write waiting to be mined
+type transaction struct {
+	entry *pb.EntryMsg
+	// Receives the outcome once the transaction is in a block
+	done chan error
+}
+
+// The simulated chain backend. A contract keeps the entries and the status of the epochs: while an epoch runs it only takes a write signed by the node it is written for, phase 2 of a secret after the phase 3 it was last shared in, phase 3 after phase 2, the beacon of an epoch after its phase 3 of the beacon secret, and one write per node and secret in each phase.
+// Before an epoch that runs no phases is recorded, genesis included, it takes the unsigned commitments the operator deals in phase 3. A failed epoch leaves every secret where it was shared before.
+// Writes are mined in blocks, Append answers once the block of its transaction is out. Reads and subscriptions are free, like calls and events.
+type chain struct {
+	config  ChainConfig
+	counter int
+	pks     []*ecdsa.PublicKey
+	// Contract storage
+	store *memory
+
+	mutex   sync.Mutex
+	pending []*transaction
+	// Writes on the contract per phase of an epoch and secret, under the slot without an index
+	written map[slot]int
+	// Status of every epoch recorded, and the epoch each secret was last shared in, whose phase 3 its next handoff starts from
+	epochs []*pb.EpochStatusMsg
+	held   map[string]int64
+	gas    map[int64]*EpochGas
+	blocks int64
+	closed chan struct{}
+}
+
+// NewChain returns a backend that keeps the bulletinboard on a simulated chain, for a committee of counter nodes whose keys are in metadataPath
+func NewChain(config ChainConfig, counter int, metadataPath string) (Backend, error) {
+	if config.BlockInterval.Duration <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "block interval must be positive, got %v", config.BlockInterval.Duration)
+	}
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return nil, err
+	}
+	c := &chain{
+		config:  config,
+		counter: counter,
+		pks:     pks,
+		store:   newMemory(),
+		written: make(map[slot]int),
+		held:    make(map[string]int64),
+		gas:     make(map[int64]*EpochGas),
+		closed:  make(chan struct{}),
+	}
+	go c.mine()
+	return c, nil
+}
+
+func (c *chain) Append(entry *pb.EntryMsg) error {
+	tx := &transaction{
+		entry: proto.Clone(entry).(*pb.EntryMsg),
+		done:  make(chan error, 1),
+	}
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+		c.mutex.Unlock()
+		return status.Error(codes.Unavailable, "chain is closed")
+	default:
+	}
+	if entry.GetEpoch() == 0 {
+		// genesis goes in with the deployment of the contract instead of a block of its own
+		defer c.mutex.Unlock()
+		_, err := c.include(tx)
+		return err
+	}
+	c.pending = append(c.pending, tx)
+	c.mutex.Unlock()
+	return <-tx.done
+}
+
+func (c *chain) Read(epoch int64, phase int32) ([]*pb.EntryMsg, error) {
+	return c.store.Read(epoch, phase)
+}
+
+func (c *chain) Subscribe(epoch int64) (<-chan *pb.EntryMsg, func()) {
+	return c.store.Subscribe(epoch)
+}
+
+func (c *chain) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	select {
+	case <-c.closed:
+		return nil
+	default:
+	}
+	close(c.closed)
+	for _, tx := range c.pending {
+		tx.done <- status.Error(codes.Unavailable, "chain is closed")
+	}
+	c.pending = nil
+	return c.store.Close()
+}
+
+// RecordEpoch runs on the contract for free, as the transaction of the operator that opens or closes an epoch
+func (c *chain) RecordEpoch(msg *pb.EpochStatusMsg) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	epoch := msg.GetEpoch()
+	last := len(c.epochs) - 1
+	switch {
+	case last >= 0 && epoch == int64(last) && c.epochs[last].GetState() == pb.EpochStatusMsg_RUNNING:
+		c.epochs[last] = proto.Clone(msg).(*pb.EpochStatusMsg)
+	case epoch == int64(last+1) && (last < 0 || c.epochs[last].GetState() != pb.EpochStatusMsg_RUNNING):
+		c.epochs = append(c.epochs, proto.Clone(msg).(*pb.EpochStatusMsg))
+	default:
+		return status.Errorf(codes.FailedPrecondition, "epoch %d cannot be recorded after epoch %d", epoch, last)
+	}
+	if msg.GetState() != pb.EpochStatusMsg_COMPLETED {
+		return nil
+	}
+	held := make(map[string]int64)
+	for _, id := range msg.HeldSecrets() {
+		shared, ok := c.held[id]
+		if !ok || !msg.GetDeleted() && (!msg.GetDealt() || id == msg.GetSecret()) {
+			shared = epoch
+		}
+		held[id] = shared
+	}
+	c.held = held
+	return nil
+}
+
+func (c *chain) Epochs() []*pb.EpochStatusMsg {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	epochs := make([]*pb.EpochStatusMsg, len(c.epochs))
+	for i, msg := range c.epochs {
+		epochs[i] = proto.Clone(msg).(*pb.EpochStatusMsg)
+	}
+	return epochs
+}
+
+func (c *chain) EpochGas(epoch int64) EpochGas {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if gas, ok := c.gas[epoch]; ok {
+		return *gas
+	}
+	return EpochGas{}
+}
+
+// Produce a block every interval out of the pending transactions, in the order they came in
+func (c *chain) mine() {
+	ticker := time.NewTicker(c.config.BlockInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+		}
+		c.mutex.Lock()
+		used := uint64(0)
+		count := 0
+		for len(c.pending) > 0 {
+			tx := c.pending[0]
+			write, verification := c.cost(tx.entry)
+			if count > 0 && used+write+verification > c.config.BlockGasLimit {
+				break
+			}
+			c.pending = c.pending[1:]
+			count++
+			charged, err := c.include(tx)
+			used += charged
+			tx.done <- err
+		}
+		if count > 0 {
+			c.blocks++
+			logger.WithField("backend", "chain").Debugf("block %d: %d transactions, %d gas", c.blocks, count, used)
+		}
+		c.mutex.Unlock()
+	}
+}
+
+// Run a transaction and charge its gas to its epoch, returns the gas charged. The caller holds c.mutex.
+func (c *chain) include(tx *transaction) (uint64, error) {
+	write, verification := c.cost(tx.entry)
+	err := c.execute(tx.entry, write, verification)
+	if status.Code(err) == codes.ResourceExhausted {
+		// never fits a block, nothing is charged
+		return 0, err
+	}
+	gas := c.epochGas(tx.entry.GetEpoch())
+	gas.Transactions++
+	if err != nil {
+		// a reverted transaction pays for its calldata but changes nothing
+		gas.Reverted++
+		write, verification = c.config.Gas.Transaction+c.calldata(tx.entry), 0
+	}
+	gas.Write += write
+	gas.Verification += verification
+	return write + verification, err
+}
+
+// Run the contract on a write. The caller holds c.mutex.
+func (c *chain) execute(entry *pb.EntryMsg, write uint64, verification uint64) error {
+	if write+verification > c.config.BlockGasLimit {
+		return status.Errorf(codes.ResourceExhausted, "write needs %d gas, more than a block holds", write+verification)
+	}
+	if err := c.authorize(entry); err != nil {
+		return err
+	}
+	if err := c.store.Append(entry); err != nil {
+		return err
+	}
+	c.written[phaseOf(entry)]++
+	return nil
+}
+
+// The contract rules on who may write what, in which order. The caller holds c.mutex.
+func (c *chain) authorize(entry *pb.EntryMsg) error {
+	epoch := entry.GetEpoch()
+	phase := entry.GetPhase()
+	if phase != phaseProactivization && phase != phaseShareDist && phase != phaseBeacon {
+		return status.Errorf(codes.InvalidArgument, "no entries in phase %d", phase)
+	}
+	if index := entry.GetIndex(); index < 1 || int(index) > c.counter {
+		return status.Errorf(codes.PermissionDenied, "node %d is not in the committee", index)
+	}
+	dealing := c.dealing(entry)
+	if !dealing {
+		if err := c.open(entry); err != nil {
+			return err
+		}
+	}
+	var msg writtenMsg = &pb.Cmt2Msg{}
+	switch phase {
+	case phaseShareDist:
+		msg = &pb.Cmt1Msg{}
+	case phaseBeacon:
+		msg = &pb.BeaconShareMsg{}
+	}
+	if err := proto.Unmarshal(entry.GetData(), msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "cannot decode the commitment: %v", err)
+	}
+	if msg.GetIndex() != entry.GetIndex() || msg.GetEpoch() != epoch {
+		return status.Error(codes.PermissionDenied, "commitment does not belong to the entry")
+	}
+	if cmt, ok := msg.(pb.Committed); ok && cmt.GetSecret() != entry.GetSecret() {
+		return status.Error(codes.PermissionDenied, "commitment does not belong to the entry")
+	}
+	if dealing {
+		// the operator deals without a key of the committee
+		return nil
+	}
+	return pb.VerifySigned(c.pks, msg)
+}
+
+// Whether the entry is a commitment the operator deals, before it records the epoch it deals in. The caller holds c.mutex.
+func (c *chain) dealing(entry *pb.EntryMsg) bool {
+	last := len(c.epochs) - 1
+	return entry.GetPhase() == phaseShareDist && entry.GetEpoch() == int64(last+1) &&
+		(last < 0 || c.epochs[last].GetState() != pb.EpochStatusMsg_RUNNING)
+}
+
+// Whether the phase of the entry is open for the nodes to write in. The caller holds c.mutex.
+func (c *chain) open(entry *pb.EntryMsg) error {
+	epoch := entry.GetEpoch()
+	phase := entry.GetPhase()
+	if epoch >= int64(len(c.epochs)) {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d has not started", epoch)
+	}
+	msg := c.epochs[epoch]
+	if epoch == 0 {
+		return status.Error(codes.PermissionDenied, "genesis is closed")
+	}
+	if msg.GetDealt() || msg.GetDeleted() {
+		return status.Errorf(codes.PermissionDenied, "epoch %d runs no phases", epoch)
+	}
+	if phase != phaseBeacon && msg.GetState() != pb.EpochStatusMsg_RUNNING {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d is %s", epoch, strings.ToLower(msg.GetState().String()))
+	}
+	var previous slot
+	switch phase {
+	case phaseProactivization:
+		shared, ok := c.held[entry.GetSecret()]
+		if !ok {
+			return status.Errorf(codes.FailedPrecondition, "the committee holds no %s", pb.SecretName(entry.GetSecret()))
+		}
+		previous = slot{epoch: shared, phase: phaseShareDist, secret: entry.GetSecret()}
+	case phaseShareDist:
+		previous = slot{epoch: epoch, phase: phaseProactivization, secret: entry.GetSecret()}
+	case phaseBeacon:
+		previous = slot{epoch: epoch, phase: phaseShareDist, secret: pb.BeaconSecret}
+	}
+	if c.written[previous] < c.counter {
+		return status.Errorf(codes.FailedPrecondition, "phase %d of epoch %d is not open", phase, epoch)
+	}
+	return nil
+}
+
+// Gas of a write, and of the verification it triggers if it closes its phase. The caller holds c.mutex.
+func (c *chain) cost(entry *pb.EntryMsg) (uint64, uint64) {
+	gas := c.config.Gas
+	write := gas.Transaction + c.calldata(entry)
+	write += gas.StorageWord * uint64((len(entry.GetData())+31)/32+1)
+	dealing := c.dealing(entry)
+	if !dealing {
+		write += gas.Signature
+	}
+	verification := uint64(0)
+	// the beacon is checked off the chain, by the bulletinboard in front of it
+	if !dealing && entry.GetPhase() != phaseBeacon && c.written[phaseOf(entry)] == c.counter-1 {
+		verification = gas.Verification * uint64(c.counter)
+	}
+	return write, verification
+}
+
+func (c *chain) calldata(entry *pb.EntryMsg) uint64 {
+	data, _ := proto.Marshal(entry)
+	gas := uint64(0)
+	for _, b := range data {
+		if b == 0 {
+			gas += c.config.Gas.ZeroByte
+		} else {
+			gas += c.config.Gas.NonZeroByte
+		}
+	}
+	return gas
+}
+
+// The caller holds c.mutex
+func (c *chain) epochGas(epoch int64) *EpochGas {
+	gas, ok := c.gas[epoch]
+	if !ok {
+		gas = &EpochGas{}
+		c.gas[epoch] = gas
+	}
+	return gas
+}
+
+// A phase of an epoch for a secret, as the slot without an index
+func phaseOf(entry *pb.EntryMsg) slot {
+	return slot{epoch: entry.GetEpoch(), phase: entry.GetPhase(), secret: entry.GetSecret()}
+}
@@ -0,0 +1,432 @@
+package bulletinboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Small prices that tell the parts of a charge apart
+var testGas = GasModel{
+	Transaction:  1000,
+	ZeroByte:     1,
+	NonZeroByte:  2,
+	StorageWord:  100,
+	Signature:    10,
+	Verification: 7,
+}
+
+// A chain of three nodes with fast blocks, and the keys the nodes sign their writes with
+type testChain struct {
+	*chain
+	ids []*identity.Identity
+	dir string
+}
+
+func newTestChain(t *testing.T, blockGasLimit uint64) *testChain {
+	dir, err := ioutil.TempDir("", "chain")
+	assert.Nil(t, err)
+	assert.Nil(t, identity.GenerateAll(3, dir))
+	config := ChainConfig{Gas: testGas, BlockInterval: duration{5 * time.Millisecond}, BlockGasLimit: blockGasLimit}
+	backend, err := NewChain(config, 3, dir)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	c := &testChain{chain: backend.(*chain), dir: dir}
+	for label := 1; label <= 3; label++ {
+		id, err := identity.Load(identity.KeyPath(dir, label))
+		assert.Nil(t, err)
+		c.ids = append(c.ids, id)
+	}
+	return c
+}
+
+func (c *testChain) close() {
+	c.Close()
+	os.RemoveAll(c.dir)
+}
+
+// The write of node index in a phase of an epoch, signed by node signer
+func (c *testChain) write(epoch int64, phase int32, index int32, signer int32) *pb.EntryMsg {
+	return c.writeFor("", epoch, phase, index, signer)
+}
+
+// The write of node index for a secret in a phase of an epoch, signed by node signer, or unsigned as the operator deals it for signer 0
+func (c *testChain) writeFor(secret string, epoch int64, phase int32, index int32, signer int32) *pb.EntryMsg {
+	var msg interface {
+		proto.Message
+		pb.Signed
+	}
+	switch phase {
+	case phaseProactivization:
+		cmt := &pb.Cmt2Msg{Index: index, Epoch: epoch, Secret: secret, Polycmt: []byte{0, 1, 2, byte(index)}}
+		if signer > 0 {
+			cmt.Signature = pb.Sign(c.ids[signer-1], cmt)
+		}
+		msg = cmt
+	case phaseShareDist:
+		cmt := &pb.Cmt1Msg{Index: index, Epoch: epoch, Secret: secret, Polycmt: []byte{3, 0, 4, byte(index)}}
+		if signer > 0 {
+			cmt.Signature = pb.Sign(c.ids[signer-1], cmt)
+		}
+		msg = cmt
+	default:
+		share := &pb.BeaconShareMsg{Index: index, Epoch: epoch}
+		share.Signature = pb.Sign(c.ids[signer-1], share)
+		msg = share
+	}
+	data, _ := proto.Marshal(msg)
+	return &pb.EntryMsg{Epoch: epoch, Phase: phase, Index: index, Secret: secret, Data: data}
+}
+
+// Write every node in a phase and return the gas the writes should use. A signature differs in length from one signing to the next, so the gas is worked out from the entries written.
+func (c *testChain) writeAll(t *testing.T, epoch int64, phase int32) uint64 {
+	return c.writeAllFor(t, "", epoch, phase)
+}
+
+func (c *testChain) writeAllFor(t *testing.T, secret string, epoch int64, phase int32) uint64 {
+	gas := uint64(0)
+	for i := int32(1); i <= 3; i++ {
+		entry := c.writeFor(secret, epoch, phase, i, i)
+		gas += writeGas(entry) + testGas.Signature
+		assert.Nil(t, c.Append(entry), "epoch %d phase %d node %d", epoch, phase, i)
+	}
+	return gas
+}
+
+// Deal the commitments of a secret in an epoch without recording it, and return the gas the writes should use
+func (c *testChain) deal(t *testing.T, epoch int64, secret string) uint64 {
+	gas := uint64(0)
+	for i := int32(1); i <= 3; i++ {
+		entry := c.writeFor(secret, epoch, phaseShareDist, i, 0)
+		gas += writeGas(entry)
+		assert.Nil(t, c.Append(entry), "deal in epoch %d node %d", epoch, i)
+	}
+	return gas
+}
+
+// Deal and record genesis, and return the gas of its writes
+func (c *testChain) genesis(t *testing.T) uint64 {
+	gas := c.deal(t, 0, "")
+	c.record(t, 0, pb.EpochStatusMsg_COMPLETED)
+	return gas
+}
+
+func (c *testChain) record(t *testing.T, epoch int64, state pb.EpochStatusMsg_State, secrets ...string) {
+	assert.Nil(t, c.RecordEpoch(&pb.EpochStatusMsg{Epoch: epoch, State: state, Secrets: secrets}))
+}
+
+// Gas of a write of the entry without its signature check, worked out apart from the chain
+func writeGas(entry *pb.EntryMsg) uint64 {
+	return testGas.Transaction + calldataGas(entry) + testGas.StorageWord*uint64((len(entry.GetData())+31)/32+1)
+}
+
+func calldataGas(entry *pb.EntryMsg) uint64 {
+	data, _ := proto.Marshal(entry)
+	gas := uint64(0)
+	for _, b := range data {
+		if b == 0 {
+			gas += testGas.ZeroByte
+		} else {
+			gas += testGas.NonZeroByte
+		}
+	}
+	return gas
+}
+
+func TestChainChargesWritesAndVerification(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+
+	// genesis is deployed with the contract, it is dealt without signatures and closes no phase
+	genesis := c.genesis(t)
+	assert.Equal(t, EpochGas{Transactions: 3, Write: genesis}, c.EpochGas(0))
+	c.mutex.Lock()
+	assert.Equal(t, int64(0), c.blocks)
+	c.mutex.Unlock()
+
+	// the third write of a phase closes it and pays for verifying the commitment of every node
+	c.record(t, 1, pb.EpochStatusMsg_RUNNING)
+	write := c.writeAll(t, 1, phaseProactivization)
+	assert.Equal(t, EpochGas{Transactions: 3, Write: write, Verification: 3 * testGas.Verification}, c.EpochGas(1))
+
+	write += c.writeAll(t, 1, phaseShareDist)
+	gas := c.EpochGas(1)
+	assert.Equal(t, EpochGas{Transactions: 6, Write: write, Verification: 6 * testGas.Verification}, gas)
+	assert.Equal(t, write+6*testGas.Verification, gas.Total())
+
+	// every epoch keeps its own totals
+	c.record(t, 1, pb.EpochStatusMsg_COMPLETED)
+	c.record(t, 2, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 2, phaseProactivization)
+	assert.Equal(t, 3, c.EpochGas(2).Transactions)
+	assert.Equal(t, 3*testGas.Verification, c.EpochGas(2).Verification)
+	assert.Equal(t, 6, c.EpochGas(1).Transactions)
+	assert.Equal(t, EpochGas{Transactions: 3, Write: genesis}, c.EpochGas(0))
+	assert.Equal(t, EpochGas{}, c.EpochGas(3))
+
+	entries, err := c.Read(1, phaseShareDist)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestChainRevertsDuplicates(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+	c.genesis(t)
+	c.deal(t, 1, "signing")
+	assert.Nil(t, c.RecordEpoch(&pb.EpochStatusMsg{
+		Epoch:   1,
+		State:   pb.EpochStatusMsg_COMPLETED,
+		Dealt:   true,
+		Secret:  "signing",
+		Secrets: []string{"", "signing"},
+	}))
+	c.record(t, 2, pb.EpochStatusMsg_RUNNING, "", "signing")
+
+	first := c.write(2, phaseProactivization, 1, 1)
+	assert.Nil(t, c.Append(first))
+	// a second write for the same slot reverts, it pays for its calldata only and the first stays
+	cmt := &pb.Cmt2Msg{Index: 1, Epoch: 2, Polycmt: []byte{9}}
+	cmt.Signature = pb.Sign(c.ids[0], cmt)
+	second := &pb.EntryMsg{Epoch: 2, Phase: phaseProactivization, Index: 1}
+	second.Data, _ = proto.Marshal(cmt)
+	assert.Equal(t, ErrTaken, c.Append(second))
+	assert.Equal(t, EpochGas{
+		Transactions: 2,
+		Reverted:     1,
+		Write:        writeGas(first) + testGas.Signature + testGas.Transaction + calldataGas(second),
+	}, c.EpochGas(2))
+	entries, err := c.Read(2, phaseProactivization)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, first.GetData(), entries[0].GetData())
+	}
+
+	// the same node may write once for each secret
+	assert.Nil(t, c.Append(c.writeFor("signing", 2, phaseProactivization, 1, 1)))
+	assert.Equal(t, 1, c.EpochGas(2).Reverted)
+}
+
+func TestChainPhaseOrder(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+	c.genesis(t)
+	// genesis is closed once it is recorded
+	assert.Equal(t, codes.PermissionDenied, status.Code(c.Append(c.write(0, phaseShareDist, 1, 1))))
+	// no node writes before its epoch starts
+	early := c.write(1, phaseProactivization, 1, 1)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.Append(early)))
+	c.record(t, 1, pb.EpochStatusMsg_RUNNING)
+
+	refused := []struct {
+		entry *pb.EntryMsg
+		code  codes.Code
+	}{
+		// phase 3 opens once every node wrote phase 2
+		{c.write(1, phaseShareDist, 1, 1), codes.FailedPrecondition},
+		{c.write(1, phaseBeacon, 1, 1), codes.FailedPrecondition},
+		// the next epoch has not started, and nothing is dealt while an epoch runs
+		{c.write(2, phaseProactivization, 1, 1), codes.FailedPrecondition},
+		{c.writeFor("", 2, phaseShareDist, 1, 0), codes.FailedPrecondition},
+		// a secret the committee does not hold
+		{c.writeFor("signing", 1, phaseProactivization, 1, 1), codes.FailedPrecondition},
+		// a write signed by another node, unsigned, or for a node outside the committee
+		{c.write(1, phaseProactivization, 2, 1), codes.Unauthenticated},
+		{c.writeFor("", 1, phaseProactivization, 1, 0), codes.Unauthenticated},
+		{c.write(1, phaseProactivization, 4, 1), codes.PermissionDenied},
+		{&pb.EntryMsg{Epoch: 1, Phase: 1, Index: 1}, codes.InvalidArgument},
+	}
+	charged := testGas.Transaction + calldataGas(early)
+	for i, r := range refused {
+		assert.Equal(t, r.code, status.Code(c.Append(r.entry)), "write %d", i)
+		charged += testGas.Transaction + calldataGas(r.entry)
+	}
+	reverted := c.EpochGas(1)
+	assert.Equal(t, 8, reverted.Reverted)
+	assert.Equal(t, 2, c.EpochGas(2).Reverted)
+	assert.Equal(t, charged, reverted.Write+c.EpochGas(2).Write)
+	assert.Zero(t, reverted.Verification)
+
+	// a commitment lifted from another entry does not fit this one
+	lifted := c.write(1, phaseProactivization, 1, 1)
+	lifted.Index = 2
+	assert.Equal(t, codes.PermissionDenied, status.Code(c.Append(lifted)))
+
+	c.writeAll(t, 1, phaseProactivization)
+	c.writeAll(t, 1, phaseShareDist)
+	c.record(t, 1, pb.EpochStatusMsg_COMPLETED)
+	// an epoch takes no commitments once it is over
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.Append(c.write(1, phaseShareDist, 1, 1))))
+	c.record(t, 2, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 2, phaseProactivization)
+}
+
+// A secret the operator deals is handed off from the dealt commitments, the others from the epoch before the dealing
+func TestChainDealtSecret(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+	c.genesis(t)
+	c.record(t, 1, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 1, phaseProactivization)
+	c.writeAll(t, 1, phaseShareDist)
+	c.record(t, 1, pb.EpochStatusMsg_COMPLETED)
+
+	// the dealt commitments are not signed by the committee and close no phase
+	dealt := c.deal(t, 2, "signing")
+	assert.Equal(t, EpochGas{Transactions: 3, Write: dealt}, c.EpochGas(2))
+	assert.Nil(t, c.RecordEpoch(&pb.EpochStatusMsg{
+		Epoch:   2,
+		State:   pb.EpochStatusMsg_COMPLETED,
+		Dealt:   true,
+		Secret:  "signing",
+		Secrets: []string{"", "signing"},
+	}))
+	// an epoch in which the operator dealt runs no phases
+	assert.Equal(t, codes.PermissionDenied, status.Code(c.Append(c.writeFor("signing", 2, phaseShareDist, 1, 0))))
+	assert.Equal(t, codes.PermissionDenied, status.Code(c.Append(c.write(2, phaseProactivization, 1, 1))))
+
+	c.record(t, 3, pb.EpochStatusMsg_RUNNING, "", "signing")
+	c.writeAll(t, 3, phaseProactivization)
+	c.writeAllFor(t, "signing", 3, phaseProactivization)
+	c.writeAllFor(t, "signing", 3, phaseShareDist)
+	c.writeAll(t, 3, phaseShareDist)
+	c.record(t, 3, pb.EpochStatusMsg_COMPLETED, "", "signing")
+
+	// a deleted secret is no longer handed off
+	assert.Nil(t, c.RecordEpoch(&pb.EpochStatusMsg{
+		Epoch:   4,
+		State:   pb.EpochStatusMsg_COMPLETED,
+		Deleted: true,
+		Secret:  "signing",
+		Secrets: []string{""},
+	}))
+	c.record(t, 5, pb.EpochStatusMsg_RUNNING)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.Append(c.writeFor("signing", 5, phaseProactivization, 1, 1))))
+	c.writeAll(t, 5, phaseProactivization)
+}
+
+// The beacon of an epoch follows the handoff of the beacon secret, and is checked by the bulletinboard rather than the contract
+func TestChainBeacon(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+	c.genesis(t)
+	c.deal(t, 1, pb.BeaconSecret)
+	assert.Nil(t, c.RecordEpoch(&pb.EpochStatusMsg{
+		Epoch:   1,
+		State:   pb.EpochStatusMsg_COMPLETED,
+		Dealt:   true,
+		Secret:  pb.BeaconSecret,
+		Secrets: []string{"", pb.BeaconSecret},
+	}))
+	// an epoch in which the operator dealt has no beacon
+	assert.Equal(t, codes.PermissionDenied, status.Code(c.Append(c.write(1, phaseBeacon, 1, 1))))
+
+	c.record(t, 2, pb.EpochStatusMsg_RUNNING, "", pb.BeaconSecret)
+	c.writeAll(t, 2, phaseProactivization)
+	c.writeAll(t, 2, phaseShareDist)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.Append(c.write(2, phaseBeacon, 1, 1))))
+	c.writeAllFor(t, pb.BeaconSecret, 2, phaseProactivization)
+	c.writeAllFor(t, pb.BeaconSecret, 2, phaseShareDist)
+	c.record(t, 2, pb.EpochStatusMsg_COMPLETED, "", pb.BeaconSecret)
+
+	before := c.EpochGas(2)
+	beacon := c.write(2, phaseBeacon, 1, 1)
+	assert.Nil(t, c.Append(beacon))
+	gas := c.EpochGas(2)
+	assert.Equal(t, before.Write+writeGas(beacon)+testGas.Signature, gas.Write)
+	assert.Equal(t, before.Verification, gas.Verification)
+}
+
+// An epoch that fails partway leaves the secrets where the epoch before shared them
+func TestChainFailedEpoch(t *testing.T) {
+	c := newTestChain(t, 30000000)
+	defer c.close()
+	c.genesis(t)
+	c.record(t, 1, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 1, phaseProactivization)
+	c.writeAll(t, 1, phaseShareDist)
+	c.record(t, 1, pb.EpochStatusMsg_COMPLETED)
+
+	c.record(t, 2, pb.EpochStatusMsg_RUNNING)
+	assert.Nil(t, c.Append(c.write(2, phaseProactivization, 1, 1)))
+	assert.Nil(t, c.Append(c.write(2, phaseProactivization, 2, 2)))
+	// an epoch starts once the one before it is over
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.RecordEpoch(&pb.EpochStatusMsg{Epoch: 3})))
+	c.record(t, 2, pb.EpochStatusMsg_FAILED)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.Append(c.write(2, phaseProactivization, 3, 3))))
+
+	c.record(t, 3, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 3, phaseProactivization)
+	c.writeAll(t, 3, phaseShareDist)
+	c.record(t, 3, pb.EpochStatusMsg_COMPLETED)
+	c.record(t, 4, pb.EpochStatusMsg_RUNNING)
+	c.writeAll(t, 4, phaseProactivization)
+
+	epochs := c.Epochs()
+	if assert.Len(t, epochs, 5) {
+		assert.Equal(t, pb.EpochStatusMsg_FAILED, epochs[2].GetState())
+	}
+	// epochs are recorded in order
+	assert.Equal(t, codes.FailedPrecondition, status.Code(c.RecordEpoch(&pb.EpochStatusMsg{Epoch: 6})))
+}
+
+func TestChainBlockLimit(t *testing.T) {
+	sample := newTestChain(t, 30000000)
+	single := writeGas(sample.write(1, phaseProactivization, 1, 1)) + testGas.Signature + 3*testGas.Verification
+	sample.close()
+
+	// a block holds one write, with room for signatures of another length
+	c := newTestChain(t, single+200)
+	defer c.close()
+	c.genesis(t)
+	c.record(t, 1, pb.EpochStatusMsg_RUNNING)
+	var wg sync.WaitGroup
+	for i := int32(1); i <= 3; i++ {
+		wg.Add(1)
+		go func(i int32) {
+			defer wg.Done()
+			assert.Nil(t, c.Append(c.write(1, phaseProactivization, i, i)))
+		}(i)
+	}
+	wg.Wait()
+	c.mutex.Lock()
+	assert.Equal(t, int64(3), c.blocks)
+	c.mutex.Unlock()
+
+	// a write that never fits a block is refused and not charged
+	big := c.write(1, phaseShareDist, 1, 1)
+	big.Data = append(big.Data, make([]byte, 4096)...)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(c.Append(big)))
+	assert.Equal(t, 3, c.EpochGas(1).Transactions)
+	assert.Zero(t, c.EpochGas(1).Reverted)
+}
+
+func TestChainConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "chain.toml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("block_interval = \"250ms\"\n[gas]\nsignature = 42\n"), 0644))
+
+	config, err := ReadChainConfig(path)
+	assert.Nil(t, err)
+	want := DefaultChainConfig()
+	want.BlockInterval = duration{250 * time.Millisecond}
+	want.Gas.Signature = 42
+	assert.Equal(t, want, config)
+
+	config.BlockInterval = duration{}
+	_, err = NewChain(config, 3, dir)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,186 @@
+package bulletinboard
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deal records a secret the operator dealt under an ID as an epoch that runs no phases. Like genesis, its share distribution holds one commitment, unsigned, under the index of every node.
+// The nodes take their shares from the operator, and the next epoch reconstructs from this commitment. A new ID adds a secret to the committee, a known one replaces the secret held under it. Dealing the same commitment again is acknowledged.
+func (bb *BulletinBoard) Deal(ctx context.Context, msg *pb.DealMsg) (*pb.AckMsg, error) {
+	entry := epochEntry(msg.GetEpoch()).WithFields(logrus.Fields{"rpc": "Deal", "secret": msg.GetSecret()})
+	if err := pb.VerifyOperated(bb.operator, msg); err != nil {
+		entry.WithError(err).Warn("reject dealing")
+		return nil, err
+	}
+	if len(msg.GetPolycmt()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the polynomial commitment is empty")
+	}
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if msg.GetCommittee() != bb.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), bb.committee)
+	}
+	ack := &pb.AckMsg{
+		Epoch:     msg.GetEpoch(),
+		Committee: bb.committee,
+	}
+	epoch := msg.GetEpoch()
+	if current := bb.history[*bb.epoch]; epoch == *bb.epoch && current.GetDealt() && current.GetSecret() == msg.GetSecret() {
+		if err := bb.checkShared(epoch, msg.GetSecret(), msg.GetPolycmt()); err != nil {
+			return nil, err
+		}
+		return ack, nil
+	}
+	if err := bb.checkNext(epoch); err != nil {
+		return nil, err
+	}
+	if err := appendShared(bb.backend, bb.committee, bb.counter, epoch, msg.GetSecret(), msg.GetPolycmt()); err != nil {
+		entry.WithError(err).Error("failed to store the dealing")
+		return nil, err
+	}
+	// a dealing that failed halfway may have left another commitment behind
+	if err := bb.checkShared(epoch, msg.GetSecret(), msg.GetPolycmt()); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+	record := &pb.EpochStatusMsg{
+		Epoch:     epoch,
+		Committee: bb.committee,
+		State:     pb.EpochStatusMsg_COMPLETED,
+		Start:     now,
+		End:       now,
+		Dealt:     true,
+		Secret:    msg.GetSecret(),
+		Secrets:   withSecret(bb.history[*bb.epoch].HeldSecrets(), msg.GetSecret()),
+	}
+	if err := bb.appendEpoch(record); err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot record the dealing of epoch %d: %v", epoch, err)
+	}
+	entry.Info("record dealt secret")
+	return ack, nil
+}
+
+// Delete records that the committee drops a secret as an epoch that runs no phases and writes nothing to the log. The epochs after it no longer hand the secret off.
+// The secret the committee started with stays. Deleting the same secret again in the same epoch is acknowledged.
+func (bb *BulletinBoard) Delete(ctx context.Context, msg *pb.DeleteMsg) (*pb.AckMsg, error) {
+	entry := epochEntry(msg.GetEpoch()).WithFields(logrus.Fields{"rpc": "Delete", "secret": msg.GetSecret()})
+	if err := pb.VerifyOperated(bb.operator, msg); err != nil {
+		entry.WithError(err).Warn("reject deletion")
+		return nil, err
+	}
+	if msg.GetSecret() == "" {
+		return nil, status.Error(codes.InvalidArgument, "the default secret cannot be deleted")
+	}
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if msg.GetCommittee() != bb.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), bb.committee)
+	}
+	ack := &pb.AckMsg{
+		Epoch:     msg.GetEpoch(),
+		Committee: bb.committee,
+	}
+	epoch := msg.GetEpoch()
+	if current := bb.history[*bb.epoch]; epoch == *bb.epoch && current.GetDeleted() && current.GetSecret() == msg.GetSecret() {
+		return ack, nil
+	}
+	if err := bb.checkNext(epoch); err != nil {
+		return nil, err
+	}
+	if err := bb.holds(*bb.epoch, msg.GetSecret()); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+	record := &pb.EpochStatusMsg{
+		Epoch:     epoch,
+		Committee: bb.committee,
+		State:     pb.EpochStatusMsg_COMPLETED,
+		Start:     now,
+		End:       now,
+		Deleted:   true,
+		Secret:    msg.GetSecret(),
+		Secrets:   withoutSecret(bb.history[*bb.epoch].HeldSecrets(), msg.GetSecret()),
+	}
+	if err := bb.appendEpoch(record); err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot record the deletion of epoch %d: %v", epoch, err)
+	}
+	entry.Info("record deleted secret")
+	return ack, nil
+}
+
+// An epoch of the operator must directly follow the current one, which must have ended. The caller holds bb.mutex.
+func (bb *BulletinBoard) checkNext(epoch int64) error {
+	if epoch != *bb.epoch+1 {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d cannot follow epoch %d", epoch, *bb.epoch)
+	}
+	if bb.history[*bb.epoch].GetState() == pb.EpochStatusMsg_RUNNING {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d is still running", *bb.epoch)
+	}
+	return nil
+}
+
+// Record an epoch without phases and make it the current one. The caller holds bb.mutex.
+func (bb *BulletinBoard) appendEpoch(record *pb.EpochStatusMsg) error {
+	if err := bb.recordEpoch(record); err != nil {
+		return err
+	}
+	*bb.epoch = record.GetEpoch()
+	bb.history = append(bb.history, record)
+	bb.metrics.startEpoch(record)
+	bb.metrics.endEpoch(record)
+	return nil
+}
+
+// Append the same commitment for a secret under the index of every node as the share distribution of an epoch that runs no phases, genesis or a dealing.
+// A commitment already there is left alone, it is from a backend that outlived the board or an earlier attempt.
+func appendShared(backend Backend, committee string, counter int, epoch int64, secret string, polycmt []byte) error {
+	for i := 0; i < counter; i++ {
+		data, err := proto.Marshal(&pb.Cmt1Msg{
+			Index:     int32(i + 1),
+			Polycmt:   polycmt,
+			Epoch:     epoch,
+			Committee: committee,
+			Secret:    secret,
+		})
+		if err != nil {
+			return err
+		}
+		err = backend.Append(&pb.EntryMsg{
+			Epoch:  epoch,
+			Phase:  phaseShareDist,
+			Index:  int32(i + 1),
+			Data:   data,
+			Secret: secret,
+		})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// Every node holds polycmt for the secret in the share distribution of the epoch. The caller holds bb.mutex.
+func (bb *BulletinBoard) checkShared(epoch int64, secret string, polycmt []byte) error {
+	entries, err := bb.readPhase(epoch, phaseShareDist, secret)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		msg := &pb.Cmt1Msg{}
+		if err := proto.Unmarshal(entry.GetData(), msg); err != nil {
+			return status.Errorf(codes.DataLoss, "corrupted entry of [node %d]: %v", entry.GetIndex(), err)
+		}
+		if !bytes.Equal(msg.GetPolycmt(), polycmt) {
+			return status.Errorf(codes.AlreadyExists, "epoch %d holds another dealing", epoch)
+		}
+	}
+	return nil
+}
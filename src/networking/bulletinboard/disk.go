@@ -0,0 +1,166 @@
+package bulletinboard
+
+import (
+	"sort"
+	"sync"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/boardstore"
+	"github.com/golang/protobuf/proto"
+)
+
+// Records in the log of the disk backend before it folds them into a snapshot
+const compactAfter = 1024
+
+// The file-backed backend. Entries and epoch statuses are appended to a log on disk before they take effect, and kept in memory to serve reads and subscriptions.
+// Once the log holds compactAfter records it is folded into a snapshot with one record per entry and per epoch.
+type disk struct {
+	store *memory
+	file  *boardstore.Store
+
+	mutex sync.Mutex
+	// Latest status of every epoch
+	epochs map[int64]*pb.EpochStatusMsg
+}
+
+// NewDisk returns a backend that keeps the bulletinboard in files under dir, together with what was stored there before
+func NewDisk(dir string) (Backend, error) {
+	file, err := boardstore.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	records, err := file.Load()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	d := &disk{
+		store:  newMemory(),
+		file:   file,
+		epochs: make(map[int64]*pb.EpochStatusMsg),
+	}
+	for _, data := range records {
+		rec := &pb.RecordMsg{}
+		if err := proto.Unmarshal(data, rec); err != nil {
+			file.Close()
+			return nil, err
+		}
+		d.apply(rec)
+	}
+	logger.WithField("backend", "disk").Infof("recovered %d entries and %d epochs from %s", len(d.store.entries()), len(d.epochs), dir)
+	return d, nil
+}
+
+func (d *disk) Append(entry *pb.EntryMsg) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.store.taken(slotOf(entry)) {
+		return ErrTaken
+	}
+	// the chain is rebuilt when the entries are applied again
+	return d.record(&pb.RecordMsg{Entry: &pb.EntryMsg{
+		Epoch:  entry.GetEpoch(),
+		Phase:  entry.GetPhase(),
+		Index:  entry.GetIndex(),
+		Data:   entry.GetData(),
+		Secret: entry.GetSecret(),
+	}})
+}
+
+func (d *disk) Read(epoch int64, phase int32) ([]*pb.EntryMsg, error) {
+	return d.store.Read(epoch, phase)
+}
+
+func (d *disk) Subscribe(epoch int64) (<-chan *pb.EntryMsg, func()) {
+	return d.store.Subscribe(epoch)
+}
+
+func (d *disk) Close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.store.Close()
+	return d.file.Close()
+}
+
+func (d *disk) RecordEpoch(msg *pb.EpochStatusMsg) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.record(&pb.RecordMsg{Status: proto.Clone(msg).(*pb.EpochStatusMsg)})
+}
+
+func (d *disk) Epochs() []*pb.EpochStatusMsg {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.sortedEpochs()
+}
+
+// Store a record on disk, then apply it. The caller holds d.mutex.
+func (d *disk) record(rec *pb.RecordMsg) error {
+	data, err := proto.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := d.file.Append(data); err != nil {
+		return err
+	}
+	d.apply(rec)
+	if d.file.Logged() >= compactAfter {
+		if err := d.compact(); err != nil {
+			logger.WithField("backend", "disk").WithError(err).Error("failed to compact the log")
+		}
+	}
+	return nil
+}
+
+// Apply a record to the content in memory. A record is applied twice if the process crashed while compacting, which leaves the content as it was.
+func (d *disk) apply(rec *pb.RecordMsg) {
+	if entry := rec.GetEntry(); entry != nil {
+		// the only other failure is a closed store
+		d.store.Append(entry)
+	}
+	if msg := rec.GetStatus(); msg != nil {
+		d.epochs[msg.GetEpoch()] = msg
+	}
+}
+
+// Fold the log into a snapshot. The caller holds d.mutex.
+func (d *disk) compact() error {
+	records := make([][]byte, 0)
+	for _, entry := range d.store.entries() {
+		data, err := proto.Marshal(&pb.RecordMsg{Entry: &pb.EntryMsg{
+			Epoch:  entry.GetEpoch(),
+			Phase:  entry.GetPhase(),
+			Index:  entry.GetIndex(),
+			Data:   entry.GetData(),
+			Secret: entry.GetSecret(),
+		}})
+		if err != nil {
+			return err
+		}
+		records = append(records, data)
+	}
+	for _, msg := range d.sortedEpochs() {
+		data, err := proto.Marshal(&pb.RecordMsg{Status: msg})
+		if err != nil {
+			return err
+		}
+		records = append(records, data)
+	}
+	if err := d.file.Snapshot(records); err != nil {
+		return err
+	}
+	logger.WithField("backend", "disk").Infof("compacted the log into a snapshot of %d records", len(records))
+	return nil
+}
+
+// The caller holds d.mutex
+func (d *disk) sortedEpochs() []*pb.EpochStatusMsg {
+	epochs := make([]*pb.EpochStatusMsg, 0, len(d.epochs))
+	for _, msg := range d.epochs {
+		epochs = append(epochs, proto.Clone(msg).(*pb.EpochStatusMsg))
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i].GetEpoch() < epochs[j].GetEpoch()
+	})
+	return epochs
+}
@@ -0,0 +1,104 @@
+package bulletinboard
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Interval at which a draining bulletinboard checks whether its epoch has ended
+const drainInterval = 50 * time.Millisecond
+
+var errDraining = status.Error(codes.Unavailable, "bulletinboard is shutting down")
+
+// Check answers a health check. The bulletinboard is live while it serves, and ready while it can start an epoch.
+func (bb *BulletinBoard) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return pb.CheckHealth(in, bb.notReady())
+}
+
+func (bb *BulletinBoard) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return pb.ErrWatchHealth
+}
+
+// Why the bulletinboard cannot start an epoch now, empty if it can
+func (bb *BulletinBoard) notReady() string {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	switch {
+	case *bb.draining:
+		return "shutting down"
+	case bb.counter > 0 && bb.nConn[0] == nil:
+		return "not connected"
+	}
+	return ""
+}
+
+// Record the outcome of a call to a node, and log when the node goes down or comes back
+func (bb *BulletinBoard) recordCall(peer string, err error) {
+	h, ok := bb.health[peer]
+	// calls cut short by the bulletinboard stopping say nothing about the node
+	if !ok || bb.ctx.Err() != nil {
+		return
+	}
+	if !h.Record(err) {
+		return
+	}
+	if h.Down() {
+		logger.WithField("peer", peer).WithError(err).Warn("peer is down")
+	} else {
+		logger.WithField("peer", peer).Info("peer is up again")
+	}
+}
+
+// No epoch is running
+func (bb *BulletinBoard) drained() bool {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	return bb.history[*bb.epoch].GetState() != pb.EpochStatusMsg_RUNNING
+}
+
+// Shutdown stops the bulletinboard once the running epoch has ended and the calls it serves have returned. It refuses new epochs meanwhile.
+// If ctx is done first, the bulletinboard stops right away and Shutdown returns the error of ctx. The backend stays open, it belongs to the caller.
+func (bb *BulletinBoard) Shutdown(ctx context.Context) error {
+	bb.mutex.Lock()
+	*bb.draining = true
+	server := bb.server
+	epoch := *bb.epoch
+	bb.mutex.Unlock()
+	epochEntry(epoch).Info("drain before stopping")
+
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+	for !bb.drained() && ctx.Err() == nil {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		}
+	}
+	err := ctx.Err()
+	if server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			err = ctx.Err()
+			server.Stop()
+		}
+	}
+	bb.cancel()
+	bb.Disconnect()
+	if err != nil {
+		epochEntry(epoch).WithError(err).Warn("stopped before the epoch ended")
+		return err
+	}
+	epochEntry(epoch).Info("stopped")
+	return nil
+}
@@ -0,0 +1,42 @@
+package bulletinboard
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Logger of the bulletinboard and its backends
+var logger = logrus.WithField("component", "bulletinboard")
+
+// Logger of the bulletinboard in an epoch
+func epochEntry(epoch int64) *logrus.Entry {
+	return logger.WithField("epoch", epoch)
+}
+
+// Logger of the bulletinboard about a call to a node
+func nodeEntry(epoch int64, rpc string, peer string) *logrus.Entry {
+	return epochEntry(epoch).WithFields(logrus.Fields{"rpc": rpc, "peer": peer})
+}
+
+// Logger of a replica
+func (r *Replica) entry() *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{"component": "replica", "replica": r.id + 1})
+}
+
+// Start a stretch of the epoch, timed and traced
+func (bb *BulletinBoard) startStretch(name string) {
+	bb.metrics.phases.Start(name)
+	bb.steps.Start(name)
+}
+
+// End a stretch of the epoch
+func (bb *BulletinBoard) endStretch(name string) {
+	bb.metrics.phases.End(name)
+	bb.steps.End(name, nil)
+}
+
+// Make a call to a node in a span under the current stretch
+func (bb *BulletinBoard) traceCall(rpc string, peer string, call func() error) error {
+	err := bb.steps.Call(rpc, map[string]interface{}{"rpc": rpc, "peer": peer}, call)
+	bb.recordCall(peer, err)
+	return err
+}
@@ -0,0 +1,139 @@
+package bulletinboard
+
+import (
+	"errors"
+	"sync"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+)
+
+// The in-memory backend. Its content is lost when the process exits.
+type memory struct {
+	mutex sync.Mutex
+	// Signalled whenever an entry is appended or the backend is closed
+	cond *sync.Cond
+	// Entries in the order they were appended, each chained to the one before
+	log []*pb.EntryMsg
+	// Occupied slots
+	slots  map[slot]bool
+	closed bool
+}
+
+// NewMemory returns a backend that keeps the bulletinboard in memory
+func NewMemory() Backend {
+	return newMemory()
+}
+
+func newMemory() *memory {
+	m := &memory{
+		slots: make(map[slot]bool),
+	}
+	m.cond = sync.NewCond(&m.mutex)
+	return m
+}
+
+func (m *memory) Append(entry *pb.EntryMsg) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.closed {
+		return errors.New("backend is closed")
+	}
+	key := slotOf(entry)
+	if m.slots[key] {
+		return ErrTaken
+	}
+	m.slots[key] = true
+	// chain the entry to the end of the log
+	stored := proto.Clone(entry).(*pb.EntryMsg)
+	stored.Seq = int64(len(m.log) + 1)
+	stored.Prev = nil
+	if len(m.log) > 0 {
+		stored.Prev = m.log[len(m.log)-1].GetHash()
+	}
+	stored.Hash = pb.EntryHash(stored)
+	m.log = append(m.log, stored)
+	m.cond.Broadcast()
+	return nil
+}
+
+// Whether the slot holds an entry
+func (m *memory) taken(key slot) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.slots[key]
+}
+
+// All entries in the order they were appended
+func (m *memory) entries() []*pb.EntryMsg {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]*pb.EntryMsg{}, m.log...)
+}
+
+func (m *memory) Read(epoch int64, phase int32) ([]*pb.EntryMsg, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	entries := make([]*pb.EntryMsg, 0)
+	for _, entry := range m.log {
+		if entry.GetEpoch() == epoch && entry.GetPhase() == phase {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memory) Subscribe(epoch int64) (<-chan *pb.EntryMsg, func()) {
+	entries := make(chan *pb.EntryMsg)
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			m.mutex.Lock()
+			m.cond.Broadcast()
+			m.mutex.Unlock()
+		})
+	}
+	go func() {
+		defer close(entries)
+		for next := 0; ; next++ {
+			m.mutex.Lock()
+			for next == len(m.log) && !m.closed && !isDone(done) {
+				m.cond.Wait()
+			}
+			if m.closed || isDone(done) {
+				m.mutex.Unlock()
+				return
+			}
+			entry := m.log[next]
+			m.mutex.Unlock()
+			if entry.GetEpoch() < epoch {
+				continue
+			}
+			select {
+			case entries <- entry:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return entries, cancel
+}
+
+func (m *memory) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.closed = true
+	m.cond.Broadcast()
+	return nil
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
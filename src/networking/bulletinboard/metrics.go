@@ -0,0 +1,150 @@
+package bulletinboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/metrics"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/status"
+)
+
+// Stretches of an epoch the bulletinboard times, the phase label of churp_phase_duration_seconds.
+// The board sees reconstruction and proactivization as one stretch that ends when every node has written in phase 2.
+const (
+	timedEpoch           = "epoch"
+	timedProactivization = "proactivization"
+	timedVerification2   = "verification2"
+	timedShareDist       = "sharedist"
+	timedVerification3   = "verification3"
+)
+
+// The metrics of a bulletinboard
+type boardMetrics struct {
+	registry *metrics.Registry
+	phases   *metrics.Phases
+
+	received      *metrics.Counter
+	receivedBytes *metrics.Counter
+	sent          *metrics.Counter
+	sentBytes     *metrics.Counter
+	rejected      *metrics.Counter
+	failures      *metrics.Counter
+	epochs        *metrics.Counter
+
+	epoch       *metrics.Gauge
+	running     *metrics.Gauge
+	startedAt   *metrics.Gauge
+	completed   *metrics.Gauge
+	completedAt *metrics.Gauge
+
+	// Bytes written before the current epoch started, the log of the epoch holds what came after
+	bytesBefore float64
+}
+
+func newBoardMetrics(counter int, degree int, committee string, history []*pb.EpochStatusMsg) *boardMetrics {
+	r := metrics.New()
+	m := &boardMetrics{
+		registry:      r,
+		phases:        metrics.NewPhases(r.Histogram("churp_phase_duration_seconds", "How long the phases of an epoch took on the bulletinboard.", metrics.DurationBuckets, "phase")),
+		received:      r.Counter("churp_messages_received_total", "Commitments written to the bulletinboard, by RPC and writing node.", "rpc", "peer"),
+		receivedBytes: r.Counter("churp_received_bytes_total", "Bytes of the commitments written to the bulletinboard, by RPC and writing node.", "rpc", "peer"),
+		sent:          r.Counter("churp_messages_sent_total", "Messages the bulletinboard sent, every attempt counted, by RPC and receiving node. Reads are not authenticated and carry no peer.", "rpc", "peer"),
+		sentBytes:     r.Counter("churp_sent_bytes_total", "Bytes of the messages the bulletinboard sent, by RPC and receiving node.", "rpc", "peer"),
+		rejected:      r.Counter("churp_rejected_writes_total", "Writes the bulletinboard refused, by RPC and gRPC code.", "rpc", "code"),
+		failures:      r.Counter("churp_verification_failures_total", "Verifications a node failed, by check.", "check"),
+		epochs:        r.Counter("churp_epochs_total", "Epochs that ended, by final state.", "state"),
+		epoch:         r.Gauge("churp_epoch", "Latest epoch the bulletinboard started."),
+		running:       r.Gauge("churp_epoch_running", "Set to 1 while the latest epoch is running."),
+		startedAt:     r.Gauge("churp_epoch_started_timestamp_seconds", "Unix time the latest epoch started."),
+		completed:     r.Gauge("churp_completed_epoch", "Latest epoch that completed."),
+		completedAt:   r.Gauge("churp_epoch_completed_timestamp_seconds", "Unix time the latest completed epoch ended."),
+	}
+	r.Gauge("churp_committee_nodes", "Number of nodes in the committee.").Set(float64(counter))
+	r.Gauge("churp_committee_degree", "Degree of the sharing polynomials, one below the number of shares that reconstruct the secret.").Set(float64(degree))
+	r.Gauge("churp_committee_member", "Set to 1 for the committee the bulletinboard serves, with label 0 for the bulletinboard.", "committee", "label").Set(1, committee, "0")
+	for _, msg := range history {
+		m.startEpoch(msg)
+		if msg.GetState() != pb.EpochStatusMsg_RUNNING {
+			m.endEpoch(msg)
+		}
+	}
+	return m
+}
+
+// Metrics returns the registry of the metrics of the bulletinboard, for a /metrics endpoint
+func (bb *BulletinBoard) Metrics() *metrics.Registry {
+	return bb.metrics.registry
+}
+
+func (m *boardMetrics) startEpoch(msg *pb.EpochStatusMsg) {
+	m.epoch.Set(float64(msg.GetEpoch()))
+	m.running.Set(1)
+	m.startedAt.Set(float64(msg.GetStart()) / float64(time.Second))
+}
+
+func (m *boardMetrics) endEpoch(msg *pb.EpochStatusMsg) {
+	m.running.Set(0)
+	if msg.GetState() == pb.EpochStatusMsg_COMPLETED {
+		m.completed.Set(float64(msg.GetEpoch()))
+		m.completedAt.Set(float64(msg.GetEnd()) / float64(time.Second))
+	}
+}
+
+// Start timing an epoch
+func (bb *BulletinBoard) startEpochMetrics(msg *pb.EpochStatusMsg) {
+	bb.metrics.startEpoch(msg)
+	bb.metrics.bytesBefore = bb.metrics.receivedBytes.Total()
+	bb.metrics.phases.Reset()
+	bb.metrics.phases.Start(timedEpoch)
+	bb.steps.Begin(bb.committee, msg.GetEpoch())
+	bb.startStretch(timedProactivization)
+}
+
+// Record the end of an epoch. The caller holds bb.mutex.
+func (bb *BulletinBoard) endEpochMetrics(msg *pb.EpochStatusMsg) {
+	bb.metrics.endEpoch(msg)
+	bb.metrics.epochs.Inc(strings.ToLower(msg.GetState().String()))
+	if msg.GetState() == pb.EpochStatusMsg_COMPLETED {
+		bb.metrics.phases.End(timedEpoch)
+		bb.steps.Finish(nil)
+	} else {
+		bb.steps.Finish(errors.New("epoch " + strings.ToLower(msg.GetState().String())))
+	}
+}
+
+// Bytes written to the board in the current epoch
+func (bb *BulletinBoard) epochBytes() int64 {
+	return int64(bb.metrics.receivedBytes.Total() - bb.metrics.bytesBefore)
+}
+
+// Peer label of a node writing to the board, unknown for indexes outside the committee
+func (bb *BulletinBoard) peer(index int32) string {
+	if index < 1 || int(index) > bb.counter {
+		return "unknown"
+	}
+	return fmt.Sprintf("node%d", index)
+}
+
+// Count a write of node index, and why it was refused if it was
+func (bb *BulletinBoard) countWrite(phase int32, index int32, msg proto.Message, err error) {
+	bb.countReceived(fmt.Sprintf("WritePhase%d", phase), index, msg, err)
+}
+
+// Count a call of node index to rpc, and why it was refused if it was
+func (bb *BulletinBoard) countReceived(rpc string, index int32, msg proto.Message, err error) {
+	peer := bb.peer(index)
+	bb.metrics.received.Inc(rpc, peer)
+	bb.metrics.receivedBytes.Add(float64(proto.Size(msg)), rpc, peer)
+	if err != nil {
+		bb.metrics.rejected.Inc(rpc, status.Code(err).String())
+	}
+}
+
+func (bb *BulletinBoard) countSent(rpc string, peer string, msg proto.Message) {
+	bb.metrics.sent.Inc(rpc, peer)
+	bb.metrics.sentBytes.Add(float64(proto.Size(msg)), rpc, peer)
+}
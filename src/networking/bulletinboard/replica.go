@@ -0,0 +1,679 @@
+package bulletinboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/boardstore"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// A leader sends heartbeats at this interval
+	heartbeatInterval = 50 * time.Millisecond
+	// A follower that hears nothing from a leader for a random time in [electionTimeout, 2*electionTimeout) starts an election
+	electionTimeout = 300 * time.Millisecond
+	// Deadline of an RPC between replicas
+	replicaRPCTimeout = 200 * time.Millisecond
+	// How long a leader waits for an appended entry to commit
+	commitTimeout = 2 * time.Second
+	// Most log entries sent in one AppendEntries
+	maxBatch = 64
+)
+
+// Roles of a replica
+const (
+	follower = iota
+	candidate
+	leader
+)
+
+// Replica is one member of the replicated backend of the bulletinboard.
+// Replicas agree on a single log of entries through Raft and apply the committed part to an in-memory store.
+// Appends go through as long as a majority of replicas is up, the others may crash. A replica makes its term, its vote and its log durable before it answers or acts on them, so a restarted replica resumes from them and the leader catches it up on the rest.
+type Replica struct {
+	// Position of this replica in the replica list
+	id int
+	// Addresses of all replicas
+	peers []string
+	// Rand
+	randState *rand.Rand
+
+	mutex sync.Mutex
+	role  int
+	// Raft state
+	term     int64
+	votedFor int32
+	leader   int32
+	// The replicated log. log[0] is a placeholder so that indexes start at 1.
+	log     []*pb.LogEntryMsg
+	commit  int64
+	applied int64
+	// Leader state, per replica
+	nextIndex  []int64
+	matchIndex []int64
+	inflight   []bool
+	// Last time this replica heard from a leader or granted a vote, and the election timeout drawn since
+	lastContact time.Time
+	timeout     time.Duration
+	// Appends waiting for their log index to be applied
+	waiting map[int64]chan error
+	// Applied entries
+	store *memory
+	// [+] Durable term, vote and log, nil to keep them in memory only
+	file *boardstore.Store
+	// Term and vote last made durable
+	savedTerm int64
+	savedVote int32
+	// Set once the replica stopped
+	stopped bool
+
+	conns   []*grpc.ClientConn
+	clients []pb.ReplicaServiceClient
+	server  *grpc.Server
+}
+
+// ReadReplicaList returns the addresses of the replicas, one per line of replica_list
+func ReadReplicaList(metadataPath string) ([]string, error) {
+	data, err := ioutil.ReadFile(metadataPath + "/replica_list")
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			addrs = append(addrs, line)
+		}
+	}
+	return addrs, nil
+}
+
+// ReplicaDir returns the directory under the metadata path where replica label keeps its durable state by default
+func ReplicaDir(metadataPath string, label int) string {
+	return filepath.Join(metadataPath, "replica"+strconv.Itoa(label))
+}
+
+// NewReplica returns the replica with the given label, counted from 1 in the replica list.
+// With a non-empty dir the replica keeps its term, its vote and its log in files there and resumes from what it stored before, otherwise it keeps them in memory only.
+func NewReplica(label int, metadataPath string, dir string) (Replica, error) {
+	peers, err := ReadReplicaList(metadataPath)
+	if err != nil {
+		return Replica{}, err
+	}
+	if label < 1 || label > len(peers) {
+		return Replica{}, status.Errorf(codes.InvalidArgument, "replica %d is not in a list of %d replicas", label, len(peers))
+	}
+	state := &pb.ReplicaRecordMsg{VotedFor: -1}
+	log := []*pb.LogEntryMsg{{}}
+	var file *boardstore.Store
+	if dir != "" {
+		if file, err = boardstore.Open(dir); err != nil {
+			return Replica{}, err
+		}
+		if log, err = loadReplicaState(file, state); err != nil {
+			file.Close()
+			return Replica{}, err
+		}
+	}
+	return Replica{
+		id:         label - 1,
+		peers:      peers,
+		randState:  rand.New(rand.NewSource(time.Now().UnixNano() + int64(label))),
+		term:       state.GetTerm(),
+		votedFor:   state.GetVotedFor(),
+		leader:     -1,
+		log:        log,
+		nextIndex:  make([]int64, len(peers)),
+		matchIndex: make([]int64, len(peers)),
+		inflight:   make([]bool, len(peers)),
+		waiting:    make(map[int64]chan error),
+		store:      newMemory(),
+		file:       file,
+		savedTerm:  state.GetTerm(),
+		savedVote:  state.GetVotedFor(),
+		conns:      make([]*grpc.ClientConn, len(peers)),
+		clients:    make([]pb.ReplicaServiceClient, len(peers)),
+	}, nil
+}
+
+// Replay the records of a replica into its log, leaving its latest term and vote in state
+func loadReplicaState(file *boardstore.Store, state *pb.ReplicaRecordMsg) ([]*pb.LogEntryMsg, error) {
+	records, err := file.Load()
+	if err != nil {
+		return nil, err
+	}
+	log := []*pb.LogEntryMsg{{}}
+	for _, data := range records {
+		rec := &pb.ReplicaRecordMsg{}
+		if err := proto.Unmarshal(data, rec); err != nil {
+			return nil, err
+		}
+		if len(rec.GetEntries()) > 0 {
+			index := rec.GetIndex()
+			if index < 1 || index > int64(len(log)) {
+				return nil, errors.New(fmt.Sprintf("stored entries from %d do not follow a log of %d entries", index, len(log)-1))
+			}
+			log = append(log[:index], rec.GetEntries()...)
+		}
+		state.Term = rec.GetTerm()
+		state.VotedFor = rec.GetVotedFor()
+	}
+	return log, nil
+}
+
+func (r *Replica) Serve() {
+	lis, err := net.Listen("tcp", r.peers[r.id])
+	if err != nil {
+		r.entry().Fatalf("replica failed to listen %v", err)
+	}
+	for i := range r.peers {
+		if i == r.id {
+			continue
+		}
+		conn, err := grpc.Dial(r.peers[i], grpc.WithInsecure())
+		if err != nil {
+			r.entry().Fatalf("replica did not connect: %v", err)
+		}
+		r.conns[i] = conn
+		r.clients[i] = pb.NewReplicaServiceClient(conn)
+	}
+	s := grpc.NewServer()
+	pb.RegisterReplicaServiceServer(s, r)
+	reflection.Register(s)
+	r.mutex.Lock()
+	if r.stopped {
+		r.mutex.Unlock()
+		lis.Close()
+		return
+	}
+	r.server = s
+	r.resetTimer()
+	r.entry().WithField("term", r.term).Infof("serve on %s with %d entries", r.peers[r.id], r.lastIndex())
+	r.mutex.Unlock()
+	go r.run()
+	if err := s.Serve(lis); err != nil {
+		r.entry().Fatalf("replica failed to serve %v", err)
+	}
+}
+
+// Stop closes the replica as a crash would, what it made durable is left for a replica restarted on the same directory
+func (r *Replica) Stop() {
+	r.mutex.Lock()
+	if r.stopped {
+		r.mutex.Unlock()
+		return
+	}
+	r.stopped = true
+	r.becomeFollower()
+	server := r.server
+	r.mutex.Unlock()
+	if server != nil {
+		server.Stop()
+	}
+	for _, conn := range r.conns {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.store.Close()
+	if r.file != nil {
+		r.file.Close()
+	}
+}
+
+// Heartbeats as a leader, elections otherwise
+func (r *Replica) run() {
+	for {
+		time.Sleep(heartbeatInterval)
+		r.mutex.Lock()
+		role := r.role
+		expired := time.Since(r.lastContact) > r.timeout
+		stopped := r.stopped
+		r.mutex.Unlock()
+		if stopped {
+			return
+		}
+		if role == leader {
+			r.replicate()
+		} else if expired {
+			r.campaign()
+		}
+	}
+}
+
+// Stand for leader in a new term
+func (r *Replica) campaign() {
+	r.mutex.Lock()
+	r.role = candidate
+	r.term++
+	r.votedFor = int32(r.id)
+	r.leader = -1
+	r.resetTimer()
+	if err := r.persist(r.lastIndex() + 1); err != nil {
+		r.role = follower
+		r.mutex.Unlock()
+		r.entry().WithError(err).Error("failed to store the term, give up the election")
+		return
+	}
+	term := r.term
+	msg := &pb.VoteMsg{
+		Term:      term,
+		Candidate: int32(r.id),
+		LastIndex: r.lastIndex(),
+		LastTerm:  r.log[r.lastIndex()].GetTerm(),
+	}
+	r.mutex.Unlock()
+	r.entry().WithField("term", term).Info("stand for leader")
+
+	votes := 1
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := range r.peers {
+		if i == r.id {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), replicaRPCTimeout)
+			defer cancel()
+			reply, err := r.clients[i].RequestVote(ctx, msg)
+			if err != nil {
+				return
+			}
+			r.mutex.Lock()
+			r.observeTerm(reply.GetTerm())
+			r.mutex.Unlock()
+			if reply.GetGranted() {
+				mutex.Lock()
+				votes++
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.role != candidate || r.term != term || votes < r.majority() {
+		return
+	}
+	r.role = leader
+	r.leader = int32(r.id)
+	for i := range r.peers {
+		r.nextIndex[i] = r.lastIndex() + 1
+		r.matchIndex[i] = 0
+	}
+	// an empty entry of the new term lets the entries of earlier terms commit
+	r.log = append(r.log, &pb.LogEntryMsg{Term: term})
+	if err := r.persist(r.lastIndex()); err != nil {
+		r.log = r.log[:r.lastIndex()]
+		r.becomeFollower()
+		r.entry().WithError(err).Error("failed to store the log, give up the lead")
+		return
+	}
+	r.matchIndex[r.id] = r.lastIndex()
+	r.advanceCommit()
+	r.entry().WithField("term", term).Info("lead the term")
+	go r.replicate()
+}
+
+// Send every follower the entries it misses, or a heartbeat
+func (r *Replica) replicate() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.role != leader {
+		return
+	}
+	for i := range r.peers {
+		if i == r.id || r.inflight[i] {
+			continue
+		}
+		prev := r.nextIndex[i] - 1
+		last := r.lastIndex()
+		if last-prev > maxBatch {
+			last = prev + maxBatch
+		}
+		entries := make([]*pb.LogEntryMsg, last-prev)
+		copy(entries, r.log[prev+1:last+1])
+		msg := &pb.AppendEntriesMsg{
+			Term:      r.term,
+			Leader:    int32(r.id),
+			PrevIndex: prev,
+			PrevTerm:  r.log[prev].GetTerm(),
+			Entries:   entries,
+			Commit:    r.commit,
+		}
+		r.inflight[i] = true
+		go r.sendEntries(i, msg)
+	}
+}
+
+func (r *Replica) sendEntries(i int, msg *pb.AppendEntriesMsg) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaRPCTimeout)
+	defer cancel()
+	reply, err := r.clients[i].AppendEntries(ctx, msg)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.inflight[i] = false
+	if err != nil {
+		return
+	}
+	r.observeTerm(reply.GetTerm())
+	if r.role != leader || r.term != msg.GetTerm() {
+		return
+	}
+	if reply.GetSuccess() {
+		match := msg.GetPrevIndex() + int64(len(msg.GetEntries()))
+		if match > r.matchIndex[i] {
+			r.matchIndex[i] = match
+		}
+		r.nextIndex[i] = r.matchIndex[i] + 1
+		r.advanceCommit()
+		return
+	}
+	// back up to the last entry the follower may share with the leader
+	next := reply.GetLast() + 1
+	if next >= r.nextIndex[i] {
+		next = r.nextIndex[i] - 1
+	}
+	if next < 1 {
+		next = 1
+	}
+	r.nextIndex[i] = next
+}
+
+// Commit the highest entry of the current term that a majority holds. The caller holds r.mutex.
+func (r *Replica) advanceCommit() {
+	for n := r.lastIndex(); n > r.commit; n-- {
+		if r.log[n].GetTerm() != r.term {
+			break
+		}
+		count := 0
+		for i := range r.peers {
+			if r.matchIndex[i] >= n {
+				count++
+			}
+		}
+		if count >= r.majority() {
+			r.commit = n
+			r.apply()
+			return
+		}
+	}
+}
+
+// Apply the committed entries to the store and answer the appends waiting for them. The caller holds r.mutex.
+func (r *Replica) apply() {
+	for r.applied < r.commit {
+		r.applied++
+		var err error
+		if entry := r.log[r.applied].GetEntry(); entry != nil {
+			err = r.store.Append(entry)
+		}
+		if done, ok := r.waiting[r.applied]; ok {
+			done <- err
+			delete(r.waiting, r.applied)
+		}
+	}
+}
+
+// Follow any replica with a higher term. The caller holds r.mutex.
+func (r *Replica) observeTerm(term int64) {
+	if term <= r.term {
+		return
+	}
+	r.term = term
+	r.votedFor = -1
+	r.becomeFollower()
+	if err := r.persist(r.lastIndex() + 1); err != nil {
+		r.entry().WithError(err).Error("failed to store the term")
+	}
+}
+
+// Make the term, the vote and the log from index on durable before the replica answers or acts on them. Nothing is written if they did not change or are kept in memory only. The caller holds r.mutex.
+func (r *Replica) persist(index int64) error {
+	if r.stopped {
+		return status.Error(codes.Unavailable, "replica is stopped")
+	}
+	if r.file == nil || (index > r.lastIndex() && r.term == r.savedTerm && r.votedFor == r.savedVote) {
+		return nil
+	}
+	rec := &pb.ReplicaRecordMsg{
+		Term:     r.term,
+		VotedFor: r.votedFor,
+		Index:    index,
+	}
+	if index <= r.lastIndex() {
+		rec.Entries = r.log[index:]
+	}
+	data, err := proto.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := r.file.Append(data); err != nil {
+		return err
+	}
+	r.savedTerm = r.term
+	r.savedVote = r.votedFor
+	if r.file.Logged() >= compactAfter {
+		if err := r.compact(); err != nil {
+			r.entry().WithError(err).Error("failed to compact the stored log")
+		}
+	}
+	return nil
+}
+
+// Fold the stored records into a snapshot of one record with the whole log. The caller holds r.mutex.
+func (r *Replica) compact() error {
+	data, err := proto.Marshal(&pb.ReplicaRecordMsg{
+		Term:     r.term,
+		VotedFor: r.votedFor,
+		Index:    1,
+		Entries:  r.log[1:],
+	})
+	if err != nil {
+		return err
+	}
+	return r.file.Snapshot([][]byte{data})
+}
+
+// The caller holds r.mutex
+func (r *Replica) becomeFollower() {
+	if r.role == leader {
+		// an entry still waiting may be dropped by the next leader, its writer has to retry
+		for index, done := range r.waiting {
+			done <- status.Error(codes.Unavailable, "leadership lost")
+			delete(r.waiting, index)
+		}
+	}
+	r.role = follower
+}
+
+// The caller holds r.mutex
+func (r *Replica) resetTimer() {
+	r.lastContact = time.Now()
+	r.timeout = electionTimeout + time.Duration(r.randState.Int63n(int64(electionTimeout)))
+}
+
+func (r *Replica) lastIndex() int64 {
+	return int64(len(r.log) - 1)
+}
+
+func (r *Replica) majority() int {
+	return len(r.peers)/2 + 1
+}
+
+func (r *Replica) RequestVote(ctx context.Context, msg *pb.VoteMsg) (*pb.VoteReplyMsg, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.observeTerm(msg.GetTerm())
+	lastTerm := r.log[r.lastIndex()].GetTerm()
+	upToDate := msg.GetLastTerm() > lastTerm || (msg.GetLastTerm() == lastTerm && msg.GetLastIndex() >= r.lastIndex())
+	granted := msg.GetTerm() == r.term && (r.votedFor == -1 || r.votedFor == msg.GetCandidate()) && upToDate
+	if granted {
+		r.votedFor = msg.GetCandidate()
+		r.resetTimer()
+	}
+	if err := r.persist(r.lastIndex() + 1); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store the vote: %v", err)
+	}
+	return &pb.VoteReplyMsg{
+		Term:    r.term,
+		Granted: granted,
+	}, nil
+}
+
+func (r *Replica) AppendEntries(ctx context.Context, msg *pb.AppendEntriesMsg) (*pb.AppendEntriesReplyMsg, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.observeTerm(msg.GetTerm())
+	if msg.GetTerm() < r.term {
+		return &pb.AppendEntriesReplyMsg{Term: r.term, Last: r.lastIndex()}, nil
+	}
+	if r.role != follower {
+		r.becomeFollower()
+	}
+	r.leader = msg.GetLeader()
+	r.resetTimer()
+	if err := r.persist(r.lastIndex() + 1); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store the term: %v", err)
+	}
+	prev := msg.GetPrevIndex()
+	if prev > r.lastIndex() || r.log[prev].GetTerm() != msg.GetPrevTerm() {
+		last := r.lastIndex()
+		if last >= prev {
+			last = prev - 1
+		}
+		return &pb.AppendEntriesReplyMsg{Term: r.term, Last: last}, nil
+	}
+	// the log changes from position from on
+	from := r.lastIndex() + 1
+	for i, entry := range msg.GetEntries() {
+		index := prev + 1 + int64(i)
+		if index <= r.lastIndex() {
+			if r.log[index].GetTerm() == entry.GetTerm() {
+				continue
+			}
+			r.log = r.log[:index]
+		}
+		if index < from {
+			from = index
+		}
+		r.log = append(r.log, entry)
+	}
+	if err := r.persist(from); err != nil {
+		// the leader sends the entries again
+		if from <= r.lastIndex() {
+			r.log = r.log[:from]
+		}
+		return nil, status.Errorf(codes.Internal, "failed to store the entries: %v", err)
+	}
+	last := prev + int64(len(msg.GetEntries()))
+	if commit := msg.GetCommit(); commit > r.commit {
+		if commit > last {
+			commit = last
+		}
+		r.commit = commit
+		r.apply()
+	}
+	return &pb.AppendEntriesReplyMsg{Term: r.term, Success: true, Last: last}, nil
+}
+
+// Append adds an entry to the replicated log and answers once it is applied
+func (r *Replica) Append(ctx context.Context, entry *pb.EntryMsg) (*pb.AckMsg, error) {
+	r.mutex.Lock()
+	if err := r.checkLeader(); err != nil {
+		r.mutex.Unlock()
+		return nil, err
+	}
+	r.log = append(r.log, &pb.LogEntryMsg{Term: r.term, Entry: entry})
+	index := r.lastIndex()
+	if err := r.persist(index); err != nil {
+		r.log = r.log[:index]
+		r.mutex.Unlock()
+		return nil, status.Errorf(codes.Internal, "failed to store entry %d: %v", index, err)
+	}
+	done := make(chan error, 1)
+	r.waiting[index] = done
+	r.matchIndex[r.id] = index
+	r.advanceCommit()
+	r.mutex.Unlock()
+	go r.replicate()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		return &pb.AckMsg{}, nil
+	case <-time.After(commitTimeout):
+		r.mutex.Lock()
+		delete(r.waiting, index)
+		r.mutex.Unlock()
+		return nil, status.Errorf(codes.Unavailable, "entry %d did not commit in time", index)
+	}
+}
+
+// Read sends the applied entries of a phase of an epoch
+func (r *Replica) Read(in *pb.ReadMsg, stream pb.ReplicaService_ReadServer) error {
+	r.mutex.Lock()
+	err := r.checkLeader()
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	entries, err := r.store.Read(in.GetEpoch(), in.GetPhase())
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := stream.Send(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe sends the applied entries from an epoch on, then every entry as it is applied. Any replica serves it.
+func (r *Replica) Subscribe(in *pb.ReadMsg, stream pb.ReplicaService_SubscribeServer) error {
+	entries, cancel := r.store.Subscribe(in.GetEpoch())
+	defer cancel()
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				return status.Error(codes.Unavailable, "replica is closing")
+			}
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// Clients of the bulletinboard talk to the leader only, the others send them on with Unavailable. The caller holds r.mutex.
+func (r *Replica) checkLeader() error {
+	if r.role != leader {
+		return status.Errorf(codes.Unavailable, "replica %d is not the leader", r.id+1)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package bulletinboard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// Start replica label of the list under dir, on the files it stored there before
+func startReplica(t *testing.T, dir string, label int) *Replica {
+	r, err := NewReplica(label, dir, ReplicaDir(dir, label))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	replica := &r
+	go replica.Serve()
+	return replica
+}
+
+// Label of the replica that leads, 0 if none does yet
+func leaderOf(replicas []*Replica) int {
+	for i, r := range replicas {
+		r.mutex.Lock()
+		role := r.role
+		r.mutex.Unlock()
+		if role == leader {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Entries appended while the leader and a follower are up survive both crashing, even with the third replica down by the time they restart
+func TestReplicaRestartKeepsCommittedEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replica")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	addrs := []string{"127.0.0.1:13340", "127.0.0.1:13341", "127.0.0.1:13342"}
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "replica_list"), []byte(strings.Join(addrs, "\n")+"\n"), 0644))
+
+	replicas := make([]*Replica, len(addrs))
+	for i := range replicas {
+		replicas[i] = startReplica(t, dir, i+1)
+	}
+	defer func() {
+		for _, r := range replicas {
+			r.Stop()
+		}
+	}()
+	backend, err := NewReplicated(addrs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer backend.Close()
+
+	entry := func(index int32) *pb.EntryMsg {
+		return &pb.EntryMsg{Epoch: 1, Phase: phaseProactivization, Index: index, Data: []byte(fmt.Sprintf("commitment %d", index))}
+	}
+	for index := int32(1); index <= 3; index++ {
+		if !assert.Nil(t, backend.Append(entry(index))) {
+			return
+		}
+	}
+
+	// an append answers once the leader applied it, so the leader is known by now
+	first := leaderOf(replicas)
+	if !assert.NotZero(t, first) {
+		return
+	}
+	follower := first%len(replicas) + 1
+	third := follower%len(replicas) + 1
+	replicas[first-1].Stop()
+	replicas[follower-1].Stop()
+	// the third replica goes down too, the entries can only come back from the files of the other two
+	replicas[third-1].Stop()
+	time.Sleep(2 * electionTimeout)
+	replicas[first-1] = startReplica(t, dir, first)
+	replicas[follower-1] = startReplica(t, dir, follower)
+
+	// the new leader commits the entries of the earlier term along with one of its own
+	if !assert.Nil(t, backend.Append(entry(4))) {
+		return
+	}
+	entries, err := backend.Read(1, phaseProactivization)
+	assert.Nil(t, err)
+	if assert.Len(t, entries, 4) {
+		for i, e := range entries {
+			assert.Equal(t, int32(i+1), e.GetIndex())
+			assert.Equal(t, entry(int32(i+1)).GetData(), e.GetData())
+		}
+	}
+}
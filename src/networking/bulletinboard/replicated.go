@@ -0,0 +1,141 @@
+package bulletinboard
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deadline of a call of the bulletinboard to a replica
+const backendRPCTimeout = 3 * time.Second
+
+// The backend kept on a group of replicas, see Replica
+type replicated struct {
+	conns   []*grpc.ClientConn
+	clients []pb.ReplicaServiceClient
+
+	mutex sync.Mutex
+	// The replica that served the last call, most likely the leader
+	current int
+}
+
+// NewReplicated returns a backend that keeps the bulletinboard on the replicas at the given addresses
+func NewReplicated(addrs []string) (Backend, error) {
+	if len(addrs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no replicas")
+	}
+	r := &replicated{
+		conns:   make([]*grpc.ClientConn, len(addrs)),
+		clients: make([]pb.ReplicaServiceClient, len(addrs)),
+	}
+	for i, addr := range addrs {
+		conn, err := grpc.Dial(addr, grpc.WithInsecure())
+		if err != nil {
+			return nil, err
+		}
+		r.conns[i] = conn
+		r.clients[i] = pb.NewReplicaServiceClient(conn)
+	}
+	return r, nil
+}
+
+// Call the replica that answered last, and the others in turn while the one called is down or not the leader
+func (r *replicated) call(f func(ctx context.Context, client pb.ReplicaServiceClient) error) error {
+	r.mutex.Lock()
+	i := r.current
+	r.mutex.Unlock()
+	return pb.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), backendRPCTimeout)
+		defer cancel()
+		err := f(ctx, r.clients[i])
+		if status.Code(err) == codes.DeadlineExceeded {
+			err = status.Errorf(codes.Unavailable, "replica %d did not answer: %v", i+1, err)
+		}
+		if status.Code(err) == codes.Unavailable {
+			i = (i + 1) % len(r.clients)
+			return err
+		}
+		r.mutex.Lock()
+		r.current = i
+		r.mutex.Unlock()
+		return err
+	})
+}
+
+func (r *replicated) Append(entry *pb.EntryMsg) error {
+	return r.call(func(ctx context.Context, client pb.ReplicaServiceClient) error {
+		_, err := client.Append(ctx, entry)
+		return err
+	})
+}
+
+func (r *replicated) Read(epoch int64, phase int32) ([]*pb.EntryMsg, error) {
+	var entries []*pb.EntryMsg
+	err := r.call(func(ctx context.Context, client pb.ReplicaServiceClient) error {
+		entries = make([]*pb.EntryMsg, 0)
+		stream, err := client.Read(ctx, &pb.ReadMsg{Epoch: epoch, Phase: phase})
+		if err != nil {
+			return err
+		}
+		for {
+			entry, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+	})
+	return entries, err
+}
+
+// Subscribe follows one replica and moves on to the next when it goes down. Entries are delivered once even though a new subscription starts over.
+func (r *replicated) Subscribe(epoch int64) (<-chan *pb.EntryMsg, func()) {
+	entries := make(chan *pb.EntryMsg)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(entries)
+		delivered := make(map[slot]bool)
+		r.mutex.Lock()
+		i := r.current
+		r.mutex.Unlock()
+		for ctx.Err() == nil {
+			stream, err := r.clients[i].Subscribe(ctx, &pb.ReadMsg{Epoch: epoch})
+			for err == nil {
+				var entry *pb.EntryMsg
+				entry, err = stream.Recv()
+				if err != nil || delivered[slotOf(entry)] {
+					continue
+				}
+				delivered[slotOf(entry)] = true
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			logger.WithField("backend", "replicated").WithError(err).Warnf("lost the subscription to replica %d", i+1)
+			i = (i + 1) % len(r.clients)
+			time.Sleep(pb.RetryInterval)
+		}
+	}()
+	return entries, cancel
+}
+
+func (r *replicated) Close() error {
+	for _, conn := range r.conns {
+		conn.Close()
+	}
+	return nil
+}
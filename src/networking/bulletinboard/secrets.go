@@ -0,0 +1,77 @@
+package bulletinboard
+
+import (
+	"sort"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The latest epoch up to the given one whose share distribution holds the commitments to the polynomials of a secret.
+// An epoch with phases writes them for every secret it hands off unless it failed, of the epochs without phases only the one that dealt the secret writes any.
+func (bb *BulletinBoard) sharedIn(epoch int64, secret string) (int64, error) {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	if err := bb.holds(epoch, secret); err != nil {
+		return 0, err
+	}
+	for ; epoch > 0; epoch-- {
+		msg := bb.history[epoch]
+		switch {
+		case msg.GetState() == pb.EpochStatusMsg_FAILED:
+		case msg.GetDeleted():
+		case msg.GetDealt():
+			if msg.GetSecret() == secret {
+				return epoch, nil
+			}
+		default:
+			return epoch, nil
+		}
+	}
+	return 0, nil
+}
+
+// The committee holds the secret at the end of the epoch
+func (bb *BulletinBoard) checkSecret(epoch int64, secret string) error {
+	bb.mutex.Lock()
+	defer bb.mutex.Unlock()
+	return bb.holds(epoch, secret)
+}
+
+// The caller holds bb.mutex
+func (bb *BulletinBoard) holds(epoch int64, secret string) error {
+	if epoch < 0 || epoch >= int64(len(bb.history)) {
+		return status.Errorf(codes.NotFound, "epoch %d has not started", epoch)
+	}
+	for _, id := range bb.history[epoch].HeldSecrets() {
+		if id == secret {
+			return nil
+		}
+	}
+	return status.Errorf(codes.NotFound, "the committee holds no %s in epoch %d", pb.SecretName(secret), epoch)
+}
+
+// The IDs with secret added, in order
+func withSecret(ids []string, secret string) []string {
+	out := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		if id != secret {
+			out = append(out, id)
+		}
+	}
+	out = append(out, secret)
+	sort.Strings(out)
+	return out
+}
+
+// The IDs without secret
+func withoutSecret(ids []string, secret string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != secret {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/auditor"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Audit checks the bulletinboard log of a range of epochs against what every node read, one line per epoch, and exits with 1 if any of them disagrees
+func Audit(args []string) {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+	counter := flags.Int("c", 1, "Enter number of nodes")
+	epoch := flags.Int64("e", 0, "Enter the first epoch to audit, the log is checked from genesis if it is 0")
+	count := flags.Int64("n", 0, "Enter the number of epochs to audit, 0 audits up to the current epoch")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	flags.Parse(args)
+
+	a, err := auditor.New(*counter, *metadataPath, transport.GRPC())
+	if err != nil {
+		log.Fatalf("auditor failed to initialize: %v", err)
+	}
+	a.Connect()
+	defer a.Disconnect()
+
+	failed := false
+	for e := *epoch; *count == 0 || e < *epoch+*count; e++ {
+		report, err := a.Audit(e)
+		if status.Code(err) == codes.Unavailable && *count == 0 {
+			break
+		}
+		if err != nil {
+			log.Printf("audit of epoch %d failed: %v", e, err)
+			failed = true
+			break
+		}
+		line := fmt.Sprintf("%d\t%d entries\troot %x\t%d/%d nodes agree", report.Epoch, report.Size, report.Root, len(report.Agree), *counter)
+		if len(report.Disagree) > 0 {
+			line += fmt.Sprintf("\tdisagree: %v", report.Disagree)
+			failed = true
+		}
+		fmt.Println(line)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/bench"
+)
+
+// Bench runs a grid of committee configurations a few times each and writes the report as CSV or JSON
+func Bench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	nodes := flags.String("n", "4", "Comma separated numbers of nodes to sweep")
+	degrees := flags.String("t", "1", "Comma separated polynomial degrees to sweep, those not below the number of nodes are skipped")
+	schemes := flags.String("scheme", string(bench.KZG), "Comma separated commitment schemes to sweep, kzg-pbc for the secret alone or feldman-p521 for a P-521 key refreshed next to it")
+	repeat := flags.Int("repeat", 3, "Enter how many times to run each configuration")
+	epochs := flags.Int64("epochs", 2, "Enter the number of epochs of each run")
+	mode := flags.String("mode", "inprocess", "run the committee inprocess, or as local processes from the binaries in -bin")
+	bin := flags.String("bin", ".", "directory holding churp.exe for -mode process")
+	port := flags.Int("port", 11000, "first port of the committee for -mode process")
+	timeout := flags.Duration("timeout", 5*time.Minute, "how long a run may take for -mode process")
+	csvPath := flags.String("csv", "", "write the report as CSV to this file, - for the standard output")
+	jsonPath := flags.String("json", "", "write the report as JSON to this file, - for the standard output")
+	flags.Parse(args)
+
+	var runner bench.Runner
+	switch *mode {
+	case "inprocess":
+		runner = bench.InProcess{}
+	case "process":
+		runner = bench.Processes{Bin: *bin, BasePort: *port, Timeout: *timeout}
+	default:
+		log.Fatalf("unknown mode %q, use inprocess or process", *mode)
+	}
+	grid := bench.Grid(ints(*nodes), ints(*degrees), schemeList(*schemes))
+	if len(grid) == 0 {
+		log.Fatalf("no configuration has a degree below its number of nodes")
+	}
+	for _, config := range grid {
+		if err := config.Check(); err != nil {
+			log.Fatalf("cannot run %v: %v", config, err)
+		}
+	}
+
+	rows, err := bench.Benchmark(runner, grid, *repeat, *epochs)
+	if err != nil {
+		log.Fatalf("benchmark failed: %v", err)
+	}
+	if *csvPath == "" && *jsonPath == "" {
+		*csvPath = "-"
+	}
+	if *csvPath != "" {
+		writeReport(*csvPath, func(f *os.File) error { return bench.WriteCSV(f, rows) })
+	}
+	if *jsonPath != "" {
+		writeReport(*jsonPath, func(f *os.File) error { return bench.WriteJSON(f, rows) })
+	}
+}
+
+func ints(list string) []int {
+	values := make([]int, 0)
+	for _, s := range strings.Split(list, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Fatalf("%q is not a number", s)
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func schemeList(list string) []bench.Scheme {
+	values := make([]bench.Scheme, 0)
+	for _, s := range strings.Split(list, ",") {
+		values = append(values, bench.Scheme(strings.TrimSpace(s)))
+	}
+	return values
+}
+
+func writeReport(path string, report func(f *os.File) error) {
+	f := os.Stdout
+	if path != "-" {
+		var err error
+		f, err = os.Create(path)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", path, err)
+		}
+		defer f.Close()
+	}
+	if err := report(f); err != nil {
+		log.Fatalf("failed to write the report: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/bulletinboard"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"github.com/bl4ck5un/ChuRP/src/utils/metrics"
+)
+
+// Board serves the bulletinboard of the committee, keeping its content in memory unless told otherwise
+func Board(args []string) {
+	flags := flag.NewFlagSet("board", flag.ExitOnError)
+	cnt := flags.Int("c", 2, "Enter number of nodes")
+	degree := flags.Int("d", 1, "Enter the polynomial degree")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	aws := flags.Bool("aws", false, "if test on real aws")
+	metricsAddr := flags.String("metrics", "", "serve metrics at /metrics on this address, like :9100")
+	replicated := flags.Bool("r", false, "keep the content on the replicas in replica_list")
+	chain := flags.Bool("chain", false, "keep the content on a simulated chain that charges gas")
+	chainConfig := flags.String("gas", "", "TOML file with the gas model and blocks of the simulated chain")
+	dir := flags.String("dir", "", "keep the content in files under this directory, where a restarted bulletinboard finds it again")
+	drain := flags.Duration("drain", 30*time.Second, "on SIGINT or SIGTERM, how long to wait for the running epoch to end before stopping")
+	epochTimeout := flags.Duration("epoch-timeout", bulletinboard.DefaultEpochTimeout, "fail an epoch that has not ended after this long, 0 to wait forever")
+	out := outputFlags(flags, true)
+	flags.Parse(args)
+	out.setup("bulletinboard")
+
+	backend := bulletinboard.NewMemory()
+	if *replicated {
+		addrs, err := bulletinboard.ReadReplicaList(*metadataPath)
+		if err != nil {
+			log.Fatalf("bulletinboard failed to read the replica list: %v", err)
+		}
+		backend, err = bulletinboard.NewReplicated(addrs)
+		if err != nil {
+			log.Fatalf("bulletinboard failed to connect to the replicas: %v", err)
+		}
+	}
+	if *dir != "" {
+		var err error
+		backend, err = bulletinboard.NewDisk(*dir)
+		if err != nil {
+			log.Fatalf("bulletinboard failed to open its files: %v", err)
+		}
+	}
+	if *chain {
+		config := bulletinboard.DefaultChainConfig()
+		if *chainConfig != "" {
+			var err error
+			config, err = bulletinboard.ReadChainConfig(*chainConfig)
+			if err != nil {
+				log.Fatalf("bulletinboard failed to read the chain config: %v", err)
+			}
+		}
+		var err error
+		backend, err = bulletinboard.NewChain(config, *cnt, *metadataPath)
+		if err != nil {
+			log.Fatalf("bulletinboard failed to start the chain: %v", err)
+		}
+	}
+	bb, err := bulletinboard.New(*degree, *cnt, *metadataPath, backend, transport.GRPC())
+	if err != nil {
+		log.Fatalf("bulletinboard failed to initialize: %v", err)
+	}
+	bb.SetEpochTimeout(*epochTimeout)
+	if *metricsAddr != "" {
+		go func() {
+			log.Fatalf("bulletinboard failed to serve metrics: %v", metrics.Serve(*metricsAddr, bb.Metrics()))
+		}()
+	}
+	stopped := stopOnSignal("bulletinboard", *drain, bb.Shutdown)
+	bb.Serve(*aws)
+	<-stopped
+	backend.Close()
+}
@@ -0,0 +1,97 @@
+// Package cli holds the subcommands of the churp binary, each parsing its own flags: the services, which serve until the process ends, and the tools that talk to them or run the committee in a simulation or a benchmark.
+package cli
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/logging"
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
+)
+
+// Command is a subcommand of the churp binary, given the arguments after its name
+type Command func(args []string)
+
+// Commands are the subcommands of the churp binary by name
+var Commands = map[string]Command{
+	"node":    Node,
+	"board":   Board,
+	"clock":   Clock,
+	"replica": Replica,
+	"keygen":  Keygen,
+	"ctl":     Ctl,
+	"status":  Status,
+	"audit":   Audit,
+	"sim":     Sim,
+	"bench":   Bench,
+}
+
+// Flags every service takes to set up its log and trace
+type output struct {
+	logLevel  *string
+	logFormat *string
+	tracePath *string
+}
+
+func outputFlags(flags *flag.FlagSet, traced bool) output {
+	out := output{
+		logLevel:  flags.String("log-level", "info", "lowest level to log: debug, info, warning or error"),
+		logFormat: flags.String("log-format", "text", "format of the log: text or json"),
+	}
+	if traced {
+		out.tracePath = flags.String("trace", "", "append a span for every phase and outbound RPC to this file, as JSON lines")
+	}
+	return out
+}
+
+// Set up the log and the trace of the service name
+func (out output) setup(name string) {
+	if err := logging.Setup(*out.logLevel, *out.logFormat); err != nil {
+		log.Fatalf("%s failed to set up logging: %v", name, err)
+	}
+	if out.tracePath != nil && *out.tracePath != "" {
+		if err := trace.ToFile(*out.tracePath); err != nil {
+			log.Fatalf("%s failed to open the trace file: %v", name, err)
+		}
+	}
+}
+
+// The passphrase that encrypts the stored shares, from passFile if given or else from $CHURP_PASSPHRASE
+func readPassphrase(passFile string) string {
+	passphrase := os.Getenv("CHURP_PASSPHRASE")
+	if passFile != "" {
+		data, err := ioutil.ReadFile(passFile)
+		if err != nil {
+			log.Fatalf("node failed to read passphrase: %v", err)
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+	return passphrase
+}
+
+// On the first SIGINT or SIGTERM, shut the service name down within drain. A second signal kills the process.
+// The returned channel is closed once the shutdown is over.
+func stopOnSignal(name string, drain time.Duration, shutdown func(ctx context.Context) error) <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		sig := <-signals
+		signal.Reset(os.Interrupt, syscall.SIGTERM)
+		log.Printf("%s got %v, stopping within %v", name, sig, drain)
+		ctx, cancel := context.WithTimeout(context.Background(), drain)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			log.Printf("%s did not drain in time: %v", name, err)
+		}
+		close(done)
+	}()
+	return done
+}
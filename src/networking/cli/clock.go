@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/clock"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+)
+
+// Clock starts epochs on the bulletinboard one period apart until it is stopped, or returns once it has run the number asked for
+func Clock(args []string) {
+	flags := flag.NewFlagSet("clock", flag.ExitOnError)
+	counter := flags.Int("c", 1, "Enter number of nodes")
+	epoch := flags.Int64("e", 0, "Enter the first epoch to start, 0 continues after the latest epoch on the bulletinboard")
+	count := flags.Int64("n", 0, "Enter the number of epochs to run, 0 runs until stopped")
+	period := flags.Duration("t", 10*time.Second, "Enter the epoch duration")
+	history := flags.Bool("history", false, "print the epoch history and exit")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	out := outputFlags(flags, true)
+	flags.Parse(args)
+	out.setup("clock")
+
+	clock, err := clock.New(*counter, *metadataPath, transport.GRPC())
+	if err != nil {
+		log.Fatalf("clock failed to initialize: %v", err)
+	}
+	if err := clock.Connect(); err != nil {
+		log.Fatal(err)
+	}
+	defer clock.Disconnect()
+
+	if *history {
+		msgs, err := clock.ClientEpochHistory()
+		if err != nil {
+			log.Fatalf("clock failed to read epoch history: %v", err)
+		}
+		for _, msg := range msgs {
+			line := fmt.Sprintf("%d\t%s\t%s", msg.GetEpoch(), msg.GetState(), time.Unix(0, msg.GetStart()).Format(time.RFC3339))
+			if msg.GetEnd() != 0 {
+				line += fmt.Sprintf("\t%v", time.Duration(msg.GetEnd()-msg.GetStart()))
+			}
+			fmt.Println(line)
+		}
+		return
+	}
+
+	if *epoch == 0 {
+		latest, err := clock.Latest()
+		if err != nil {
+			log.Fatalf("clock failed to read epoch history: %v", err)
+		}
+		*epoch = latest.GetEpoch() + 1
+	}
+	// the running epoch still ends on the first signal, a second one kills the clock
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Reset(os.Interrupt, syscall.SIGTERM)
+		log.Printf("clock got %v, stopping after the running epoch", sig)
+		clock.Stop()
+	}()
+	if err := clock.Run(*epoch, *count, *period); err != nil {
+		log.Fatalf("clock stopped: %v", err)
+	}
+}
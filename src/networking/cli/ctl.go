@@ -0,0 +1,430 @@
+package cli
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/clock"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"github.com/bl4ck5un/ChuRP/src/utils/conv"
+	"github.com/bl4ck5un/ChuRP/src/utils/elgamal"
+	"github.com/bl4ck5un/ChuRP/src/utils/frost"
+	"github.com/ncw/gmp"
+)
+
+const ctlUsage = `usage: churp ctl [-c nodes] [-path metadata] <command> [flags]
+
+commands:
+  status            show the status of the nodes and the bulletinboard
+  start-epoch       start an epoch and wait for it to complete
+  store             deal a secret to the committee, in place of the one under the same ID
+  create            deal a new secret to the committee under an ID
+  secrets           list the secrets of the committee
+  refresh           run an epoch that hands every secret off and wait for it to end
+  delete            have the committee drop a secret
+  retrieve          reconstruct a secret from the shares of the nodes
+  transcript        print the bulletinboard log of an epoch
+  verify-share      check the shares of a node against the published commitments
+  sign              have the committee sign a message with a secret
+  verify-signature  check a signature of the committee under its public key
+  public-key        derive the public key of the committee from the public shares of the nodes
+  encrypt           encrypt a message under the public key of the committee
+  decrypt           have the committee decrypt a ciphertext
+  deal-key          deal a P-521 key to the committee for Schnorr signatures
+  schnorr-sign      have the committee sign a message with its P-521 key
+  verify-schnorr    check a Schnorr signature of the committee under its P-521 public key
+  beacon            read the random value of a round from the bulletinboard and check it
+  verify-beacon     check the random value of a round under the public key of the committee
+
+store, create, delete, retrieve, sign, decrypt, deal-key and schnorr-sign authenticate their requests with the operator key sk_operator of the metadata path.
+Every command on a secret takes -id, the secret the committee started with if not given. It cannot be deleted.
+`
+
+// Ctl carries out the operator tasks on a committee, each a command of its own with its own flags
+func Ctl(args []string) {
+	global := flag.NewFlagSet("ctl", flag.ExitOnError)
+	counter := global.Int("c", 1, "Enter number of nodes")
+	metadataPath := global.String("path", "/mpss/metadata", "Enter the metadata path")
+	global.Usage = func() {
+		fmt.Fprint(os.Stderr, ctlUsage)
+		global.PrintDefaults()
+	}
+	global.Parse(args)
+	if global.NArg() == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	command, args := global.Arg(0), global.Args()[1:]
+	flags := flag.NewFlagSet("ctl "+command, flag.ExitOnError)
+	switch command {
+	case "status":
+		label := flags.Int("l", -1, "Enter the label of the node to ask, 0 for the bulletinboard, all of them if not given")
+		asJSON := flags.Bool("json", false, "print every status as JSON")
+		flags.Parse(args)
+		report(*counter, *metadataPath, *label, *asJSON)
+	case "start-epoch":
+		epoch := flags.Int64("e", 0, "Enter the epoch to start, 0 starts the one after the latest epoch on the bulletinboard")
+		wait := flags.Bool("wait", true, "wait for the epoch to complete")
+		flags.Parse(args)
+		startEpoch(*counter, *metadataPath, *epoch, *wait)
+	case "store":
+		value := flags.String("secret", "", "Enter the secret to store, in decimal or in hexadecimal after 0x")
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		if *value == "" {
+			log.Fatal("ctl store needs -secret")
+		}
+		secret, err := operator.ParseSecret(*value)
+		if err != nil {
+			log.Fatal(err)
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		epoch, err := o.Deal(*id, secret)
+		if err != nil {
+			log.Fatalf("ctl failed to store the secret: %v", err)
+		}
+		fmt.Printf("secret dealt in epoch %d\n", epoch)
+	case "create":
+		id := flags.String("id", "", "Enter the ID of the new secret")
+		value := flags.String("secret", "", "Enter the secret, in decimal or in hexadecimal after 0x, a random one if not given")
+		flags.Parse(args)
+		if *id == "" {
+			log.Fatal("ctl create needs -id")
+		}
+		var secret *gmp.Int
+		if *value != "" {
+			var err error
+			if secret, err = operator.ParseSecret(*value); err != nil {
+				log.Fatal(err)
+			}
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		epoch, err := o.Create(*id, secret)
+		if err != nil {
+			log.Fatalf("ctl failed to create the secret: %v", err)
+		}
+		fmt.Printf("secret %q dealt in epoch %d\n", *id, epoch)
+	case "secrets":
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		secrets, err := o.Secrets()
+		if err != nil {
+			log.Fatalf("ctl failed to list the secrets: %v", err)
+		}
+		operator.WriteSecrets(os.Stdout, secrets)
+	case "refresh":
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		status, err := o.Refresh()
+		if err != nil {
+			log.Fatalf("ctl failed to refresh the secrets: %v", err)
+		}
+		fmt.Printf("epoch %d refreshed %d secrets\n", status.GetEpoch(), len(status.HeldSecrets()))
+	case "delete":
+		id := flags.String("id", "", "Enter the ID of the secret to delete")
+		flags.Parse(args)
+		if *id == "" {
+			log.Fatal("ctl delete needs -id")
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		epoch, err := o.Delete(*id)
+		if err != nil {
+			log.Fatalf("ctl failed to delete the secret: %v", err)
+		}
+		fmt.Printf("secret %q deleted in epoch %d\n", *id, epoch)
+	case "retrieve":
+		list := flags.String("l", "", "Enter the labels of the nodes to ask, separated by commas, all of them if not given")
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		labels, err := parseLabels(*list, *counter)
+		if err != nil {
+			log.Fatal(err)
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		secret, err := o.Secret(labels, *id)
+		if err != nil {
+			log.Fatalf("ctl failed to retrieve the secret: %v", err)
+		}
+		fmt.Println(secret.String())
+	case "transcript":
+		epoch := flags.Int64("e", -1, "Enter the epoch to print, the latest one if not given")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		if *epoch < 0 {
+			latest, err := o.Latest()
+			if err != nil {
+				log.Fatalf("ctl failed to read epoch history: %v", err)
+			}
+			*epoch = latest.GetEpoch()
+		}
+		t, err := o.Transcript(*epoch)
+		if err != nil {
+			log.Fatalf("ctl failed to read the transcript of epoch %d: %v", *epoch, err)
+		}
+		operator.WriteTranscript(os.Stdout, t)
+	case "verify-share":
+		label := flags.Int("l", 1, "Enter the label of the node to check")
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		report, err := o.VerifyShares(*label, *id)
+		if err != nil {
+			log.Fatalf("ctl failed to check node %d: %v", *label, err)
+		}
+		operator.WriteShareReport(os.Stdout, report, *counter)
+		if len(report.Bad) > 0 {
+			os.Exit(1)
+		}
+	case "sign":
+		message := flags.String("message", "", "Enter the message to sign")
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		sig, err := o.Sign(*id, []byte(*message))
+		if err != nil {
+			log.Fatalf("ctl failed to sign: %v", err)
+		}
+		operator.WriteSignature(os.Stdout, sig)
+	case "verify-signature":
+		message := flags.String("message", "", "Enter the message that was signed")
+		signature := flags.String("signature", "", "Enter the signature in hexadecimal")
+		publicKey := flags.String("public-key", "", "Enter the public key of the committee in hexadecimal")
+		flags.Parse(args)
+		sig, err := hex.DecodeString(*signature)
+		if err != nil {
+			log.Fatalf("bad -signature: %v", err)
+		}
+		pub, err := hex.DecodeString(*publicKey)
+		if err != nil {
+			log.Fatalf("bad -public-key: %v", err)
+		}
+		if err := operator.VerifySignature(pub, []byte(*message), sig); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("the signature is valid")
+	case "public-key":
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		pk, err := o.PublicKey(*id)
+		if err != nil {
+			log.Fatalf("ctl failed to derive the public key: %v", err)
+		}
+		operator.WritePublicKey(os.Stdout, pk)
+	case "encrypt":
+		message := flags.String("message", "", "Enter the message to encrypt")
+		publicKey := flags.String("public-key", "", "Enter the public key of the committee in hexadecimal, derived from the nodes if not given")
+		id := flags.String("id", "", "Enter the ID of the secret whose public key to derive, the one the committee started with if not given")
+		flags.Parse(args)
+		var pub []byte
+		if *publicKey == "" {
+			o := connect(*counter, *metadataPath)
+			pk, err := o.PublicKey(*id)
+			o.Disconnect()
+			if err != nil {
+				log.Fatalf("ctl failed to derive the public key: %v", err)
+			}
+			pub = pk.Key
+		} else {
+			var err error
+			if pub, err = hex.DecodeString(*publicKey); err != nil {
+				log.Fatalf("bad -public-key: %v", err)
+			}
+		}
+		c, err := operator.Encrypt(pub, []byte(*message))
+		if err != nil {
+			log.Fatalf("ctl failed to encrypt: %v", err)
+		}
+		fmt.Printf("%x\n", c.Bytes())
+	case "decrypt":
+		ciphertext := flags.String("ciphertext", "", "Enter the ciphertext in hexadecimal")
+		id := flags.String("id", "", "Enter the ID of the secret, the one the committee started with if not given")
+		flags.Parse(args)
+		b, err := hex.DecodeString(*ciphertext)
+		if err != nil {
+			log.Fatalf("bad -ciphertext: %v", err)
+		}
+		c, err := elgamal.ParseCiphertext(b)
+		if err != nil {
+			log.Fatalf("bad -ciphertext: %v", err)
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		dec, err := o.Decrypt(*id, c)
+		if err != nil {
+			log.Fatalf("ctl failed to decrypt: %v", err)
+		}
+		for label, reason := range dec.Bad {
+			log.Printf("left out node %d: %s", label, reason)
+		}
+		fmt.Printf("%s\n", dec.Plaintext)
+	case "deal-key":
+		value := flags.String("key", "", "Enter the key to deal, in decimal or in hexadecimal after 0x, a random one if not given")
+		flags.Parse(args)
+		var key *big.Int
+		if *value == "" {
+			var err error
+			if key, err = frost.RandomScalar(crand.Reader); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			secret, err := operator.ParseSecret(*value)
+			if err != nil {
+				log.Fatal(err)
+			}
+			key = conv.GmpInt2BigInt(secret)
+		}
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		pub, err := o.DealKey(key)
+		if err != nil {
+			log.Fatalf("ctl failed to deal the key: %v", err)
+		}
+		fmt.Printf("public key\t%x\n", pub)
+	case "schnorr-sign":
+		message := flags.String("message", "", "Enter the message to sign")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		sig, err := o.SchnorrSign([]byte(*message))
+		if err != nil {
+			log.Fatalf("ctl failed to sign: %v", err)
+		}
+		operator.WriteSchnorrSignature(os.Stdout, sig)
+	case "verify-schnorr":
+		message := flags.String("message", "", "Enter the message that was signed")
+		signature := flags.String("signature", "", "Enter the signature in hexadecimal")
+		publicKey := flags.String("public-key", "", "Enter the P-521 public key of the committee in hexadecimal")
+		flags.Parse(args)
+		sig, err := hex.DecodeString(*signature)
+		if err != nil {
+			log.Fatalf("bad -signature: %v", err)
+		}
+		pub, err := hex.DecodeString(*publicKey)
+		if err != nil {
+			log.Fatalf("bad -public-key: %v", err)
+		}
+		if err := operator.VerifySchnorr(pub, []byte(*message), sig); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("the signature is valid")
+	case "beacon":
+		round := flags.Int64("e", 0, "Enter the epoch whose round to read, the latest completed one if not given")
+		flags.Parse(args)
+		o := connect(*counter, *metadataPath)
+		defer o.Disconnect()
+		b, err := o.Beacon(*round)
+		if err != nil {
+			log.Fatalf("ctl failed to read the beacon: %v", err)
+		}
+		operator.WriteBeacon(os.Stdout, b)
+	case "verify-beacon":
+		round := flags.Int64("e", 0, "Enter the epoch of the round")
+		output := flags.String("output", "", "Enter the value of the round in hexadecimal")
+		proof := flags.String("proof", "", "Enter the proof in hexadecimal")
+		publicKey := flags.String("public-key", "", "Enter the public key of the committee in hexadecimal")
+		flags.Parse(args)
+		out, err := hex.DecodeString(*output)
+		if err != nil {
+			log.Fatalf("bad -output: %v", err)
+		}
+		p, err := hex.DecodeString(*proof)
+		if err != nil {
+			log.Fatalf("bad -proof: %v", err)
+		}
+		pub, err := hex.DecodeString(*publicKey)
+		if err != nil {
+			log.Fatalf("bad -public-key: %v", err)
+		}
+		if err := operator.VerifyBeacon(pub, *round, out, p); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("the beacon is valid")
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		global.Usage()
+		os.Exit(2)
+	}
+}
+
+func connect(counter int, metadataPath string) *operator.Operator {
+	o, err := operator.New(counter, metadataPath, transport.GRPC())
+	if err != nil {
+		log.Fatalf("ctl failed to initialize: %v", err)
+	}
+	if err := o.Connect(); err != nil {
+		log.Fatal(err)
+	}
+	return &o
+}
+
+func startEpoch(counter int, metadataPath string, epoch int64, wait bool) {
+	c, err := clock.New(counter, metadataPath, transport.GRPC())
+	if err != nil {
+		log.Fatalf("ctl failed to initialize: %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		log.Fatal(err)
+	}
+	defer c.Disconnect()
+	if epoch == 0 {
+		latest, err := c.Latest()
+		if err != nil {
+			log.Fatalf("ctl failed to read epoch history: %v", err)
+		}
+		epoch = latest.GetEpoch() + 1
+	}
+	if !wait {
+		if err := c.ClientStartEpoch(epoch); err != nil {
+			log.Fatalf("ctl failed to start epoch %d: %v", epoch, err)
+		}
+		fmt.Printf("epoch %d started\n", epoch)
+		return
+	}
+	if err := c.Run(epoch, 1, 0); err != nil {
+		log.Fatalf("epoch %d failed: %v", epoch, err)
+	}
+	fmt.Printf("epoch %d completed\n", epoch)
+}
+
+// Labels separated by commas, or every node if list is empty
+func parseLabels(list string, counter int) ([]int, error) {
+	var labels []int
+	if list == "" {
+		for i := 1; i <= counter; i++ {
+			labels = append(labels, i)
+		}
+		return labels, nil
+	}
+	for _, s := range strings.Split(list, ",") {
+		label, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || label < 1 || label > counter {
+			return nil, errors.New(fmt.Sprintf("%q is not the label of a node", s))
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+)
+
+// Keygen writes the identity keys of the nodes of a committee and the operator key to the metadata path
+func Keygen(args []string) {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	counter := flags.Int("c", 1, "Enter number of nodes")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	flags.Parse(args)
+
+	if err := identity.GenerateAll(*counter, *metadataPath); err != nil {
+		log.Fatalf("keygen failed: %v", err)
+	}
+}
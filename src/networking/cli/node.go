@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/nodes"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"github.com/bl4ck5un/ChuRP/src/utils/metrics"
+)
+
+// Node serves node -l of the committee
+func Node(args []string) {
+	flags := flag.NewFlagSet("node", flag.ExitOnError)
+	label := flags.Int("l", 1, "Enter node label")
+	counter := flags.Int("c", 1, "Enter number of nodes")
+	degree := flags.Int("d", 1, "Enter the polynomial degree")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	passFile := flags.String("passfile", "", "file holding the passphrase that encrypts the stored shares, defaults to $CHURP_PASSPHRASE")
+	aws := flags.Bool("aws", false, "if test on real aws")
+	metricsAddr := flags.String("metrics", "", "serve metrics at /metrics on this address, like :9100")
+	drain := flags.Duration("drain", 30*time.Second, "on SIGINT or SIGTERM, how long to wait for the running epoch to complete before stopping")
+	out := outputFlags(flags, true)
+	flags.Parse(args)
+	out.setup("node")
+
+	passphrase := readPassphrase(*passFile)
+	if passphrase == "" {
+		log.Print("no passphrase given, shares are kept in memory only")
+	}
+
+	n, err := nodes.New(*degree, *label, *counter, *metadataPath, []byte(passphrase), transport.GRPC())
+	if err != nil {
+		log.Fatalf("node failed to initialize: %v", err)
+	}
+	if *metricsAddr != "" {
+		go func() {
+			log.Fatalf("node failed to serve metrics: %v", metrics.Serve(*metricsAddr, n.Metrics()))
+		}()
+	}
+	stopped := stopOnSignal("node", *drain, n.Shutdown)
+	n.Serve(*aws)
+	<-stopped
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/bulletinboard"
+)
+
+// Replica serves replica -l of the content of the bulletinboard
+func Replica(args []string) {
+	flags := flag.NewFlagSet("replica", flag.ExitOnError)
+	label := flags.Int("l", 1, "Enter the replica label, its line in replica_list")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	dir := flags.String("dir", "", "keep the term, the vote and the log in files under this directory, replica<l> under the metadata path by default")
+	memory := flags.Bool("memory", false, "keep the term, the vote and the log in memory only, a restarted replica rejoins empty")
+	out := outputFlags(flags, false)
+	flags.Parse(args)
+	out.setup("replica")
+
+	if *dir == "" {
+		*dir = bulletinboard.ReplicaDir(*metadataPath, *label)
+	}
+	if *memory {
+		*dir = ""
+	}
+	replica, err := bulletinboard.NewReplica(*label, *metadataPath, *dir)
+	if err != nil {
+		log.Fatalf("replica failed to initialize: %v", err)
+	}
+	replica.Serve()
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/simnet"
+)
+
+// Sim runs a scenario on the simulated network and checks that the shares still reconstruct the genesis secret, exiting with 1 if they do not
+func Sim(args []string) {
+	flags := flag.NewFlagSet("sim", flag.ExitOnError)
+	scenarioPath := flags.String("scenario", "", "TOML file with the scenario to run, the default runs one calm epoch of four nodes")
+	seed := flags.Int64("seed", 0, "Override the seed of the scenario")
+	metadataPath := flags.String("path", "", "Enter the metadata path, a temporary directory if empty")
+	trace := flags.Bool("trace", false, "print what the simulator did")
+	flags.Parse(args)
+
+	scenario := simnet.DefaultScenario()
+	if *scenarioPath != "" {
+		var err error
+		scenario, err = simnet.ReadScenario(*scenarioPath)
+		if err != nil {
+			log.Fatalf("simulator failed to read the scenario: %v", err)
+		}
+	}
+	if *seed != 0 {
+		scenario.Seed = *seed
+	}
+	dir := *metadataPath
+	if dir == "" {
+		var err error
+		dir, err = ioutil.TempDir("", "churp-sim")
+		if err != nil {
+			log.Fatalf("simulator failed to create a directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	sim, err := simnet.New(scenario, dir)
+	if err != nil {
+		log.Fatalf("simulator failed to initialize: %v", err)
+	}
+	result, err := sim.Run()
+	sim.Stop()
+	if *trace {
+		for _, line := range result.Trace {
+			fmt.Println(line)
+		}
+	}
+	for _, msg := range result.Epochs {
+		fmt.Printf("epoch %d\t%v\n", msg.GetEpoch(), msg.GetState())
+	}
+	fmt.Printf("seed %d\t%v of virtual time\t%d events\n", scenario.Seed, result.Elapsed, len(result.Trace))
+	if err != nil {
+		log.Printf("run failed: %v", err)
+		os.Exit(1)
+	}
+	if err := sim.Committee().Verify(); err != nil {
+		log.Printf("shares are broken: %v", err)
+		os.Exit(1)
+	}
+	fmt.Println("shares reconstruct the genesis secret")
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+)
+
+// Status prints the status of the nodes and the bulletinboard, and exits with 1 if any of them does not answer
+func Status(args []string) {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	counter := flags.Int("c", 1, "Enter number of nodes")
+	label := flags.Int("l", -1, "Enter the label of the node to ask, 0 for the bulletinboard, all of them if not given")
+	metadataPath := flags.String("path", "/mpss/metadata", "Enter the metadata path")
+	asJSON := flags.Bool("json", false, "print every status as JSON")
+	flags.Parse(args)
+
+	report(*counter, *metadataPath, *label, *asJSON)
+}
+
+// Report the status of node label, or of every node and the bulletinboard if label is negative
+func report(counter int, metadataPath string, label int, asJSON bool) {
+	addrs, err := operator.ReadIpList(metadataPath)
+	if err != nil {
+		log.Fatalf("status failed to read iplist: %v", err)
+	}
+	if len(addrs) < counter+1 {
+		log.Fatalf("ip_list holds %d addresses, need %d", len(addrs), counter+1)
+	}
+	asked := addrs[:counter+1]
+	if label >= 0 {
+		asked = addrs[label : label+1]
+	}
+	if !admin.Report(os.Stdout, transport.GRPC(), asked, asJSON) {
+		os.Exit(1)
+	}
+}
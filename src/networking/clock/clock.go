@@ -2,60 +2,250 @@ package clock
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
 	pb "github.com/bl4ck5un/ChuRP/src/services"
-	"google.golang.org/grpc"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 	"io/ioutil"
-	"log"
 	"strings"
+	"sync"
+	"time"
 )
 
+// Logger of the clock
+var logger = logrus.WithField("component", "clock")
+
+// Interval at which the clock asks the bulletinboard whether the running epoch has completed
+const pollInterval = 100 * time.Millisecond
+
 // Clock Simulator Structure
 type Clock struct {
 	// Metadata Directory Path
 	metadataPath string
 	// BulltinBoard IP
 	bip string
+	// Committee ID
+	committee string
 	// BulletinBoard Service Client
-	bConn   *grpc.ClientConn
-	bClient pb.BulletinBoardServiceClient
+	transport transport.Transport
+	bConn     transport.Conn
+	bClient   pb.BulletinBoardServiceClient
+	// Closed by Stop
+	stop     chan struct{}
+	stopOnce *sync.Once
 }
 
-func (clock *Clock) Connect() {
-	bConn, err := grpc.Dial(clock.bip, grpc.WithInsecure())
+func (clock *Clock) Connect() error {
+	bConn, err := clock.transport.Dial(clock.bip)
 	if err != nil {
-		log.Fatalf("clock did not connect: %v", err)
+		return errors.New(fmt.Sprintf("clock did not connect: %v", err))
 	}
 	clock.bConn = bConn
-	clock.bClient = pb.NewBulletinBoardServiceClient(clock.bConn)
+	clock.bClient = bConn.BulletinBoard()
+	return nil
 }
 
 func (clock *Clock) Disconnect() {
 	clock.bConn.Close()
 }
 
-func (clock *Clock) ClientStartEpoch() {
-	ctx, cancel := context.WithCancel(context.Background())
+// Run starts one epoch per period, beginning with epoch first, and returns after count epochs. A count of 0 runs forever.
+// An epoch still running at the end of its period overruns it: the next epoch starts as soon as the bulletinboard reports the overrunning one completed, and the schedule continues from there.
+// A period of 0 starts every epoch as soon as the one before has completed. After Stop, Run returns once the running epoch has ended.
+// An epoch the bulletinboard fails does not stop the schedule, the committee rolls back to the shares it held before and the next epoch starts from them. Run returns an error naming the failed epochs once it is done.
+func (clock *Clock) Run(first int64, count int64, period time.Duration) error {
+	failed := make([]int64, 0)
+	for epoch := first; count == 0 || epoch < first+count; epoch++ {
+		if clock.stopped() {
+			break
+		}
+		start := time.Now()
+		deadline := start.Add(period)
+		if period == 0 {
+			// without a schedule there is nothing to overrun
+			deadline = time.Time{}
+		}
+		msg, err := clock.runEpoch(epoch, deadline)
+		if err != nil {
+			return err
+		}
+		took := time.Duration(msg.GetEnd() - msg.GetStart())
+		if msg.GetState() == pb.EpochStatusMsg_FAILED {
+			logger.WithField("epoch", epoch).Warnf("epoch failed after %v, continue with the next one", took)
+			failed = append(failed, epoch)
+		} else {
+			logger.WithField("epoch", epoch).Infof("epoch completed in %v", took)
+		}
+		if wait := time.Until(start.Add(period)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-clock.stop:
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(fmt.Sprintf("epochs %v failed", failed))
+	}
+	return nil
+}
+
+// Stop makes Run return without starting another epoch
+func (clock *Clock) Stop() {
+	clock.stopOnce.Do(func() { close(clock.stop) })
+}
+
+func (clock *Clock) stopped() bool {
+	select {
+	case <-clock.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start an epoch and wait for it to end, in a span of the trace of the epoch that records a failure as its error
+func (clock *Clock) runEpoch(epoch int64, deadline time.Time) (msg *pb.EpochStatusMsg, err error) {
+	id := trace.Epoch(clock.committee, epoch)
+	span := trace.Start(id, nil, "clock", map[string]interface{}{"component": "clock", "epoch": epoch})
+	defer func() { span.Finish(err) }()
+	start := trace.Start(id, span, "StartEpoch", map[string]interface{}{"component": "clock", "rpc": "StartEpoch", "peer": "bulletinboard"})
+	err = clock.startEpoch(epoch)
+	start.Finish(err)
+	if err != nil {
+		return nil, err
+	}
+	msg, err = clock.waitEpoch(epoch, deadline)
+	if err != nil {
+		return nil, err
+	}
+	if msg.GetState() == pb.EpochStatusMsg_FAILED {
+		span.Finish(errors.New(fmt.Sprintf("epoch %d failed", epoch)))
+	}
+	return msg, nil
+}
+
+// Start an epoch, retrying while the bulletinboard may be restarting.
+// A retry that finds the epoch already running went after a start the bulletinboard took but could not acknowledge, and the epoch counts as started.
+func (clock *Clock) startEpoch(epoch int64) error {
+	retried := false
+	err := pb.Retry(func() error {
+		err := clock.ClientStartEpoch(epoch)
+		if status.Code(err) == codes.FailedPrecondition && retried {
+			if msg, statusErr := clock.ClientEpochStatus(epoch); statusErr == nil && msg.GetEpoch() == epoch {
+				return nil
+			}
+		}
+		retried = true
+		return err
+	})
+	return err
+}
+
+// Poll the bulletinboard until the epoch is no longer running, reporting an overrun once the deadline, if any, has passed.
+func (clock *Clock) waitEpoch(epoch int64, deadline time.Time) (*pb.EpochStatusMsg, error) {
+	overrun := false
+	for {
+		// the bulletinboard may be restarting
+		var msg *pb.EpochStatusMsg
+		err := pb.Retry(func() error {
+			var err error
+			msg, err = clock.ClientEpochStatus(epoch)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetState() != pb.EpochStatusMsg_RUNNING {
+			return msg, nil
+		}
+		if !overrun && !deadline.IsZero() && time.Now().After(deadline) {
+			logger.WithField("epoch", epoch).Warn("epoch overran its period, the next epoch waits for it")
+			overrun = true
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Latest returns the most recent epoch the bulletinboard knows about
+func (clock *Clock) Latest() (*pb.EpochStatusMsg, error) {
+	history, err := clock.ClientEpochHistory()
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, errors.New("bulletinboard returned an empty epoch history")
+	}
+	return history[len(history)-1], nil
+}
+
+func (clock *Clock) ClientStartEpoch(epoch int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
+	defer cancel()
+	logger.WithField("epoch", epoch).Info("start epoch")
+	_, err := clock.bClient.StartEpoch(ctx, clock.epochMsg(epoch))
+	return err
+}
+
+func (clock *Clock) ClientEpochStatus(epoch int64) (*pb.EpochStatusMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
 	defer cancel()
-	log.Print("client start epoch")
-	_, err := clock.bClient.StartEpoch(ctx, &pb.EmptyMsg{})
+	return clock.bClient.EpochStatus(ctx, clock.epochMsg(epoch))
+}
+
+func (clock *Clock) ClientEpochHistory() ([]*pb.EpochStatusMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pb.CallTimeout)
+	defer cancel()
+	stream, err := clock.bClient.EpochHistory(ctx, clock.epochMsg(0))
 	if err != nil {
-		log.Fatalf("clock start epoch failed: %v", err)
+		return nil, err
+	}
+	history := make([]*pb.EpochStatusMsg, 0)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return history, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, msg)
+	}
+}
+
+func (clock *Clock) epochMsg(epoch int64) *pb.EpochMsg {
+	return &pb.EpochMsg{
+		Epoch:     epoch,
+		Committee: clock.committee,
 	}
 }
 
 func ReadIpList(metadataPath string) []string {
 	ipData, err := ioutil.ReadFile(metadataPath + "/ip_list")
 	if err != nil {
-		log.Fatalf("clock failed to read iplist %v\n", err)
+		logger.Fatalf("clock failed to read iplist %v", err)
 	}
 	return strings.Split(string(ipData), "\n")
 }
 
-// New returns a network node structure
-func New(metadataPath string) (Clock, error) {
+// New returns a network node structure that reaches the bulletinboard over tr
+func New(counter int, metadataPath string, tr transport.Transport) (Clock, error) {
 	bip := ReadIpList(metadataPath)[0]
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return Clock{}, err
+	}
 	return Clock{
 		metadataPath: metadataPath,
 		bip:          bip,
+		committee:    identity.CommitteeID(pks),
+		transport:    tr,
+		stop:         make(chan struct{}),
+		stopOnce:     &sync.Once{},
 	}, nil
 }
@@ -0,0 +1,341 @@
+// Package devnet runs a committee on this machine as child processes of the churp binary: it writes the metadata and keys, starts the bulletinboard and the nodes, waits until they answer, runs epochs with a clock and stops them all again.
+package devnet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+)
+
+// How long a child has to exit after it was interrupted, before it is killed
+const grace = 5 * time.Second
+
+// Interval at which a service that is not ready yet is probed again
+const probeInterval = 100 * time.Millisecond
+
+// Config of a committee on this machine
+type Config struct {
+	// The churp binary, every child runs one of its subcommands
+	Bin string
+	// Number of nodes and degree of the polynomials
+	Nodes  int
+	Degree int
+	// Directory holding the metadata, the stored shares and a file with the output of every child
+	Dir string
+	// The bulletinboard listens on BasePort, node i on BasePort+i and replica i on BasePort+Nodes+i
+	BasePort int
+	// Number of replicas keeping the content of the bulletinboard, none keeps it in memory
+	Replicas int
+	// Gas config of a simulated chain that keeps the content of the bulletinboard instead, "default" for the default gas model
+	Chain string
+	// Passphrase the nodes store their shares under
+	Passphrase string
+	// How long the services have to become ready
+	Timeout time.Duration
+	// Flags given to every child after its own, like -log-level debug
+	Flags []string
+	// Where the output of every child goes line by line, prefixed with its name
+	Log io.Writer
+}
+
+// Devnet is a committee running as child processes
+type Devnet struct {
+	config Config
+	// Address of the bulletinboard followed by those of the nodes
+	addrs []string
+	// Children in the order they started
+	mutex    sync.Mutex
+	children []*child
+	log      *lineLog
+}
+
+// A child process and the outcome of waiting on it
+type child struct {
+	name string
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// Start writes the metadata and fresh keys of a new committee to the directory of config, dropping the shares stored there, then starts the replicas, the bulletinboard and the nodes and returns once all of them are ready.
+// A node or bulletinboard is ready once it passes its readiness check, a replica once it accepts connections.
+func Start(config Config) (*Devnet, error) {
+	if config.Nodes < 2*config.Degree+1 {
+		return nil, errors.New(fmt.Sprintf("%d nodes cannot hold polynomials of degree %d, there must be at least %d", config.Nodes, config.Degree, 2*config.Degree+1))
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, err
+	}
+	d := &Devnet{config: config, log: &lineLog{out: config.Log}}
+	for i := 0; i <= config.Nodes; i++ {
+		d.addrs = append(d.addrs, fmt.Sprintf("127.0.0.1:%d", config.BasePort+i))
+	}
+	if err := ioutil.WriteFile(filepath.Join(config.Dir, "ip_list"), []byte(strings.Join(d.addrs, "\n")+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	// new keys start a new committee, the shares of the old one are useless to it
+	if err := identity.GenerateAll(config.Nodes, config.Dir); err != nil {
+		return nil, err
+	}
+	stale, err := filepath.Glob(filepath.Join(config.Dir, "share*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	// and so is the log the replicas of the old one stored
+	replicaDirs, err := filepath.Glob(filepath.Join(config.Dir, "replica[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range replicaDirs {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if err := os.RemoveAll(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	deadline := time.Now().Add(config.Timeout)
+	n := strconv.Itoa(config.Nodes)
+	t := strconv.Itoa(config.Degree)
+	boardArgs := []string{"-c", n, "-d", t, "-path", config.Dir}
+	if config.Replicas > 0 {
+		replicas := make([]string, config.Replicas)
+		for i := range replicas {
+			replicas[i] = fmt.Sprintf("127.0.0.1:%d", config.BasePort+config.Nodes+i+1)
+		}
+		if err := ioutil.WriteFile(filepath.Join(config.Dir, "replica_list"), []byte(strings.Join(replicas, "\n")+"\n"), 0644); err != nil {
+			return nil, err
+		}
+		for i := range replicas {
+			if _, err := d.start(fmt.Sprintf("replica%d", i+1), "replica", "-l", strconv.Itoa(i+1), "-path", config.Dir); err != nil {
+				d.Stop()
+				return nil, err
+			}
+		}
+		for i, addr := range replicas {
+			if err := d.waitReady(d.children[i], deadline, listening(addr)); err != nil {
+				d.Stop()
+				return nil, err
+			}
+		}
+		boardArgs = append(boardArgs, "-r")
+	}
+	if config.Chain != "" {
+		boardArgs = append(boardArgs, "-chain")
+		if config.Chain != "default" {
+			boardArgs = append(boardArgs, "-gas", config.Chain)
+		}
+	}
+
+	// the nodes connect to the bulletinboard as they start, so it must be up first
+	board, err := d.start("bulletinboard", "board", boardArgs...)
+	if err == nil {
+		err = d.waitReady(board, deadline, ready(d.addrs[0]))
+	}
+	if err != nil {
+		d.Stop()
+		return nil, err
+	}
+	nodes := make([]*child, config.Nodes)
+	for i := range nodes {
+		nodes[i], err = d.start(fmt.Sprintf("node%d", i+1), "node", "-l", strconv.Itoa(i+1), "-c", n, "-d", t, "-path", config.Dir)
+		if err != nil {
+			d.Stop()
+			return nil, err
+		}
+	}
+	for i, node := range nodes {
+		if err := d.waitReady(node, deadline, ready(d.addrs[i+1])); err != nil {
+			d.Stop()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Run starts a clock that runs count epochs after the latest one, one period apart, and returns once it is done. A count of 0 runs until the devnet is stopped.
+func (d *Devnet) Run(count int64, period time.Duration) error {
+	clock, err := d.start("clock", "clock", "-c", strconv.Itoa(d.config.Nodes), "-n", strconv.FormatInt(count, 10), "-t", period.String(), "-path", d.config.Dir)
+	if err != nil {
+		return err
+	}
+	<-clock.done
+	if clock.err != nil {
+		return errors.New(fmt.Sprintf("clock failed: %v", clock.err))
+	}
+	return nil
+}
+
+// Addrs returns the address of the bulletinboard followed by those of the nodes
+func (d *Devnet) Addrs() []string {
+	return d.addrs
+}
+
+// Stop interrupts the children in the reverse order they started and waits for them to exit, killing those still running after a grace period
+func (d *Devnet) Stop() {
+	d.mutex.Lock()
+	children := d.children
+	d.children = nil
+	d.mutex.Unlock()
+	for i := len(children) - 1; i >= 0; i-- {
+		c := children[i]
+		select {
+		case <-c.done:
+			continue
+		default:
+		}
+		c.cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-c.done:
+		case <-time.After(grace):
+			c.cmd.Process.Kill()
+			<-c.done
+		}
+	}
+	d.log.flush()
+}
+
+// Start the churp subcommand command as the child name. Its output goes to the file name.out in the directory and to the log of the devnet.
+func (d *Devnet) start(name string, command string, args ...string) (*child, error) {
+	f, err := os.Create(filepath.Join(d.config.Dir, name+".out"))
+	if err != nil {
+		return nil, err
+	}
+	args = append(append([]string{command}, args...), d.config.Flags...)
+	cmd := exec.Command(d.config.Bin, args...)
+	cmd.Env = append(os.Environ(), "CHURP_PASSPHRASE="+d.config.Passphrase)
+	out := io.Writer(f)
+	if d.config.Log != nil {
+		out = io.MultiWriter(f, d.log.prefixed(name))
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// an interrupt from the terminal reaches only the devnet, which stops the children in order
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		f.Close()
+		return nil, errors.New(fmt.Sprintf("cannot start %s: %v", name, err))
+	}
+	c := &child{name: name, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		c.err = cmd.Wait()
+		f.Close()
+		close(c.done)
+	}()
+	d.mutex.Lock()
+	d.children = append(d.children, c)
+	d.mutex.Unlock()
+	return c, nil
+}
+
+// Probe the child until ready succeeds, failing if the child exits first or the deadline passes
+func (d *Devnet) waitReady(c *child, deadline time.Time, ready func() error) error {
+	for {
+		err := ready()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-c.done:
+			return errors.New(fmt.Sprintf("%s exited before it was ready: %v", c.name, c.err))
+		default:
+		}
+		if time.Now().After(deadline) {
+			return errors.New(fmt.Sprintf("%s is not ready after %v: %v", c.name, d.config.Timeout, err))
+		}
+		time.Sleep(probeInterval)
+	}
+}
+
+// The node or bulletinboard at addr is ready
+func ready(addr string) func() error {
+	return func() error {
+		return admin.Ready(transport.GRPC(), addr)
+	}
+}
+
+// Something accepts connections at addr
+func listening(addr string) func() error {
+	return func() error {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// The combined output of the children, written a whole line at a time so the lines of different children do not interleave
+type lineLog struct {
+	mutex   sync.Mutex
+	out     io.Writer
+	writers []*prefixWriter
+}
+
+func (l *lineLog) prefixed(name string) *prefixWriter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	w := &prefixWriter{log: l, prefix: name + " | "}
+	l.writers = append(l.writers, w)
+	return w
+}
+
+// Write out what is left of lines that did not end in a newline
+func (l *lineLog) flush() {
+	if l.out == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, w := range l.writers {
+		if w.partial.Len() > 0 {
+			fmt.Fprintf(l.out, "%s%s\n", w.prefix, w.partial.Bytes())
+			w.partial.Reset()
+		}
+	}
+}
+
+type prefixWriter struct {
+	log     *lineLog
+	prefix  string
+	partial bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.log.mutex.Lock()
+	defer w.log.mutex.Unlock()
+	w.partial.Write(p)
+	for {
+		line, err := w.partial.ReadBytes('\n')
+		if err != nil {
+			// keep the incomplete line for the next write
+			rest := append([]byte(nil), line...)
+			w.partial.Reset()
+			w.partial.Write(rest)
+			return len(p), nil
+		}
+		if _, err := fmt.Fprintf(w.log.out, "%s%s", w.prefix, line); err != nil {
+			return len(p), err
+		}
+	}
+}
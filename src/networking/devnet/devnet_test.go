@@ -0,0 +1,131 @@
+package devnet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/cli"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+// Set in the children the tests start, which then run a churp subcommand instead of the tests
+const childEnv = "CHURP_DEVNET_CHILD"
+
+// The test binary stands in for the churp binary
+func TestMain(m *testing.M) {
+	if os.Getenv(childEnv) != "" && len(os.Args) > 1 {
+		run, ok := cli.Commands[os.Args[1]]
+		if !ok {
+			os.Exit(2)
+		}
+		run(os.Args[2:])
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func config(t *testing.T, dir string, port int) Config {
+	bin, err := os.Executable()
+	assert.Nil(t, err)
+	os.Setenv(childEnv, "1")
+	return Config{
+		Bin:        bin,
+		Nodes:      3,
+		Degree:     1,
+		Dir:        dir,
+		BasePort:   port,
+		Passphrase: "devnet-test",
+		Timeout:    30 * time.Second,
+	}
+}
+
+// A buffer the children may write to at the same time as the test reads it
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestDevnet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devnet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var out syncBuffer
+	c := config(t, dir, 13300)
+	c.Log = &out
+	d, err := Start(c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer d.Stop()
+	assert.Len(t, d.Addrs(), 4)
+
+	assert.Nil(t, d.Run(2, 0))
+	msg, err := admin.Query(transport.GRPC(), d.Addrs()[0])
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(2), msg.GetCompleted())
+	}
+	d.Stop()
+
+	// every line of the combined log names the process it came from
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.NotEmpty(t, lines)
+	for _, line := range lines {
+		assert.Regexp(t, `^(bulletinboard|node[1-3]|clock) \| `, line)
+	}
+	assert.Contains(t, out.String(), "clock | ")
+	for _, name := range []string{"bulletinboard", "node1", "node2", "node3", "clock"} {
+		data, err := ioutil.ReadFile(dir + "/" + name + ".out")
+		assert.Nil(t, err)
+		assert.NotEmpty(t, data, name)
+	}
+
+	// the processes are gone and their ports are free again
+	_, err = admin.Query(transport.GRPC(), d.Addrs()[1])
+	assert.NotNil(t, err)
+}
+
+func TestDevnetChildExits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devnet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// a board without its -c flag value fails to parse its flags and exits right away
+	c := config(t, dir, 13320)
+	c.Flags = []string{"-c"}
+	_, err = Start(c)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "bulletinboard exited before it was ready")
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	log := &lineLog{out: &out}
+	a := log.prefixed("a")
+	b := log.prefixed("b")
+	a.Write([]byte("one\ntw"))
+	b.Write([]byte("three\n"))
+	a.Write([]byte("o\nfour"))
+	log.flush()
+	assert.Equal(t, "a | one\nb | three\na | two\na | four\n", out.String())
+}
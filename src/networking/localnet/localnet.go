@@ -0,0 +1,283 @@
+package localnet
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/bulletinboard"
+	"github.com/bl4ck5un/ChuRP/src/networking/clock"
+	"github.com/bl4ck5un/ChuRP/src/networking/nodes"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"github.com/ncw/gmp"
+)
+
+// The nodes of a local committee store their shares under this passphrase, so that their shares can be read back
+const passphrase = "localnet"
+
+// Addresses of the bulletinboard and the clock. They are also the names of their endpoints.
+const (
+	BoardAddr = "bulletinboard"
+	ClockName = "clock"
+)
+
+// NodeAddr is the address of node label, also the name of its endpoint
+func NodeAddr(label int) string {
+	return fmt.Sprintf("node%d", label)
+}
+
+// Network gives every member of a committee the transport it talks over
+type Network interface {
+	Endpoint(name string) transport.Transport
+}
+
+// Committee is a committee, its bulletinboard and the clock, all in one process
+type Committee struct {
+	Degree  int
+	Counter int
+	// Metadata Directory Path, holding the keys, the ip_list and the stored shares
+	Dir     string
+	Network Network
+	Board   *bulletinboard.BulletinBoard
+	Nodes   []*nodes.Node
+	Clock   clock.Clock
+
+	backend bulletinboard.Backend
+	// Closes the network, if the committee owns it
+	close func()
+	// Dir is removed on Stop, the committee made it
+	temp bool
+	// Operators handed out by Operator, disconnected on Stop
+	operators []*operator.Operator
+}
+
+// Start sets up a committee of counter nodes that share a secret with polynomials of the given degree in dir, and serves it over a local transport
+func Start(degree int, counter int, dir string) (*Committee, error) {
+	tr := transport.NewLocal()
+	c, err := StartOn(tr, degree, counter, dir)
+	if err != nil {
+		tr.Close()
+		return nil, err
+	}
+	c.close = tr.Close
+	return c, nil
+}
+
+// StartTemp is Start in a temporary directory of its own, which Stop removes
+func StartTemp(degree int, counter int) (*Committee, error) {
+	return startTemp(degree, counter, Start)
+}
+
+// StartTempOn is StartOn in a temporary directory of its own, which Stop removes
+func StartTempOn(network Network, degree int, counter int) (*Committee, error) {
+	return startTemp(degree, counter, func(degree int, counter int, dir string) (*Committee, error) {
+		return StartOn(network, degree, counter, dir)
+	})
+}
+
+func startTemp(degree int, counter int, start func(degree int, counter int, dir string) (*Committee, error)) (*Committee, error) {
+	dir, err := ioutil.TempDir("", "localnet")
+	if err != nil {
+		return nil, err
+	}
+	c, err := start(degree, counter, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	c.temp = true
+	return c, nil
+}
+
+// StartOn is Start over the given network
+func StartOn(network Network, degree int, counter int, dir string) (*Committee, error) {
+	if degree < 1 || degree >= counter {
+		return nil, errors.New(fmt.Sprintf("degree must be between 1 and %d, got %d", counter-1, degree))
+	}
+	addrs := []string{BoardAddr}
+	for i := 1; i <= counter; i++ {
+		addrs = append(addrs, NodeAddr(i))
+	}
+	if err := ioutil.WriteFile(dir+"/ip_list", []byte(strings.Join(addrs, "\n")+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := identity.GenerateAll(counter, dir); err != nil {
+		return nil, err
+	}
+
+	backend := bulletinboard.NewMemory()
+	board, err := bulletinboard.New(degree, counter, dir, backend, network.Endpoint(BoardAddr))
+	if err != nil {
+		return nil, err
+	}
+	committee := &Committee{
+		Degree:  degree,
+		Counter: counter,
+		Dir:     dir,
+		Network: network,
+		Board:   &board,
+		Nodes:   make([]*nodes.Node, counter),
+		backend: backend,
+	}
+	for i := 0; i < counter; i++ {
+		committee.Nodes[i], err = committee.newNode(i + 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	committee.Clock, err = clock.New(counter, dir, network.Endpoint(ClockName))
+	if err != nil {
+		return nil, err
+	}
+
+	go committee.Board.Serve(false)
+	for _, node := range committee.Nodes {
+		go node.Serve(false)
+	}
+	if err := committee.Clock.Connect(); err != nil {
+		return nil, err
+	}
+	// the clock is the first to call, it waits until the bulletinboard serves
+	err = pb.Retry(func() error {
+		_, err := committee.Clock.Latest()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return committee, nil
+}
+
+func (c *Committee) newNode(label int) (*nodes.Node, error) {
+	node, err := nodes.New(c.Degree, label, c.Counter, c.Dir, []byte(passphrase), c.Network.Endpoint(NodeAddr(label)))
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// Restart replaces node label with a new one that resumes from the shares and the log the old one stored, as after a crash.
+// The old node must no longer serve, it is left to the garbage collector.
+func (c *Committee) Restart(label int) error {
+	node, err := c.newNode(label)
+	if err != nil {
+		return err
+	}
+	c.Nodes[label-1] = node
+	go node.Serve(false)
+	return nil
+}
+
+// Operator returns an operator of the committee connected over tr, which Stop disconnects
+func (c *Committee) Operator(tr transport.Transport) (*operator.Operator, error) {
+	op, err := operator.New(c.Counter, c.Dir, tr)
+	if err != nil {
+		return nil, err
+	}
+	if err := op.Connect(); err != nil {
+		op.Disconnect()
+		return nil, err
+	}
+	c.operators = append(c.operators, &op)
+	return &op, nil
+}
+
+// Run runs count epochs after the latest one, each starting as soon as the one before has completed
+func (c *Committee) Run(count int64) error {
+	latest, err := c.Clock.Latest()
+	if err != nil {
+		return err
+	}
+	return c.Clock.Run(latest.GetEpoch()+1, count, 0)
+}
+
+// Shares returns the shares node label stored at the end of its latest epoch
+func (c *Committee) Shares(label int) (*sharestore.State, error) {
+	store, err := sharestore.Open(sharestore.Path(c.Dir, label), []byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	return store.Load()
+}
+
+// Secret reconstructs the secret from the stored shares of the nodes in labels, which must be more than the degree and all at the same epoch
+func (c *Committee) Secret(labels []int) (*gmp.Int, error) {
+	if len(labels) <= c.Degree {
+		return nil, errors.New(fmt.Sprintf("need more than %d shares, got %d", c.Degree, len(labels)))
+	}
+	shares := make([]*pb.SharesMsg, len(labels))
+	var epoch int64
+	for j, label := range labels {
+		state, err := c.Shares(label)
+		if err != nil {
+			return nil, err
+		}
+		if j > 0 && state.Epoch != epoch {
+			return nil, errors.New(fmt.Sprintf("node %d stored epoch %d, node %d stored epoch %d", labels[0], epoch, label, state.Epoch))
+		}
+		epoch = state.Epoch
+		shares[j] = &pb.SharesMsg{Index: int32(label), Epoch: state.Epoch}
+		for _, point := range state.Shares {
+			shares[j].Shares = append(shares[j].Shares, &pb.PointMsg{X: point.X, Y: point.Y})
+		}
+	}
+	return operator.Reconstruct(c.Degree, c.Counter, shares, modulus())
+}
+
+// Verify checks that the stored shares of any degree+1 consecutive nodes reconstruct the genesis secret
+func (c *Committee) Verify() error {
+	genesis := GenesisSecret(c.Degree)
+	for first := 1; first+c.Degree <= c.Counter; first++ {
+		labels := make([]int, 0, c.Degree+1)
+		for label := first; label <= first+c.Degree; label++ {
+			labels = append(labels, label)
+		}
+		secret, err := c.Secret(labels)
+		if err != nil {
+			return err
+		}
+		if secret.Cmp(genesis) != 0 {
+			return errors.New(fmt.Sprintf("nodes %v reconstruct a secret other than the genesis one", labels))
+		}
+	}
+	return nil
+}
+
+// GenesisSecret is the secret the nodes and the bulletinboard draw for genesis from their fixed seed
+func GenesisSecret(degree int) *gmp.Int {
+	p := modulus()
+	poly, _ := polyring.NewRand(degree, rand.New(rand.NewSource(int64(3))), p)
+	secret := gmp.NewInt(0)
+	poly.EvalMod(gmp.NewInt(0), p, secret)
+	return secret
+}
+
+// Stop closes the bulletinboard, and the local transport of a committee from Start. Stored shares stay in the directory, unless the committee made it.
+func (c *Committee) Stop() {
+	for _, op := range c.operators {
+		op.Disconnect()
+	}
+	c.Clock.Disconnect()
+	if c.close != nil {
+		c.close()
+	}
+	c.backend.Close()
+	if c.temp {
+		os.RemoveAll(c.Dir)
+	}
+}
+
+// The prime the shares live in, as in the nodes
+func modulus() *gmp.Int {
+	p := gmp.NewInt(0)
+	p.SetString("57896044618658097711785492504343953926634992332820282019728792006155588075521", 10)
+	return p
+}
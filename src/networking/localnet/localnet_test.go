@@ -0,0 +1,106 @@
+package localnet
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEpochsKeepSecret(t *testing.T) {
+	for _, c := range []struct{ counter, degree int }{
+		{2, 1},
+		{3, 1},
+		{4, 1},
+		{5, 2},
+		{7, 2},
+		{7, 3},
+	} {
+		t.Run(fmt.Sprintf("n=%d,t=%d", c.counter, c.degree), func(t *testing.T) {
+			if testing.Short() && c.counter > 4 {
+				t.Skip("large committee")
+			}
+			committee, err := StartTemp(c.degree, c.counter)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer committee.Stop()
+
+			genesis := GenesisSecret(c.degree)
+			var shares []byte
+			for epoch := int64(1); epoch <= 3; epoch++ {
+				assert.Nil(t, committee.Run(1))
+				state, err := committee.Shares(1)
+				if !assert.Nil(t, err) {
+					return
+				}
+				assert.Equal(t, epoch, state.Epoch)
+				// the shares are refreshed in every epoch
+				assert.NotEqual(t, shares, state.Shares[0].Y)
+				shares = state.Shares[0].Y
+
+				// any degree+1 nodes reconstruct the genesis secret
+				for first := 1; first+c.degree <= c.counter; first++ {
+					labels := make([]int, 0)
+					for label := first; label <= first+c.degree; label++ {
+						labels = append(labels, label)
+					}
+					secret, err := committee.Secret(labels)
+					assert.Nil(t, err)
+					assert.Equal(t, 0, genesis.Cmp(secret), "epoch %d, nodes %v", epoch, labels)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretNeedsEnoughShares(t *testing.T) {
+	committee, err := StartTemp(2, 5)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	assert.Nil(t, committee.Run(1))
+	assert.Nil(t, committee.Verify())
+	_, err = committee.Secret([]int{1, 2})
+	assert.NotNil(t, err)
+}
+
+// The clock goes on with an epoch the bulletinboard started but could not acknowledge, as when it restarts right after the start
+func TestClockRetriesStart(t *testing.T) {
+	lost := 0
+	drop := &Tamper{StartEpoch: func(in *pb.EpochMsg, out *pb.AckMsg) error {
+		if in.GetEpoch() == 2 && lost == 0 {
+			lost++
+			return status.Error(codes.Unavailable, "connection lost")
+		}
+		return nil
+	}}
+	tr := transport.NewLocal()
+	defer tr.Close()
+	committee, err := StartTempOn(drop.Network(tr, ClockName), 1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	assert.Nil(t, committee.Run(3))
+	assert.Equal(t, 1, lost)
+	latest, err := committee.Clock.Latest()
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(3), latest.GetEpoch())
+		assert.Equal(t, pb.EpochStatusMsg_COMPLETED, latest.GetState())
+	}
+	assert.Nil(t, committee.Verify())
+}
+
+func TestStartChecksDegree(t *testing.T) {
+	_, err := Start(3, 3, os.TempDir())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,167 @@
+package localnet
+
+import (
+	"context"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc"
+)
+
+// Tamper changes what node Label, or the bulletinboard for Label 0, answers to whoever dials it through a tampered transport, as a node that lies about its shares.
+// A hook is called with the request and the answer of the node once the call succeeded, and changes the answer in place or returns the error to answer with instead. Calls without a hook are passed on.
+type Tamper struct {
+	Label int
+
+	SignShare        func(in *pb.SignRequestMsg, out *pb.PartialSigMsg) error
+	DecryptShare     func(in *pb.DecryptRequestMsg, out *pb.PartialDecryptionMsg) error
+	SignSchnorr      func(in *pb.SchnorrRequestMsg, out *pb.SchnorrShareMsg) error
+	StartVerifPhase3 func(in *pb.EpochMsg, out *pb.AckMsg) error
+	StartEpoch       func(in *pb.EpochMsg, out *pb.AckMsg) error
+}
+
+// Transport returns tr with the answers of node Label tampered with
+func (t *Tamper) Transport(tr transport.Transport) transport.Transport {
+	return tamperTransport{Transport: tr, tamper: t}
+}
+
+// Network returns network on which endpoint name, and only it, gets the answers of node Label tampered with
+func (t *Tamper) Network(network Network, name string) Network {
+	return tamperNetwork{network: network, name: name, tamper: t}
+}
+
+type tamperNetwork struct {
+	network Network
+	name    string
+	tamper  *Tamper
+}
+
+func (n tamperNetwork) Endpoint(name string) transport.Transport {
+	tr := n.network.Endpoint(name)
+	if name != n.name {
+		return tr
+	}
+	return n.tamper.Transport(tr)
+}
+
+type tamperTransport struct {
+	transport.Transport
+	tamper *Tamper
+}
+
+func (t tamperTransport) Dial(addr string) (transport.Conn, error) {
+	conn, err := t.Transport.Dial(addr)
+	target := BoardAddr
+	if t.tamper.Label > 0 {
+		target = NodeAddr(t.tamper.Label)
+	}
+	if err != nil || addr != target {
+		return conn, err
+	}
+	return tamperConn{Conn: conn, tamper: t.tamper}, nil
+}
+
+type tamperConn struct {
+	transport.Conn
+	tamper *Tamper
+}
+
+func (c tamperConn) Node() pb.NodeServiceClient {
+	return tamperNodeClient{c.Conn.Node(), c.tamper}
+}
+
+func (c tamperConn) BulletinBoard() pb.BulletinBoardServiceClient {
+	return tamperBoardClient{c.Conn.BulletinBoard(), c.tamper}
+}
+
+func (c tamperConn) Sign() pb.SignServiceClient {
+	return tamperSignClient{c.Conn.Sign(), c.tamper}
+}
+
+func (c tamperConn) Decrypt() pb.DecryptServiceClient {
+	return tamperDecryptClient{c.Conn.Decrypt(), c.tamper}
+}
+
+func (c tamperConn) Schnorr() pb.SchnorrServiceClient {
+	return tamperSchnorrClient{c.Conn.Schnorr(), c.tamper}
+}
+
+type tamperNodeClient struct {
+	pb.NodeServiceClient
+	tamper *Tamper
+}
+
+func (c tamperNodeClient) StartVerifPhase3(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.NodeServiceClient.StartVerifPhase3(ctx, in, opts...)
+	if err != nil || c.tamper.StartVerifPhase3 == nil {
+		return out, err
+	}
+	if err := c.tamper.StartVerifPhase3(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type tamperBoardClient struct {
+	pb.BulletinBoardServiceClient
+	tamper *Tamper
+}
+
+func (c tamperBoardClient) StartEpoch(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.BulletinBoardServiceClient.StartEpoch(ctx, in, opts...)
+	if err != nil || c.tamper.StartEpoch == nil {
+		return out, err
+	}
+	if err := c.tamper.StartEpoch(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type tamperSignClient struct {
+	pb.SignServiceClient
+	tamper *Tamper
+}
+
+func (c tamperSignClient) SignShare(ctx context.Context, in *pb.SignRequestMsg, opts ...grpc.CallOption) (*pb.PartialSigMsg, error) {
+	out, err := c.SignServiceClient.SignShare(ctx, in, opts...)
+	if err != nil || c.tamper.SignShare == nil {
+		return out, err
+	}
+	if err := c.tamper.SignShare(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type tamperDecryptClient struct {
+	pb.DecryptServiceClient
+	tamper *Tamper
+}
+
+func (c tamperDecryptClient) DecryptShare(ctx context.Context, in *pb.DecryptRequestMsg, opts ...grpc.CallOption) (*pb.PartialDecryptionMsg, error) {
+	out, err := c.DecryptServiceClient.DecryptShare(ctx, in, opts...)
+	if err != nil || c.tamper.DecryptShare == nil {
+		return out, err
+	}
+	if err := c.tamper.DecryptShare(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type tamperSchnorrClient struct {
+	pb.SchnorrServiceClient
+	tamper *Tamper
+}
+
+func (c tamperSchnorrClient) SignSchnorr(ctx context.Context, in *pb.SchnorrRequestMsg, opts ...grpc.CallOption) (*pb.SchnorrShareMsg, error) {
+	out, err := c.SchnorrServiceClient.SignSchnorr(ctx, in, opts...)
+	if err != nil || c.tamper.SignSchnorr == nil {
+		return out, err
+	}
+	if err := c.tamper.SignSchnorr(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package nodes
+
+import (
+	"context"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+)
+
+// Status
+// Report where the node is in the current epoch, for operators. The counts add up over the secrets, and a peer is waited on while the message of any secret is missing. The node answers even while it replays its log.
+func (node *Node) Status(ctx context.Context, in *pb.StatusRequestMsg) (*pb.StatusMsg, error) {
+	notReady := node.notReady()
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	status := &pb.NodeStatusMsg{
+		Phase:      node.metrics.phase,
+		Recovering: *node.recovering,
+		Waiting:    make([]int32, 0),
+		PolyCmts:   make([][]byte, node.counter),
+		Timings:    node.metrics.timings,
+		Faults:     int32(len(node.faults[*node.epoch])),
+	}
+	waiting := make([]bool, node.counter)
+	for _, s := range node.secrets {
+		status.RecCnt += int32(s.recCnt)
+		status.ZeroCnt += int32(s.zeroCnt)
+		status.ShareCnt += int32(s.shareCnt)
+		var recv []bool
+		switch node.metrics.phase {
+		case phaseReconstruction:
+			recv = s.recvPoint1
+		case phaseProactivization:
+			recv = s.recvZero
+		case phaseShareDist:
+			recv = s.recvPoint3
+		}
+		for i, ok := range recv {
+			if !ok {
+				waiting[i] = true
+			}
+		}
+		// the commitments shown are the ones of the secret the committee started with
+		if s.id == "" {
+			for i := 0; i < node.counter; i++ {
+				status.PolyCmts[i] = s.oldPolyCmt[i].CompressedBytes()
+			}
+		}
+	}
+	for i, wait := range waiting {
+		if wait {
+			status.Waiting = append(status.Waiting, int32(i+1))
+		}
+	}
+	conns := []*pb.ConnStatusMsg{connStatus(peerBoard, node.bip, node.bConn, node.health[peerBoard])}
+	for i := 0; i < node.counter; i++ {
+		if i != node.label-1 {
+			peer := node.peer(int32(i + 1))
+			conns = append(conns, connStatus(peer, node.ipList[i], node.nConn[i], node.health[peer]))
+		}
+	}
+	return &pb.StatusMsg{
+		Label:     int32(node.label),
+		Committee: node.committee,
+		Counter:   int32(node.counter),
+		Degree:    int32(node.degree),
+		Epoch:     *node.epoch,
+		Completed: *node.completed,
+		Conns:     conns,
+		Node:      status,
+		Ready:     notReady == "",
+		NotReady:  notReady,
+	}, nil
+}
+
+func connStatus(peer string, addr string, conn transport.Conn, health *transport.Health) *pb.ConnStatusMsg {
+	state := transport.NotDialed
+	if conn != nil {
+		state = conn.State()
+	}
+	msg := &pb.ConnStatusMsg{
+		Peer:    peer,
+		Address: addr,
+		State:   state,
+	}
+	health.Report(msg)
+	return msg
+}
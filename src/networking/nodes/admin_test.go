@@ -0,0 +1,110 @@
+package nodes_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(1))
+
+	for label := 1; label <= 3; label++ {
+		msg, err := admin.Query(committee.Network.Endpoint(localnet.ClockName), localnet.NodeAddr(label))
+		if !assert.Nil(t, err) {
+			return
+		}
+		node := msg.GetNode()
+		assert.Equal(t, int32(label), msg.GetLabel())
+		assert.Equal(t, int64(1), msg.GetEpoch())
+		assert.Equal(t, int64(1), msg.GetCompleted())
+		assert.Equal(t, "idle", node.GetPhase())
+		assert.Empty(t, node.GetWaiting())
+		assert.Equal(t, int32(0), node.GetFaults())
+		assert.Len(t, node.GetPolyCmts(), 3)
+		assert.Len(t, node.GetTimings(), 4)
+		for _, timing := range node.GetTimings() {
+			assert.True(t, timing.GetTook() > 0, timing.GetPhase())
+		}
+		assert.True(t, msg.GetReady())
+		assert.Empty(t, msg.GetNotReady())
+		// a node dials the bulletinboard and its two peers
+		assert.Len(t, msg.GetConns(), 3)
+		for _, conn := range msg.GetConns() {
+			assert.Equal(t, "READY", conn.GetState(), conn.GetPeer())
+			assert.False(t, conn.GetDown(), conn.GetPeer())
+			assert.Equal(t, int32(0), conn.GetFailures(), conn.GetPeer())
+			assert.NotZero(t, conn.GetLastOk(), conn.GetPeer())
+		}
+	}
+
+	msg, err := admin.Query(committee.Network.Endpoint(localnet.ClockName), localnet.BoardAddr)
+	if !assert.Nil(t, err) {
+		return
+	}
+	board := msg.GetBoard()
+	assert.Equal(t, int64(1), msg.GetCompleted())
+	assert.Equal(t, pb.EpochStatusMsg_COMPLETED, board.GetStatus().GetState())
+	assert.Len(t, board.GetPhases(), 2)
+	for _, phase := range board.GetPhases() {
+		assert.Equal(t, []int32{1, 2, 3}, phase.GetWritten())
+	}
+
+	var out bytes.Buffer
+	admin.WriteText(&out, msg)
+	assert.Contains(t, out.String(), "ready\tyes\n")
+	assert.Contains(t, out.String(), "state\tcompleted\n")
+	assert.Contains(t, out.String(), "written\tphase 3 by 3/3: node1 node2 node3\n")
+}
+
+func TestShutdown(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	tr := committee.Network.Endpoint(localnet.ClockName)
+	for _, addr := range []string{localnet.BoardAddr, localnet.NodeAddr(1), localnet.NodeAddr(2), localnet.NodeAddr(3)} {
+		assert.Nil(t, admin.Ready(tr, addr), addr)
+	}
+
+	// a node told to stop in the middle of an epoch sees it through first
+	assert.Nil(t, committee.Clock.ClientStartEpoch(1))
+	for i := 0; i < 100; i++ {
+		msg, err := admin.Query(tr, localnet.NodeAddr(1))
+		if err == nil && msg.GetEpoch() == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	assert.Nil(t, committee.Nodes[0].Shutdown(ctx))
+	state, err := committee.Shares(1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(1), state.Epoch)
+	}
+	_, err = admin.Query(tr, localnet.NodeAddr(1))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the bulletinboard drains too, then refuses the next epoch
+	assert.Nil(t, committee.Board.Shutdown(ctx))
+	msg, err := committee.Clock.ClientEpochStatus(1)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Nil(t, msg)
+	assert.NotNil(t, committee.Clock.ClientStartEpoch(2))
+	assert.NotNil(t, admin.Ready(tr, localnet.BoardAddr))
+}
@@ -0,0 +1,81 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Phases of the bulletinboard log that hold commitments
+const (
+	logProactivization int32 = 2
+	logShareDist       int32 = 3
+)
+
+// Audit returns the Merkle roots and the last entry of the bulletinboard log of an epoch as this node read them
+func (node *Node) Audit(ctx context.Context, in *pb.EpochMsg) (*pb.AuditMsg, error) {
+	if in.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), node.committee)
+	}
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	msg, ok := node.audits[in.GetEpoch()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "node %d has not read the log of epoch %d", node.label, in.GetEpoch())
+	}
+	return proto.Clone(msg).(*pb.AuditMsg), nil
+}
+
+// Check the inclusion proofs of the commitments read from the bulletinboard in a phase of an epoch.
+// All of them must lead to the same root, and to the root this node saw for that phase before, otherwise the bulletinboard shows different logs.
+func (node *Node) verifyLog(epoch int64, phase int32, msgs []pb.Committed) error {
+	var root []byte
+	var size int32
+	var head *pb.EntryMsg
+	for _, msg := range msgs {
+		if err := pb.VerifyInclusion(msg, phase); err != nil {
+			return err
+		}
+		inclusion := msg.GetInclusion()
+		if root == nil {
+			root = inclusion.GetRoot()
+			size = inclusion.GetSize()
+		} else if !bytes.Equal(root, inclusion.GetRoot()) || size != inclusion.GetSize() {
+			return errors.New(fmt.Sprintf("commitments of phase %d of epoch %d are proven against different roots", phase, epoch))
+		}
+		if entry := pb.LogEntry(msg, phase); head == nil || entry.GetSeq() > head.GetSeq() {
+			head = entry
+		}
+	}
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	audit, ok := node.audits[epoch]
+	if !ok {
+		audit = &pb.AuditMsg{
+			Epoch:     epoch,
+			Committee: node.committee,
+		}
+		node.audits[epoch] = audit
+	}
+	seen := &audit.ProactivizationRoot
+	if phase == logShareDist {
+		seen = &audit.Root
+	}
+	if *seen != nil && !bytes.Equal(*seen, root) {
+		return errors.New(fmt.Sprintf("root of phase %d of epoch %d differs from the one read before", phase, epoch))
+	}
+	*seen = root
+	// every secret has its own entries in the log, the last entry is the latest of all read
+	if phase == logShareDist && (audit.Head == nil || head.GetSeq() > audit.Seq) {
+		audit.Size = size
+		audit.Seq = head.GetSeq()
+		audit.Head = head.GetHash()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package nodes
+
+import (
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/beacon"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Evaluate the beacon of the round of the epoch the node just completed with its new share of the beacon secret, and post the evaluation to the bulletinboard along with the public share it verifies under.
+// The evaluation is made right away, before the next epoch can change the share, and sent in the background so the epoch does not wait on it. Any degree+1 evaluations on the board give the beacon of the round, see the beacon package. A committee without a beacon secret has no beacon.
+func (node *Node) publishBeacon(epoch int64) {
+	node.mutex.Lock()
+	if err := node.checkCompleted(epoch); err != nil {
+		node.mutex.Unlock()
+		node.entry().WithError(err).Warn("skip the beacon")
+		return
+	}
+	s, err := node.find(pb.BeaconSecret)
+	if err != nil {
+		node.mutex.Unlock()
+		node.entry().WithError(err).Debug("skip the beacon")
+		return
+	}
+	if s.dealt {
+		// the epoch that completed did not hand the secret off, the operator knows it
+		node.mutex.Unlock()
+		node.entry().Warn("skip the beacon, the beacon secret is the dealt one")
+		return
+	}
+	share, public := node.shareInExponent(s)
+	node.mutex.Unlock()
+	msg := &pb.BeaconShareMsg{
+		Index:     int32(node.label),
+		Epoch:     epoch,
+		Committee: node.committee,
+		Partial:   beacon.Evaluate(share, epoch).CompressedBytes(),
+		Share:     public,
+	}
+	msg.Signature = pb.Sign(node.id, msg)
+	go func() {
+		ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
+		defer cancel()
+		// the bulletinboard takes the evaluation once it marked the epoch completed, which may be a moment after this node did
+		err := pb.Retry(func() error {
+			node.countSent("WriteBeacon", peerBoard, msg)
+			_, err := node.bClient.WriteBeacon(ctx, msg)
+			return err
+		})
+		node.recordCall(peerBoard, err)
+		entry := node.logger.WithFields(logrus.Fields{"epoch": epoch, "rpc": "WriteBeacon", "peer": peerBoard})
+		if err != nil {
+			entry.WithError(err).Error("bulletinboard rejected the beacon evaluation")
+			return
+		}
+		entry.Debug("post beacon evaluation")
+	}()
+}
+
+// The first epoch that hands the beacon secret off after the operator dealt it adds a random value of every node to it instead of zero, so that no one, the operator included, knows the secret the beacon is keyed on. The caller holds the mutex.
+func (node *Node) randomizes(s *sharing) bool {
+	return s.id == pb.BeaconSecret && s.dealt
+}
+
+// The beacon secret only keys the beacon. A node neither hands out its shares of it nor uses them on what the operator sends, either would let the operator learn the value of a round before the committee publishes it.
+func checkOperatorUse(id string) error {
+	if id == pb.BeaconSecret {
+		return status.Errorf(codes.PermissionDenied, "%s only keys the beacon", pb.SecretName(id))
+	}
+	return nil
+}
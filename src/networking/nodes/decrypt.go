@@ -0,0 +1,81 @@
+package nodes
+
+import (
+	"context"
+	crand "crypto/rand"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/elgamal"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PublicShare
+// Hand out the public share of the node for a secret at the end of the latest completed epoch, with the values that tie it to the commitments on the bulletinboard.
+// The public shares of more than degree nodes give the public key of the committee. They are public, so anyone may ask.
+func (node *Node) PublicShare(ctx context.Context, msg *pb.EpochMsg) (*pb.PublicShareMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	node.settleFor(msg.GetEpoch())
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if err := node.checkCompleted(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	s, err := node.find(msg.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+	_, public := node.shareInExponent(s)
+	return public, nil
+}
+
+// DecryptShare
+// Decrypt the first part c1 of an ElGamal ciphertext under the public key of a secret of the committee for the operator, as c1^s for the share s the node holds at the end of the latest completed epoch.
+// A Chaum-Pedersen proof shows that c1^s and the public share g^s have the same exponent, so a wrong partial decryption is caught before it spoils the plaintext.
+func (node *Node) DecryptShare(ctx context.Context, msg *pb.DecryptRequestMsg) (*pb.PartialDecryptionMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		node.entry().WithField("rpc", "DecryptShare").WithError(err).Warn("refuse to decrypt")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if err := checkOperatorUse(msg.GetSecret()); err != nil {
+		return nil, err
+	}
+	c1, err := elgamal.ParseC1(msg.GetC1())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad c1: %v", err)
+	}
+	node.settleFor(msg.GetEpoch())
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if err := node.checkCompleted(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	s, err := node.find(msg.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+	share, public := node.shareInExponent(s)
+	partial, proof, err := elgamal.PartialDecrypt(share, c1, crand.Reader)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decrypt: %v", err)
+	}
+	node.logger.WithFields(logrus.Fields{"epoch": *node.completed, "secret": s.id}).Info("decrypt for the operator")
+	return &pb.PartialDecryptionMsg{
+		Share:     public,
+		Partial:   partial.CompressedBytes(),
+		Challenge: proof.Challenge.Bytes(),
+		Response:  proof.Response.Bytes(),
+	}, nil
+}
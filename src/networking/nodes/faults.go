@@ -0,0 +1,89 @@
+package nodes
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fault is misbehaviour a node caught while verifying an epoch
+type Fault struct {
+	Epoch int64
+	Phase int32
+	// ID of the secret whose handoff broke, empty for the secret the committee started with
+	Secret string
+	// Label of the node at fault, 0 when the check cannot pin it on one node or the bulletinboard served it
+	Culprit int
+	Reason  string
+}
+
+func (f Fault) Error() string {
+	where := fmt.Sprintf("epoch %d phase %d", f.Epoch, f.Phase)
+	if f.Secret != "" {
+		where += fmt.Sprintf(" secret %q", f.Secret)
+	}
+	if f.Culprit == 0 {
+		return fmt.Sprintf("%s: %s", where, f.Reason)
+	}
+	return fmt.Sprintf("%s: node %d: %s", where, f.Culprit, f.Reason)
+}
+
+// Checks a node runs on what it receives, the check label of churp_verification_failures_total
+const (
+	// A point of phase 1 sits at the label of its sender
+	checkPointIndex = "point_index"
+	// What the bulletinboard serves is signed, belongs to the epoch and is in its log
+	checkBoard = "board"
+	// A point of phase 1 matches the commitment to the polynomial of this node
+	checkPoint = "point"
+	// Enough good points of phase 1 interpolate the polynomial
+	checkReconstruction = "reconstruction"
+	// The zero shares of all nodes sum to zero
+	checkZeroSum = "zero_sum"
+	// The witness of a zero polynomial opens it to zero at 0
+	checkZeroWitness = "zero_witness"
+	// The new commitment is the old one refreshed by the proactivization commitment
+	checkRefresh = "refresh"
+	// The new share matches the new commitment
+	checkNewShare = "new_share"
+	// The zero share of the key refresh matches the commitment to the zero polynomial of its sender
+	checkKeyZero = "key_zero"
+	// The nodes holding the same key refresh it to the same commitment
+	checkKeyRefresh = "key_refresh"
+)
+
+// Faults returns what the node caught in an epoch, in the order it caught it
+func (node *Node) Faults(epoch int64) []Fault {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return append([]Fault{}, node.faults[epoch]...)
+}
+
+// Record a fault of the current epoch in the handoff of a secret. The node gives up on the epoch once it has checked everything it can, the verification RPC of the bulletinboard then fails with the fault.
+func (node *Node) complain(s *sharing, phase int32, culprit int, check string, reason string) Fault {
+	epoch := node.getEpoch()
+	f := Fault{
+		Epoch:   epoch,
+		Phase:   phase,
+		Secret:  s.id,
+		Culprit: culprit,
+		Reason:  reason,
+	}
+	node.mutex.Lock()
+	node.faults[epoch] = append(node.faults[epoch], f)
+	node.mutex.Unlock()
+	node.metrics.failures.Inc(check)
+	logger := node.phaseEntry(phase).WithFields(logrus.Fields{"check": check, "secret": s.id})
+	if culprit != 0 {
+		logger = logger.WithField("peer", node.peer(int32(culprit)))
+	}
+	logger.Warn("complain: " + reason)
+	return f
+}
+
+// What a verification RPC answers after the node gave up on the epoch
+func abort(f Fault) error {
+	return status.Error(codes.Aborted, f.Error())
+}
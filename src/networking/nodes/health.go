@@ -0,0 +1,119 @@
+package nodes
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Interval at which a draining node checks whether its epoch has completed
+const drainInterval = 50 * time.Millisecond
+
+var errDraining = status.Error(codes.Unavailable, "node is shutting down")
+
+// Check
+// Answer a health check. The node is live while it serves, and ready while it can take part in an epoch.
+func (node *Node) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return pb.CheckHealth(in, node.notReady())
+}
+
+// Watch
+// Not supported, clients poll Check instead
+func (node *Node) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return pb.ErrWatchHealth
+}
+
+// Why the node cannot take part in an epoch now, empty if it can
+func (node *Node) notReady() string {
+	node.mutex.Lock()
+	draining := *node.draining
+	recovering := *node.recovering
+	connected := node.bConn != nil
+	node.mutex.Unlock()
+	switch {
+	case draining:
+		return "shutting down"
+	case !connected:
+		return "not connected"
+	case recovering:
+		return "recovering from its log"
+	case node.health[peerBoard].Down():
+		return "bulletinboard unreachable"
+	}
+	return ""
+}
+
+// Record the outcome of a call to a peer, and log when the peer goes down or comes back
+func (node *Node) recordCall(peer string, err error) {
+	h, ok := node.health[peer]
+	// calls cut short by the node stopping say nothing about the peer
+	if !ok || node.ctx.Err() != nil {
+		return
+	}
+	if !h.Record(err) {
+		return
+	}
+	if h.Down() {
+		node.logger.WithField("peer", peer).WithError(err).Warn("peer is down")
+	} else {
+		node.logger.WithField("peer", peer).Info("peer is up again")
+	}
+}
+
+func (node *Node) isDraining() bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return *node.draining
+}
+
+// The node is not in the middle of an epoch or of replaying its log
+func (node *Node) drained() bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return *node.epoch == *node.completed && !*node.recovering
+}
+
+// Shutdown stops the node once the epoch it is in has completed and the calls it serves have returned. It refuses new epochs meanwhile.
+// If ctx is done first, the node stops right away and Shutdown returns the error of ctx. The node cannot serve again.
+func (node *Node) Shutdown(ctx context.Context) error {
+	node.mutex.Lock()
+	*node.draining = true
+	server := node.server
+	node.mutex.Unlock()
+	node.entry().Info("drain before stopping")
+
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+	for !node.drained() && ctx.Err() == nil {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		}
+	}
+	err := ctx.Err()
+	if server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			err = ctx.Err()
+			server.Stop()
+		}
+	}
+	node.cancel()
+	node.Disconnect()
+	if err != nil {
+		node.entry().WithError(err).Warn("stopped before the epoch completed")
+		return err
+	}
+	node.entry().Info("stopped")
+	return nil
+}
@@ -0,0 +1,266 @@
+package nodes
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/frost"
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+)
+
+// Every epoch hands the P-521 signing key off along with the default secret, with Feldman commitments in place of the polynomial commitments of the pairing curve.
+// The committee stays, so the key needs no reconstruction: in phase 1 the node checks that the bulletinboard holds the commitment to the key it refreshed last.
+// In phase 2 every node holding the key shares a random polynomial that is zero at 0 and writes the commitment to it. In phase 3 each adds the zero shares of the nodes holding the same key to its share, and writes the refreshed commitment, which all of them must agree on.
+// The public key stays the same, while the shares of an epoch no longer combine with the ones of an earlier epoch.
+
+// Order of the group of P-521
+var keyOrder = p521.Curve.Params().N
+
+// The refresh of the key of the node in an epoch
+type keyRefresh struct {
+	// Coefficients of the zero polynomial of the node from degree 1 up, nil until the node draws them in phase 2
+	poly []*big.Int
+	// Commitment to the zero polynomial
+	commitment p521.PolyCommit
+	// Zero shares the nodes sent, indexed by label - 1, nil until the zero message of a node arrives
+	shares []*big.Int
+	// Labels of the nodes holding the same key, whose zero polynomials refreshed it in phase 2
+	holders []int
+	// The refreshed key, nil until phase 2 verified
+	next *schnorrKey
+}
+
+// Drop the refresh the previous epoch left behind, and start a new one if the node holds a key
+func (s *schnorrState) reset(counter int) {
+	s.refresh = nil
+	if s.key != nil {
+		s.refresh = &keyRefresh{shares: make([]*big.Int, counter)}
+	}
+}
+
+// Take the key phase 2 refreshed, keeping the one it replaces until the bulletinboard confirms the epoch
+func (s *schnorrState) complete() {
+	s.prev = s.key
+	if s.refresh != nil && s.refresh.next != nil {
+		s.setKey(s.refresh.next)
+	}
+}
+
+// Go back to the key from before the latest completed epoch, which the bulletinboard failed
+func (s *schnorrState) rollback() {
+	if s.prev != s.key {
+		s.setKey(s.prev)
+	}
+	s.confirm()
+}
+
+// Drop the key from before the latest completed epoch once the bulletinboard confirmed it
+func (s *schnorrState) confirm() {
+	s.prev = nil
+}
+
+// Draw the zero polynomial of the key refresh along with the zero shares of the default secret, or take the one logged in rnd while replaying.
+// The coefficients are returned for the log, nil if the node refreshes no key.
+func (node *Node) drawKeyZero(s *sharing, rnd *zeroRand) ([][]byte, error) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	refresh := node.schnorr.refresh
+	if s.id != "" || refresh == nil {
+		return nil, nil
+	}
+	if rnd != nil && rnd.Key == nil {
+		// the node shared no refresh of its key before it crashed
+		node.schnorr.refresh = nil
+		return nil, nil
+	}
+	poly := make([]*big.Int, node.degree)
+	logged := make([][]byte, node.degree)
+	for i := range poly {
+		if rnd != nil {
+			if len(rnd.Key) != node.degree {
+				return nil, errors.New(fmt.Sprintf("the logged zero polynomial of the key has %d coefficients, need %d", len(rnd.Key), node.degree))
+			}
+			poly[i] = new(big.Int).SetBytes(rnd.Key[i])
+		} else {
+			a, err := frost.RandomScalar(crand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			poly[i] = a
+		}
+		logged[i] = poly[i].Bytes()
+	}
+	points := make([][]byte, node.degree)
+	for i := range poly {
+		points[i] = p521.ScalarBaseMult(poly[i]).Bytes()
+	}
+	commitment, err := p521.ParseZeroPolyCommit(points)
+	if err != nil {
+		return nil, err
+	}
+	refresh.poly = poly
+	refresh.commitment = commitment
+	refresh.shares[node.label-1] = evalZero(poly, node.label)
+	return logged, nil
+}
+
+// P(x) for the polynomial with the given coefficients from degree 1 up, which is zero at 0
+func evalZero(poly []*big.Int, x int) *big.Int {
+	y := new(big.Int)
+	for i := len(poly) - 1; i >= 0; i-- {
+		y.Add(y, poly[i])
+		y.Mul(y, big.NewInt(int64(x)))
+		y.Mod(y, keyOrder)
+	}
+	return y
+}
+
+// The zero share of the key refresh for node x, nil if the node refreshes no key in the epoch
+func (node *Node) keyZeroShare(s *sharing, x int) []byte {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if s.id != "" || node.schnorr.refresh == nil || node.schnorr.refresh.poly == nil {
+		return nil
+	}
+	return evalZero(node.schnorr.refresh.poly, x).Bytes()
+}
+
+// Keep the zero share of the key refresh that came with the zero message of node index. A node that holds another key or none sends none.
+func (node *Node) receiveKeyZero(s *sharing, index int32, share []byte) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if s.id != "" || node.schnorr.refresh == nil || len(share) == 0 {
+		return
+	}
+	node.schnorr.refresh.shares[index-1] = new(big.Int).SetBytes(share)
+}
+
+// Commitments to the key the node holds and to its zero polynomial, written in phase 2 along with the default secret. Both are nil if the node refreshes no key.
+func (node *Node) keyZeroCommitments(s *sharing) ([][]byte, [][]byte) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	refresh := node.schnorr.refresh
+	if s.id != "" || refresh == nil || refresh.poly == nil {
+		return nil, nil
+	}
+	return node.schnorr.key.commitment.Compressed(), refresh.commitment.CompressedZero()
+}
+
+// Check the zero shares of the key refresh against the commitments the nodes holding the same key wrote in phase 2, and refresh the key with them.
+// Nodes holding another key or none are left out, they cannot hurt the key of this node.
+func (node *Node) refreshKey(s *sharing, msgs []*pb.Cmt2Msg) *Fault {
+	node.mutex.Lock()
+	key, refresh := node.schnorr.key, node.schnorr.refresh
+	node.mutex.Unlock()
+	if s.id != "" || refresh == nil {
+		return nil
+	}
+	held := key.commitment.Compressed()
+	share := new(big.Int).Set(key.share)
+	commitment := key.commitment
+	holders := make([]int, 0, node.counter)
+	var fault *Fault
+	for _, msg := range msgs {
+		if !equalPoints(msg.GetKey(), held) {
+			continue
+		}
+		index := int(msg.GetIndex())
+		zero, err := p521.ParseZeroPolyCommit(msg.GetKeyzero())
+		if err == nil && zero.Degree() != node.degree {
+			err = errors.New(fmt.Sprintf("it is to a polynomial of degree %d, need %d", zero.Degree(), node.degree))
+		}
+		if err != nil {
+			f := node.complain(s, 2, index, checkKeyZero, "malformed commitment to the zero polynomial of the key: "+err.Error())
+			if fault == nil {
+				fault = &f
+			}
+			continue
+		}
+		node.mutex.Lock()
+		y := refresh.shares[index-1]
+		node.mutex.Unlock()
+		if y == nil || y.Cmp(keyOrder) >= 0 || !zero.VerifyEval(big.NewInt(int64(node.label)), y) {
+			f := node.complain(s, 2, index, checkKeyZero, "the zero share of the key does not match the commitment to the zero polynomial")
+			if fault == nil {
+				fault = &f
+			}
+			continue
+		}
+		share.Add(share, y)
+		commitment = p521.AdditiveHomomorphism(commitment, zero)
+		holders = append(holders, index)
+	}
+	if fault != nil {
+		return fault
+	}
+	share.Mod(share, keyOrder)
+	node.mutex.Lock()
+	refresh.holders = holders
+	refresh.next = &schnorrKey{share: share, commitment: commitment}
+	node.mutex.Unlock()
+	return nil
+}
+
+// Commitment to the refreshed key, written in phase 3 along with the default secret, nil if the node refreshes no key
+func (node *Node) refreshedKey(s *sharing) [][]byte {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	refresh := node.schnorr.refresh
+	if s.id != "" || refresh == nil || refresh.next == nil {
+		return nil
+	}
+	return refresh.next.commitment.Compressed()
+}
+
+// Check that every node that refreshed the same key in phase 2 wrote the same refreshed commitment in phase 3
+func (node *Node) verifyRefreshedKey(s *sharing, msgs []*pb.Cmt1Msg) *Fault {
+	node.mutex.Lock()
+	refresh := node.schnorr.refresh
+	node.mutex.Unlock()
+	if s.id != "" || refresh == nil || refresh.next == nil {
+		return nil
+	}
+	refreshed := refresh.next.commitment.Compressed()
+	holder := make(map[int]bool)
+	for _, label := range refresh.holders {
+		holder[label] = true
+	}
+	var fault *Fault
+	for _, msg := range msgs {
+		index := int(msg.GetIndex())
+		if holder[index] && !equalPoints(msg.GetKeycmt(), refreshed) {
+			f := node.complain(s, 3, index, checkKeyRefresh, "the refreshed commitment to the key is not the one the zero polynomials lead to")
+			if fault == nil {
+				fault = &f
+			}
+		}
+	}
+	return fault
+}
+
+// The commitment to the key the node wrote in the epoch that last refreshed it must be the one it holds, unless the operator dealt the key since
+func (node *Node) checkHeldKey(keycmt [][]byte, from int64) error {
+	node.mutex.Lock()
+	key := node.schnorr.key
+	node.mutex.Unlock()
+	if key == nil || key.dealt || equalPoints(keycmt, key.commitment.Compressed()) {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("commitment to the key of node %d differs from the one verified in epoch %d", node.label, from))
+}
+
+func equalPoints(a [][]byte, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package nodes
+
+import (
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logger of the node in its current epoch
+func (node *Node) entry() *logrus.Entry {
+	return node.logger.WithField("epoch", node.getEpoch())
+}
+
+// Logger of the node in a phase of its current epoch
+func (node *Node) phaseEntry(phase int32) *logrus.Entry {
+	return node.entry().WithField("phase", phase)
+}
+
+// Logger of the node about a message exchanged with a peer in a phase
+func (node *Node) peerEntry(phase int32, rpc string, peer string) *logrus.Entry {
+	return node.phaseEntry(phase).WithFields(logrus.Fields{"rpc": rpc, "peer": peer})
+}
+
+// Log a refused message. A message refused as unavailable comes again once the node caught up, so that is only worth a debug line.
+func logReject(logger *logrus.Entry, err error, msg string) {
+	if status.Code(err) == codes.Unavailable {
+		logger.Debug(msg)
+		return
+	}
+	logger.Warn(msg)
+}
+
+// Start a step of the epoch, timed and traced
+func (node *Node) startPhase(name string) {
+	node.mutex.Lock()
+	node.metrics.phase = name
+	node.mutex.Unlock()
+	node.metrics.phases.Start(name)
+	node.steps.Start(name)
+}
+
+// End a step of the epoch
+func (node *Node) endPhase(name string) {
+	node.metrics.phases.End(name)
+	node.steps.End(name, nil)
+}
+
+// Make an outbound RPC in a span under the current step
+func (node *Node) traceCall(rpc string, peer string, call func() error) error {
+	err := node.steps.Call(rpc, map[string]interface{}{"rpc": rpc, "peer": peer}, call)
+	node.recordCall(peer, err)
+	return err
+}
+
+// Give up on the epoch, the spans still open end with err
+func (node *Node) abandonEpoch(err error) {
+	node.steps.Finish(err)
+}
+
+func newSteps(label int) *trace.Steps {
+	return trace.NewSteps(map[string]interface{}{"component": "node", "node": label})
+}
@@ -0,0 +1,54 @@
+package nodes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	recorder := &trace.Recorder{}
+	trace.SetExporter(recorder)
+	defer trace.SetExporter(nil)
+
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(1))
+
+	spans := recorder.Spans()
+	if !assert.NotEmpty(t, spans) {
+		return
+	}
+	ids := make(map[string]*trace.Span)
+	count := make(map[string]int)
+	for _, span := range spans {
+		ids[span.ID] = span
+	}
+	for _, span := range spans {
+		// every process derives the same trace for the epoch
+		assert.Equal(t, spans[0].Trace, span.Trace)
+		assert.Equal(t, "", span.Error, span.Name)
+		if span.Parent != "" {
+			assert.Contains(t, ids, span.Parent, span.Name)
+		}
+		count[fmt.Sprintf("%v/%s", span.Fields["component"], span.Name)]++
+	}
+	assert.Equal(t, 3, count["node/epoch"])
+	for _, phase := range []string{"reconstruction", "proactivization", "sharedist"} {
+		assert.Equal(t, 3, count["node/"+phase], phase)
+	}
+	// every node sends its point of phase 1 to both peers and reads the bulletinboard once
+	assert.Equal(t, 6, count["node/SharePhase1"])
+	assert.Equal(t, 3, count["node/ReadPhase1"])
+	assert.Equal(t, 3, count["node/WritePhase2"])
+	assert.Equal(t, 1, count["bulletinboard/epoch"])
+	assert.Equal(t, 3, count["bulletinboard/StartVerifPhase3"])
+	assert.Equal(t, 1, count["clock/clock"])
+	assert.Equal(t, 1, count["clock/StartEpoch"])
+}
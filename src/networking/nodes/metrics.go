@@ -0,0 +1,127 @@
+package nodes
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/metrics"
+	"github.com/golang/protobuf/proto"
+)
+
+// Phases the node times, the phase label of churp_phase_duration_seconds
+const (
+	phaseEpoch           = "epoch"
+	phaseReconstruction  = "reconstruction"
+	phaseProactivization = "proactivization"
+	phaseShareDist       = "sharedist"
+	// Not in a phase, the status of a node between epochs
+	phaseIdle = "idle"
+)
+
+// Peer label of what the node exchanges with the bulletinboard
+const peerBoard = "bulletinboard"
+
+// The metrics of a node
+type nodeMetrics struct {
+	registry *metrics.Registry
+	phases   *metrics.Phases
+
+	received      *metrics.Counter
+	receivedBytes *metrics.Counter
+	sent          *metrics.Counter
+	sentBytes     *metrics.Counter
+	failures      *metrics.Counter
+
+	epoch       *metrics.Gauge
+	completed   *metrics.Gauge
+	completedAt *metrics.Gauge
+
+	// Bytes received before the current epoch started, the log of the epoch holds what came after
+	bytesBefore float64
+
+	// Phase the node is in, idle once the epoch completed, and how long the phases of the latest completed epoch took. Guarded by node.mutex.
+	phase   string
+	timings []*pb.PhaseTimeMsg
+}
+
+func newNodeMetrics(label int, counter int, degree int, committee string) *nodeMetrics {
+	r := metrics.New()
+	m := &nodeMetrics{
+		registry:      r,
+		phases:        metrics.NewPhases(r.Histogram("churp_phase_duration_seconds", "How long the phases of an epoch took on this node.", metrics.DurationBuckets, "phase")),
+		received:      r.Counter("churp_messages_received_total", "Messages the node received, by RPC and sending peer.", "rpc", "peer"),
+		receivedBytes: r.Counter("churp_received_bytes_total", "Bytes of the messages the node received, by RPC and sending peer.", "rpc", "peer"),
+		sent:          r.Counter("churp_messages_sent_total", "Messages the node sent, every attempt counted, by RPC and receiving peer.", "rpc", "peer"),
+		sentBytes:     r.Counter("churp_sent_bytes_total", "Bytes of the messages the node sent, by RPC and receiving peer.", "rpc", "peer"),
+		failures:      r.Counter("churp_verification_failures_total", "Checks that caught a bad message, by check.", "check"),
+		epoch:         r.Gauge("churp_epoch", "Epoch the node is in."),
+		completed:     r.Gauge("churp_completed_epoch", "Latest epoch the node completed."),
+		completedAt:   r.Gauge("churp_epoch_completed_timestamp_seconds", "Unix time the node completed its latest epoch."),
+		phase:         phaseIdle,
+	}
+	r.Gauge("churp_committee_nodes", "Number of nodes in the committee.").Set(float64(counter))
+	r.Gauge("churp_committee_degree", "Degree of the sharing polynomials, one below the number of shares that reconstruct the secret.").Set(float64(degree))
+	r.Gauge("churp_committee_member", "Set to 1 for the committee this node is a member of, with the label of the node.", "committee", "label").Set(1, committee, strconv.Itoa(label))
+	return m
+}
+
+// Metrics returns the registry of the metrics of the node, for a /metrics endpoint
+func (node *Node) Metrics() *metrics.Registry {
+	return node.metrics.registry
+}
+
+// Peer label of the node sending or receiving a message, unknown for labels outside the committee
+func (node *Node) peer(label int32) string {
+	if label < 1 || int(label) > node.counter {
+		return "unknown"
+	}
+	return fmt.Sprintf("node%d", label)
+}
+
+func (node *Node) countReceived(rpc string, peer string, msg proto.Message) {
+	node.metrics.received.Inc(rpc, peer)
+	node.metrics.receivedBytes.Add(float64(proto.Size(msg)), rpc, peer)
+}
+
+func (node *Node) countSent(rpc string, peer string, msg proto.Message) {
+	node.metrics.sent.Inc(rpc, peer)
+	node.metrics.sentBytes.Add(float64(proto.Size(msg)), rpc, peer)
+}
+
+// Start timing an epoch the bulletinboard started
+func (node *Node) startEpochMetrics(epoch int64) {
+	node.metrics.epoch.Set(float64(epoch))
+	node.metrics.bytesBefore = node.metrics.receivedBytes.Total()
+	node.metrics.phases.Reset()
+	node.metrics.phases.Start(phaseEpoch)
+	node.steps.Begin(node.committee, epoch)
+	node.startPhase(phaseReconstruction)
+}
+
+// Record a completed epoch and append what it cost to the log of the node
+func (node *Node) completeEpochMetrics(epoch int64) {
+	m := node.metrics
+	node.endPhase(phaseShareDist)
+	m.phases.End(phaseEpoch)
+	node.steps.Finish(nil)
+	m.completed.Set(float64(epoch))
+	timings := make([]*pb.PhaseTimeMsg, 0, 4)
+	for _, phase := range []string{phaseEpoch, phaseReconstruction, phaseProactivization, phaseShareDist} {
+		timings = append(timings, &pb.PhaseTimeMsg{Phase: phase, Took: m.phases.Took(phase).Nanoseconds()})
+	}
+	node.mutex.Lock()
+	m.phase = phaseIdle
+	m.timings = timings
+	node.mutex.Unlock()
+	m.completedAt.Set(float64(time.Now().UnixNano()) / float64(time.Second))
+	f, _ := os.OpenFile(node.metadataPath+"/log"+strconv.Itoa(node.label), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	defer f.Close()
+	fmt.Fprintf(f, "totMsgSize,%d\n", int64(m.receivedBytes.Total()-m.bytesBefore))
+	fmt.Fprintf(f, "epochLatency,%d\n", m.phases.Took(phaseEpoch).Nanoseconds())
+	fmt.Fprintf(f, "reconstructionLatency,%d\n", m.phases.Took(phaseReconstruction).Nanoseconds())
+	fmt.Fprintf(f, "proactivizationLatency,%d\n", m.phases.Took(phaseProactivization).Nanoseconds())
+	fmt.Fprintf(f, "sharedistLatency,%d\n", m.phases.Took(phaseShareDist).Nanoseconds())
+}
@@ -0,0 +1,41 @@
+package nodes_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(2))
+
+	var out bytes.Buffer
+	assert.Nil(t, committee.Nodes[0].Metrics().WriteText(&out))
+	text := out.String()
+	assert.Contains(t, text, "churp_epoch 2\n")
+	assert.Contains(t, text, "churp_completed_epoch 2\n")
+	assert.Contains(t, text, "churp_committee_nodes 3\n")
+	for _, phase := range []string{"epoch", "reconstruction", "proactivization", "sharedist"} {
+		assert.Contains(t, text, fmt.Sprintf("churp_phase_duration_seconds_count{phase=%q} 2\n", phase))
+	}
+	// every peer sent its point of phase 1 in both epochs
+	assert.Contains(t, text, "churp_messages_received_total{rpc=\"SharePhase1\",peer=\"node2\"} 2\n")
+	assert.Contains(t, text, "churp_messages_received_total{rpc=\"ReadPhase3\",peer=\"bulletinboard\"} 6\n")
+	assert.NotContains(t, text, "churp_verification_failures_total{")
+
+	out.Reset()
+	assert.Nil(t, committee.Board.Metrics().WriteText(&out))
+	text = out.String()
+	assert.Contains(t, text, "churp_epochs_total{state=\"completed\"} 2\n")
+	assert.Contains(t, text, "churp_epoch_running 0\n")
+	assert.Contains(t, text, "churp_messages_received_total{rpc=\"WritePhase2\",peer=\"node3\"} 2\n")
+	assert.Contains(t, text, "churp_phase_duration_seconds_count{phase=\"verification3\"} 2\n")
+}
@@ -2,23 +2,25 @@ package nodes
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
-	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
 	pb "github.com/bl4ck5un/ChuRP/src/services"
 	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
 	"github.com/bl4ck5un/ChuRP/src/utils/interpolation"
 	"github.com/bl4ck5un/ChuRP/src/utils/polypoint"
 	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
-	"github.com/golang/protobuf/proto"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"github.com/bl4ck5un/ChuRP/src/utils/trace"
 	"github.com/ncw/gmp"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io/ioutil"
-	"log"
 	"math/big"
 	"math/rand"
-	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -40,6 +42,16 @@ type Node struct {
 	// [+] Prime Defining Group Z_p
 	p *gmp.Int
 
+	// Epoch Information
+	// [+] Current Epoch
+	epoch *int64
+	// [+] Last Epoch Completed by This Node
+	completed *int64
+	// [+] Epoch Completed Before It, Whose Shares Are Kept Until the Bulletinboard Confirms the Last One, -1 Once It Did
+	previous *int64
+	// [+] Committee ID
+	committee string
+
 	// IP Information
 	// [+] Bulletinboard IP Address
 	bip string
@@ -52,85 +64,142 @@ type Node struct {
 	// [+] Commitment
 	dc  *commitment.DLCommit
 	dpc *commitment.DLPolyCommit
+	// [+] Long-term Signing Key
+	id *identity.Identity
+	// [+] Identity Keys of All Nodes
+	pks []*ecdsa.PublicKey
+	// [+] Key of the Operator Who Deals and Retrieves the Secret, nil if There Is None
+	operator *ecdsa.PublicKey
+	// [+] Encrypted Share Storage and Write-ahead Log, nil if the shares are kept in memory only
+	store *sharestore.Store
 
 	// Sharing State
-	// [+] Polynomial State
-	secretShares []*polypoint.PolyPoint
-
-	// Reconstruction Phase
-	// [+] Polynomial Reconstruction State
-	recShares []*polypoint.PolyPoint
-	// [+] Counter of Polynomial Reconstruction State
-	recCnt *int
+	// [+] Sharing of every secret of the committee, in the order of the IDs. The one with the empty ID is the secret the committee started with, and only Store and Delete change the others between epochs.
+	secrets []*sharing
+	// [+] Share of the P-521 Signing Key the Operator Dealt and Nonces of Open Signing Sessions
+	schnorr *schnorrState
 	// [+] Mutex for everything
 	mutex sync.Mutex
-	// [+] Reconstructed Polynomial
-	recPoly *polyring.Polynomial
-
-	// Proactivization Phase
 	// [+] Lagrange Coefficient
 	lambda []*gmp.Int
-	// [+] Zero Shares
-	zeroShares []*gmp.Int
-	// [+] Counter of Messages Received
-	zeroCnt *int
-	// [+] Zero Share
-	zeroShare *gmp.Int
-	// [+] Proactivization Polynomial
-	proPoly *polyring.Polynomial
-	// [+] Commitment & Witness in Phase 2
-	zeroShareCmt *pbc.Element
-	zeroPolyCmt  *pbc.Element
-	zeroPolyWit  *pbc.Element
-
-	// Share Distribution Phase
-	// [+] New Poynomials
-	newPoly *polyring.Polynomial
-	// [+] Counter for New Secret Shares
-	shareCnt *int
-
-	// Commitment and Witness from BulletinBoard
-	oldPolyCmt      []*pbc.Element
-	zerosumShareCmt []*pbc.Element
-	zerosumPolyCmt  []*pbc.Element
-	zerosumPolyWit  []*pbc.Element
-	midPolyCmt      []*pbc.Element
-	newPolyCmt      []*pbc.Element
+	// [+] Secrets Reconstructed in Phase 1 of This Epoch
+	reconstructed *int
+
+	// Recovery
+	// [+] Set while the node replays its write-ahead log after a restart
+	recovering *bool
+	// [+] Set once the bulletinboard started verification in phase 2 and 3 of this epoch
+	verif2 *bool
+	verif3 *bool
+
+	// Audit
+	// [+] Bulletinboard log of each epoch as this node read it
+	audits map[int64]*pb.AuditMsg
+	// [+] Misbehaviour caught in each epoch
+	faults map[int64][]Fault
 
 	// Metrics
-	totMsgSize *int
-	s1         *time.Time
-	e1         *time.Time
-	s2         *time.Time
-	e2         *time.Time
-	s3         *time.Time
-	e3         *time.Time
+	metrics *nodeMetrics
 
-	// gRPC Clients and Server
-	bConn   *grpc.ClientConn
-	nConn   []*grpc.ClientConn
-	bClient pb.BulletinBoardServiceClient
-	nClient []pb.NodeServiceClient
+	// Logging and Tracing
+	// [+] Logger with the label of the node
+	logger *logrus.Entry
+	// [+] Spans of the phases of the current epoch
+	steps *trace.Steps
 
-	// Initialize Flag
-	iniflag *bool
+	// Transport, Clients and Server
+	transport transport.Transport
+	bConn     transport.Conn
+	nConn     []transport.Conn
+	bClient   pb.BulletinBoardServiceClient
+	nClient   []pb.NodeServiceClient
+	// [+] Outcome of the latest calls to the bulletinboard and each peer
+	health map[string]*transport.Health
+
+	// Lifecycle
+	// [+] Cancelled once the node stops, ending the calls it still makes
+	ctx    context.Context
+	cancel context.CancelFunc
+	// [+] Set once the node refuses new epochs to stop after the current one
+	draining *bool
+	// [+] Server the node serves on, nil until it serves
+	server transport.Server
 }
 
 // Start Phase 1
-// Call ClientSharePhase1 to share secret shares with other nodes
-func (node *Node) StartPhase1(ctx context.Context, in *pb.EmptyMsg) (*pb.AckMsg, error) {
-	log.Printf("[node %d] start phase 1", node.label)
-	*node.s1 = time.Now()
+// Enter the epoch announced by the bulletinboard and call ClientSharePhase1 to share secret shares with other nodes
+func (node *Node) StartPhase1(ctx context.Context, in *pb.EpochMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if node.isDraining() {
+		return nil, errDraining
+	}
+	// the bulletinboard retries its calls, so the epoch may have started already
+	if node.checkEpoch(in) == nil {
+		node.entry().Debug("ignore repeated start of the epoch")
+		return node.ack(), nil
+	}
+	if err := node.enterEpoch(in); err != nil {
+		node.entry().WithError(err).Warnf("refuse to start epoch %d", in.GetEpoch())
+		return nil, err
+	}
+	node.phaseEntry(1).Info("start epoch")
+	node.startEpochMetrics(in.GetEpoch())
 	node.ClientSharePhase1()
-	return &pb.AckMsg{}, nil
+	return node.ack(), nil
 }
 
 // Share Phase 1
 // The server function which takes the sent message of secret shares and store it locally. Then it starts ClientReadPhase1 to read the commitments of polynomials on bulletinboard.
 func (node *Node) SharePhase1(ctx context.Context, msg *pb.PointMsg) (*pb.AckMsg, error) {
-	*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
+	node.countReceived("SharePhase1", node.peer(msg.GetIndex()), msg)
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if msg.GetPhase() != 1 {
+		err := status.Errorf(codes.InvalidArgument, "a point of phase %d sent in phase 1", msg.GetPhase())
+		node.phaseEntry(1).WithField("rpc", "SharePhase1").WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	if err := pb.VerifySigned(node.pks, msg); err != nil {
+		node.phaseEntry(1).WithField("rpc", "SharePhase1").WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	if err := node.checkEpoch(msg); err != nil {
+		logReject(node.peerEntry(1, "SharePhase1", node.peer(msg.GetIndex())).WithError(err), err, "reject point message")
+		return nil, err
+	}
+	s, err := node.lookup(msg.GetSecret())
+	if err != nil {
+		node.peerEntry(1, "SharePhase1", node.peer(msg.GetIndex())).WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	// the point of a node is at its own label, the interpolation needs distinct points
+	if msg.GetX() != msg.GetIndex() {
+		f := node.complain(s, 1, int(msg.GetIndex()), checkPointIndex, fmt.Sprintf("sent a point at %d", msg.GetX()))
+		return nil, status.Error(codes.InvalidArgument, f.Error())
+	}
+	if err := node.receivePoint1(s, msg); err != nil {
+		return nil, err
+	}
+	return node.ack(), nil
+}
+
+// Store a verified point message of phase 1 and reconstruct the polynomial of the secret once all points are in
+func (node *Node) receivePoint1(s *sharing, msg *pb.PointMsg) error {
 	index := msg.GetIndex()
-	log.Printf("[node %d] receives point message from [node %d] in phase 1", node.label, index)
+	logger := node.peerEntry(1, "SharePhase1", node.peer(index)).WithField("secret", s.id)
+	if !node.markReceived(s.recvPoint1, index) {
+		logger.Debug("drop duplicate point message")
+		return nil
+	}
+	if err := node.logRecord(recordPoint1, msg); err != nil {
+		node.unmarkReceived(s.recvPoint1, index)
+		logger.WithError(err).Error("failed to log point message")
+		return status.Errorf(codes.Internal, "failed to log message: %v", err)
+	}
+	logger.Debug("receive point message")
 	x := msg.GetX()
 	y := gmp.NewInt(0)
 	y.SetBytes(msg.Y)
@@ -142,105 +211,296 @@ func (node *Node) SharePhase1(ctx context.Context, msg *pb.PointMsg) (*pb.AckMsg
 		PolyWit: witness,
 	}
 	node.mutex.Lock()
-	node.recShares[*node.recCnt] = &p
-	*node.recCnt = *node.recCnt + 1
-	flag := (*node.recCnt == node.counter)
+	s.recShares[s.recCnt] = &p
+	s.recCnt = s.recCnt + 1
+	flag := (s.recCnt == node.counter)
 	node.mutex.Unlock()
 	if flag {
-		*node.recCnt = 0
-		node.ClientReadPhase1()
+		s.recCnt = 0
+		node.ClientReadPhase1(s)
 	}
-	return &pb.AckMsg{}, nil
+	return nil
 }
 
 // Share Phase 2
 // The server function which takes the sent message of zero shares and sum them up to get the final share and generate the proactivization polynomial according to the zero share. It then calls ClientWritePhase2 to write the commitment of zeroshare, zeropolynomial and the witness at zero on the bulletinboard.
 func (node *Node) SharePhase2(ctx context.Context, msg *pb.ZeroMsg) (*pb.AckMsg, error) {
-	*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
+	node.countReceived("SharePhase2", node.peer(msg.GetIndex()), msg)
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifySigned(node.pks, msg); err != nil {
+		node.phaseEntry(2).WithField("rpc", "SharePhase2").WithError(err).Warn("reject zero message")
+		return nil, err
+	}
+	if err := node.checkEpoch(msg); err != nil {
+		logReject(node.peerEntry(2, "SharePhase2", node.peer(msg.GetIndex())).WithError(err), err, "reject zero message")
+		return nil, err
+	}
+	s, err := node.lookup(msg.GetSecret())
+	if err != nil {
+		node.peerEntry(2, "SharePhase2", node.peer(msg.GetIndex())).WithError(err).Warn("reject zero message")
+		return nil, err
+	}
+	if err := node.receiveZero(s, msg); err != nil {
+		return nil, err
+	}
+	return node.ack(), nil
+}
+
+// Add a verified zero share of phase 2 to the sum of the secret and write the commitments once all shares are in
+func (node *Node) receiveZero(s *sharing, msg *pb.ZeroMsg) error {
 	index := msg.GetIndex()
-	log.Printf("[node %d] receive zero message from [node %d] in phase 2", node.label, index)
+	logger := node.peerEntry(2, "SharePhase2", node.peer(index)).WithField("secret", s.id)
+	if !node.markReceived(s.recvZero, index) {
+		logger.Debug("drop duplicate zero message")
+		return nil
+	}
+	if err := node.logRecord(recordZero, msg); err != nil {
+		node.unmarkReceived(s.recvZero, index)
+		logger.WithError(err).Error("failed to log zero message")
+		return status.Errorf(codes.Internal, "failed to log message: %v", err)
+	}
+	logger.Debug("receive zero message")
+	node.receiveKeyZero(s, index, msg.GetKeyShare())
 	inter := gmp.NewInt(0)
 	inter.SetBytes(msg.GetShare())
 	node.mutex.Lock()
-	node.zeroShare.Add(node.zeroShare, inter)
-	*node.zeroCnt = *node.zeroCnt + 1
-	flag := (*node.zeroCnt == node.counter)
+	s.zeroShare.Add(s.zeroShare, inter)
+	s.zeroCnt = s.zeroCnt + 1
+	flag := (s.zeroCnt == node.counter)
 	node.mutex.Unlock()
 	if flag {
-		*node.zeroCnt = 0
-		node.zeroShare.Mod(node.zeroShare, node.p)
-		node.dc.Commit(node.zeroShareCmt, node.zeroShare)
-		poly, _ := polyring.NewRand(node.degree, node.randState, node.p)
-		poly.SetCoefficient(0, 0)
-		node.dpc.Commit(node.zeroPolyCmt, poly)
-		node.dpc.CreateWitness(node.zeroPolyWit, poly, gmp.NewInt(0))
-
-		poly.SetCoefficientBig(0, node.zeroShare)
-		node.proPoly.ResetTo(poly.DeepCopy())
-
-		node.ClientWritePhase2()
+		s.zeroCnt = 0
+		node.commitZeroShare(s)
+		node.ClientWritePhase2(s)
 	}
-	return &pb.AckMsg{}, nil
+	return nil
 }
 
-// After the bulletinboard has received the writing of all nodes, it will start a client call to this function telling the nodes to read the commitment on it.
-func (node *Node) StartVerifPhase2(ctx context.Context, in *pb.EmptyMsg) (*pb.AckMsg, error) {
-	log.Printf("[node %d] start verification in phase 2", node.label)
-	node.ClientReadPhase2()
-	return &pb.AckMsg{}, nil
+// After the bulletinboard has received the writing of all nodes for every secret, it will start a client call to this function telling the nodes to read the commitment on it.
+func (node *Node) StartVerifPhase2(ctx context.Context, in *pb.EpochMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := node.checkEpoch(in); err != nil {
+		node.phaseEntry(2).WithError(err).Warn("refuse verification")
+		return nil, err
+	}
+	if !node.markOnce(node.verif2) {
+		node.phaseEntry(2).Debug("ignore repeated verification")
+		return node.ack(), nil
+	}
+	if err := node.logRecord(recordVerif2, nil); err != nil {
+		node.unmarkOnce(node.verif2)
+		node.phaseEntry(2).WithError(err).Error("failed to log verification")
+		return nil, status.Errorf(codes.Internal, "failed to log verification: %v", err)
+	}
+	node.phaseEntry(2).Info("start verification")
+	if err := node.ClientReadPhase2(); err != nil {
+		// the bulletinboard retries once the node can read it again
+		if status.Code(err) == codes.Unavailable {
+			node.unmarkOnce(node.verif2)
+		}
+		return nil, err
+	}
+	return node.ack(), nil
 }
 
 // Share Phase 3
 // The server function which takes the sent message in share distribution phase and store it locally as the new secret shares. It then calls ClientWritePhase3 to write the commitment of the new polynomial on the bulletinboard.
 func (node *Node) SharePhase3(ctx context.Context, msg *pb.PointMsg) (*pb.AckMsg, error) {
-	*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
+	node.countReceived("SharePhase3", node.peer(msg.GetIndex()), msg)
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if msg.GetPhase() != 3 {
+		err := status.Errorf(codes.InvalidArgument, "a point of phase %d sent in phase 3", msg.GetPhase())
+		node.phaseEntry(3).WithField("rpc", "SharePhase3").WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	if err := pb.VerifySigned(node.pks, msg); err != nil {
+		node.phaseEntry(3).WithField("rpc", "SharePhase3").WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	if err := node.checkEpoch(msg); err != nil {
+		logReject(node.peerEntry(3, "SharePhase3", node.peer(msg.GetIndex())).WithError(err), err, "reject point message")
+		return nil, err
+	}
+	s, err := node.lookup(msg.GetSecret())
+	if err != nil {
+		node.peerEntry(3, "SharePhase3", node.peer(msg.GetIndex())).WithError(err).Warn("reject point message")
+		return nil, err
+	}
+	if err := node.receivePoint3(s, msg); err != nil {
+		return nil, err
+	}
+	return node.ack(), nil
+}
+
+// Store a verified point message of phase 3 as the new share of the secret and write the new commitment once all shares are in
+func (node *Node) receivePoint3(s *sharing, msg *pb.PointMsg) error {
 	index := msg.GetIndex()
-	log.Printf("[node %d] receive point message from [node %d] in phase3", node.label, index)
+	logger := node.peerEntry(3, "SharePhase3", node.peer(index)).WithField("secret", s.id)
+	if !node.markReceived(s.recvPoint3, index) {
+		logger.Debug("drop duplicate point message")
+		return nil
+	}
+	if err := node.logRecord(recordPoint3, msg); err != nil {
+		node.unmarkReceived(s.recvPoint3, index)
+		logger.WithError(err).Error("failed to log point message")
+		return status.Errorf(codes.Internal, "failed to log message: %v", err)
+	}
+	logger.Debug("receive point message")
 	Y := msg.GetY()
 	witness := msg.GetWitness()
-	node.secretShares[index-1].Y.SetBytes(Y)
-	node.secretShares[index-1].PolyWit.SetCompressedBytes(witness)
+	s.newShares[index-1].Y.SetBytes(Y)
+	s.newShares[index-1].PolyWit.SetCompressedBytes(witness)
 	node.mutex.Lock()
-	*node.shareCnt = *node.shareCnt + 1
-	flag := (*node.shareCnt == node.counter)
+	s.shareCnt = s.shareCnt + 1
+	flag := (s.shareCnt == node.counter)
 	node.mutex.Unlock()
 	if flag {
-		*node.shareCnt = 0
-		node.ClientWritePhase3()
+		s.shareCnt = 0
+		node.ClientWritePhase3(s)
+	}
+	return nil
+}
+
+// After the bulletinboard receives all the writings in phase 3 for every secret. It will start a client call to this function notifying the nodes to read the new commitment and verify its own shares.
+func (node *Node) StartVerifPhase3(ctx context.Context, in *pb.EpochMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := node.checkEpoch(in); err != nil {
+		node.phaseEntry(3).WithError(err).Warn("refuse verification")
+		return nil, err
+	}
+	if !node.markOnce(node.verif3) {
+		node.phaseEntry(3).Debug("ignore repeated verification")
+		return node.ack(), nil
+	}
+	if err := node.logRecord(recordVerif3, nil); err != nil {
+		node.unmarkOnce(node.verif3)
+		node.phaseEntry(3).WithError(err).Error("failed to log verification")
+		return nil, status.Errorf(codes.Internal, "failed to log verification: %v", err)
+	}
+	node.phaseEntry(3).Info("start verification")
+	if err := node.ClientReadPhase3(); err != nil {
+		if status.Code(err) == codes.Unavailable {
+			node.unmarkOnce(node.verif3)
+		}
+		return nil, err
+	}
+	return node.ack(), nil
+}
+
+// Move to the epoch announced by the bulletinboard. The epochs since the last one the node completed must all have failed, and the last one must not have, see settle and skip.
+func (node *Node) enterEpoch(in *pb.EpochMsg) error {
+	if in.GetCommittee() != node.committee {
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", in.GetCommittee(), node.committee)
+	}
+	if epoch := node.getEpoch(); in.GetEpoch() <= epoch {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d cannot follow epoch %d", in.GetEpoch(), epoch)
 	}
-	return &pb.AckMsg{}, nil
+	if err := node.settle(); err != nil {
+		return err
+	}
+	if err := node.skip(node.getEpoch()+1, in.GetEpoch()-1); err != nil {
+		return err
+	}
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if in.GetEpoch() <= *node.epoch {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d cannot follow epoch %d", in.GetEpoch(), *node.epoch)
+	}
+	if *node.completed != *node.epoch {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d has not completed", *node.epoch)
+	}
+	// log the epoch before any message of it can be accepted
+	if err := node.appendLog(in.GetEpoch(), recordEnter, nil); err != nil {
+		return status.Errorf(codes.Internal, "failed to log epoch %d: %v", in.GetEpoch(), err)
+	}
+	*node.epoch = in.GetEpoch()
+	node.resetEpochState()
+	return nil
+}
+
+// Drop everything the previous epoch left behind. Only the secret shares and the commitments to them carry over. The caller holds node.mutex.
+func (node *Node) resetEpochState() {
+	*node.reconstructed = 0
+	*node.verif2 = false
+	*node.verif3 = false
+	for _, s := range node.secrets {
+		s.reset()
+	}
+	node.schnorr.reset(node.counter)
+}
+
+// Reject messages that do not belong to the current epoch of this committee.
+func (node *Node) checkEpoch(msg pb.EpochScoped) error {
+	return pb.CheckEpoch(msg, node.getEpoch(), node.committee)
+}
+
+func (node *Node) getEpoch() int64 {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return *node.epoch
 }
 
-// After the bulletinboard receives all the writings in phase 3. It will start a client call to this function notifying the nodes to read the new commitment and verify its own shares.
-func (node *Node) StartVerifPhase3(ctx context.Context, in *pb.EmptyMsg) (*pb.AckMsg, error) {
-	log.Printf("[node %d] start verification in phase 3", node.label)
-	node.ClientReadPhase3()
-	return &pb.AckMsg{}, nil
+func (node *Node) epochMsg() *pb.EpochMsg {
+	return &pb.EpochMsg{
+		Epoch:     node.getEpoch(),
+		Committee: node.committee,
+	}
 }
 
-func (node *Node) Connect() {
-	bConn, err := grpc.Dial(node.bip, grpc.WithInsecure())
+// Read request for the commitments of a secret in the current epoch
+func (node *Node) secretMsg(s *sharing) *pb.EpochMsg {
+	msg := node.epochMsg()
+	msg.Secret = s.id
+	return msg
+}
+
+func (node *Node) ack() *pb.AckMsg {
+	return &pb.AckMsg{
+		Epoch:     node.getEpoch(),
+		Committee: node.committee,
+	}
+}
+
+func (node *Node) Connect() error {
+	bConn, err := node.transport.Dial(node.bip)
 	if err != nil {
-		log.Fatalf("node did not connect to bulletinboard: %v", err)
+		return errors.New(fmt.Sprintf("node did not connect to bulletinboard: %v", err))
 	}
+	node.mutex.Lock()
 	node.bConn = bConn
-	node.bClient = pb.NewBulletinBoardServiceClient(node.bConn)
+	node.bClient = bConn.BulletinBoard()
+	node.mutex.Unlock()
 	for i := 0; i < node.counter; i++ {
 		if i != node.label-1 {
-			nConn, err := grpc.Dial(node.ipList[i], grpc.WithInsecure())
+			nConn, err := node.transport.Dial(node.ipList[i])
 			if err != nil {
-				log.Fatalf("node did not connect to node: %v", err)
+				return errors.New(fmt.Sprintf("node did not connect to node %d: %v", i+1, err))
 			}
+			node.mutex.Lock()
 			node.nConn[i] = nConn
-			node.nClient[i] = pb.NewNodeServiceClient(nConn)
+			node.nClient[i] = nConn.Node()
+			node.mutex.Unlock()
 		}
 	}
+	return nil
 }
 
 func (node *Node) Disconnect() {
-	node.bConn.Close()
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if node.bConn != nil {
+		node.bConn.Close()
+	}
 	for i := 0; i < node.counter; i++ {
-		if i != node.label-1 {
+		if node.nConn[i] != nil {
 			node.nConn[i].Close()
 		}
 	}
@@ -251,204 +511,307 @@ func (node *Node) Serve(aws bool) {
 	if aws {
 		port = "0.0.0.0:12001"
 	}
-	lis, err := net.Listen("tcp", port)
+	s, err := node.transport.Listen(port)
 	if err != nil {
-		log.Fatalf("node failed to listen %v", err)
+		node.logger.Fatalf("node failed to listen %v", err)
 	}
-	s := grpc.NewServer()
-	pb.RegisterNodeServiceServer(s, node)
-	reflection.Register(s)
-	log.Printf("node %d serve on %s", node.label, port)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("node failed to serve %v", err)
+	s.RegisterNode(node)
+	s.RegisterAdmin(node)
+	s.RegisterSecret(node)
+	s.RegisterSign(node)
+	s.RegisterDecrypt(node)
+	s.RegisterSchnorr(node)
+	s.RegisterHealth(node)
+	node.mutex.Lock()
+	node.server = s
+	node.mutex.Unlock()
+	// the connections reach the bulletinboard and the peers once they serve, the node is not ready before
+	if err := node.Connect(); err != nil {
+		node.logger.Fatal(err)
+	}
+	// peers are told to retry until the node has caught up with its log
+	go node.Recover()
+	node.logger.Infof("serve on %s", port)
+	if err := s.Serve(); err != nil {
+		node.logger.Fatalf("node failed to serve %v", err)
 	}
 }
 
-// The function that starts client calls to all other nodes to send the secret shares.
+// The function that starts client calls to all other nodes to send the secret shares of every secret.
 func (node *Node) ClientSharePhase1() {
-	if *node.iniflag {
-		node.Connect()
-		*node.iniflag = false
-	}
-	p := polypoint.PolyPoint{
-		X:       node.secretShares[node.label-1].X,
-		Y:       node.secretShares[node.label-1].Y,
-		PolyWit: node.secretShares[node.label-1].PolyWit,
-	}
-	// Race Condition Here
-	node.mutex.Lock()
-	node.recShares[*node.recCnt] = &p
-	*node.recCnt = *node.recCnt + 1
-	flag := (*node.recCnt == node.counter)
-	node.mutex.Unlock()
-	if flag {
-		*node.recCnt = 0
-		node.ClientReadPhase1()
+	secrets := node.secretList()
+	for _, s := range secrets {
+		node.sharePhase1(s)
 	}
 	var wg sync.WaitGroup
+	for _, s := range secrets {
+		wg.Add(1)
+		go func(s *sharing) {
+			defer wg.Done()
+			node.broadcastPhase(1, s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// Build the point messages of phase 1 for a secret and take the point of the node itself
+func (node *Node) sharePhase1(s *sharing) {
+	epoch := node.getEpoch()
 	for i := 0; i < node.counter; i++ {
 		if i != node.label-1 {
-			log.Printf("[node %d] send point message to [node %d] in phase 1", node.label, i+1)
-			x := node.secretShares[i].X
-			y := node.secretShares[i].Y.Bytes()
-			witness := node.secretShares[i].PolyWit.CompressedBytes()
+			x := s.secretShares[i].X
+			y := s.secretShares[i].Y.Bytes()
+			witness := s.secretShares[i].PolyWit.CompressedBytes()
 			msg := &pb.PointMsg{
-				X:       x,
-				Y:       y,
-				Witness: witness,
+				Index:     int32(node.label),
+				X:         x,
+				Y:         y,
+				Witness:   witness,
+				Epoch:     epoch,
+				Committee: node.committee,
+				Secret:    s.id,
+				Phase:     1,
 			}
-			wg.Add(1)
-			go func(i int, msg *pb.PointMsg) {
-				defer wg.Done()
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-				node.nClient[i].SharePhase1(ctx, msg)
-			}(i, msg)
+			msg.Signature = pb.Sign(node.id, msg)
+			node.mutex.Lock()
+			s.sentPoint1[i] = msg
+			node.mutex.Unlock()
 		}
 	}
-	wg.Wait()
+	p := polypoint.PolyPoint{
+		X:       s.secretShares[node.label-1].X,
+		Y:       s.secretShares[node.label-1].Y,
+		PolyWit: s.secretShares[node.label-1].PolyWit,
+	}
+	// Race Condition Here
+	node.mutex.Lock()
+	s.recvPoint1[node.label-1] = true
+	s.recShares[s.recCnt] = &p
+	s.recCnt = s.recCnt + 1
+	flag := (s.recCnt == node.counter)
+	node.mutex.Unlock()
+	if flag {
+		s.recCnt = 0
+		node.ClientReadPhase1(s)
+	}
 }
 
-// Read from the bulletinboard and does the interpolation and verifiication.
-func (node *Node) ClientReadPhase1() {
-	if *node.iniflag {
-		node.Connect()
-		*node.iniflag = false
-	}
-	log.Printf("[node %d] read bulletinboard in phase 1", node.label)
-	ctx, cancel := context.WithCancel(context.Background())
+// Read the commitments to the polynomials of a secret from the bulletinboard and does the interpolation and verifiication.
+func (node *Node) ClientReadPhase1(s *sharing) {
+	node.phaseEntry(1).WithField("secret", s.id).Info("read bulletinboard")
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
 	defer cancel()
-	stream, err := node.bClient.ReadPhase1(ctx, &pb.EmptyMsg{})
+	var msgs []*pb.Cmt1Msg
+	err := node.traceCall("ReadPhase1", peerBoard, func() error {
+		stream, err := node.bClient.ReadPhase1(ctx, node.secretMsg(s))
+		if err != nil {
+			return err
+		}
+		msgs = make([]*pb.Cmt1Msg, node.counter)
+		for i := range msgs {
+			if msgs[i], err = stream.Recv(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatalf("client failed to read phase1: %v", err)
+		// nothing else reads the bulletinboard in phase 1, the epoch cannot go on
+		node.peerEntry(1, "ReadPhase1", peerBoard).WithField("secret", s.id).WithError(err).Error("failed to read bulletinboard, abandon the epoch")
+		node.abandonEpoch(err)
+		return
 	}
-	for i := 0; i < node.counter; i++ {
-		msg, err := stream.Recv()
-		*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
-		if err != nil {
-			log.Fatalf("client failed to receive in read phase1: %v", err)
+	// the commitments are the ones of the epoch that last refreshed or dealt the secret
+	from := msgs[0].GetEpoch()
+	read := make([]pb.Committed, 0, node.counter)
+	for _, msg := range msgs {
+		node.countReceived("ReadPhase1", peerBoard, msg)
+		if err := node.verifyOldPolyCmt(s, msg, from); err != nil {
+			node.complain(s, 1, 0, checkBoard, "reconstruction commitment rejected: "+err.Error())
+			return
 		}
-		index := msg.GetIndex()
-		polycmt := msg.GetPolycmt()
-		node.oldPolyCmt[index-1].SetCompressedBytes(polycmt)
+		read = append(read, msg)
+	}
+	if err := node.verifyLog(from, logShareDist, read); err != nil {
+		node.complain(s, 1, 0, checkBoard, "reconstruction commitment rejected: "+err.Error())
+		return
 	}
 	x := make([]*gmp.Int, 0)
 	y := make([]*gmp.Int, 0)
 	polyCmt := node.dpc.NewG1()
-	polyCmt.Set(node.oldPolyCmt[node.label-1])
-	for i := 0; i <= node.degree; i++ {
-		point := node.recShares[i]
-		x = append(x, gmp.NewInt(int64(point.X)))
-		y = append(y, point.Y)
+	polyCmt.Set(s.oldPolyCmt[node.label-1])
+	// every point is checked so that each node sending a bad one is caught, the first degree+1 good ones are interpolated
+	for i := 0; i < node.counter; i++ {
+		point := s.recShares[i]
 		if !node.dpc.VerifyEval(polyCmt, gmp.NewInt(int64(point.X)), point.Y, point.PolyWit) {
-			panic("Reconstruction Verification failed")
+			node.complain(s, 1, int(point.X), checkPoint, "point does not match the commitment to the polynomial of this node")
+			continue
+		}
+		if len(x) <= node.degree {
+			x = append(x, gmp.NewInt(int64(point.X)))
+			y = append(y, point.Y)
 		}
 	}
+	if len(x) <= node.degree {
+		node.complain(s, 1, 0, checkReconstruction, fmt.Sprintf("%d good points cannot reconstruct a polynomial of degree %d", len(x), node.degree))
+		return
+	}
 	poly, err := interpolation.LagrangeInterpolate(node.degree, x, y, node.p)
 	if err != nil {
-		for i := 0; i < len(x); i++ {
-			log.Print(x[i])
-			log.Print(y[i])
-		}
-		log.Print(err)
-		panic("Interpolation failed")
-	}
-	node.recPoly.ResetTo(poly)
-	*node.e1 = time.Now()
-	*node.s2 = time.Now()
-	node.ClientSharePhase2()
-}
-
-// The function that really does the work of generating and sending zero shares.
-func (node *Node) ClientSharePhase2() {
-	// Generate Random Numbers
-	for i := 0; i < node.counter-1; i++ {
-		node.zeroShares[i].Rand(node.randState, gmp.NewInt(10))
-		inter := gmp.NewInt(0)
-		inter.Mul(node.zeroShares[i], node.lambda[i])
-		node.zeroShares[node.counter-1].Sub(node.zeroShares[node.counter-1], inter)
+		node.complain(s, 1, 0, checkReconstruction, "interpolation failed: "+err.Error())
+		return
 	}
-	node.zeroShares[node.counter-1].Mod(node.zeroShares[node.counter-1], node.p)
-	inter := gmp.NewInt(0)
-	inter.ModInverse(node.lambda[node.counter-1], node.p)
-	node.zeroShares[node.counter-1].Mul(node.zeroShares[node.counter-1], inter)
-	node.zeroShares[node.counter-1].Mod(node.zeroShares[node.counter-1], node.p)
+	s.recPoly.ResetTo(poly)
+	// the phase ends with the last secret reconstructed, the others already went on
 	node.mutex.Lock()
-	node.zeroShare.Add(node.zeroShare, node.zeroShares[node.label-1])
-	*node.zeroCnt = *node.zeroCnt + 1
-	flag := (*node.zeroCnt == node.counter)
+	*node.reconstructed = *node.reconstructed + 1
+	last := *node.reconstructed == len(node.secrets)
 	node.mutex.Unlock()
-	if flag {
-		*node.zeroCnt = 0
-		node.zeroShare.Mod(node.zeroShare, node.p)
-		node.dc.Commit(node.zeroShareCmt, node.zeroShare)
-		poly, _ := polyring.NewRand(node.degree, node.randState, node.p)
-		poly.SetCoefficient(0, 0)
-		node.dpc.Commit(node.zeroPolyCmt, poly)
-		node.dpc.CreateWitness(node.zeroPolyWit, poly, gmp.NewInt(0))
-
-		poly.SetCoefficientBig(0, node.zeroShare)
-		node.proPoly.ResetTo(poly.DeepCopy())
-		node.ClientWritePhase2()
+	if last {
+		node.endPhase(phaseReconstruction)
+		node.startPhase(phaseProactivization)
+	}
+	node.ClientSharePhase2(s)
+}
+
+// The function that really does the work of generating and sending zero shares for a secret.
+func (node *Node) ClientSharePhase2(s *sharing) {
+	epoch := node.getEpoch()
+	if err := node.drawZeroShares(s); err != nil {
+		// peers get no zero share from this node, so the epoch cannot complete
+		node.phaseEntry(2).WithField("secret", s.id).WithError(err).Error("failed to log zero shares, abandon the epoch")
+		node.abandonEpoch(err)
+		return
 	}
-	var wg sync.WaitGroup
 	for i := 0; i < node.counter; i++ {
 		if i != node.label-1 {
-			log.Printf("[node %d] send message to [node %d] in phase 2", node.label, i+1)
 			msg := &pb.ZeroMsg{
-				Index: int32(node.label),
-				Share: node.zeroShares[i].Bytes(),
+				Index:     int32(node.label),
+				Share:     s.zeroShares[i].Bytes(),
+				Epoch:     epoch,
+				Committee: node.committee,
+				Secret:    s.id,
+				KeyShare:  node.keyZeroShare(s, i+1),
 			}
-			wg.Add(1)
-			go func(i int, msg *pb.ZeroMsg) {
-				defer wg.Done()
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-				node.nClient[i].SharePhase2(ctx, msg)
-			}(i, msg)
+			msg.Signature = pb.Sign(node.id, msg)
+			node.mutex.Lock()
+			s.sentZero[i] = msg
+			node.mutex.Unlock()
 		}
 	}
-	wg.Wait()
+	node.mutex.Lock()
+	s.recvZero[node.label-1] = true
+	s.zeroShare.Add(s.zeroShare, s.zeroShares[node.label-1])
+	s.zeroCnt = s.zeroCnt + 1
+	flag := (s.zeroCnt == node.counter)
+	node.mutex.Unlock()
+	if flag {
+		s.zeroCnt = 0
+		node.commitZeroShare(s)
+		node.ClientWritePhase2(s)
+	}
+	node.broadcastPhase(2, s)
 }
 
-func (node *Node) ClientWritePhase2() {
-	log.Printf("[node %d] write bulletinboard in phase 2", node.label)
-	ctx, cancel := context.WithCancel(context.Background())
+func (node *Node) ClientWritePhase2(s *sharing) {
+	if node.isRecovering() {
+		return
+	}
+	node.phaseEntry(2).WithField("secret", s.id).Info("write bulletinboard")
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
 	defer cancel()
+	key, keyzero := node.keyZeroCommitments(s)
 	msg := &pb.Cmt2Msg{
 		Index:       int32(node.label),
-		Sharecmt:    node.zeroShareCmt.CompressedBytes(),
-		Polycmt:     node.zeroPolyCmt.CompressedBytes(),
-		Zerowitness: node.zeroPolyWit.CompressedBytes(),
+		Sharecmt:    s.zeroShareCmt.CompressedBytes(),
+		Polycmt:     s.zeroPolyCmt.CompressedBytes(),
+		Zerowitness: s.zeroPolyWit.CompressedBytes(),
+		Epoch:       node.getEpoch(),
+		Committee:   node.committee,
+		Secret:      s.id,
+		Key:         key,
+		Keyzero:     keyzero,
+	}
+	msg.Signature = pb.Sign(node.id, msg)
+	// the bulletinboard may be restarting
+	err := node.traceCall("WritePhase2", peerBoard, func() error {
+		return pb.Retry(func() error {
+			node.countSent("WritePhase2", peerBoard, msg)
+			_, err := node.bClient.WritePhase2(ctx, msg)
+			return err
+		})
+	})
+	if err != nil {
+		node.peerEntry(2, "WritePhase2", peerBoard).WithField("secret", s.id).WithError(err).Error("bulletinboard rejected write")
 	}
-	node.bClient.WritePhase2(ctx, msg)
 }
 
-// Read from bulletinboard and does the verification in phase 2.
-func (node *Node) ClientReadPhase2() {
-	log.Printf("[node %d] read bulletinboard in phase 2", node.label)
-	ctx, cancel := context.WithCancel(context.Background())
+// Read from bulletinboard and does the verification in phase 2 for every secret, then sends the new shares of all of them. The error tells the bulletinboard what the verification caught.
+func (node *Node) ClientReadPhase2() error {
+	secrets := node.secretList()
+	for _, s := range secrets {
+		if err := node.verifyPhase2(s); err != nil {
+			return err
+		}
+	}
+	node.endPhase(phaseProactivization)
+	node.startPhase(phaseShareDist)
+	for _, s := range secrets {
+		node.ClientSharePhase3(s)
+	}
+	return nil
+}
+
+// Read the commitments of phase 2 for a secret and check that the zero shares of all nodes sum to zero and every zero polynomial is zero at 0
+func (node *Node) verifyPhase2(s *sharing) error {
+	node.phaseEntry(2).WithField("secret", s.id).Info("read bulletinboard")
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
 	defer cancel()
-	stream, err := node.bClient.ReadPhase2(ctx, &pb.EmptyMsg{})
+	epoch := node.getEpoch()
+	var msgs []*pb.Cmt2Msg
+	err := node.traceCall("ReadPhase2", peerBoard, func() error {
+		stream, err := node.bClient.ReadPhase2(ctx, node.secretMsg(s))
+		if err != nil {
+			return err
+		}
+		msgs = make([]*pb.Cmt2Msg, node.counter)
+		for i := range msgs {
+			if msgs[i], err = stream.Recv(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatalf("client failed to read phase2: %v", err)
+		node.peerEntry(2, "ReadPhase2", peerBoard).WithField("secret", s.id).WithError(err).Error("failed to read bulletinboard")
+		return status.Errorf(codes.Unavailable, "failed to read the bulletinboard: %v", err)
 	}
-	for i := 0; i < node.counter; i++ {
-		msg, err := stream.Recv()
-		*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
-		if err != nil {
-			log.Fatalf("client failed to receive in read phase1: %v", err)
+	read := make([]pb.Committed, 0, node.counter)
+	for _, msg := range msgs {
+		node.countReceived("ReadPhase2", peerBoard, msg)
+		if err := pb.VerifySigned(node.pks, msg); err != nil {
+			return abort(node.complain(s, 2, 0, checkBoard, "proactivization commitment rejected: "+err.Error()))
+		}
+		if err := pb.CheckEpoch(msg, epoch, node.committee); err != nil {
+			return abort(node.complain(s, 2, 0, checkBoard, "proactivization commitment rejected: "+err.Error()))
+		}
+		if msg.GetSecret() != s.id {
+			return abort(node.complain(s, 2, 0, checkBoard, "proactivization commitment rejected: it commits to "+pb.SecretName(msg.GetSecret())))
 		}
 		index := msg.GetIndex()
 		sharecmt := msg.GetSharecmt()
 		polycmt := msg.GetPolycmt()
 		zerowitness := msg.GetZerowitness()
-		node.zerosumShareCmt[index-1].SetCompressedBytes(sharecmt)
+		s.zerosumShareCmt[index-1].SetCompressedBytes(sharecmt)
 		inter := node.dpc.NewG1()
-		inter.SetString(node.zerosumShareCmt[index-1].String(), 10)
-		node.zerosumPolyCmt[index-1].SetCompressedBytes(polycmt)
-		node.midPolyCmt[index-1].Mul(inter, node.zerosumPolyCmt[index-1])
-		node.zerosumPolyWit[index-1].SetCompressedBytes(zerowitness)
+		inter.SetString(s.zerosumShareCmt[index-1].String(), 10)
+		s.zerosumPolyCmt[index-1].SetCompressedBytes(polycmt)
+		s.midPolyCmt[index-1].Mul(inter, s.zerosumPolyCmt[index-1])
+		s.zerosumPolyWit[index-1].SetCompressedBytes(zerowitness)
+		read = append(read, msg)
+	}
+	if err := node.verifyLog(epoch, logProactivization, read); err != nil {
+		return abort(node.complain(s, 2, 0, checkBoard, "proactivization commitment rejected: "+err.Error()))
 	}
 	exponentSum := node.dc.NewG1()
 	exponentSum.Set1()
@@ -456,140 +819,266 @@ func (node *Node) ClientReadPhase2() {
 		lambda := big.NewInt(0)
 		lambda.SetString(node.lambda[i].String(), 10)
 		tmp := node.dc.NewG1()
-		tmp.PowBig(node.zerosumShareCmt[i], lambda)
-		// log.Printf("label: %d #share %d\nlambda %s\nzeroshareCmt %s\ntmp %s", node.label, i+1, lambda.String(), node.zerosumShareCmt[i].String(), tmp.String())
+		tmp.PowBig(s.zerosumShareCmt[i], lambda)
 		exponentSum.Mul(exponentSum, tmp)
 	}
-	// log.Printf("%d exponentSum: %s", node.label, exponentSum.String())
-	if !exponentSum.Is1() {
-		panic("Proactivization Verification 1 failed")
+	// the zero shares of all nodes together interpolate to zero, any node may have broken that
+	node.mutex.Lock()
+	randomizes := node.randomizes(s)
+	node.mutex.Unlock()
+	var fault *Fault
+	if !exponentSum.Is1() && !randomizes {
+		f := node.complain(s, 2, 0, checkZeroSum, "the zero shares do not sum to zero")
+		fault = &f
 	}
-	flag := true
 	for i := 0; i < node.counter; i++ {
-		if !node.dpc.VerifyEval(node.zerosumPolyCmt[i], gmp.NewInt(0), gmp.NewInt(0), node.zerosumPolyWit[i]) {
-			flag = false
+		if !node.dpc.VerifyEval(s.zerosumPolyCmt[i], gmp.NewInt(0), gmp.NewInt(0), s.zerosumPolyWit[i]) {
+			f := node.complain(s, 2, i+1, checkZeroWitness, "the witness does not show the zero polynomial is zero at 0")
+			if fault == nil {
+				fault = &f
+			}
 		}
 	}
-	if !flag {
-		panic("Proactivization Verification 2 failed")
+	if f := node.refreshKey(s, msgs); f != nil && fault == nil {
+		fault = f
 	}
-	*node.e2 = time.Now()
-	*node.s3 = time.Now()
-	node.ClientSharePhase3()
+	if fault != nil {
+		return abort(*fault)
+	}
+	return nil
 }
 
-// The function that does the real work of sending new secret shares to all nodes.
-func (node *Node) ClientSharePhase3() {
-	node.newPoly.Add(*node.recPoly, *node.proPoly)
-	var wg sync.WaitGroup
+// The function that does the real work of sending new secret shares of a secret to all nodes.
+func (node *Node) ClientSharePhase3(s *sharing) {
+	epoch := node.getEpoch()
+	s.newPoly.Add(*s.recPoly, *s.proPoly)
 	for i := 0; i < node.counter; i++ {
 		eval := gmp.NewInt(0)
-		node.newPoly.EvalMod(gmp.NewInt(int64(i+1)), node.p, eval)
+		s.newPoly.EvalMod(gmp.NewInt(int64(i+1)), node.p, eval)
 		witness := node.dpc.NewG1()
-		node.dpc.CreateWitness(witness, *node.newPoly, gmp.NewInt(int64(i+1)))
+		node.dpc.CreateWitness(witness, *s.newPoly, gmp.NewInt(int64(i+1)))
 		if i != node.label-1 {
-			log.Printf("[node %d] send point message to [node %d] in phase 3", node.label, i+1)
 			msg := &pb.PointMsg{
-				Index:   int32(node.label),
-				X:       int32(i + 1),
-				Y:       eval.Bytes(),
-				Witness: witness.CompressedBytes(),
+				Index:     int32(node.label),
+				X:         int32(i + 1),
+				Y:         eval.Bytes(),
+				Witness:   witness.CompressedBytes(),
+				Epoch:     epoch,
+				Committee: node.committee,
+				Secret:    s.id,
+				Phase:     3,
 			}
-			wg.Add(1)
-			go func(i int, msg *pb.PointMsg) {
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-				defer wg.Done()
-				node.nClient[i].SharePhase3(ctx, msg)
-			}(i, msg)
-		} else {
-			node.secretShares[i].Y.Set(eval)
-			node.secretShares[i].PolyWit.Set(witness)
+			msg.Signature = pb.Sign(node.id, msg)
 			node.mutex.Lock()
-			*node.shareCnt = *node.shareCnt + 1
-			flag := (*node.shareCnt == node.counter)
+			s.sentPoint3[i] = msg
 			node.mutex.Unlock()
-			if flag {
-				*node.shareCnt = 0
-				node.ClientWritePhase3()
-			}
+		} else {
+			s.newShares[i].Y.Set(eval)
+			s.newShares[i].PolyWit.Set(witness)
 		}
 	}
+	go node.broadcastPhase(3, s)
+	node.mutex.Lock()
+	s.recvPoint3[node.label-1] = true
+	s.shareCnt = s.shareCnt + 1
+	flag := (s.shareCnt == node.counter)
+	node.mutex.Unlock()
+	if flag {
+		s.shareCnt = 0
+		node.ClientWritePhase3(s)
+	}
 }
 
-func (node *Node) ClientWritePhase3() {
-	log.Printf("[node %d] write bulletinboard in phase 3", node.label)
-	ctx, cancel := context.WithCancel(context.Background())
+func (node *Node) ClientWritePhase3(s *sharing) {
+	if node.isRecovering() {
+		return
+	}
+	node.phaseEntry(3).WithField("secret", s.id).Info("write bulletinboard")
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
 	defer cancel()
 	C := node.dpc.NewG1()
-	node.dpc.Commit(C, *node.newPoly)
+	node.dpc.Commit(C, *s.newPoly)
 	msg := &pb.Cmt1Msg{
-		Index:   int32(node.label),
-		Polycmt: C.CompressedBytes(),
+		Index:     int32(node.label),
+		Polycmt:   C.CompressedBytes(),
+		Epoch:     node.getEpoch(),
+		Committee: node.committee,
+		Secret:    s.id,
+		Keycmt:    node.refreshedKey(s),
+	}
+	msg.Signature = pb.Sign(node.id, msg)
+	// the bulletinboard may be restarting
+	err := node.traceCall("WritePhase3", peerBoard, func() error {
+		return pb.Retry(func() error {
+			node.countSent("WritePhase3", peerBoard, msg)
+			_, err := node.bClient.WritePhase3(ctx, msg)
+			return err
+		})
+	})
+	if err != nil {
+		node.peerEntry(3, "WritePhase3", peerBoard).WithField("secret", s.id).WithError(err).Error("bulletinboard rejected write")
 	}
-	node.bClient.WritePhase3(ctx, msg)
 }
 
-// Read from the bulletinboard and do the verification in phase 3.
-func (node *Node) ClientReadPhase3() {
-	log.Printf("[node %d] read bulletinboard in phase 3", node.label)
-	ctx, cancel := context.WithCancel(context.Background())
+// Read from the bulletinboard and do the verification in phase 3 for every secret, then complete the epoch. The error tells the bulletinboard what the verification caught.
+func (node *Node) ClientReadPhase3() error {
+	epoch := node.getEpoch()
+	secrets := node.secretList()
+	for _, s := range secrets {
+		if err := node.verifyPhase3(s); err != nil {
+			return err
+		}
+	}
+	// the verified commitments are what phase 1 of the next epoch must find on the bulletinboard, unless the bulletinboard fails the epoch and the node rolls back
+	node.mutex.Lock()
+	if *node.epoch != epoch {
+		node.mutex.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "the node left epoch %d", epoch)
+	}
+	for _, s := range secrets {
+		s.complete()
+	}
+	node.schnorr.complete()
+	*node.previous = *node.completed
+	*node.completed = epoch
+	state := node.shareState(epoch, secrets)
+	node.mutex.Unlock()
+	node.completeEpochMetrics(epoch)
+	if node.store != nil {
+		// the log of the epoch is only dropped once the shares it led to are stored
+		if err := node.store.Save(state); err != nil {
+			node.entry().WithError(err).Error("failed to store shares")
+		} else if err := node.store.TruncateLog(); err != nil {
+			node.entry().WithError(err).Error("failed to truncate the log")
+		}
+	}
+	node.entry().Info("complete epoch")
+	node.publishBeacon(epoch)
+	return nil
+}
+
+// Read the commitments of phase 3 for a secret and check that each refreshes the old one and opens the new share
+func (node *Node) verifyPhase3(s *sharing) error {
+	node.phaseEntry(3).WithField("secret", s.id).Info("read bulletinboard")
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
 	defer cancel()
-	stream, err := node.bClient.ReadPhase3(ctx, &pb.EmptyMsg{})
+	epoch := node.getEpoch()
+	var msgs []*pb.Cmt1Msg
+	err := node.traceCall("ReadPhase3", peerBoard, func() error {
+		stream, err := node.bClient.ReadPhase3(ctx, node.secretMsg(s))
+		if err != nil {
+			return err
+		}
+		msgs = make([]*pb.Cmt1Msg, node.counter)
+		for i := range msgs {
+			if msgs[i], err = stream.Recv(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatalf("client failed to read phase3: %v", err)
+		node.peerEntry(3, "ReadPhase3", peerBoard).WithField("secret", s.id).WithError(err).Error("failed to read bulletinboard")
+		return status.Errorf(codes.Unavailable, "failed to read the bulletinboard: %v", err)
 	}
-	for i := 0; i < node.counter; i++ {
-		msg, err := stream.Recv()
-		*node.totMsgSize = *node.totMsgSize + proto.Size(msg)
-		if err != nil {
-			log.Fatalf("client failed to receive in read phase1: %v", err)
+	read := make([]pb.Committed, 0, node.counter)
+	for _, msg := range msgs {
+		node.countReceived("ReadPhase3", peerBoard, msg)
+		if err := pb.VerifySigned(node.pks, msg); err != nil {
+			return abort(node.complain(s, 3, 0, checkBoard, "share distribution commitment rejected: "+err.Error()))
+		}
+		if err := pb.CheckEpoch(msg, epoch, node.committee); err != nil {
+			return abort(node.complain(s, 3, 0, checkBoard, "share distribution commitment rejected: "+err.Error()))
+		}
+		if msg.GetSecret() != s.id {
+			return abort(node.complain(s, 3, 0, checkBoard, "share distribution commitment rejected: it commits to "+pb.SecretName(msg.GetSecret())))
 		}
 		index := msg.GetIndex()
 		polycmt := msg.GetPolycmt()
-		node.newPolyCmt[index-1].SetCompressedBytes(polycmt)
+		s.newPolyCmt[index-1].SetCompressedBytes(polycmt)
+		read = append(read, msg)
+	}
+	if err := node.verifyLog(epoch, logShareDist, read); err != nil {
+		return abort(node.complain(s, 3, 0, checkBoard, "share distribution commitment rejected: "+err.Error()))
 	}
+	var fault *Fault
 	for i := 0; i < node.counter; i++ {
 		tmp := node.dpc.NewG1()
-		if !node.newPolyCmt[i].Equals(tmp.Mul(node.oldPolyCmt[i], node.midPolyCmt[i])) {
-			panic("Share Distribution Verification 1 failed")
+		if !s.newPolyCmt[i].Equals(tmp.Mul(s.oldPolyCmt[i], s.midPolyCmt[i])) {
+			f := node.complain(s, 3, i+1, checkRefresh, "the new commitment is not the old one refreshed by the proactivization commitment")
+			if fault == nil {
+				fault = &f
+			}
+			continue
 		}
-		if !node.dpc.VerifyEval(node.newPolyCmt[i], gmp.NewInt(int64(node.label)), node.secretShares[i].Y, node.secretShares[i].PolyWit) {
-			panic("Share Distribution Verification 2 failed")
+		if !node.dpc.VerifyEval(s.newPolyCmt[i], gmp.NewInt(int64(node.label)), s.newShares[i].Y, s.newShares[i].PolyWit) {
+			f := node.complain(s, 3, i+1, checkNewShare, "the new share does not match the new commitment")
+			if fault == nil {
+				fault = &f
+			}
 		}
+	}
+	if f := node.verifyRefreshedKey(s, msgs); f != nil && fault == nil {
+		fault = f
+	}
+	if fault != nil {
+		return abort(*fault)
+	}
+	return nil
+}
 
+// The commitments read in phase 1 were written by the nodes in the epoch that last refreshed the secret and must carry their signatures. Only the genesis commitment, which every node derives from the fixed seed, and the commitment of a dealing, which the operator sent along with the shares, are accepted unsigned. Either way the commitment must be the one this node verified when it last took shares of the secret.
+func (node *Node) verifyOldPolyCmt(s *sharing, msg *pb.Cmt1Msg, from int64) error {
+	if epoch := node.getEpoch(); from >= epoch {
+		return errors.New(fmt.Sprintf("commitments of epoch %d cannot start epoch %d", from, epoch))
 	}
-	*node.e3 = time.Now()
-	f, _ := os.OpenFile(node.metadataPath+"/log"+strconv.Itoa(node.label), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	defer f.Close()
-	fmt.Fprintf(f, "totMsgSize,%d\n", *node.totMsgSize)
-	fmt.Fprintf(f, "epochLatency,%d\n", node.e3.Sub(*node.s1).Nanoseconds())
-	fmt.Fprintf(f, "reconstructionLatency,%d\n", node.e1.Sub(*node.s1).Nanoseconds())
-	fmt.Fprintf(f, "proactivizationLatency,%d\n", node.e2.Sub(*node.s2).Nanoseconds())
-	fmt.Fprintf(f, "sharedistLatency,%d\n", node.e3.Sub(*node.s3).Nanoseconds())
-	*node.totMsgSize = 0
-	for i := 0; i < node.counter; i++ {
-		node.zeroShares[i].SetInt64(0)
+	if err := pb.CheckEpoch(msg, from, node.committee); err != nil {
+		return err
+	}
+	if msg.GetSecret() != s.id {
+		return errors.New(fmt.Sprintf("commitment to %s", pb.SecretName(msg.GetSecret())))
+	}
+	index := int(msg.GetIndex())
+	if index < 1 || index > node.counter {
+		return errors.New(fmt.Sprintf("commitment index %d out of range", index))
 	}
-	node.zeroShare.SetInt64(0)
+	node.mutex.Lock()
+	dealt := s.dealt
+	node.mutex.Unlock()
+	if (from > 0 && !dealt) || len(msg.GetSignature()) != 0 {
+		if err := pb.VerifySigned(node.pks, msg); err != nil {
+			return err
+		}
+	}
+	cmt := node.dpc.NewG1()
+	cmt.SetCompressedBytes(msg.GetPolycmt())
+	if !cmt.Equals(s.oldPolyCmt[index-1]) {
+		return errors.New(fmt.Sprintf("commitment for node %d differs from the one verified in epoch %d", index, from))
+	}
+	if s.id == "" && index == node.label && len(msg.GetKeycmt()) != 0 {
+		return node.checkHeldKey(msg.GetKeycmt(), from)
+	}
+	return nil
 }
 
 func ReadIpList(metadataPath string) []string {
 	ipData, err := ioutil.ReadFile(metadataPath + "/ip_list")
 	if err != nil {
-		log.Fatalf("node failed to read iplist %v\n", err)
+		logrus.Fatalf("node failed to read iplist %v", err)
 	}
 	return strings.Split(string(ipData), "\n")
 }
 
-// New a Network Node Structure
-func New(degree int, label int, counter int, metadataPath string) (Node, error) {
+// New a Network Node Structure that talks to its peers over tr
+// With a non-empty passphrase the shares are stored encrypted after every epoch, and a node that finds stored shares resumes from them.
+func New(degree int, label int, counter int, metadataPath string, passphrase []byte, tr transport.Transport) (Node, error) {
 	f, _ := os.Create(metadataPath + "/log" + strconv.Itoa(label))
 	defer f.Close()
 
 	ipRaw := ReadIpList(metadataPath)[0 : counter+1]
 	bip := ipRaw[0]
 	ipList := ipRaw[1 : counter+1]
+	logger := logrus.WithFields(logrus.Fields{"component": "node", "node": label})
 
 	if label < 0 {
 		return Node{}, errors.New(fmt.Sprintf("label must be non-negative, got %d", label))
@@ -599,6 +1088,25 @@ func New(degree int, label int, counter int, metadataPath string) (Node, error)
 		return Node{}, errors.New(fmt.Sprintf("counter must be non-negtive, got %d", counter))
 	}
 
+	id, err := identity.Load(identity.KeyPath(metadataPath, label))
+	if err != nil {
+		return Node{}, err
+	}
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return Node{}, err
+	}
+
+	operator, err := identity.ReadOperatorPk(metadataPath)
+	if err != nil {
+		return Node{}, err
+	}
+
+	committee := identity.CommitteeID(pks)
+	epoch := int64(0)
+	completed := int64(0)
+	previous := int64(-1)
+
 	randState := rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
 	fixedRandState := rand.New(rand.NewSource(int64(3)))
 	dc := commitment.DLCommit{}
@@ -631,110 +1139,114 @@ func New(degree int, label int, counter int, metadataPath string) (Node, error)
 		lambda[i].Mod(lambda[i], p)
 	}
 
-	zeroShares := make([]*gmp.Int, counter)
+	// the genesis secret, every node derives its polynomials from the fixed seed
+	genesis := newSharing("", label, counter, degree, &dc, &dpc)
+	poly, err := polyring.NewRand(degree, fixedRandState, p)
+	if err != nil {
+		panic("Error initializing random poly")
+	}
+	genesisCmt := dpc.NewG1()
+	dpc.Commit(genesisCmt, poly)
 	for i := 0; i < counter; i++ {
-		zeroShares[i] = gmp.NewInt(0)
+		poly.EvalMod(gmp.NewInt(int64(label)), p, genesis.secretShares[i].Y)
+		dpc.CreateWitness(genesis.secretShares[i].PolyWit, poly, gmp.NewInt(int64(label)))
+		genesis.oldPolyCmt[i].Set(genesisCmt)
 	}
-	zeroCnt := 0
-	zeroShare := gmp.NewInt(0)
+	secrets := []*sharing{genesis}
 
-	recShares := make([]*polypoint.PolyPoint, counter)
-	recCnt := 0
+	nConn := make([]transport.Conn, counter)
+	nClient := make([]pb.NodeServiceClient, counter)
 
-	secretShares := make([]*polypoint.PolyPoint, counter)
-	poly, err := polyring.NewRand(degree, fixedRandState, p)
-	for i := 0; i < counter; i++ {
+	schnorr := newSchnorrState()
+	var store *sharestore.Store
+	if len(passphrase) > 0 {
+		store, err = sharestore.Open(sharestore.Path(metadataPath, label), passphrase)
 		if err != nil {
-			panic("Error initializing random poly")
+			return Node{}, err
+		}
+		state, err := store.Load()
+		switch err {
+		case nil:
+			secrets, err = restoreShareState(state, committee, label, counter, degree, &dc, &dpc, genesis)
+			if err != nil {
+				return Node{}, err
+			}
+			if schnorr.key, err = restoreKey(state.Schnorr); err != nil {
+				return Node{}, err
+			}
+			if state.Previous != nil {
+				if err := restorePrevious(state.Previous, committee, label, counter, degree, &dc, &dpc, secrets); err != nil {
+					return Node{}, err
+				}
+				if schnorr.prev, err = restoreKey(state.Previous.Schnorr); err != nil {
+					return Node{}, err
+				}
+				previous = state.Previous.Epoch
+			}
+			epoch = state.Epoch
+			completed = state.Epoch
+			logger.WithField("epoch", state.Epoch).Info("resume from the stored shares")
+		case sharestore.ErrNoState:
+			logger.Info("no stored shares, start from genesis")
+		default:
+			return Node{}, err
 		}
-		x := int32(label)
-		y := gmp.NewInt(0)
-		w := dpc.NewG1()
-		poly.EvalMod(gmp.NewInt(int64(x)), p, y)
-		dpc.CreateWitness(w, poly, gmp.NewInt(int64(x)))
-		secretShares[i] = polypoint.NewPoint(x, y, w)
-	}
-
-	proPoly, _ := polyring.New(degree)
-	recPoly, _ := polyring.New(degree)
-	newPoly, _ := polyring.New(degree)
-	shareCnt := 0
-
-	oldPolyCmt := make([]*pbc.Element, counter)
-	midPolyCmt := make([]*pbc.Element, counter)
-	newPolyCmt := make([]*pbc.Element, counter)
-	for i := 0; i < counter; i++ {
-		oldPolyCmt[i] = dpc.NewG1()
-		midPolyCmt[i] = dpc.NewG1()
-		newPolyCmt[i] = dpc.NewG1()
 	}
 
-	zeroShareCmt := dc.NewG1()
-	zeroPolyCmt := dpc.NewG1()
-	zeroPolyWit := dpc.NewG1()
+	recovering := store != nil
+	verif2 := false
+	verif3 := false
+	reconstructed := 0
 
-	zerosumShareCmt := make([]*pbc.Element, counter)
-	zerosumPolyCmt := make([]*pbc.Element, counter)
-	zerosumPolyWit := make([]*pbc.Element, counter)
+	nodeMetrics := newNodeMetrics(label, counter, degree, committee)
+	nodeMetrics.epoch.Set(float64(epoch))
+	nodeMetrics.completed.Set(float64(completed))
 
-	for i := 0; i < counter; i++ {
-		zerosumShareCmt[i] = dc.NewG1()
-		zerosumPolyCmt[i] = dpc.NewG1()
-		zerosumPolyWit[i] = dpc.NewG1()
+	health := map[string]*transport.Health{peerBoard: &transport.Health{}}
+	for i := 1; i <= counter; i++ {
+		if i != label {
+			health[fmt.Sprintf("node%d", i)] = &transport.Health{}
+		}
 	}
-
-	totMsgSize := 0
-	s1 := time.Now()
-	e1 := time.Now()
-	s2 := time.Now()
-	e2 := time.Now()
-	s3 := time.Now()
-	e3 := time.Now()
-
-	nConn := make([]*grpc.ClientConn, counter)
-	nClient := make([]pb.NodeServiceClient, counter)
-
-	iniflag := true
+	ctx, cancel := context.WithCancel(context.Background())
+	draining := false
 	return Node{
-		metadataPath:    metadataPath,
-		bip:             bip,
-		ipList:          ipList,
-		degree:          degree,
-		label:           label,
-		counter:         counter,
-		randState:       randState,
-		dc:              &dc,
-		dpc:             &dpc,
-		p:               p,
-		lambda:          lambda,
-		zeroShares:      zeroShares,
-		zeroCnt:         &zeroCnt,
-		zeroShare:       zeroShare,
-		secretShares:    secretShares,
-		recShares:       recShares,
-		recCnt:          &recCnt,
-		recPoly:         &recPoly,
-		proPoly:         &proPoly,
-		newPoly:         &newPoly,
-		shareCnt:        &shareCnt,
-		oldPolyCmt:      oldPolyCmt,
-		midPolyCmt:      midPolyCmt,
-		newPolyCmt:      newPolyCmt,
-		zeroShareCmt:    zeroShareCmt,
-		zeroPolyCmt:     zeroPolyCmt,
-		zeroPolyWit:     zeroPolyWit,
-		zerosumShareCmt: zerosumShareCmt,
-		zerosumPolyCmt:  zerosumPolyCmt,
-		zerosumPolyWit:  zerosumPolyWit,
-		totMsgSize:      &totMsgSize,
-		s1:              &s1,
-		e1:              &e1,
-		s2:              &s2,
-		e2:              &e2,
-		s3:              &s3,
-		e3:              &e3,
-		nConn:           nConn,
-		nClient:         nClient,
-		iniflag:         &iniflag,
+		metadataPath:  metadataPath,
+		bip:           bip,
+		ipList:        ipList,
+		degree:        degree,
+		label:         label,
+		counter:       counter,
+		randState:     randState,
+		transport:     tr,
+		dc:            &dc,
+		dpc:           &dpc,
+		id:            id,
+		pks:           pks,
+		operator:      operator,
+		store:         store,
+		recovering:    &recovering,
+		verif2:        &verif2,
+		verif3:        &verif3,
+		audits:        make(map[int64]*pb.AuditMsg),
+		faults:        make(map[int64][]Fault),
+		p:             p,
+		epoch:         &epoch,
+		completed:     &completed,
+		previous:      &previous,
+		committee:     committee,
+		lambda:        lambda,
+		secrets:       secrets,
+		reconstructed: &reconstructed,
+		schnorr:       schnorr,
+		metrics:       nodeMetrics,
+		logger:        logger,
+		steps:         newSteps(label),
+		nConn:         nConn,
+		nClient:       nClient,
+		health:        health,
+		ctx:           ctx,
+		cancel:        cancel,
+		draining:      &draining,
 	}, nil
 }
@@ -0,0 +1,499 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"github.com/golang/protobuf/proto"
+	"github.com/ncw/gmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kinds of records in the write-ahead log. A record is appended before the node acts on what it describes.
+const (
+	// The node entered the epoch
+	recordEnter = "enter"
+	// A point message of phase 1 arrived
+	recordPoint1 = "point1"
+	// The node drew its zero shares and zero polynomial
+	recordZeroRand = "zerorand"
+	// A zero message of phase 2 arrived
+	recordZero = "zero"
+	// The bulletinboard started verification in phase 2
+	recordVerif2 = "verif2"
+	// A point message of phase 3 arrived
+	recordPoint3 = "point3"
+	// The bulletinboard started verification in phase 3
+	recordVerif3 = "verif3"
+)
+
+// Answer of every RPC while the node replays its log. Senders retry on Unavailable.
+var errRecovering = status.Error(codes.Unavailable, "node is recovering")
+
+// The randomness a node draws in phase 2 for a secret. It is logged because peers and the bulletinboard already depend on it.
+type zeroRand struct {
+	// ID of the secret
+	Secret string
+	// Zero shares for all nodes, indexed by label - 1
+	Shares [][]byte
+	// Coefficients of the zero polynomial, lowest degree first
+	Poly [][]byte
+	// Coefficients of the zero polynomial refreshing the P-521 key from degree 1 up, along with the default secret if the node holds a key
+	Key [][]byte
+}
+
+// Resend
+// A node recovering from a crash asks for the messages of a phase of the current epoch it may have missed. The message of every secret goes out again through the usual RPC of its phase.
+func (node *Node) Resend(ctx context.Context, in *pb.ResendMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := node.checkEpoch(in); err != nil {
+		return nil, err
+	}
+	index := int(in.GetIndex())
+	if index < 1 || index > node.counter || index == node.label {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot resend to node %d", index)
+	}
+	phase := in.GetPhase()
+	if phase < 1 || phase > 3 {
+		return nil, status.Errorf(codes.InvalidArgument, "no phase %d", phase)
+	}
+	sent := make([]*sharing, 0)
+	for _, s := range node.secretList() {
+		if node.hasSent(phase, s, index-1) {
+			sent = append(sent, s)
+		}
+	}
+	if len(sent) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "no message of phase %d yet", phase)
+	}
+	logger := node.peerEntry(phase, "Resend", node.peer(int32(index)))
+	logger.Info("resend message")
+	go func() {
+		for _, s := range sent {
+			if err := node.sendPhase(phase, s, index-1); err != nil {
+				logger.WithField("secret", s.id).WithError(err).Error("failed to resend message")
+			}
+		}
+	}()
+	return node.ack(), nil
+}
+
+// Recover replays the write-ahead log of an epoch the node entered but did not complete, then catches up with its peers.
+// The node answers every RPC with Unavailable until the replay is over.
+func (node *Node) Recover() {
+	if !node.isRecovering() {
+		return
+	}
+	completed := *node.completed
+	records, err := node.store.ReadLog()
+	if err != nil {
+		node.logger.WithError(err).Fatal("failed to read the log")
+	}
+	// the log holds the records of one epoch, which follows the completed one directly or after epochs that failed
+	epoch := completed + 1
+	if len(records) > 0 && records[len(records)-1].Epoch > epoch {
+		epoch = records[len(records)-1].Epoch
+	}
+	pending := make([]*sharestore.Record, 0)
+	for _, rec := range records {
+		if rec.Epoch == epoch {
+			pending = append(pending, rec)
+		}
+	}
+	if len(pending) == 0 {
+		if len(records) > 0 {
+			if err := node.store.TruncateLog(); err != nil {
+				node.logger.WithError(err).Error("failed to truncate the log")
+			}
+		}
+		node.setRecovering(false)
+		return
+	}
+
+	node.logger.WithField("epoch", epoch).Infof("replay %d records", len(pending))
+	node.mutex.Lock()
+	*node.epoch = epoch
+	node.resetEpochState()
+	node.mutex.Unlock()
+	for _, rec := range pending {
+		if rec.Kind == recordZeroRand {
+			rnd := &zeroRand{}
+			if err := json.Unmarshal(rec.Data, rnd); err != nil {
+				node.logger.WithField("epoch", epoch).WithError(err).Fatal("corrupted zero shares in the log")
+			}
+			s, err := node.lookup(rnd.Secret)
+			if err != nil {
+				node.logger.WithField("epoch", epoch).WithError(err).Fatal("zero shares in the log for a secret the node does not hold")
+			}
+			s.replayZero = rnd
+		}
+	}
+	for _, rec := range pending {
+		if err := node.replay(rec); err != nil {
+			node.logger.WithField("epoch", epoch).WithError(err).Fatalf("failed to replay %s record", rec.Kind)
+		}
+	}
+	node.mutex.Lock()
+	for _, s := range node.secrets {
+		s.replayZero = nil
+	}
+	node.mutex.Unlock()
+	node.setRecovering(false)
+	node.entry().Info("replayed epoch")
+
+	node.mutex.Lock()
+	done := *node.completed == epoch
+	node.mutex.Unlock()
+	if !done {
+		node.catchUp()
+	}
+}
+
+// Rebuild the state a record led to. Nothing is sent while replaying.
+func (node *Node) replay(rec *sharestore.Record) error {
+	switch rec.Kind {
+	case recordEnter:
+		node.ClientSharePhase1()
+	case recordPoint1, recordPoint3:
+		msg := &pb.PointMsg{}
+		if err := proto.Unmarshal(rec.Data, msg); err != nil {
+			return err
+		}
+		s, err := node.lookup(msg.GetSecret())
+		if err != nil {
+			return err
+		}
+		if rec.Kind == recordPoint1 {
+			return node.receivePoint1(s, msg)
+		}
+		return node.receivePoint3(s, msg)
+	case recordZero:
+		msg := &pb.ZeroMsg{}
+		if err := proto.Unmarshal(rec.Data, msg); err != nil {
+			return err
+		}
+		s, err := node.lookup(msg.GetSecret())
+		if err != nil {
+			return err
+		}
+		return node.receiveZero(s, msg)
+	case recordVerif2:
+		node.markOnce(node.verif2)
+		node.ClientReadPhase2()
+	case recordVerif3:
+		node.markOnce(node.verif3)
+		node.ClientReadPhase3()
+	}
+	return nil
+}
+
+// After a replay, send again everything the node had sent before the crash, since peers drop duplicates and the bulletinboard accepts an identical rewrite, and ask peers for the messages that have not arrived.
+func (node *Node) catchUp() {
+	for _, s := range node.secretList() {
+		node.mutex.Lock()
+		wrote2 := allTrue(s.recvZero)
+		wrote3 := allTrue(s.recvPoint3)
+		node.mutex.Unlock()
+		for phase := int32(1); phase <= 3; phase++ {
+			go node.broadcastPhase(phase, s)
+		}
+		if wrote2 {
+			node.ClientWritePhase2(s)
+		}
+		if wrote3 {
+			node.ClientWritePhase3(s)
+		}
+	}
+	node.requestMissing()
+}
+
+// Ask every peer whose message of a phase is missing to send it again.
+// A peer that has not reached the phase yet refuses and sends the message once it gets there.
+func (node *Node) requestMissing() {
+	epoch := node.getEpoch()
+	var wg sync.WaitGroup
+	for phase := int32(1); phase <= 3; phase++ {
+		for i := 0; i < node.counter; i++ {
+			if i == node.label-1 || node.received(phase, i) {
+				continue
+			}
+			msg := &pb.ResendMsg{
+				Index:     int32(node.label),
+				Phase:     phase,
+				Epoch:     epoch,
+				Committee: node.committee,
+			}
+			wg.Add(1)
+			go func(i int, msg *pb.ResendMsg) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
+				defer cancel()
+				err := pb.Retry(func() error {
+					_, err := node.nClient[i].Resend(ctx, msg)
+					return err
+				})
+				if err != nil {
+					node.peerEntry(msg.GetPhase(), "Resend", node.peer(int32(i+1))).WithError(err).Warn("peer did not resend its message")
+				}
+			}(i, msg)
+		}
+	}
+	wg.Wait()
+}
+
+// Send the messages of a phase for a secret to every peer they were built for. Nothing is sent while replaying.
+func (node *Node) broadcastPhase(phase int32, s *sharing) {
+	if node.isRecovering() {
+		return
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < node.counter; i++ {
+		if i != node.label-1 && node.hasSent(phase, s, i) {
+			logger := node.peerEntry(phase, fmt.Sprintf("SharePhase%d", phase), node.peer(int32(i+1))).WithField("secret", s.id)
+			logger.Debug("send message")
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := node.sendPhase(phase, s, i); err != nil {
+					logger.WithError(err).Error("failed to send message")
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+}
+
+// Deliver the message of a phase for a secret built for node i+1
+func (node *Node) sendPhase(phase int32, s *sharing, i int) error {
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
+	defer cancel()
+	node.mutex.Lock()
+	point1 := s.sentPoint1[i]
+	zero := s.sentZero[i]
+	point3 := s.sentPoint3[i]
+	node.mutex.Unlock()
+	peer := node.peer(int32(i + 1))
+	return node.traceCall(fmt.Sprintf("SharePhase%d", phase), peer, func() error {
+		return pb.Retry(func() error {
+			var err error
+			switch phase {
+			case 1:
+				node.countSent("SharePhase1", peer, point1)
+				_, err = node.nClient[i].SharePhase1(ctx, point1)
+			case 2:
+				node.countSent("SharePhase2", peer, zero)
+				_, err = node.nClient[i].SharePhase2(ctx, zero)
+			case 3:
+				node.countSent("SharePhase3", peer, point3)
+				_, err = node.nClient[i].SharePhase3(ctx, point3)
+			}
+			return err
+		})
+	})
+}
+
+func (node *Node) hasSent(phase int32, s *sharing, i int) bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	switch phase {
+	case 1:
+		return s.sentPoint1[i] != nil
+	case 2:
+		return s.sentZero[i] != nil
+	case 3:
+		return s.sentPoint3[i] != nil
+	}
+	return false
+}
+
+// The message of a phase from node i+1 arrived for every secret
+func (node *Node) received(phase int32, i int) bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	for _, s := range node.secrets {
+		var recv []bool
+		switch phase {
+		case 1:
+			recv = s.recvPoint1
+		case 2:
+			recv = s.recvZero
+		case 3:
+			recv = s.recvPoint3
+		}
+		if recv == nil || !recv[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Mark the message of node index as received. False if it had already arrived.
+func (node *Node) markReceived(recv []bool, index int32) bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if recv[index-1] {
+		return false
+	}
+	recv[index-1] = true
+	return true
+}
+
+func (node *Node) unmarkReceived(recv []bool, index int32) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	recv[index-1] = false
+}
+
+// Set a flag of the current epoch. False if it was set already.
+func (node *Node) markOnce(flag *bool) bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if *flag {
+		return false
+	}
+	*flag = true
+	return true
+}
+
+func (node *Node) unmarkOnce(flag *bool) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	*flag = false
+}
+
+func allTrue(flags []bool) bool {
+	for _, flag := range flags {
+		if !flag {
+			return false
+		}
+	}
+	return true
+}
+
+func (node *Node) isRecovering() bool {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return *node.recovering
+}
+
+func (node *Node) setRecovering(recovering bool) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	*node.recovering = recovering
+}
+
+// Append a record of the given epoch to the log, if the node keeps one
+func (node *Node) appendLog(epoch int64, kind string, data []byte) error {
+	if node.store == nil {
+		return nil
+	}
+	return node.store.AppendLog(&sharestore.Record{
+		Epoch: epoch,
+		Kind:  kind,
+		Data:  data,
+	})
+}
+
+// Log a record of the current epoch. Records are not logged again while they are replayed.
+func (node *Node) logRecord(kind string, msg proto.Message) error {
+	if node.store == nil || node.isRecovering() {
+		return nil
+	}
+	var data []byte
+	if msg != nil {
+		var err error
+		data, err = proto.Marshal(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return node.appendLog(node.getEpoch(), kind, data)
+}
+
+// Draw the zero shares and the zero polynomial of phase 2 for a secret and log them before anything depending on them leaves the node.
+// While replaying, the logged ones are taken instead.
+func (node *Node) drawZeroShares(s *sharing) error {
+	if rnd := s.replayZero; rnd != nil {
+		s.replayZero = nil
+		for i := 0; i < node.counter; i++ {
+			s.zeroShares[i].SetBytes(rnd.Shares[i])
+		}
+		poly, _ := polyring.New(node.degree)
+		for i := 0; i <= node.degree; i++ {
+			coeff := gmp.NewInt(0)
+			coeff.SetBytes(rnd.Poly[i])
+			poly.SetCoefficientBig(i, coeff)
+		}
+		s.proPoly.ResetTo(poly)
+		_, err := node.drawKeyZero(s, rnd)
+		return err
+	}
+	// Generate Random Numbers
+	// the secrets draw from the same source, possibly at the same time
+	node.mutex.Lock()
+	if node.randomizes(s) {
+		// shares drawn at random add a random value to the secret instead of zero
+		for i := 0; i < node.counter; i++ {
+			s.zeroShares[i].Rand(node.randState, node.p)
+		}
+	} else {
+		for i := 0; i < node.counter-1; i++ {
+			s.zeroShares[i].Rand(node.randState, node.p)
+			inter := gmp.NewInt(0)
+			inter.Mul(s.zeroShares[i], node.lambda[i])
+			s.zeroShares[node.counter-1].Sub(s.zeroShares[node.counter-1], inter)
+		}
+		s.zeroShares[node.counter-1].Mod(s.zeroShares[node.counter-1], node.p)
+		inter := gmp.NewInt(0)
+		inter.ModInverse(node.lambda[node.counter-1], node.p)
+		s.zeroShares[node.counter-1].Mul(s.zeroShares[node.counter-1], inter)
+		s.zeroShares[node.counter-1].Mod(s.zeroShares[node.counter-1], node.p)
+	}
+	poly, _ := polyring.NewRand(node.degree, node.randState, node.p)
+	node.mutex.Unlock()
+	poly.SetCoefficient(0, 0)
+	s.proPoly.ResetTo(poly)
+
+	rnd := zeroRand{
+		Secret: s.id,
+		Shares: make([][]byte, node.counter),
+		Poly:   make([][]byte, node.degree+1),
+	}
+	for i := 0; i < node.counter; i++ {
+		rnd.Shares[i] = s.zeroShares[i].Bytes()
+	}
+	for i := 0; i <= node.degree; i++ {
+		coeff, _ := poly.GetCoefficient(i)
+		rnd.Poly[i] = coeff.Bytes()
+	}
+	key, err := node.drawKeyZero(s, nil)
+	if err != nil {
+		return err
+	}
+	rnd.Key = key
+	data, err := json.Marshal(rnd)
+	if err != nil {
+		return err
+	}
+	return node.appendLog(node.getEpoch(), recordZeroRand, data)
+}
+
+// Commit to the summed zero share and the zero polynomial of a secret, then move the zero share into the constant term of the proactivization polynomial
+func (node *Node) commitZeroShare(s *sharing) {
+	s.zeroShare.Mod(s.zeroShare, node.p)
+	node.dc.Commit(s.zeroShareCmt, s.zeroShare)
+	poly := s.proPoly.DeepCopy()
+	node.dpc.Commit(s.zeroPolyCmt, poly)
+	node.dpc.CreateWitness(s.zeroPolyWit, poly, gmp.NewInt(0))
+
+	poly.SetCoefficientBig(0, s.zeroShare)
+	s.proPoly.ResetTo(poly)
+}
@@ -0,0 +1,265 @@
+package nodes
+
+import (
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"math/big"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/frost"
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Most signing sessions a node keeps nonces for at a time, opening one more drops the oldest
+const maxSessions = 256
+
+// Longest session ID the operator may pick
+const maxSessionLen = 64
+
+// The share of the P-521 signing key of the node and the nonces of its open signing sessions, guarded by the mutex of the node
+type schnorrState struct {
+	key *schnorrKey
+	// [+] Key from before the latest epoch the node completed, kept until the bulletinboard confirms that epoch
+	prev *schnorrKey
+	// [+] Refresh of the key in the current epoch, nil if the node entered it without a key
+	refresh *keyRefresh
+	nonces  map[string]*session
+	// IDs of the open sessions from the oldest on
+	order []string
+}
+
+type schnorrKey struct {
+	share      *big.Int
+	commitment p521.PolyCommit
+	// Set until an epoch refreshes the key the operator dealt
+	dealt bool
+}
+
+// The nonces the node committed to in the first round of a signing session
+type session struct {
+	nonce      *frost.Nonce
+	commitment *frost.Commitment
+}
+
+func newSchnorrState() *schnorrState {
+	return &schnorrState{nonces: make(map[string]*session)}
+}
+
+// A key for the share storage, nil if the node holds none
+func storedKey(key *schnorrKey) *sharestore.SchnorrKey {
+	if key == nil {
+		return nil
+	}
+	return &sharestore.SchnorrKey{
+		Share:      key.share.Bytes(),
+		Commitment: key.commitment.Compressed(),
+		Dealt:      key.dealt,
+	}
+}
+
+// Take a key back from the share storage
+func restoreKey(stored *sharestore.SchnorrKey) (*schnorrKey, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	commitment, err := p521.ParsePolyCommit(stored.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	return &schnorrKey{share: new(big.Int).SetBytes(stored.Share), commitment: commitment, dealt: stored.Dealt}, nil
+}
+
+// Hold another key. The nonces committed to under the one before are dropped, a signer must answer with the share its commitment was checked against.
+func (s *schnorrState) setKey(key *schnorrKey) {
+	s.key = key
+	s.nonces = make(map[string]*session)
+	s.order = nil
+}
+
+// Keep the nonces of a new session, dropping the oldest session once too many are open.
+// Sessions the operator opened but never finished, like those of signers it did not pick, would otherwise pile up.
+func (s *schnorrState) open(id string, nonces *session) {
+	for len(s.order) >= maxSessions {
+		delete(s.nonces, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.nonces[id] = nonces
+	s.order = append(s.order, id)
+}
+
+// Take the nonces of a session out for good
+func (s *schnorrState) close(id string) (*session, bool) {
+	nonces, ok := s.nonces[id]
+	if !ok {
+		return nil, false
+	}
+	delete(s.nonces, id)
+	for i, open := range s.order {
+		if open == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nonces, true
+}
+
+// StoreKey
+// Take the share of a P-521 signing key the operator dealt. The key is shared apart from the secret of the committee, and every epoch refreshes it along with the default secret, see keyrefresh.go.
+// The node must be between epochs, since it stores the key along with its shares, and the bulletinboard must have confirmed the last one. Nonces committed to under a previous key are dropped, and the same key sent again is acknowledged.
+func (node *Node) StoreKey(ctx context.Context, msg *pb.DealtKeyMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	entry := node.entry().WithField("rpc", "StoreKey")
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		entry.WithError(err).Warn("reject dealt key")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if msg.GetX() != int32(node.label) {
+		return nil, status.Errorf(codes.InvalidArgument, "the share of node %d was sent to node %d", msg.GetX(), node.label)
+	}
+	commitment, err := p521.ParsePolyCommit(msg.GetCommitment())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed commitment: %v", err)
+	}
+	if commitment.Degree() != node.degree {
+		return nil, status.Errorf(codes.InvalidArgument, "the commitment is to a polynomial of degree %d, need %d", commitment.Degree(), node.degree)
+	}
+	share := new(big.Int).SetBytes(msg.GetShare())
+	if share.Cmp(p521.Curve.Params().N) >= 0 || !commitment.VerifyEval(big.NewInt(int64(node.label)), share) {
+		return nil, status.Error(codes.InvalidArgument, "the share does not match the commitment")
+	}
+	// a rollback of the last epoch would otherwise bring back the key held before it
+	if err := node.settle(); err != nil {
+		return nil, err
+	}
+
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	ack := &pb.AckMsg{
+		Epoch:     *node.completed,
+		Committee: node.committee,
+	}
+	if key := node.schnorr.key; key != nil && key.commitment.Equals(commitment) && key.share.Cmp(share) == 0 {
+		return ack, nil
+	}
+	if *node.epoch != *node.completed {
+		return nil, status.Errorf(codes.FailedPrecondition, "epoch %d has not completed", *node.epoch)
+	}
+	if *node.previous >= 0 {
+		return nil, status.Errorf(codes.Unavailable, "epoch %d is not confirmed yet", *node.completed)
+	}
+	previous := node.schnorr.key
+	node.schnorr.key = &schnorrKey{share: share, commitment: commitment, dealt: true}
+	if node.store != nil {
+		if err := node.store.Save(node.shareState(*node.completed, node.secrets)); err != nil {
+			node.schnorr.key = previous
+			entry.WithError(err).Error("failed to store dealt key")
+			return nil, status.Errorf(codes.Internal, "failed to store the key: %v", err)
+		}
+	}
+	node.schnorr.setKey(node.schnorr.key)
+	node.logger.WithField("epoch", *node.completed).Info("store dealt signing key")
+	return ack, nil
+}
+
+// CommitNonce
+// Draw fresh nonces for the signing session the operator opens, and answer with their commitments along with the commitment to the key they will sign with.
+// The nonces are kept until the operator asks for the signature of the session or too many newer sessions are open, and every session gets its own.
+func (node *Node) CommitNonce(ctx context.Context, msg *pb.NonceRequestMsg) (*pb.NonceCommitMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		node.entry().WithField("rpc", "CommitNonce").WithError(err).Warn("refuse to commit to nonces")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	id := msg.GetSession()
+	if len(id) == 0 || len(id) > maxSessionLen {
+		return nil, status.Errorf(codes.InvalidArgument, "a session ID takes 1 to %d bytes, got %d", maxSessionLen, len(id))
+	}
+	nonce, commitment, err := frost.NewNonce(node.label, crand.Reader)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to draw nonces: %v", err)
+	}
+
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if node.schnorr.key == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no signing key was dealt")
+	}
+	if _, ok := node.schnorr.nonces[string(id)]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "session %x is open already", id)
+	}
+	node.schnorr.open(string(id), &session{nonce: nonce, commitment: commitment})
+	return &pb.NonceCommitMsg{
+		Index:   int32(node.label),
+		Session: id,
+		Hiding:  commitment.Hiding.Bytes(),
+		Binding: commitment.Binding.Bytes(),
+		Key:     node.schnorr.key.commitment.Compressed(),
+	}, nil
+}
+
+// SignSchnorr
+// Answer the second round of a signing session with the share of the key, given the commitments of all signers of the session.
+// The nonces of the session are dropped whatever the outcome, so they never sign twice.
+func (node *Node) SignSchnorr(ctx context.Context, msg *pb.SchnorrRequestMsg) (*pb.SchnorrShareMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		node.entry().WithField("rpc", "SignSchnorr").WithError(err).Warn("refuse to sign")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	id := msg.GetSession()
+	node.mutex.Lock()
+	s, ok := node.schnorr.close(string(id))
+	key := node.schnorr.key
+	node.mutex.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "session %x is not open", id)
+	}
+
+	commitments := make([]*frost.Commitment, len(msg.GetCommitments()))
+	own := false
+	for i, c := range msg.GetCommitments() {
+		commitment, err := frost.ParseCommitment(int(c.GetIndex()), c.GetHiding(), c.GetBinding())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "malformed commitment of node %d: %v", c.GetIndex(), err)
+		}
+		if commitment.Index == node.label {
+			if !commitment.Hiding.Equals(s.commitment.Hiding) || !commitment.Binding.Equals(s.commitment.Binding) {
+				return nil, status.Errorf(codes.InvalidArgument, "the commitment of node %d is not the one it made", node.label)
+			}
+			own = true
+		}
+		commitments[i] = commitment
+	}
+	if !own {
+		return nil, status.Errorf(codes.InvalidArgument, "the commitments leave out node %d", node.label)
+	}
+	z, err := frost.Sign(node.label, key.share, s.nonce, key.commitment.EvalInExponent(big.NewInt(0)), msg.GetMessage(), commitments)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot sign: %v", err)
+	}
+	node.logger.WithField("session", fmt.Sprintf("%x", id)).Info("sign with the P-521 key for the operator")
+	return &pb.SchnorrShareMsg{
+		Index:    int32(node.label),
+		Session:  id,
+		Response: z.Bytes(),
+	}, nil
+}
@@ -0,0 +1,206 @@
+package nodes
+
+import (
+	"bytes"
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/ncw/gmp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Store
+// Take the share of a secret the operator dealt under an ID in an epoch the bulletinboard recorded without phases. Every polynomial of the secret becomes the dealt one, so the next epoch reconstructs and refreshes it like any other. A new ID adds a secret, a known one replaces it.
+// The node must be between epochs. A node that missed a dealing takes the next one, and the same dealing sent again is acknowledged.
+func (node *Node) Store(ctx context.Context, msg *pb.DealtShareMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	entry := node.entry().WithFields(logrus.Fields{"rpc": "Store", "secret": msg.GetSecret()})
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		entry.WithError(err).Warn("reject dealt share")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if msg.GetX() != int32(node.label) {
+		return nil, status.Errorf(codes.InvalidArgument, "the share of node %d was sent to node %d", msg.GetX(), node.label)
+	}
+	y := gmp.NewInt(0)
+	y.SetBytes(msg.GetY())
+	witness := node.dpc.NewG1()
+	witness.SetCompressedBytes(msg.GetWitness())
+	cmt := node.dpc.NewG1()
+	cmt.SetCompressedBytes(msg.GetPolycmt())
+	if !node.dpc.VerifyEval(cmt, gmp.NewInt(int64(node.label)), y, witness) {
+		return nil, status.Error(codes.InvalidArgument, "the share does not match the commitment")
+	}
+	// the epochs since the last one the node completed may have failed, and the last one must be confirmed before a dealing replaces its shares
+	if err := node.settle(); err != nil {
+		return nil, err
+	}
+
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	ack := &pb.AckMsg{
+		Epoch:     msg.GetEpoch(),
+		Committee: node.committee,
+	}
+	if held, err := node.find(msg.GetSecret()); err == nil && msg.GetEpoch() == *node.completed && held.dealt {
+		if !cmt.Equals(held.oldPolyCmt[0]) || !bytes.Equal(y.Bytes(), held.secretShares[0].Y.Bytes()) {
+			return nil, status.Errorf(codes.AlreadyExists, "node %d holds another dealing of %s in epoch %d", node.label, pb.SecretName(msg.GetSecret()), msg.GetEpoch())
+		}
+		return ack, nil
+	}
+	if err := node.checkBetween(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	s := newSharing(msg.GetSecret(), node.label, node.counter, node.degree, node.dc, node.dpc)
+	s.deal(msg.GetX(), y, witness, cmt)
+	secrets := withSharing(node.secrets, s)
+	if node.store != nil {
+		// the dealt share replaces the stored one before the node uses it, a restarted node resumes from it
+		if err := node.store.Save(node.shareState(msg.GetEpoch(), secrets)); err != nil {
+			entry.WithError(err).Error("failed to store dealt share")
+			return nil, status.Errorf(codes.Internal, "failed to store the share: %v", err)
+		}
+		if err := node.store.TruncateLog(); err != nil {
+			entry.WithError(err).Error("failed to truncate the log")
+		}
+	}
+	node.secrets = secrets
+	node.skipTo(msg.GetEpoch())
+	node.logger.WithFields(logrus.Fields{"epoch": msg.GetEpoch(), "secret": msg.GetSecret()}).Info("store dealt share")
+	return ack, nil
+}
+
+// Delete
+// Drop the shares of a secret in an epoch the bulletinboard recorded without phases, the epochs after it no longer hand the secret off. The secret the committee started with stays.
+// The node must be between epochs. Deleting the same secret again is acknowledged.
+func (node *Node) Delete(ctx context.Context, msg *pb.DeleteMsg) (*pb.AckMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	entry := node.entry().WithFields(logrus.Fields{"rpc": "Delete", "secret": msg.GetSecret()})
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		entry.WithError(err).Warn("reject deletion")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if msg.GetSecret() == "" {
+		return nil, status.Error(codes.InvalidArgument, "the default secret cannot be deleted")
+	}
+	if err := node.settle(); err != nil {
+		return nil, err
+	}
+
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	ack := &pb.AckMsg{
+		Epoch:     msg.GetEpoch(),
+		Committee: node.committee,
+	}
+	if _, err := node.find(msg.GetSecret()); err != nil {
+		// the node deleted the secret in this epoch already
+		if msg.GetEpoch() == *node.completed {
+			return ack, nil
+		}
+		return nil, err
+	}
+	if err := node.checkBetween(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	secrets := withoutSharing(node.secrets, msg.GetSecret())
+	if node.store != nil {
+		if err := node.store.Save(node.shareState(msg.GetEpoch(), secrets)); err != nil {
+			entry.WithError(err).Error("failed to store shares")
+			return nil, status.Errorf(codes.Internal, "failed to store the shares: %v", err)
+		}
+		if err := node.store.TruncateLog(); err != nil {
+			entry.WithError(err).Error("failed to truncate the log")
+		}
+	}
+	node.secrets = secrets
+	node.skipTo(msg.GetEpoch())
+	node.logger.WithFields(logrus.Fields{"epoch": msg.GetEpoch(), "secret": msg.GetSecret()}).Info("delete secret")
+	return ack, nil
+}
+
+// An epoch of the operator must come after the latest one the node completed, and the node must be between epochs with the bulletinboard having confirmed that epoch. The caller holds the mutex.
+func (node *Node) checkBetween(epoch int64) error {
+	if *node.epoch != *node.completed {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d has not completed", *node.epoch)
+	}
+	if *node.previous >= 0 {
+		return status.Errorf(codes.Unavailable, "epoch %d is not confirmed yet", *node.completed)
+	}
+	if epoch <= *node.completed {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d cannot follow epoch %d", epoch, *node.completed)
+	}
+	return nil
+}
+
+// Complete an epoch of the operator, which runs no phases. The caller holds the mutex.
+func (node *Node) skipTo(epoch int64) {
+	*node.epoch = epoch
+	*node.completed = epoch
+	node.metrics.epoch.Set(float64(epoch))
+	node.metrics.completed.Set(float64(epoch))
+}
+
+// Retrieve
+// Hand the shares of a secret at the end of the latest completed epoch to the operator, who reconstructs the secret from those of more than degree nodes.
+// The shares change once the next epoch distributes new ones, so the node answers only between epochs.
+func (node *Node) Retrieve(ctx context.Context, msg *pb.ShareRequestMsg) (*pb.SharesMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		node.entry().WithField("rpc", "Retrieve").WithError(err).Warn("refuse to hand out shares")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if err := checkOperatorUse(msg.GetSecret()); err != nil {
+		return nil, err
+	}
+	if msg.GetIndex() != int32(node.label) {
+		return nil, status.Errorf(codes.InvalidArgument, "the request for node %d was sent to node %d", msg.GetIndex(), node.label)
+	}
+	node.settleFor(msg.GetEpoch())
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if err := node.checkCompleted(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	s, err := node.find(msg.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.SharesMsg{
+		Index:     int32(node.label),
+		Shares:    make([]*pb.PointMsg, node.counter),
+		PolyCmts:  make([][]byte, node.counter),
+		Epoch:     *node.completed,
+		Committee: node.committee,
+		Secret:    s.id,
+	}
+	for i := 0; i < node.counter; i++ {
+		out.Shares[i] = &pb.PointMsg{
+			Index:   int32(i + 1),
+			X:       s.secretShares[i].X,
+			Y:       s.secretShares[i].Y.Bytes(),
+			Witness: s.secretShares[i].PolyWit.CompressedBytes(),
+			Secret:  s.id,
+		}
+		out.PolyCmts[i] = s.oldPolyCmt[i].CompressedBytes()
+	}
+	node.logger.WithFields(logrus.Fields{"epoch": *node.completed, "secret": s.id}).Info("hand shares to the operator")
+	return out, nil
+}
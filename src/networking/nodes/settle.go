@@ -0,0 +1,162 @@
+package nodes
+
+import (
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Settle the epochs the node entered with the bulletinboard before the node moves past them.
+// The latest epoch the node completed is confirmed, or rolled back to the shares the node held before it if the bulletinboard failed it. An epoch the node entered but did not complete must have failed, and the node drops what it left behind.
+func (node *Node) settle() error {
+	node.mutex.Lock()
+	from := *node.completed + 1
+	if *node.previous >= 0 {
+		from = *node.previous + 1
+	}
+	upTo := *node.epoch
+	node.mutex.Unlock()
+
+	states := make(map[int64]pb.EpochStatusMsg_State)
+	for epoch := from; epoch <= upTo; epoch++ {
+		state, err := node.epochState(epoch)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "cannot learn the outcome of epoch %d: %v", epoch, err)
+		}
+		states[epoch] = state
+	}
+
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	// the node entered or completed an epoch while the bulletinboard was asked
+	if *node.epoch != upTo {
+		return status.Errorf(codes.Unavailable, "epoch %d started meanwhile", *node.epoch)
+	}
+	if _, ok := states[*node.completed]; !ok && *node.previous >= 0 {
+		return status.Errorf(codes.Unavailable, "epoch %d completed meanwhile", *node.completed)
+	}
+	rolledBack := false
+	if *node.previous >= 0 {
+		switch states[*node.completed] {
+		case pb.EpochStatusMsg_COMPLETED:
+			node.confirm()
+		case pb.EpochStatusMsg_FAILED:
+			node.rollback()
+			rolledBack = true
+		default:
+			return status.Errorf(codes.Unavailable, "epoch %d is still running", *node.completed)
+		}
+	}
+	for epoch := *node.completed + 1; epoch <= upTo; epoch++ {
+		if err := node.checkFailed(epoch, states[epoch]); err != nil {
+			return err
+		}
+	}
+	if !rolledBack && *node.epoch == *node.completed {
+		return nil
+	}
+	if !rolledBack {
+		node.logger.WithFields(logrus.Fields{"epoch": *node.epoch, "completed": *node.completed}).Warn("leave the failed epoch")
+	}
+	*node.epoch = *node.completed
+	node.resetEpochState()
+	node.metrics.epoch.Set(float64(*node.epoch))
+	node.metrics.completed.Set(float64(*node.completed))
+	if node.store != nil {
+		// the stored shares must not be the ones of the failed epoch, and its log must not be replayed
+		if rolledBack {
+			if err := node.store.Save(node.shareState(*node.completed, node.secrets)); err != nil {
+				node.entry().WithError(err).Error("failed to store shares")
+				return status.Errorf(codes.Internal, "failed to store the shares: %v", err)
+			}
+		}
+		if err := node.store.TruncateLog(); err != nil {
+			node.entry().WithError(err).Error("failed to truncate the log")
+		}
+	}
+	return nil
+}
+
+// Settle before answering for the shares of epoch, unless the node holds them between epochs already. The answer then checks what the node holds.
+func (node *Node) settleFor(epoch int64) {
+	node.mutex.Lock()
+	held := *node.epoch == *node.completed && epoch == *node.completed
+	ahead := epoch > *node.epoch
+	node.mutex.Unlock()
+	if held || ahead {
+		return
+	}
+	if err := node.settle(); err != nil {
+		node.entry().WithError(err).Debug("cannot settle the epochs")
+	}
+}
+
+// The node skips the epochs from first to last, which must all have failed
+func (node *Node) skip(first int64, last int64) error {
+	for epoch := first; epoch <= last; epoch++ {
+		state, err := node.epochState(epoch)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "cannot learn the outcome of epoch %d: %v", epoch, err)
+		}
+		if err := node.checkFailed(epoch, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// An epoch the node leaves behind without having completed it must have failed
+func (node *Node) checkFailed(epoch int64, state pb.EpochStatusMsg_State) error {
+	switch state {
+	case pb.EpochStatusMsg_FAILED:
+		return nil
+	case pb.EpochStatusMsg_COMPLETED:
+		return status.Errorf(codes.FailedPrecondition, "epoch %d completed without node %d", epoch, node.label)
+	default:
+		return status.Errorf(codes.Unavailable, "epoch %d is still running", epoch)
+	}
+}
+
+// The bulletinboard confirmed the latest epoch the node completed. The caller holds node.mutex.
+func (node *Node) confirm() {
+	for _, s := range node.secrets {
+		s.confirm()
+	}
+	node.schnorr.confirm()
+	*node.previous = -1
+}
+
+// The bulletinboard failed the latest epoch the node completed, go back to the shares from before it. The caller holds node.mutex.
+func (node *Node) rollback() {
+	node.logger.WithFields(logrus.Fields{"epoch": *node.completed, "previous": *node.previous}).Warn("roll back the failed epoch")
+	for _, s := range node.secrets {
+		s.rollback()
+	}
+	node.schnorr.rollback()
+	*node.completed = *node.previous
+	*node.previous = -1
+}
+
+// State of an epoch as recorded by the bulletinboard
+func (node *Node) epochState(epoch int64) (pb.EpochStatusMsg_State, error) {
+	ctx, cancel := context.WithTimeout(node.ctx, pb.CallTimeout)
+	defer cancel()
+	msg := &pb.EpochMsg{Epoch: epoch, Committee: node.committee}
+	var out *pb.EpochStatusMsg
+	err := pb.Retry(func() error {
+		var err error
+		out, err = node.bClient.EpochStatus(ctx, msg)
+		return err
+	})
+	node.recordCall(peerBoard, err)
+	if err != nil {
+		return pb.EpochStatusMsg_RUNNING, err
+	}
+	if out.GetCommittee() != node.committee || out.GetEpoch() != epoch {
+		return pb.EpochStatusMsg_RUNNING, status.Errorf(codes.DataLoss, "the bulletinboard answered for epoch %d of committee %q", out.GetEpoch(), out.GetCommittee())
+	}
+	return out.GetState(), nil
+}
@@ -0,0 +1,89 @@
+package nodes_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Nodes that completed an epoch the bulletinboard failed roll back to the shares they held before, also after a restart, and the next epoch refreshes those
+func TestFailedEpochRollsBack(t *testing.T) {
+	// the bulletinboard fails phase 3 of epoch 1 for node 4 once the node verified its new shares
+	fail := &localnet.Tamper{Label: 4, StartVerifPhase3: func(in *pb.EpochMsg, out *pb.AckMsg) error {
+		if in.GetEpoch() == 1 {
+			return status.Error(codes.Aborted, "verification failed")
+		}
+		return nil
+	}}
+	tr := transport.NewLocal()
+	defer tr.Close()
+	committee, err := localnet.StartTempOn(fail.Network(tr, localnet.BoardAddr), 1, 4)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	pub, err := op.DealKey(big.NewInt(0x2f1))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.NotNil(t, committee.Run(1))
+	msg, err := committee.Clock.ClientEpochStatus(1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, pb.EpochStatusMsg_FAILED, msg.GetState())
+	}
+	// node 4 completed the epoch before the bulletinboard failed it, and keeps the genesis shares and the dealt key along with the new ones
+	state, err := committee.Shares(4)
+	if assert.Nil(t, err) && assert.NotNil(t, state.Previous) {
+		assert.Equal(t, int64(1), state.Epoch)
+		assert.Equal(t, int64(0), state.Previous.Epoch)
+		if assert.NotNil(t, state.Previous.Schnorr) {
+			assert.True(t, state.Previous.Schnorr.Dealt)
+			assert.NotEqual(t, state.Previous.Schnorr.Share, state.Schnorr.Share)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	assert.Nil(t, committee.Nodes[3].Shutdown(ctx))
+	assert.Nil(t, committee.Restart(4))
+	for i := 0; i < 100 && admin.Ready(committee.Network.Endpoint("test"), localnet.NodeAddr(4)) != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Nil(t, committee.Run(1))
+	var key [][]byte
+	for label := 1; label <= 4; label++ {
+		state, err := committee.Shares(label)
+		if assert.Nil(t, err) {
+			assert.Equal(t, int64(2), state.Epoch, "node %d", label)
+			// the key rolled back too, so every node refreshed the dealt key in epoch 2
+			if assert.NotNil(t, state.Schnorr, "node %d", label) && assert.NotNil(t, state.Previous, "node %d", label) && assert.NotNil(t, state.Previous.Schnorr, "node %d", label) {
+				assert.True(t, state.Previous.Schnorr.Dealt, "node %d", label)
+				if key != nil {
+					assert.Equal(t, key, state.Schnorr.Commitment, "node %d", label)
+				}
+				key = state.Schnorr.Commitment
+			}
+		}
+	}
+	assert.Nil(t, committee.Verify())
+	sig, err := op.SchnorrSign([]byte("after the rollback"))
+	if assert.Nil(t, err) {
+		assert.Empty(t, sig.Bad)
+		assert.Equal(t, pub, sig.PublicKey)
+	}
+}
@@ -0,0 +1,352 @@
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Nik-U/pbc"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/polypoint"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"github.com/ncw/gmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The state of a node for one secret of the committee. Every epoch hands each secret off through the three phases on its own, the bulletinboard verifies all of them together.
+type sharing struct {
+	// ID of the secret, empty for the one the committee started with
+	id string
+
+	// Sharing State
+	// [+] Share of the node on every polynomial, indexed by label - 1
+	secretShares []*polypoint.PolyPoint
+	// [+] Set while the shares are the ones the operator dealt
+	dealt bool
+	// [+] Shares, commitments and dealt flag from before the latest epoch the node completed, kept until the bulletinboard confirms that epoch so that a failed one can be rolled back. Nil once it did.
+	prevShares  []*polypoint.PolyPoint
+	prevPolyCmt []*pbc.Element
+	prevDealt   bool
+
+	// Reconstruction Phase
+	recShares []*polypoint.PolyPoint
+	recCnt    int
+	recPoly   *polyring.Polynomial
+
+	// Proactivization Phase
+	zeroShares   []*gmp.Int
+	zeroCnt      int
+	zeroShare    *gmp.Int
+	proPoly      *polyring.Polynomial
+	zeroShareCmt *pbc.Element
+	zeroPolyCmt  *pbc.Element
+	zeroPolyWit  *pbc.Element
+
+	// Share Distribution Phase
+	newPoly  *polyring.Polynomial
+	shareCnt int
+	// [+] New share of the node on every polynomial, taken as the secret share once the epoch completes
+	newShares []*polypoint.PolyPoint
+
+	// Recovery
+	// [+] Logged zero shares and zero polynomial to reuse while replaying
+	replayZero *zeroRand
+	// [+] Peers whose message of each phase arrived in this epoch
+	recvPoint1 []bool
+	recvZero   []bool
+	recvPoint3 []bool
+	// [+] Messages sent in this epoch, kept to answer Resend
+	sentPoint1 []*pb.PointMsg
+	sentZero   []*pb.ZeroMsg
+	sentPoint3 []*pb.PointMsg
+
+	// Commitment and Witness from BulletinBoard
+	// [+] Commitments Verified at the End of the Previous Epoch
+	oldPolyCmt      []*pbc.Element
+	zerosumShareCmt []*pbc.Element
+	zerosumPolyCmt  []*pbc.Element
+	zerosumPolyWit  []*pbc.Element
+	midPolyCmt      []*pbc.Element
+	newPolyCmt      []*pbc.Element
+}
+
+// A sharing of the secret id whose shares and commitments are all zero, to be set by genesis, a dealing or the share storage
+func newSharing(id string, label int, counter int, degree int, dc *commitment.DLCommit, dpc *commitment.DLPolyCommit) *sharing {
+	recPoly, _ := polyring.New(degree)
+	proPoly, _ := polyring.New(degree)
+	newPoly, _ := polyring.New(degree)
+	s := &sharing{
+		id:              id,
+		secretShares:    make([]*polypoint.PolyPoint, counter),
+		recShares:       make([]*polypoint.PolyPoint, counter),
+		recPoly:         &recPoly,
+		zeroShares:      make([]*gmp.Int, counter),
+		zeroShare:       gmp.NewInt(0),
+		proPoly:         &proPoly,
+		zeroShareCmt:    dc.NewG1(),
+		zeroPolyCmt:     dpc.NewG1(),
+		zeroPolyWit:     dpc.NewG1(),
+		newPoly:         &newPoly,
+		newShares:       make([]*polypoint.PolyPoint, counter),
+		recvPoint1:      make([]bool, counter),
+		recvZero:        make([]bool, counter),
+		recvPoint3:      make([]bool, counter),
+		sentPoint1:      make([]*pb.PointMsg, counter),
+		sentZero:        make([]*pb.ZeroMsg, counter),
+		sentPoint3:      make([]*pb.PointMsg, counter),
+		oldPolyCmt:      make([]*pbc.Element, counter),
+		zerosumShareCmt: make([]*pbc.Element, counter),
+		zerosumPolyCmt:  make([]*pbc.Element, counter),
+		zerosumPolyWit:  make([]*pbc.Element, counter),
+		midPolyCmt:      make([]*pbc.Element, counter),
+		newPolyCmt:      make([]*pbc.Element, counter),
+	}
+	for i := 0; i < counter; i++ {
+		s.secretShares[i] = polypoint.NewPoint(int32(label), gmp.NewInt(0), dpc.NewG1())
+		s.newShares[i] = polypoint.NewPoint(int32(label), gmp.NewInt(0), dpc.NewG1())
+		s.zeroShares[i] = gmp.NewInt(0)
+		s.oldPolyCmt[i] = dpc.NewG1()
+		s.zerosumShareCmt[i] = dc.NewG1()
+		s.zerosumPolyCmt[i] = dpc.NewG1()
+		s.zerosumPolyWit[i] = dpc.NewG1()
+		s.midPolyCmt[i] = dpc.NewG1()
+		s.newPolyCmt[i] = dpc.NewG1()
+	}
+	return s
+}
+
+// Drop everything the previous epoch left behind for the secret. Only the secret shares and the commitments to them carry over.
+func (s *sharing) reset() {
+	s.recCnt = 0
+	s.zeroCnt = 0
+	s.shareCnt = 0
+	s.zeroShare.SetInt64(0)
+	s.replayZero = nil
+	for i := range s.secretShares {
+		s.recvPoint1[i] = false
+		s.recvZero[i] = false
+		s.recvPoint3[i] = false
+		s.sentPoint1[i] = nil
+		s.sentZero[i] = nil
+		s.sentPoint3[i] = nil
+		s.recShares[i] = nil
+		s.zeroShares[i].SetInt64(0)
+		s.newShares[i].Y.SetInt64(0)
+		s.newShares[i].PolyWit.Set1()
+		s.zerosumShareCmt[i].Set1()
+		s.zerosumPolyCmt[i].Set1()
+		s.zerosumPolyWit[i].Set1()
+		s.midPolyCmt[i].Set1()
+		s.newPolyCmt[i].Set1()
+	}
+}
+
+// Take the same dealt share and commitment for every polynomial of the secret
+func (s *sharing) deal(x int32, y *gmp.Int, witness *pbc.Element, cmt *pbc.Element) {
+	for i := range s.secretShares {
+		s.secretShares[i].X = x
+		s.secretShares[i].Y.Set(y)
+		s.secretShares[i].PolyWit.Set(witness)
+		s.oldPolyCmt[i].Set(cmt)
+	}
+	s.dealt = true
+}
+
+// Take the new shares and the commitments phase 3 verified, keeping the ones they replace until the bulletinboard confirms the epoch
+func (s *sharing) complete() {
+	if s.prevShares == nil {
+		s.prevShares = make([]*polypoint.PolyPoint, len(s.secretShares))
+		s.prevPolyCmt = make([]*pbc.Element, len(s.oldPolyCmt))
+		for i := range s.secretShares {
+			s.prevShares[i] = polypoint.NewPoint(0, gmp.NewInt(0), s.secretShares[i].PolyWit.NewFieldElement())
+			s.prevPolyCmt[i] = s.oldPolyCmt[i].NewFieldElement()
+		}
+	}
+	for i := range s.secretShares {
+		copyPoint(s.prevShares[i], s.secretShares[i])
+		copyPoint(s.secretShares[i], s.newShares[i])
+		s.prevPolyCmt[i].Set(s.oldPolyCmt[i])
+		s.oldPolyCmt[i].Set(s.newPolyCmt[i])
+	}
+	s.prevDealt = s.dealt
+	s.dealt = false
+}
+
+// Go back to the shares from before the latest completed epoch, which the bulletinboard failed
+func (s *sharing) rollback() {
+	for i := range s.secretShares {
+		copyPoint(s.secretShares[i], s.prevShares[i])
+		s.oldPolyCmt[i].Set(s.prevPolyCmt[i])
+	}
+	s.dealt = s.prevDealt
+	s.confirm()
+}
+
+// Drop the shares from before the latest completed epoch once the bulletinboard confirmed it
+func (s *sharing) confirm() {
+	s.prevShares = nil
+	s.prevPolyCmt = nil
+	s.prevDealt = false
+}
+
+func copyPoint(dst *polypoint.PolyPoint, src *polypoint.PolyPoint) {
+	dst.X = src.X
+	dst.Y.Set(src.Y)
+	dst.PolyWit.Set(src.PolyWit)
+}
+
+// The sharing of a secret. The caller holds node.mutex.
+func (node *Node) find(id string) (*sharing, error) {
+	i := sort.Search(len(node.secrets), func(i int) bool { return node.secrets[i].id >= id })
+	if i == len(node.secrets) || node.secrets[i].id != id {
+		return nil, status.Errorf(codes.NotFound, "node %d holds no %s", node.label, pb.SecretName(id))
+	}
+	return node.secrets[i], nil
+}
+
+// The sharing of a secret
+func (node *Node) lookup(id string) (*sharing, error) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return node.find(id)
+}
+
+// The sharings of the node, in the order of their IDs. They only change between epochs.
+func (node *Node) secretList() []*sharing {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return append([]*sharing{}, node.secrets...)
+}
+
+// The sharings with s in place of the one of the same secret, in the order of their IDs
+func withSharing(secrets []*sharing, s *sharing) []*sharing {
+	out := make([]*sharing, 0, len(secrets)+1)
+	for _, other := range secrets {
+		if other.id != s.id {
+			out = append(out, other)
+		}
+	}
+	out = append(out, s)
+	sort.Slice(out, func(i, j int) bool { return out[i].id < out[j].id })
+	return out
+}
+
+// The sharings without the one of the secret id
+func withoutSharing(secrets []*sharing, id string) []*sharing {
+	out := make([]*sharing, 0, len(secrets))
+	for _, s := range secrets {
+		if s.id != id {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Snapshot of the verified shares of the given epoch for the share storage, along with the shares from before it while the bulletinboard has not confirmed it. The caller holds node.mutex.
+func (node *Node) shareState(epoch int64, secrets []*sharing) *sharestore.State {
+	state := node.storedShares(epoch, secrets, func(s *sharing) ([]*polypoint.PolyPoint, []*pbc.Element, bool) {
+		return s.secretShares, s.oldPolyCmt, s.dealt
+	})
+	state.Schnorr = storedKey(node.schnorr.key)
+	if *node.previous >= 0 {
+		state.Previous = node.storedShares(*node.previous, secrets, func(s *sharing) ([]*polypoint.PolyPoint, []*pbc.Element, bool) {
+			return s.prevShares, s.prevPolyCmt, s.prevDealt
+		})
+		state.Previous.Schnorr = storedKey(node.schnorr.prev)
+	}
+	return state
+}
+
+// The shares, commitments and dealt flag held picks from every sharing, stored as the state of the given epoch
+func (node *Node) storedShares(epoch int64, secrets []*sharing, held func(*sharing) ([]*polypoint.PolyPoint, []*pbc.Element, bool)) *sharestore.State {
+	state := &sharestore.State{
+		Epoch:     epoch,
+		Committee: node.committee,
+		Label:     node.label,
+		Counter:   node.counter,
+	}
+	for _, s := range secrets {
+		shares, polyCmts, dealt := held(s)
+		stored := sharestore.Secret{
+			ID:       s.id,
+			Shares:   make([]sharestore.Point, node.counter),
+			PolyCmts: make([][]byte, node.counter),
+			Dealt:    dealt,
+		}
+		for i := 0; i < node.counter; i++ {
+			stored.Shares[i] = sharestore.Point{
+				X:       shares[i].X,
+				Y:       shares[i].Y.Bytes(),
+				Witness: shares[i].PolyWit.CompressedBytes(),
+			}
+			stored.PolyCmts[i] = polyCmts[i].CompressedBytes()
+		}
+		// the default secret keeps the fields it had before the committee held others
+		if s.id == "" {
+			state.Shares = stored.Shares
+			state.PolyCmts = stored.PolyCmts
+			state.Dealt = stored.Dealt
+			continue
+		}
+		state.Secrets = append(state.Secrets, stored)
+	}
+	return state
+}
+
+// Replace the genesis shares and commitments by the ones stored after the last completed epoch, and add the sharings of the other secrets stored along with them
+func restoreShareState(state *sharestore.State, committee string, label int, counter int, degree int, dc *commitment.DLCommit, dpc *commitment.DLPolyCommit, genesis *sharing) ([]*sharing, error) {
+	if state.Committee != committee || state.Label != label || state.Counter != counter {
+		return nil, errors.New(fmt.Sprintf("stored shares belong to node %d of committee %q with %d nodes", state.Label, state.Committee, state.Counter))
+	}
+	if err := restoreSharing(genesis, state.Shares, state.PolyCmts, state.Dealt, counter); err != nil {
+		return nil, err
+	}
+	secrets := []*sharing{genesis}
+	for _, stored := range state.Secrets {
+		if stored.ID == "" {
+			return nil, errors.New("the default secret is stored twice")
+		}
+		s := newSharing(stored.ID, label, counter, degree, dc, dpc)
+		if err := restoreSharing(s, stored.Shares, stored.PolyCmts, stored.Dealt, counter); err != nil {
+			return nil, err
+		}
+		secrets = withSharing(secrets, s)
+	}
+	return secrets, nil
+}
+
+// Keep the shares stored from before the latest completed epoch along with the sharings of the same secrets
+func restorePrevious(state *sharestore.State, committee string, label int, counter int, degree int, dc *commitment.DLCommit, dpc *commitment.DLPolyCommit, secrets []*sharing) error {
+	prev, err := restoreShareState(state, committee, label, counter, degree, dc, dpc, newSharing("", label, counter, degree, dc, dpc))
+	if err != nil {
+		return err
+	}
+	for i := range prev {
+		if len(prev) != len(secrets) || prev[i].id != secrets[i].id {
+			return errors.New(fmt.Sprintf("the shares stored from epoch %d belong to other secrets", state.Epoch))
+		}
+	}
+	for i, s := range secrets {
+		s.prevShares = prev[i].secretShares
+		s.prevPolyCmt = prev[i].oldPolyCmt
+		s.prevDealt = prev[i].dealt
+	}
+	return nil
+}
+
+func restoreSharing(s *sharing, shares []sharestore.Point, polyCmts [][]byte, dealt bool, counter int) error {
+	if len(shares) != counter || len(polyCmts) != counter {
+		return errors.New(fmt.Sprintf("stored shares of %s cover %d and %d polynomials, need %d", pb.SecretName(s.id), len(shares), len(polyCmts), counter))
+	}
+	for i := 0; i < counter; i++ {
+		s.secretShares[i].X = shares[i].X
+		s.secretShares[i].Y.SetBytes(shares[i].Y)
+		s.secretShares[i].PolyWit.SetCompressedBytes(shares[i].Witness)
+		s.oldPolyCmt[i].SetCompressedBytes(polyCmts[i])
+	}
+	s.dealt = dealt
+	return nil
+}
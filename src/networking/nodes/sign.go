@@ -0,0 +1,96 @@
+package nodes
+
+import (
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/ncw/gmp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SignShare
+// Sign a message for the operator with the share of a secret the node holds at the end of the latest completed epoch.
+// Along with H(m)^s for its share s, the node hands out its public share g^s and the values that tie it to the commitments on the bulletinboard, see shareInExponent.
+// The shares change once the next epoch distributes new ones, so the node answers only between epochs.
+func (node *Node) SignShare(ctx context.Context, msg *pb.SignRequestMsg) (*pb.PartialSigMsg, error) {
+	if node.isRecovering() {
+		return nil, errRecovering
+	}
+	if err := pb.VerifyOperated(node.operator, msg); err != nil {
+		node.entry().WithField("rpc", "SignShare").WithError(err).Warn("refuse to sign")
+		return nil, err
+	}
+	if msg.GetCommittee() != node.committee {
+		return nil, status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), node.committee)
+	}
+	if err := checkOperatorUse(msg.GetSecret()); err != nil {
+		return nil, err
+	}
+	node.settleFor(msg.GetEpoch())
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	if err := node.checkCompleted(msg.GetEpoch()); err != nil {
+		return nil, err
+	}
+	s, err := node.find(msg.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+	share, public := node.shareInExponent(s)
+	node.logger.WithFields(logrus.Fields{"epoch": *node.completed, "secret": s.id}).Info("sign for the operator")
+	return &pb.PartialSigMsg{
+		Index:       public.GetIndex(),
+		Partial:     tbls.Sign(share, msg.GetMessage()).CompressedBytes(),
+		PublicShare: public.GetPublicShare(),
+		Evals:       public.GetEvals(),
+		Witnesses:   public.GetWitnesses(),
+		Epoch:       public.GetEpoch(),
+		Committee:   public.GetCommittee(),
+		Secret:      public.GetSecret(),
+	}, nil
+}
+
+// The share of a secret the node holds at the end of the latest completed epoch, and its public share with the values that prove it. The caller holds the mutex.
+// The share is s = Σ λ_i f_i(label) over the points of the node on the polynomials of the secret. Every g^f_i(label) opens the commitment to f_i on the bulletinboard with the witness of the point, and together they give g^s.
+func (node *Node) shareInExponent(s *sharing) (*gmp.Int, *pb.PublicShareMsg) {
+	share := gmp.NewInt(0)
+	out := &pb.PublicShareMsg{
+		Index:     int32(node.label),
+		Evals:     make([][]byte, node.counter),
+		Witnesses: make([][]byte, node.counter),
+		Epoch:     *node.completed,
+		Committee: node.committee,
+		Secret:    s.id,
+	}
+	term := gmp.NewInt(0)
+	for i := 0; i < node.counter; i++ {
+		eval := node.dc.NewG1()
+		node.dc.Commit(eval, s.secretShares[i].Y)
+		out.Evals[i] = eval.CompressedBytes()
+		out.Witnesses[i] = s.secretShares[i].PolyWit.CompressedBytes()
+		term.Mul(node.lambda[i], s.secretShares[i].Y)
+		share.Add(share, term)
+		share.Mod(share, node.p)
+	}
+	public := node.dc.NewG1()
+	node.dc.Commit(public, share)
+	out.PublicShare = public.CompressedBytes()
+	return share, out
+}
+
+// Check that the node is between epochs and epoch is the latest one it completed. The caller holds the mutex.
+func (node *Node) checkCompleted(epoch int64) error {
+	if *node.epoch != *node.completed {
+		return status.Errorf(codes.FailedPrecondition, "epoch %d has not completed", *node.epoch)
+	}
+	if epoch > *node.completed {
+		return status.Errorf(codes.Unavailable, "future epoch %d, completed epoch is %d", epoch, *node.completed)
+	}
+	if epoch < *node.completed {
+		return status.Errorf(codes.FailedPrecondition, "stale epoch %d, completed epoch is %d", epoch, *node.completed)
+	}
+	return nil
+}
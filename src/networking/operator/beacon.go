@@ -0,0 +1,165 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Nik-U/pbc"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/beacon"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+)
+
+// Beacon is the random value of the committee for the round of an epoch
+type Beacon struct {
+	Epoch int64
+	// The value, a hash of the proof, see beacon.Output
+	Output []byte
+	// H(round)^s for the secret s, compressed
+	Proof []byte
+	// g^s, compressed. It stays the same from round to round as long as the committee holds the same secret.
+	PublicKey []byte
+	// Labels of the nodes whose partial evaluations make the proof
+	Signers []int
+	// Why the partial evaluation of a node was left out, by its label
+	Bad map[int]string
+}
+
+// Beacon reads the beacon of the round of a completed epoch from the bulletinboard, 0 for the latest completed epoch, and checks it without trusting the board.
+// Every partial evaluation on the board must carry the signature of its node, open the commitments to the beacon secret the epoch ended with by its public share and verify under it. The first degree+1 that do combine into the proof, which must be the one the board gave.
+func (o *Operator) Beacon(round int64) (*Beacon, error) {
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := o.roundStatus(round)
+	if err != nil {
+		return nil, err
+	}
+	round = status.GetEpoch()
+	cmts, err := o.Commitments(status, pb.BeaconSecret)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	msg, err := o.bClient.ReadBeacon(ctx, o.epochMsg(round))
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Beacon{
+		Epoch: round,
+		Bad:   make(map[int]string),
+	}
+	partials := make([]*pbc.Element, 0, degree+1)
+	publics := make([]*pbc.Element, 0, degree+1)
+	seen := make(map[int]bool)
+	for _, share := range msg.GetShares() {
+		label := int(share.GetIndex())
+		if label < 1 || label > o.counter || seen[label] {
+			return nil, errors.New(fmt.Sprintf("bulletinboard sent a second or unknown evaluation of node %d", label))
+		}
+		seen[label] = true
+		if len(b.Signers) > degree {
+			continue
+		}
+		partial, public, err := o.checkEvaluation(label, round, share, cmts)
+		if err != nil {
+			b.Bad[label] = err.Error()
+			continue
+		}
+		b.Signers = append(b.Signers, label)
+		partials = append(partials, partial)
+		publics = append(publics, public)
+	}
+	if len(b.Signers) <= degree {
+		return nil, errors.New(fmt.Sprintf("need the partial evaluations of more than %d nodes, got %d: %s", degree, len(b.Signers), badList(b.Bad)))
+	}
+	proof, err := tbls.Combine(b.Signers, partials)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := tbls.Combine(b.Signers, publics)
+	if err != nil {
+		return nil, err
+	}
+	if !beacon.Verify(pub, round, proof) {
+		return nil, errors.New("the partial evaluations do not combine into a valid proof")
+	}
+	// the proof of a round is unique, whichever evaluations the board combined
+	if !bytes.Equal(proof.CompressedBytes(), msg.GetProof()) || !bytes.Equal(beacon.Output(round, proof), msg.GetOutput()) {
+		return nil, errors.New("the beacon of the bulletinboard is not the one its evaluations give")
+	}
+	b.Output = msg.GetOutput()
+	b.Proof = msg.GetProof()
+	b.PublicKey = pub.CompressedBytes()
+	return b, nil
+}
+
+// Status of the epoch of a round, which must have completed and run its phases. Round 0 is the latest completed epoch.
+func (o *Operator) roundStatus(round int64) (*pb.EpochStatusMsg, error) {
+	var status *pb.EpochStatusMsg
+	var err error
+	if round == 0 {
+		status, err = o.completed()
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+		defer cancel()
+		status, err = o.bClient.EpochStatus(ctx, o.epochMsg(round))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status.GetState() != pb.EpochStatusMsg_COMPLETED {
+		return nil, errors.New(fmt.Sprintf("epoch %d is %s, only completed epochs have a beacon", status.GetEpoch(), strings.ToLower(status.GetState().String())))
+	}
+	if status.GetDealt() || status.GetDeleted() {
+		return nil, errors.New(fmt.Sprintf("epoch %d ran no phases, it has no beacon", status.GetEpoch()))
+	}
+	return status, nil
+}
+
+// Check the partial evaluation of node label for round and return it along with the public share of the node
+func (o *Operator) checkEvaluation(label int, round int64, msg *pb.BeaconShareMsg, cmts []*pb.Cmt1Msg) (*pbc.Element, *pbc.Element, error) {
+	if err := pb.VerifySigned(o.pks, msg); err != nil {
+		return nil, nil, err
+	}
+	if msg.GetEpoch() != round || msg.GetCommittee() != o.committee {
+		return nil, nil, errors.New(fmt.Sprintf("evaluated round %d", msg.GetEpoch()))
+	}
+	public, err := o.checkPublicShare(label, round, msg.GetShare(), cmts)
+	if err != nil {
+		return nil, nil, err
+	}
+	partial, err := tbls.Parse(msg.GetPartial())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !beacon.Verify(public, round, partial) {
+		return nil, nil, errors.New("the partial evaluation does not verify under the public share")
+	}
+	return partial, public, nil
+}
+
+// VerifyBeacon checks the value of the committee for a round under its public key, given the proof, all as Beacon returns them
+func VerifyBeacon(publicKey []byte, round int64, output []byte, proof []byte) error {
+	pub, err := tbls.Parse(publicKey)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad public key: %v", err))
+	}
+	p, err := tbls.Parse(proof)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad proof: %v", err))
+	}
+	if !beacon.Verify(pub, round, p) {
+		return errors.New("the proof does not verify under the public key")
+	}
+	if !bytes.Equal(beacon.Output(round, p), output) {
+		return errors.New("the value is not the one of the proof")
+	}
+	return nil
+}
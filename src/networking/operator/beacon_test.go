@@ -0,0 +1,151 @@
+package operator_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/beacon"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/golang/protobuf/proto"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBeacon(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// a committee without a beacon secret has no beacon
+	assert.Nil(t, committee.Run(1))
+	_, err = op.Beacon(0)
+	assert.NotNil(t, err)
+
+	// nor has the epoch that deals the beacon secret, which the operator knows
+	dealt := gmp.NewInt(42)
+	created, err := op.Create(pb.BeaconSecret, dealt)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = op.Beacon(created)
+	assert.NotNil(t, err)
+
+	// the next epoch adds a random value of every node to the secret, every round then verifies under the same g^s while the shares are refreshed
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	known := make(map[string]bool)
+	for _, secret := range []*gmp.Int{dealt, localnet.GenesisSecret(1)} {
+		pk := dc.NewG1()
+		dc.Commit(pk, secret)
+		known[string(pk.CompressedBytes())] = true
+	}
+	var pk []byte
+	outputs := make(map[string]bool)
+	for epoch := created + 1; epoch <= created+3; epoch++ {
+		assert.Nil(t, committee.Run(1))
+		// the nodes post their evaluations once they completed the epoch
+		var b *operator.Beacon
+		for i := 0; i < 100; i++ {
+			if b, err = op.Beacon(0); err == nil {
+				break
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		if !assert.Nil(t, err, "epoch %d", epoch) {
+			return
+		}
+		assert.Equal(t, epoch, b.Epoch)
+		assert.Len(t, b.Signers, 2)
+		assert.Empty(t, b.Bad)
+		assert.False(t, known[string(b.PublicKey)], "epoch %d is keyed on a known secret", epoch)
+		if pk == nil {
+			pk = b.PublicKey
+		}
+		assert.Equal(t, pk, b.PublicKey)
+		assert.Nil(t, operator.VerifyBeacon(b.PublicKey, epoch, b.Output, b.Proof))
+		assert.NotNil(t, operator.VerifyBeacon(b.PublicKey, epoch+1, b.Output, b.Proof))
+		assert.False(t, outputs[string(b.Output)], "epoch %d repeats a value", epoch)
+		outputs[string(b.Output)] = true
+
+		again, err := op.Beacon(epoch)
+		if assert.Nil(t, err) {
+			assert.Equal(t, b.Output, again.Output)
+		}
+	}
+	last := created + 3
+
+	// the nodes neither hand out their shares of the beacon secret nor use them for the operator
+	_, err = op.Secret([]int{1, 2}, pb.BeaconSecret)
+	assert.NotNil(t, err)
+	_, err = op.Sign(pb.BeaconSecret, []byte("round"))
+	assert.NotNil(t, err)
+
+	var out bytes.Buffer
+	b, err := op.Beacon(last)
+	if !assert.Nil(t, err) {
+		return
+	}
+	operator.WriteBeacon(&out, b)
+	assert.Contains(t, out.String(), fmt.Sprintf("round\t%d\n", last))
+	assert.Contains(t, out.String(), fmt.Sprintf("output\t%x\n", b.Output))
+
+	// an evaluation that does not verify under the public share of its node is refused, even signed with the key of the node
+	conn, err := committee.Network.Endpoint("forger").Dial(localnet.BoardAddr)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	board, err := conn.Admin().Status(ctx, &pb.StatusRequestMsg{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	msg, err := conn.BulletinBoard().ReadBeacon(ctx, &pb.EpochMsg{Epoch: last, Committee: board.GetCommittee()})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var own *pb.BeaconShareMsg
+	for _, share := range msg.GetShares() {
+		if share.GetIndex() == 1 {
+			own = share
+		}
+	}
+	if !assert.NotNil(t, own) {
+		return
+	}
+	// the same evaluation written again is acknowledged
+	_, err = conn.BulletinBoard().WriteBeacon(ctx, own)
+	assert.Nil(t, err)
+	id, err := identity.Load(identity.KeyPath(committee.Dir, 1))
+	if !assert.Nil(t, err) {
+		return
+	}
+	forged := proto.Clone(own).(*pb.BeaconShareMsg)
+	forged.Partial = beacon.Evaluate(gmp.NewInt(1), last).CompressedBytes()
+	forged.Signature = pb.Sign(id, forged)
+	_, err = conn.BulletinBoard().WriteBeacon(ctx, forged)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// and so is an evaluation for a round that has not come
+	forged = proto.Clone(own).(*pb.BeaconShareMsg)
+	forged.Epoch = last + 1
+	forged.Signature = pb.Sign(id, forged)
+	_, err = conn.BulletinBoard().WriteBeacon(ctx, forged)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,188 @@
+package operator
+
+import (
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/Nik-U/pbc"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/elgamal"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/ncw/gmp"
+)
+
+// PublicKey is the public key g^s of a secret s of the committee
+type PublicKey struct {
+	Epoch int64
+	// ID of the secret
+	Secret string
+	// g^s, compressed
+	Key []byte
+	// Labels of the nodes whose public shares give the key
+	Nodes []int
+	// Why the public share of a node was left out, by its label
+	Bad map[int]string
+}
+
+// Decryption is the plaintext of a ciphertext under the public key of a secret of the committee
+type Decryption struct {
+	Epoch     int64
+	Plaintext []byte
+	// Labels of the nodes whose partial decryptions open the ciphertext
+	Decryptors []int
+	// Why the partial decryption of a node was left out, by its label
+	Bad map[int]string
+}
+
+// PublicKey derives the public key of the secret under id from the public shares of the first degree+1 nodes whose shares open the commitments of the latest completed epoch.
+// It needs no operator key, the public shares are public.
+func (o *Operator) PublicKey(id string) (*PublicKey, error) {
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	cmts, err := o.Commitments(latest, id)
+	if err != nil {
+		return nil, err
+	}
+	pk := &PublicKey{
+		Epoch:  latest.GetEpoch(),
+		Secret: id,
+		Bad:    make(map[int]string),
+	}
+	in := o.epochMsg(latest.GetEpoch())
+	in.Secret = id
+	publics := make([]*pbc.Element, 0, degree+1)
+	for label := 1; label <= o.counter && len(pk.Nodes) <= degree; label++ {
+		var msg *pb.PublicShareMsg
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			msg, err = o.nConn[label-1].Decrypt().PublicShare(ctx, in)
+			return err
+		})
+		if err != nil {
+			pk.Bad[label] = err.Error()
+			continue
+		}
+		public, err := o.checkPublicShare(label, latest.GetEpoch(), msg, cmts)
+		if err != nil {
+			pk.Bad[label] = err.Error()
+			continue
+		}
+		pk.Nodes = append(pk.Nodes, label)
+		publics = append(publics, public)
+	}
+	if len(pk.Nodes) <= degree {
+		return nil, errors.New(fmt.Sprintf("need the public shares of more than %d nodes, got %d: %s", degree, len(pk.Nodes), badList(pk.Bad)))
+	}
+	key, err := tbls.Combine(pk.Nodes, publics)
+	if err != nil {
+		return nil, err
+	}
+	pk.Key = key.CompressedBytes()
+	return pk, nil
+}
+
+// Decrypt has the nodes decrypt c with their shares of the secret under id at the end of the latest completed epoch, and opens it with the partial decryptions of the first degree+1 nodes that prove theirs correct.
+// A partial decryption counts once its public share opens the commitments on the bulletinboard and its Chaum-Pedersen proof holds against that public share.
+func (o *Operator) Decrypt(id string, c *elgamal.Ciphertext) (*Decryption, error) {
+	if o.id == nil {
+		return nil, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	cmts, err := o.Commitments(latest, id)
+	if err != nil {
+		return nil, err
+	}
+	req := &pb.DecryptRequestMsg{
+		C1:        c.C1.CompressedBytes(),
+		Epoch:     latest.GetEpoch(),
+		Committee: o.committee,
+		Secret:    id,
+	}
+	req.Signature = pb.SignOperated(o.id, req)
+
+	dec := &Decryption{
+		Epoch: latest.GetEpoch(),
+		Bad:   make(map[int]string),
+	}
+	partials := make([]*pbc.Element, 0, degree+1)
+	for label := 1; label <= o.counter && len(dec.Decryptors) <= degree; label++ {
+		var msg *pb.PartialDecryptionMsg
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			msg, err = o.nConn[label-1].Decrypt().DecryptShare(ctx, req)
+			return err
+		})
+		if err != nil {
+			dec.Bad[label] = err.Error()
+			continue
+		}
+		partial, err := o.checkDecryption(label, c, msg, cmts, latest.GetEpoch())
+		if err != nil {
+			dec.Bad[label] = err.Error()
+			continue
+		}
+		dec.Decryptors = append(dec.Decryptors, label)
+		partials = append(partials, partial)
+	}
+	if len(dec.Decryptors) <= degree {
+		return nil, errors.New(fmt.Sprintf("need the partial decryptions of more than %d nodes, got %d: %s", degree, len(dec.Decryptors), badList(dec.Bad)))
+	}
+	shared, err := elgamal.Combine(dec.Decryptors, partials)
+	if err != nil {
+		return nil, err
+	}
+	dec.Plaintext, err = elgamal.Open(c, shared)
+	if err != nil {
+		return nil, err
+	}
+	return dec, nil
+}
+
+// Check the partial decryption of node label and return it
+func (o *Operator) checkDecryption(label int, c *elgamal.Ciphertext, msg *pb.PartialDecryptionMsg, cmts []*pb.Cmt1Msg, epoch int64) (*pbc.Element, error) {
+	public, err := o.checkPublicShare(label, epoch, msg.GetShare(), cmts)
+	if err != nil {
+		return nil, err
+	}
+	partial, err := elgamal.ParseC1(msg.GetPartial())
+	if err != nil {
+		return nil, err
+	}
+	proof := &elgamal.Proof{
+		Challenge: gmp.NewInt(0).SetBytes(msg.GetChallenge()),
+		Response:  gmp.NewInt(0).SetBytes(msg.GetResponse()),
+	}
+	if !elgamal.VerifyPartial(public, c.C1, partial, proof) {
+		return nil, errors.New("the proof of the partial decryption does not hold against the public share")
+	}
+	return partial, nil
+}
+
+// Encrypt seals plaintext under a public key of a committee, compressed as PublicKey returns it
+func Encrypt(publicKey []byte, plaintext []byte) (*elgamal.Ciphertext, error) {
+	pk, err := tbls.Parse(publicKey)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("bad public key: %v", err))
+	}
+	return elgamal.Encrypt(pk, plaintext, crand.Reader)
+}
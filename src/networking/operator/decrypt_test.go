@@ -0,0 +1,74 @@
+package operator_test
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/elgamal"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdDecryption(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(1))
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the public key is g^s for the genesis secret s
+	pk, err := op.PublicKey("")
+	if !assert.Nil(t, err) {
+		return
+	}
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	want := dc.NewG1()
+	dc.Commit(want, localnet.GenesisSecret(1))
+	assert.Equal(t, want.CompressedBytes(), pk.Key)
+	assert.Equal(t, []int{1, 2}, pk.Nodes)
+
+	// a ciphertext made in one epoch is decrypted in a later one, after the shares were refreshed
+	plaintext := []byte("withdraw 10 coins")
+	c, err := operator.Encrypt(pk.Key, plaintext)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, committee.Run(2))
+	dec, err := op.Decrypt("", c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), dec.Epoch)
+	assert.Equal(t, plaintext, dec.Plaintext)
+	assert.Equal(t, []int{1, 2}, dec.Decryptors)
+
+	// a partial decryption whose proof fails is left out
+	tamper := &localnet.Tamper{Label: 2, DecryptShare: func(in *pb.DecryptRequestMsg, out *pb.PartialDecryptionMsg) error {
+		c1, _ := elgamal.ParseC1(in.GetC1())
+		partial, _, _ := elgamal.PartialDecrypt(gmp.NewInt(42), c1, crand.Reader)
+		out.Partial = partial.CompressedBytes()
+		return nil
+	}}
+	liar, err := committee.Operator(tamper.Transport(committee.Network.Endpoint("liar")))
+	if !assert.Nil(t, err) {
+		return
+	}
+	dec, err = liar.Decrypt("", c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, plaintext, dec.Plaintext)
+	assert.Equal(t, []int{1, 3}, dec.Decryptors)
+	assert.Contains(t, dec.Bad[2], "proof")
+}
@@ -0,0 +1,734 @@
+// Package operator carries out what an operator asks of a committee: create, refresh and delete secrets under IDs and retrieve them, have the committee sign and decrypt with them, deal a P-521 key for Schnorr signatures of the committee, check the shares of a node against the commitments on the bulletinboard, and read the transcript of an epoch.
+// Dealing, retrieving, signing and decrypting need the operator key from the metadata, reading the bulletinboard does not.
+package operator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/interpolation"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/ncw/gmp"
+)
+
+// Timeout of a single call to a node or the bulletinboard
+const Timeout = 10 * time.Second
+
+// Phase of the bulletinboard log holding the commitments an epoch ends with
+const phaseShareDist int32 = 3
+
+// How long Refresh waits for its epoch to end, and how often it asks
+const (
+	refreshTimeout = 2 * time.Minute
+	pollInterval   = 100 * time.Millisecond
+)
+
+// Operator talks to the bulletinboard and the nodes of a committee on behalf of its operator
+type Operator struct {
+	// Metadata Directory Path
+	metadataPath string
+	// Counter
+	counter int
+	// BulletinBoard IP
+	bip string
+	// Node IP Addresses
+	ipList []string
+	// Identity Keys of All Nodes
+	pks []*ecdsa.PublicKey
+	// Committee ID
+	committee string
+	// Operator Key, nil if the metadata holds none
+	id *identity.Identity
+	// Commitment
+	dpc *commitment.DLPolyCommit
+	// Prime Defining Group Z_p
+	p *gmp.Int
+	// Lagrange Coefficients at 0 of the Labels of the Committee
+	lambda []*gmp.Int
+	// Transport and Clients
+	transport transport.Transport
+	bConn     transport.Conn
+	bClient   pb.BulletinBoardServiceClient
+	nConn     []transport.Conn
+	sClient   []pb.SecretServiceClient
+}
+
+// ShareReport is the outcome of checking the shares of a node against the commitments on the bulletinboard
+type ShareReport struct {
+	Label int
+	Epoch int64
+	// Why a share failed, by the label of the node whose polynomial it is on. A node whose shares all match has none.
+	Bad map[int]string
+}
+
+// SecretInfo is what the bulletinboard holds about a secret of the committee
+type SecretInfo struct {
+	// ID of the secret, empty for the one the committee started with
+	ID string
+	// Epoch that dealt the secret, 0 for the one the committee started with
+	Dealt int64
+	// Latest completed epoch that refreshed the secret, 0 if none did since it was dealt
+	Refreshed int64
+}
+
+// Transcript is what the bulletinboard holds about an epoch
+type Transcript struct {
+	Status *pb.EpochStatusMsg
+	// Merkle roots over the phases of the epoch, set once they are complete
+	Audit *pb.AuditMsg
+	// Entries of the epoch in the order of the leaves of its Merkle tree
+	Entries []*pb.EntryMsg
+}
+
+func (o *Operator) Connect() error {
+	bConn, err := o.transport.Dial(o.bip)
+	if err != nil {
+		return errors.New(fmt.Sprintf("operator did not connect to bulletinboard: %v", err))
+	}
+	o.bConn = bConn
+	o.bClient = bConn.BulletinBoard()
+	for i := 0; i < o.counter; i++ {
+		nConn, err := o.transport.Dial(o.ipList[i])
+		if err != nil {
+			return errors.New(fmt.Sprintf("operator did not connect to node %d: %v", i+1, err))
+		}
+		o.nConn[i] = nConn
+		o.sClient[i] = nConn.Secret()
+	}
+	return nil
+}
+
+func (o *Operator) Disconnect() {
+	if o.bConn != nil {
+		o.bConn.Close()
+	}
+	for _, conn := range o.nConn {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
+
+// Latest returns the most recent epoch the bulletinboard knows about
+func (o *Operator) Latest() (*pb.EpochStatusMsg, error) {
+	history, err := o.history()
+	if err != nil {
+		return nil, err
+	}
+	return history[len(history)-1], nil
+}
+
+// Every epoch the bulletinboard knows about, genesis first
+func (o *Operator) history() ([]*pb.EpochStatusMsg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	stream, err := o.bClient.EpochHistory(ctx, o.epochMsg(0))
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*pb.EpochStatusMsg, 0)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, msg)
+	}
+	if len(history) == 0 {
+		return nil, errors.New("bulletinboard returned an empty epoch history")
+	}
+	return history, nil
+}
+
+// Latest epoch that completed, the shares of the nodes belong to it
+func (o *Operator) completed() (*pb.EpochStatusMsg, error) {
+	latest, err := o.Latest()
+	if err != nil {
+		return nil, err
+	}
+	if latest.GetState() != pb.EpochStatusMsg_COMPLETED {
+		return nil, errors.New(fmt.Sprintf("epoch %d is %s, the shares are only known between epochs", latest.GetEpoch(), strings.ToLower(latest.GetState().String())))
+	}
+	return latest, nil
+}
+
+// Degree of the sharing polynomials, as the bulletinboard reports it
+func (o *Operator) degree() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	msg, err := o.bConn.Admin().Status(ctx, &pb.StatusRequestMsg{})
+	if err != nil {
+		return 0, err
+	}
+	return int(msg.GetDegree()), nil
+}
+
+// Deal shares secret among the nodes under id in the epoch after the latest one, which must have completed, and returns that epoch.
+// The bulletinboard records the commitment to the polynomial of the dealing first, then every node takes its share. The secret the committee held under id before is gone, the other secrets stay.
+func (o *Operator) Deal(id string, secret *gmp.Int) (int64, error) {
+	if o.id == nil {
+		return 0, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	if secret.Sign() < 0 || secret.Cmp(o.p) >= 0 {
+		return 0, errors.New("the secret must be between 0 and the order of the group")
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return 0, err
+	}
+	return o.deal(latest.GetEpoch()+1, id, secret)
+}
+
+// Create shares a new secret among the nodes under id, like Deal, and returns the epoch that dealt it. A nil secret is drawn at random.
+// It fails if the committee holds a secret under id already.
+func (o *Operator) Create(id string, secret *gmp.Int) (int64, error) {
+	if o.id == nil {
+		return 0, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	if id == "" {
+		return 0, errors.New("a new secret needs an ID")
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return 0, err
+	}
+	for _, held := range latest.HeldSecrets() {
+		if held == id {
+			return 0, errors.New(fmt.Sprintf("the committee holds %s already", pb.SecretName(id)))
+		}
+	}
+	if secret == nil {
+		r, err := crand.Int(crand.Reader, new(big.Int).SetBytes(o.p.Bytes()))
+		if err != nil {
+			return 0, err
+		}
+		secret = gmp.NewInt(0).SetBytes(r.Bytes())
+	}
+	if secret.Sign() < 0 || secret.Cmp(o.p) >= 0 {
+		return 0, errors.New("the secret must be between 0 and the order of the group")
+	}
+	return o.deal(latest.GetEpoch()+1, id, secret)
+}
+
+// Deal secret under id in epoch
+func (o *Operator) deal(epoch int64, id string, secret *gmp.Int) (int64, error) {
+	degree, err := o.degree()
+	if err != nil {
+		return 0, err
+	}
+
+	// the coefficients hide the secret from any degree shares, they must not be guessable
+	var seed int64
+	if err := binary.Read(crand.Reader, binary.BigEndian, &seed); err != nil {
+		return 0, err
+	}
+	poly, err := polyring.NewRand(degree, rand.New(rand.NewSource(seed)), o.p)
+	if err != nil {
+		return 0, err
+	}
+	poly.SetCoefficientBig(0, secret)
+	cmt := o.dpc.NewG1()
+	o.dpc.Commit(cmt, poly)
+	deal := &pb.DealMsg{
+		Epoch:     epoch,
+		Committee: o.committee,
+		Polycmt:   cmt.CompressedBytes(),
+		Secret:    id,
+	}
+	deal.Signature = pb.SignOperated(o.id, deal)
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	if _, err := o.bClient.Deal(ctx, deal); err != nil {
+		return 0, errors.New(fmt.Sprintf("bulletinboard refused the dealing: %v", err))
+	}
+
+	failed := make([]string, 0)
+	for i := 0; i < o.counter; i++ {
+		x := gmp.NewInt(int64(i + 1))
+		y := gmp.NewInt(0)
+		poly.EvalMod(x, o.p, y)
+		witness := o.dpc.NewG1()
+		o.dpc.CreateWitness(witness, poly, x)
+		msg := &pb.DealtShareMsg{
+			X:         int32(i + 1),
+			Y:         y.Bytes(),
+			Witness:   witness.CompressedBytes(),
+			Polycmt:   deal.GetPolycmt(),
+			Epoch:     epoch,
+			Committee: o.committee,
+			Secret:    id,
+		}
+		msg.Signature = pb.SignOperated(o.id, msg)
+		// a node replaying its log after a restart answers once it caught up
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			_, err := o.sClient[i].Store(ctx, msg)
+			return err
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("node %d: %v", i+1, err))
+		}
+	}
+	if len(failed) > 0 {
+		return epoch, errors.New(fmt.Sprintf("epoch %d dealt, but not every node took its share: %s", epoch, strings.Join(failed, "; ")))
+	}
+	return epoch, nil
+}
+
+// Delete has the committee drop the secret under id in the epoch after the latest one, which must have completed, and returns that epoch.
+// The bulletinboard records the deletion first, then every node drops its shares. The secret the committee started with cannot be deleted.
+func (o *Operator) Delete(id string) (int64, error) {
+	if o.id == nil {
+		return 0, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	if id == "" {
+		return 0, errors.New("the default secret cannot be deleted")
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return 0, err
+	}
+	epoch := latest.GetEpoch() + 1
+	msg := &pb.DeleteMsg{
+		Secret:    id,
+		Epoch:     epoch,
+		Committee: o.committee,
+	}
+	msg.Signature = pb.SignOperated(o.id, msg)
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	if _, err := o.bClient.Delete(ctx, msg); err != nil {
+		return 0, errors.New(fmt.Sprintf("bulletinboard refused the deletion: %v", err))
+	}
+	failed := make([]string, 0)
+	for i := 0; i < o.counter; i++ {
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			_, err := o.sClient[i].Delete(ctx, msg)
+			return err
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("node %d: %v", i+1, err))
+		}
+	}
+	if len(failed) > 0 {
+		return epoch, errors.New(fmt.Sprintf("epoch %d deleted %s, but not every node dropped its shares: %s", epoch, pb.SecretName(id), strings.Join(failed, "; ")))
+	}
+	return epoch, nil
+}
+
+// Secrets lists the secrets the committee holds in the latest epoch, in the order of their IDs, with the epochs that dealt and last refreshed them
+func (o *Operator) Secrets() ([]SecretInfo, error) {
+	history, err := o.history()
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[string]*SecretInfo)
+	for _, msg := range history {
+		switch {
+		case msg.GetDealt():
+			infos[msg.GetSecret()] = &SecretInfo{ID: msg.GetSecret(), Dealt: msg.GetEpoch()}
+		case msg.GetDeleted():
+			delete(infos, msg.GetSecret())
+		case msg.GetEpoch() == 0:
+			infos[""] = &SecretInfo{}
+		case msg.GetState() == pb.EpochStatusMsg_COMPLETED:
+			for _, id := range msg.HeldSecrets() {
+				if info, ok := infos[id]; ok {
+					info.Refreshed = msg.GetEpoch()
+				}
+			}
+		}
+	}
+	held := history[len(history)-1].HeldSecrets()
+	out := make([]SecretInfo, 0, len(held))
+	for _, id := range held {
+		info, ok := infos[id]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("the epoch history of the bulletinboard never dealt %s", pb.SecretName(id)))
+		}
+		out = append(out, *info)
+	}
+	return out, nil
+}
+
+// Refresh starts the epoch after the latest one, which must have ended, and waits for it to end. The epoch hands off every secret of the committee, and the status it ended with is returned.
+// Without a clock that runs epochs on a schedule the secrets are only refreshed on request. A running clock refuses to start the epoch Refresh started and goes on with the next one.
+func (o *Operator) Refresh() (*pb.EpochStatusMsg, error) {
+	latest, err := o.Latest()
+	if err != nil {
+		return nil, err
+	}
+	epoch := latest.GetEpoch() + 1
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	if _, err := o.bClient.StartEpoch(ctx, o.epochMsg(epoch)); err != nil {
+		return nil, errors.New(fmt.Sprintf("bulletinboard did not start epoch %d: %v", epoch, err))
+	}
+	deadline := time.Now().Add(refreshTimeout)
+	for {
+		var status *pb.EpochStatusMsg
+		// the bulletinboard may be restarting
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			status, err = o.bClient.EpochStatus(ctx, o.epochMsg(epoch))
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch status.GetState() {
+		case pb.EpochStatusMsg_COMPLETED:
+			return status, nil
+		case pb.EpochStatusMsg_FAILED:
+			return status, errors.New(fmt.Sprintf("epoch %d failed", epoch))
+		}
+		if time.Now().After(deadline) {
+			return status, errors.New(fmt.Sprintf("epoch %d is still running after %v", epoch, refreshTimeout))
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Shares returns the shares of the secret under id that node label holds at the end of the latest completed epoch
+func (o *Operator) Shares(label int, id string) (*pb.SharesMsg, error) {
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	return o.shares(label, latest.GetEpoch(), id)
+}
+
+func (o *Operator) shares(label int, epoch int64, id string) (*pb.SharesMsg, error) {
+	if o.id == nil {
+		return nil, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	if label < 1 || label > o.counter {
+		return nil, errors.New(fmt.Sprintf("node %d is not a member of the committee", label))
+	}
+	msg := &pb.ShareRequestMsg{
+		Index:     int32(label),
+		Epoch:     epoch,
+		Committee: o.committee,
+		Secret:    id,
+	}
+	msg.Signature = pb.SignOperated(o.id, msg)
+	var shares *pb.SharesMsg
+	err := pb.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+		defer cancel()
+		var err error
+		shares, err = o.sClient[label-1].Retrieve(ctx, msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int(shares.GetIndex()) != label || shares.GetEpoch() != epoch || shares.GetSecret() != id || len(shares.GetShares()) != o.counter || len(shares.GetPolyCmts()) != o.counter {
+		return nil, errors.New(fmt.Sprintf("node %d answered with the shares of node %d of %s in epoch %d", label, shares.GetIndex(), pb.SecretName(shares.GetSecret()), shares.GetEpoch()))
+	}
+	return shares, nil
+}
+
+// Commitments returns the commitments to the secret under id the bulletinboard holds at the end of a completed epoch, indexed by label - 1, each checked against the log and against the signature of the node that wrote it.
+// They were written by the latest epoch up to it that refreshed or dealt the secret. Genesis and dealings carry no signature.
+func (o *Operator) Commitments(status *pb.EpochStatusMsg, id string) ([]*pb.Cmt1Msg, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	in := o.epochMsg(status.GetEpoch())
+	in.Secret = id
+	stream, err := o.bClient.ReadCommitments(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	cmts := make([]*pb.Cmt1Msg, o.counter)
+	// the epoch that wrote the commitments
+	var from *pb.EpochStatusMsg
+	for range cmts {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		index := int(msg.GetIndex())
+		if index < 1 || index > o.counter || cmts[index-1] != nil || msg.GetEpoch() > status.GetEpoch() || (from != nil && msg.GetEpoch() != from.GetEpoch()) || msg.GetSecret() != id {
+			return nil, errors.New(fmt.Sprintf("bulletinboard sent a commitment of node %d to %s in epoch %d", index, pb.SecretName(msg.GetSecret()), msg.GetEpoch()))
+		}
+		if from == nil {
+			if from, err = o.writtenIn(status, msg.GetEpoch()); err != nil {
+				return nil, err
+			}
+		}
+		if err := pb.VerifyInclusion(msg, phaseShareDist); err != nil {
+			return nil, err
+		}
+		unsigned := from.GetEpoch() == 0 || (from.GetDealt() && from.GetSecret() == id)
+		if !unsigned || len(msg.GetSignature()) != 0 {
+			if err := pb.VerifySigned(o.pks, msg); err != nil {
+				return nil, err
+			}
+		}
+		cmts[index-1] = msg
+	}
+	return cmts, nil
+}
+
+// Status of the epoch that wrote commitments read for the end of a completed epoch, which must have completed as well
+func (o *Operator) writtenIn(status *pb.EpochStatusMsg, epoch int64) (*pb.EpochStatusMsg, error) {
+	if epoch == status.GetEpoch() {
+		return status, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	from, err := o.bClient.EpochStatus(ctx, o.epochMsg(epoch))
+	if err != nil {
+		return nil, err
+	}
+	if from.GetState() != pb.EpochStatusMsg_COMPLETED {
+		return nil, errors.New(fmt.Sprintf("bulletinboard sent commitments of epoch %d, which did not complete", epoch))
+	}
+	return from, nil
+}
+
+// VerifyShares checks the shares of the secret under id that node label holds at the end of the latest completed epoch against the commitments on the bulletinboard
+func (o *Operator) VerifyShares(label int, id string) (*ShareReport, error) {
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	cmts, err := o.Commitments(latest, id)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := o.shares(label, latest.GetEpoch(), id)
+	if err != nil {
+		return nil, err
+	}
+	return o.check(shares, cmts), nil
+}
+
+// Check every share of a node against the commitment to its polynomial
+func (o *Operator) check(shares *pb.SharesMsg, cmts []*pb.Cmt1Msg) *ShareReport {
+	label := int(shares.GetIndex())
+	report := &ShareReport{
+		Label: label,
+		Epoch: shares.GetEpoch(),
+		Bad:   make(map[int]string),
+	}
+	for i, point := range shares.GetShares() {
+		cmt := o.dpc.NewG1()
+		cmt.SetCompressedBytes(cmts[i].GetPolycmt())
+		held := o.dpc.NewG1()
+		held.SetCompressedBytes(shares.GetPolyCmts()[i])
+		witness := o.dpc.NewG1()
+		witness.SetCompressedBytes(point.GetWitness())
+		switch {
+		case !held.Equals(cmt):
+			report.Bad[i+1] = "the node holds another commitment than the bulletinboard"
+		case int(point.GetX()) != label:
+			report.Bad[i+1] = fmt.Sprintf("the share is at %d", point.GetX())
+		case !o.dpc.VerifyEval(cmt, gmp.NewInt(int64(label)), gmp.NewInt(0).SetBytes(point.GetY()), witness):
+			report.Bad[i+1] = "the share does not match the commitment"
+		}
+	}
+	return report
+}
+
+// Secret reconstructs the secret under id from the shares of the nodes in labels at the end of the latest completed epoch.
+// Shares that do not match the commitments on the bulletinboard are left out, the good shares of more than degree nodes are needed.
+func (o *Operator) Secret(labels []int, id string) (*gmp.Int, error) {
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	cmts, err := o.Commitments(latest, id)
+	if err != nil {
+		return nil, err
+	}
+	good := make([]*pb.SharesMsg, 0, len(labels))
+	skipped := make([]string, 0)
+	for _, label := range labels {
+		shares, err := o.shares(label, latest.GetEpoch(), id)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("node %d: %v", label, err))
+			continue
+		}
+		if report := o.check(shares, cmts); len(report.Bad) > 0 {
+			skipped = append(skipped, fmt.Sprintf("node %d: %d bad shares", label, len(report.Bad)))
+			continue
+		}
+		good = append(good, shares)
+	}
+	if len(good) <= degree {
+		return nil, errors.New(fmt.Sprintf("need the shares of more than %d nodes, got %d: %s", degree, len(good), strings.Join(skipped, "; ")))
+	}
+	return Reconstruct(degree, o.counter, good[:degree+1], o.p)
+}
+
+// Reconstruct returns the secret shared by the polynomials of counter nodes, from the shares of degree+1 nodes on each of them.
+// Node j holds a point of the polynomial f_i of every node i, the secret is the sum of the f_i(0) weighted by the Lagrange coefficients of the committee.
+func Reconstruct(degree int, counter int, shares []*pb.SharesMsg, p *gmp.Int) (*gmp.Int, error) {
+	if len(shares) <= degree {
+		return nil, errors.New(fmt.Sprintf("need more than %d shares, got %d", degree, len(shares)))
+	}
+	zero := gmp.NewInt(0)
+	x := make([]*gmp.Int, counter)
+	y := make([]*gmp.Int, counter)
+	for i := 0; i < counter; i++ {
+		xi := make([]*gmp.Int, len(shares))
+		yi := make([]*gmp.Int, len(shares))
+		for j, msg := range shares {
+			if len(msg.GetShares()) != counter {
+				return nil, errors.New(fmt.Sprintf("node %d holds %d shares, need %d", msg.GetIndex(), len(msg.GetShares()), counter))
+			}
+			xi[j] = gmp.NewInt(int64(msg.GetShares()[i].GetX()))
+			yi[j] = gmp.NewInt(0).SetBytes(msg.GetShares()[i].GetY())
+		}
+		poly, err := interpolation.LagrangeInterpolate(degree, xi, yi, p)
+		if err != nil {
+			return nil, err
+		}
+		x[i] = gmp.NewInt(int64(i + 1))
+		y[i] = gmp.NewInt(0)
+		poly.EvalMod(zero, p, y[i])
+	}
+	poly, err := interpolation.LagrangeInterpolate(counter-1, x, y, p)
+	if err != nil {
+		return nil, err
+	}
+	secret := gmp.NewInt(0)
+	poly.EvalMod(zero, p, secret)
+	return secret, nil
+}
+
+// Transcript reads what the bulletinboard holds about an epoch, genesis included
+func (o *Operator) Transcript(epoch int64) (*Transcript, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	status, err := o.bClient.EpochStatus(ctx, o.epochMsg(epoch))
+	if err != nil {
+		return nil, err
+	}
+	audit, err := o.bClient.Audit(ctx, o.epochMsg(epoch))
+	if err != nil {
+		return nil, err
+	}
+	stream, err := o.bClient.ReadLog(ctx, o.epochMsg(epoch))
+	if err != nil {
+		return nil, err
+	}
+	t := &Transcript{
+		Status:  status,
+		Audit:   audit,
+		Entries: make([]*pb.EntryMsg, 0),
+	}
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return t, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.Entries = append(t.Entries, entry)
+	}
+}
+
+func (o *Operator) epochMsg(epoch int64) *pb.EpochMsg {
+	return &pb.EpochMsg{
+		Epoch:     epoch,
+		Committee: o.committee,
+	}
+}
+
+// ParseSecret reads a secret written in decimal, or in hexadecimal after 0x
+func ParseSecret(s string) (*gmp.Int, error) {
+	secret, ok := gmp.NewInt(0).SetString(strings.TrimSpace(s), 0)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("%q is not a number", s))
+	}
+	return secret, nil
+}
+
+func ReadIpList(metadataPath string) ([]string, error) {
+	ipData, err := ioutil.ReadFile(metadataPath + "/ip_list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(ipData), "\n"), nil
+}
+
+// New returns an operator of the committee of counter nodes described in metadataPath, that reaches it over tr.
+// Without an operator key in the metadata it can still read the bulletinboard.
+func New(counter int, metadataPath string, tr transport.Transport) (Operator, error) {
+	ipList, err := ReadIpList(metadataPath)
+	if err != nil {
+		return Operator{}, err
+	}
+	if len(ipList) < counter+1 {
+		return Operator{}, errors.New(fmt.Sprintf("ip_list holds %d addresses, need %d", len(ipList), counter+1))
+	}
+	pks, err := identity.ReadPkList(metadataPath, counter)
+	if err != nil {
+		return Operator{}, err
+	}
+	id, err := identity.Load(identity.OperatorKeyPath(metadataPath))
+	if os.IsNotExist(err) {
+		id = nil
+	} else if err != nil {
+		return Operator{}, err
+	}
+	dpc := commitment.DLPolyCommit{}
+	dpc.SetupFix(counter)
+	p := gmp.NewInt(0)
+	p.SetString("57896044618658097711785492504343953926634992332820282019728792006155588075521", 10)
+	labels := make([]int, counter)
+	for i := range labels {
+		labels[i] = i + 1
+	}
+	lambda, err := tbls.Lagrange(labels)
+	if err != nil {
+		return Operator{}, err
+	}
+	return Operator{
+		metadataPath: metadataPath,
+		counter:      counter,
+		bip:          ipList[0],
+		ipList:       ipList[1 : counter+1],
+		pks:          pks,
+		committee:    identity.CommitteeID(pks),
+		id:           id,
+		dpc:          &dpc,
+		p:            p,
+		lambda:       lambda,
+		transport:    tr,
+		nConn:        make([]transport.Conn, counter),
+		sClient:      make([]pb.SecretServiceClient, counter),
+	}, nil
+}
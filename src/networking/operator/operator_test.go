@@ -0,0 +1,205 @@
+package operator_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDealSecret(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(1))
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	secret, _ := operator.ParseSecret("0x5ec7e7")
+	epoch, err := op.Deal("", secret)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(2), epoch)
+	latest, err := op.Latest()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), latest.GetEpoch())
+	assert.True(t, latest.GetDealt())
+
+	// the dealing is an epoch of the log without phase 2
+	transcript, err := op.Transcript(2)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Len(t, transcript.Entries, 3)
+	assert.NotNil(t, transcript.Audit.GetRoot())
+	var out bytes.Buffer
+	operator.WriteTranscript(&out, transcript)
+	assert.Contains(t, out.String(), "state\tcompleted, dealt\n")
+	assert.Contains(t, out.String(), "unsigned\n")
+
+	// the next epochs refresh the dealt secret like any other
+	for e := int64(2); e <= 4; e++ {
+		if e > 2 {
+			assert.Nil(t, committee.Run(1))
+		}
+		got, err := op.Secret([]int{1, 2, 3}, "")
+		if !assert.Nil(t, err, "epoch %d", e) {
+			return
+		}
+		assert.Equal(t, 0, secret.Cmp(got), "epoch %d", e)
+		stored, err := committee.Secret([]int{2, 3})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, secret.Cmp(stored), "stored shares of epoch %d", e)
+		report, err := op.VerifyShares(1, "")
+		assert.Nil(t, err)
+		assert.Equal(t, e, report.Epoch)
+		assert.Empty(t, report.Bad)
+	}
+
+	// a second dealing replaces the refreshed secret, and the stored shares are those of the dealing
+	epoch, err = op.Deal("", gmp.NewInt(42))
+	if !assert.Nil(t, err) {
+		return
+	}
+	state, err := committee.Shares(1)
+	assert.Nil(t, err)
+	assert.Equal(t, epoch, state.Epoch)
+	assert.True(t, state.Dealt)
+	assert.Nil(t, committee.Run(1))
+	got, err := op.Secret([]int{1, 2}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, gmp.NewInt(42).Cmp(got))
+
+	// the public key of the committee follows the dealt secret
+	sig, err := op.Sign("", []byte("dealt"))
+	if assert.Nil(t, err) {
+		dc := commitment.DLCommit{}
+		dc.SetupFix()
+		pk := dc.NewG1()
+		dc.Commit(pk, gmp.NewInt(42))
+		assert.Equal(t, pk.CompressedBytes(), sig.PublicKey)
+	}
+}
+
+func TestDealNeedsOperatorKey(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	// an operator with a key of its own is refused by the bulletinboard and the nodes
+	assert.Nil(t, identity.GenerateOperator(committee.Dir))
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = op.Deal("", gmp.NewInt(7))
+	assert.NotNil(t, err)
+	_, err = op.Shares(1, "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	latest, err := op.Latest()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), latest.GetEpoch())
+}
+
+func TestSecrets(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	epoch, err := op.Create("signing", gmp.NewInt(42))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(1), epoch)
+	_, err = op.Create("signing", gmp.NewInt(7))
+	assert.NotNil(t, err)
+	// a random secret is only known through the shares
+	epoch, err = op.Create("archive", nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(2), epoch)
+	archive, err := op.Secret([]int{1, 2}, "archive")
+	if !assert.Nil(t, err) {
+		return
+	}
+	secrets, err := op.Secrets()
+	assert.Nil(t, err)
+	assert.Equal(t, []operator.SecretInfo{{ID: ""}, {ID: "archive", Dealt: 2}, {ID: "signing", Dealt: 1}}, secrets)
+
+	// every epoch refreshes all of them, each against its own commitments
+	assert.Nil(t, committee.Run(1))
+	refreshed, err := op.Refresh()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(4), refreshed.GetEpoch())
+	assert.Equal(t, []string{"", "archive", "signing"}, refreshed.HeldSecrets())
+	want := map[string]*gmp.Int{"": localnet.GenesisSecret(1), "archive": archive, "signing": gmp.NewInt(42)}
+	for id, secret := range want {
+		got, err := op.Secret([]int{2, 3}, id)
+		if assert.Nil(t, err, "secret %q", id) {
+			assert.Equal(t, 0, secret.Cmp(got), "secret %q", id)
+		}
+		report, err := op.VerifyShares(1, id)
+		if assert.Nil(t, err, "secret %q", id) {
+			assert.Empty(t, report.Bad, "secret %q", id)
+		}
+	}
+	assert.Nil(t, committee.Verify())
+	state, err := committee.Shares(1)
+	assert.Nil(t, err)
+	assert.Len(t, state.Secrets, 2)
+	// the signing key stays the same through the refresh
+	sig, err := op.Sign("signing", []byte("refreshed"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "signing", sig.Secret)
+		assert.Nil(t, operator.VerifySignature(sig.PublicKey, []byte("refreshed"), sig.Signature))
+	}
+	secrets, err = op.Secrets()
+	assert.Nil(t, err)
+	assert.Equal(t, []operator.SecretInfo{{Refreshed: 4}, {ID: "archive", Dealt: 2, Refreshed: 4}, {ID: "signing", Dealt: 1, Refreshed: 4}}, secrets)
+
+	epoch, err = op.Delete("archive")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, int64(5), epoch)
+	_, err = op.Delete("")
+	assert.NotNil(t, err)
+	_, err = op.Secret([]int{1, 2}, "archive")
+	assert.NotNil(t, err)
+	_, err = op.Shares(1, "archive")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, committee.Run(1))
+	secrets, err = op.Secrets()
+	assert.Nil(t, err)
+	assert.Equal(t, []operator.SecretInfo{{Refreshed: 6}, {ID: "signing", Dealt: 1, Refreshed: 6}}, secrets)
+	got, err := op.Secret([]int{1, 3}, "signing")
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0, gmp.NewInt(42).Cmp(got))
+	}
+}
@@ -0,0 +1,253 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/frost"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+)
+
+// Length of the random ID of a signing session
+const sessionLen = 16
+
+// SchnorrSignature is a Schnorr signature of the committee on a message under its P-521 key
+type SchnorrSignature struct {
+	Message []byte
+	// R in compressed form followed by z, see frost.Signature
+	Signature []byte
+	// Y = g^y for the key y, compressed
+	PublicKey []byte
+	// Labels of the nodes whose responses make the signature
+	Signers []int
+	// Why a node was left out, by its label
+	Bad map[int]string
+}
+
+// DealKey shares the P-521 signing key secret among the nodes on a polynomial of the degree of the committee, and returns the public key in compressed form.
+// Every node checks its share against the Feldman commitment to the polynomial, which it keeps to tell the signers of the key. The key the committee held before is gone.
+func (o *Operator) DealKey(secret *big.Int) ([]byte, error) {
+	if o.id == nil {
+		return nil, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	if secret.Sign() <= 0 || secret.Cmp(p521.Curve.Params().N) >= 0 {
+		return nil, errors.New("the key must be between 1 and the order of P-521")
+	}
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	comm, shares, err := frost.Deal(secret, degree, o.counter, crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	failed := make([]string, 0)
+	for i := 0; i < o.counter; i++ {
+		msg := &pb.DealtKeyMsg{
+			X:          int32(i + 1),
+			Share:      shares[i].Bytes(),
+			Commitment: comm.Compressed(),
+			Committee:  o.committee,
+		}
+		msg.Signature = pb.SignOperated(o.id, msg)
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			_, err := o.nConn[i].Schnorr().StoreKey(ctx, msg)
+			return err
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("node %d: %v", i+1, err))
+		}
+	}
+	pub := comm.EvalInExponent(big.NewInt(0)).Bytes()
+	if len(failed) > 0 {
+		return pub, errors.New(fmt.Sprintf("key dealt, but not every node took its share: %s", strings.Join(failed, "; ")))
+	}
+	return pub, nil
+}
+
+// SchnorrSign has the nodes sign message with their shares of the P-521 key in two rounds, and returns a Schnorr signature any verifier of P-521 Schnorr signatures accepts, see frost.Verify.
+// In the first round the operator opens a session with fresh nonce commitments of the first degree+1 nodes that hold the same key. In the second those nodes answer with their responses, which the operator checks one by one against the commitment to the key.
+// A node that fails either round is left out, and the operator opens a new session without it until degree+1 responses check out or too few nodes are left.
+func (o *Operator) SchnorrSign(message []byte) (*SchnorrSignature, error) {
+	if o.id == nil {
+		return nil, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	sig := &SchnorrSignature{
+		Message: message,
+		Bad:     make(map[int]string),
+	}
+	for {
+		id := make([]byte, sessionLen)
+		if _, err := crand.Read(id); err != nil {
+			return nil, err
+		}
+		labels, commits, comm, err := o.commitNonces(id, degree, sig.Bad)
+		if err != nil {
+			return nil, err
+		}
+		pub := comm.EvalInExponent(big.NewInt(0))
+		responses, ok := o.signSchnorr(id, message, labels, commits, comm, pub, sig.Bad)
+		if !ok {
+			continue
+		}
+		signature, err := frost.Aggregate(message, commits, responses)
+		if err != nil {
+			return nil, err
+		}
+		if !frost.Verify(pub, message, signature) {
+			return nil, errors.New("the responses do not add up to a valid signature")
+		}
+		sig.Signature = signature.Bytes()
+		sig.PublicKey = pub.Bytes()
+		sig.Signers = labels
+		return sig, nil
+	}
+}
+
+// First round of session id: the nonce commitments of the first degree+1 nodes not in bad that hold the same key, along with their labels and the commitment to that key.
+// Nodes that fail or hold another key are added to bad.
+func (o *Operator) commitNonces(id []byte, degree int, bad map[int]string) ([]int, []*frost.Commitment, p521.PolyCommit, error) {
+	req := &pb.NonceRequestMsg{
+		Session:   id,
+		Committee: o.committee,
+	}
+	req.Signature = pb.SignOperated(o.id, req)
+
+	// the nodes are grouped by the key they hold, the honest ones hold the dealt key and outnumber the others
+	type holders struct {
+		comm    p521.PolyCommit
+		labels  []int
+		commits []*frost.Commitment
+	}
+	groups := make([]*holders, 0, 1)
+	for label := 1; label <= o.counter; label++ {
+		if _, ok := bad[label]; ok {
+			continue
+		}
+		var msg *pb.NonceCommitMsg
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			msg, err = o.nConn[label-1].Schnorr().CommitNonce(ctx, req)
+			return err
+		})
+		if err == nil && (int(msg.GetIndex()) != label || !bytes.Equal(msg.GetSession(), id)) {
+			err = errors.New(fmt.Sprintf("answered as node %d for session %x", msg.GetIndex(), msg.GetSession()))
+		}
+		var commit *frost.Commitment
+		var comm p521.PolyCommit
+		if err == nil {
+			commit, err = frost.ParseCommitment(label, msg.GetHiding(), msg.GetBinding())
+		}
+		if err == nil {
+			comm, err = p521.ParsePolyCommit(msg.GetKey())
+		}
+		if err == nil && comm.Degree() != degree {
+			err = errors.New(fmt.Sprintf("holds a key on a polynomial of degree %d", comm.Degree()))
+		}
+		if err != nil {
+			bad[label] = err.Error()
+			continue
+		}
+		var group *holders
+		for _, g := range groups {
+			if g.comm.Equals(comm) {
+				group = g
+			}
+		}
+		if group == nil {
+			group = &holders{comm: comm}
+			groups = append(groups, group)
+		}
+		group.labels = append(group.labels, label)
+		group.commits = append(group.commits, commit)
+		if len(group.labels) > degree {
+			for _, g := range groups {
+				if g != group {
+					for _, other := range g.labels {
+						bad[other] = "holds another key than the other signers"
+					}
+				}
+			}
+			return group.labels, group.commits, group.comm, nil
+		}
+	}
+	return nil, nil, p521.PolyCommit{}, errors.New(fmt.Sprintf("need more than %d nodes that hold the same key: %s", degree, badList(bad)))
+}
+
+// Second round of session id with the nodes at labels: their responses in the same order, or false if a node failed, which is then added to bad
+func (o *Operator) signSchnorr(id []byte, message []byte, labels []int, commits []*frost.Commitment, comm p521.PolyCommit, pub p521.ECPoint, bad map[int]string) ([]*big.Int, bool) {
+	req := &pb.SchnorrRequestMsg{
+		Session:     id,
+		Message:     message,
+		Commitments: make([]*pb.NonceCommitMsg, len(commits)),
+		Committee:   o.committee,
+	}
+	for i, c := range commits {
+		req.Commitments[i] = &pb.NonceCommitMsg{
+			Index:   int32(c.Index),
+			Session: id,
+			Hiding:  c.Hiding.Bytes(),
+			Binding: c.Binding.Bytes(),
+		}
+	}
+	req.Signature = pb.SignOperated(o.id, req)
+
+	responses := make([]*big.Int, len(labels))
+	ok := true
+	for i, label := range labels {
+		var msg *pb.SchnorrShareMsg
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			msg, err = o.nConn[label-1].Schnorr().SignSchnorr(ctx, req)
+			return err
+		})
+		if err == nil && (int(msg.GetIndex()) != label || !bytes.Equal(msg.GetSession(), id)) {
+			err = errors.New(fmt.Sprintf("answered as node %d for session %x", msg.GetIndex(), msg.GetSession()))
+		}
+		if err == nil {
+			responses[i] = new(big.Int).SetBytes(msg.GetResponse())
+			public := comm.EvalInExponent(big.NewInt(int64(label)))
+			if !frost.VerifyShare(label, public, responses[i], pub, message, commits) {
+				err = errors.New("the response does not verify under the share of the key")
+			}
+		}
+		if err != nil {
+			bad[label] = err.Error()
+			ok = false
+		}
+	}
+	return responses, ok
+}
+
+// VerifySchnorr checks a Schnorr signature of the committee on message under its P-521 public key, both as SchnorrSign returns them
+func VerifySchnorr(publicKey []byte, message []byte, signature []byte) error {
+	pub, err := p521.ParsePoint(publicKey)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad public key: %v", err))
+	}
+	sig, err := frost.ParseSignature(signature)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad signature: %v", err))
+	}
+	if !frost.Verify(pub, message, sig) {
+		return errors.New("the signature does not verify under the public key")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package operator_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/admin"
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchnorrSignature(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+	assert.Nil(t, committee.Run(1))
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// no node signs before a key was dealt
+	_, err = op.SchnorrSign([]byte("too early"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no signing key")
+	}
+
+	key := big.NewInt(0x5c4e)
+	pub, err := op.DealKey(key)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, p521.ScalarBaseMult(key).Bytes(), pub)
+	dealt, err := committee.Shares(3)
+	if assert.Nil(t, err) && assert.NotNil(t, dealt.Schnorr) {
+		assert.Len(t, dealt.Schnorr.Commitment, 2)
+		assert.True(t, dealt.Schnorr.Dealt)
+	}
+
+	// the key outlives the epochs, which refresh its shares, and a dealing of the secret of the committee
+	message := []byte("pay 3 coins")
+	for round := 0; round < 3; round++ {
+		switch round {
+		case 1:
+			assert.Nil(t, committee.Run(2))
+			state, err := committee.Shares(3)
+			if assert.Nil(t, err) && assert.NotNil(t, state.Schnorr) && dealt.Schnorr != nil {
+				assert.False(t, state.Schnorr.Dealt)
+				assert.NotEqual(t, dealt.Schnorr.Share, state.Schnorr.Share)
+				assert.Equal(t, dealt.Schnorr.Commitment[0], state.Schnorr.Commitment[0])
+				assert.NotEqual(t, dealt.Schnorr.Commitment[1], state.Schnorr.Commitment[1])
+			}
+		case 2:
+			_, err := op.Deal("", gmp.NewInt(7))
+			assert.Nil(t, err)
+		}
+		sig, err := op.SchnorrSign(message)
+		if !assert.Nil(t, err, "round %d", round) {
+			return
+		}
+		assert.Equal(t, []int{1, 2}, sig.Signers)
+		assert.Empty(t, sig.Bad)
+		assert.Equal(t, pub, sig.PublicKey)
+		assert.Nil(t, operator.VerifySchnorr(pub, message, sig.Signature))
+		assert.NotNil(t, operator.VerifySchnorr(pub, []byte("pay 4 coins"), sig.Signature))
+	}
+
+	// a restarted node signs with the key it stored
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	assert.Nil(t, committee.Nodes[0].Shutdown(ctx))
+	assert.Nil(t, committee.Restart(1))
+	tr := committee.Network.Endpoint("operator")
+	for i := 0; i < 100 && admin.Ready(tr, localnet.NodeAddr(1)) != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sig, err := op.SchnorrSign(message)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []int{1, 2}, sig.Signers)
+	}
+
+	// a response that does not verify under the share of its node is left out, and a new session signs without it
+	tamper := &localnet.Tamper{Label: 1, SignSchnorr: func(in *pb.SchnorrRequestMsg, out *pb.SchnorrShareMsg) error {
+		out.Response = new(big.Int).Add(new(big.Int).SetBytes(out.GetResponse()), big.NewInt(1)).Bytes()
+		return nil
+	}}
+	liar, err := committee.Operator(tamper.Transport(committee.Network.Endpoint("liar")))
+	if !assert.Nil(t, err) {
+		return
+	}
+	sig, err = liar.SchnorrSign(message)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []int{2, 3}, sig.Signers)
+	assert.Contains(t, sig.Bad[1], "does not verify")
+	assert.Nil(t, operator.VerifySchnorr(pub, message, sig.Signature))
+	var out bytes.Buffer
+	operator.WriteSchnorrSignature(&out, sig)
+	assert.Contains(t, out.String(), "signers\t2,3\n")
+}
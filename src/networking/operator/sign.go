@@ -0,0 +1,182 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Nik-U/pbc"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+)
+
+// Signature is a threshold BLS signature of the committee on a message
+type Signature struct {
+	Epoch int64
+	// ID of the secret that signed
+	Secret  string
+	Message []byte
+	// H(m)^s for the secret s, compressed
+	Signature []byte
+	// g^s, compressed. It is the same in every epoch as long as the committee holds the same secret.
+	PublicKey []byte
+	// Labels of the nodes whose partial signatures make the signature
+	Signers []int
+	// Why the partial signature of a node was left out, by its label
+	Bad map[int]string
+}
+
+// Sign has the nodes sign message with their shares of the secret under id at the end of the latest completed epoch, and combines the partial signatures of the first degree+1 nodes that sign correctly.
+// A partial signature counts once the values the node gives in the exponent open the commitments on the bulletinboard, and it verifies under the public share they make. The public key comes from the same public shares.
+func (o *Operator) Sign(id string, message []byte) (*Signature, error) {
+	if o.id == nil {
+		return nil, errors.New(fmt.Sprintf("no operator key at %s", identity.OperatorKeyPath(o.metadataPath)))
+	}
+	degree, err := o.degree()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := o.completed()
+	if err != nil {
+		return nil, err
+	}
+	cmts, err := o.Commitments(latest, id)
+	if err != nil {
+		return nil, err
+	}
+	req := &pb.SignRequestMsg{
+		Message:   message,
+		Epoch:     latest.GetEpoch(),
+		Committee: o.committee,
+		Secret:    id,
+	}
+	req.Signature = pb.SignOperated(o.id, req)
+
+	sig := &Signature{
+		Epoch:   latest.GetEpoch(),
+		Secret:  id,
+		Message: message,
+		Bad:     make(map[int]string),
+	}
+	partials := make([]*pbc.Element, 0, degree+1)
+	publics := make([]*pbc.Element, 0, degree+1)
+	for label := 1; label <= o.counter && len(sig.Signers) <= degree; label++ {
+		var msg *pb.PartialSigMsg
+		err := pb.Retry(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			var err error
+			msg, err = o.nConn[label-1].Sign().SignShare(ctx, req)
+			return err
+		})
+		if err != nil {
+			sig.Bad[label] = err.Error()
+			continue
+		}
+		partial, public, err := o.checkPartial(label, req, msg, cmts)
+		if err != nil {
+			sig.Bad[label] = err.Error()
+			continue
+		}
+		sig.Signers = append(sig.Signers, label)
+		partials = append(partials, partial)
+		publics = append(publics, public)
+	}
+	if len(sig.Signers) <= degree {
+		return nil, errors.New(fmt.Sprintf("need the partial signatures of more than %d nodes, got %d: %s", degree, len(sig.Signers), badList(sig.Bad)))
+	}
+	combined, err := tbls.Combine(sig.Signers, partials)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := tbls.Combine(sig.Signers, publics)
+	if err != nil {
+		return nil, err
+	}
+	if !tbls.Verify(pub, message, combined) {
+		return nil, errors.New("the partial signatures do not combine into a valid signature")
+	}
+	sig.Signature = combined.CompressedBytes()
+	sig.PublicKey = pub.CompressedBytes()
+	return sig, nil
+}
+
+// Check the partial signature of node label and return it along with the public share of the node
+func (o *Operator) checkPartial(label int, req *pb.SignRequestMsg, msg *pb.PartialSigMsg, cmts []*pb.Cmt1Msg) (*pbc.Element, *pbc.Element, error) {
+	public, err := o.checkPublicShare(label, req.GetEpoch(), &pb.PublicShareMsg{
+		Index:       msg.GetIndex(),
+		PublicShare: msg.GetPublicShare(),
+		Evals:       msg.GetEvals(),
+		Witnesses:   msg.GetWitnesses(),
+		Epoch:       msg.GetEpoch(),
+		Committee:   msg.GetCommittee(),
+		Secret:      msg.GetSecret(),
+	}, cmts)
+	if err != nil {
+		return nil, nil, err
+	}
+	partial, err := tbls.Parse(msg.GetPartial())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !tbls.Verify(public, req.GetMessage(), partial) {
+		return nil, nil, errors.New("the partial signature does not verify under the public share")
+	}
+	return partial, public, nil
+}
+
+// Check the public share of node label in epoch against the commitments of the epoch to a secret, and return it
+func (o *Operator) checkPublicShare(label int, epoch int64, msg *pb.PublicShareMsg, cmts []*pb.Cmt1Msg) (*pbc.Element, error) {
+	if int(msg.GetIndex()) != label || msg.GetEpoch() != epoch || msg.GetCommittee() != o.committee {
+		return nil, errors.New(fmt.Sprintf("answered as node %d in epoch %d", msg.GetIndex(), msg.GetEpoch()))
+	}
+	if secret := cmts[0].GetSecret(); msg.GetSecret() != secret {
+		return nil, errors.New(fmt.Sprintf("answered for %s instead of %s", pb.SecretName(msg.GetSecret()), pb.SecretName(secret)))
+	}
+	polyCmts := make([][]byte, len(cmts))
+	for i, cmt := range cmts {
+		polyCmts[i] = cmt.GetPolycmt()
+	}
+	public, err := tbls.PublicShare(o.dpc, label, polyCmts, msg.GetEvals(), msg.GetWitnesses(), o.lambda)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(public.CompressedBytes(), msg.GetPublicShare()) {
+		return nil, errors.New("the public share does not match the commitments")
+	}
+	return public, nil
+}
+
+// Reasons by label, in the order of the labels
+func badList(bad map[int]string) string {
+	labels := make([]int, 0, len(bad))
+	for label := range bad {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+	reasons := make([]string, len(labels))
+	for i, label := range labels {
+		reasons[i] = fmt.Sprintf("node %d: %s", label, bad[label])
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// VerifySignature checks a signature of the committee on message under its public key, both compressed as Sign returns them
+func VerifySignature(publicKey []byte, message []byte, signature []byte) error {
+	pub, err := tbls.Parse(publicKey)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad public key: %v", err))
+	}
+	sig, err := tbls.Parse(signature)
+	if err != nil {
+		return errors.New(fmt.Sprintf("bad signature: %v", err))
+	}
+	if !tbls.Verify(pub, message, sig) {
+		return errors.New("the signature does not verify under the public key")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package operator_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/operator"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdSignature(t *testing.T) {
+	committee, err := localnet.StartTemp(1, 3)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer committee.Stop()
+
+	op, err := committee.Operator(committee.Network.Endpoint("operator"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the public key is g^s for the genesis secret s, and stays the same while the shares are refreshed
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	pk := dc.NewG1()
+	dc.Commit(pk, localnet.GenesisSecret(1))
+	message := []byte("hello committee")
+	var first *operator.Signature
+	for epoch := int64(1); epoch <= 3; epoch++ {
+		assert.Nil(t, committee.Run(1))
+		sig, err := op.Sign("", message)
+		if !assert.Nil(t, err, "epoch %d", epoch) {
+			return
+		}
+		assert.Equal(t, epoch, sig.Epoch)
+		assert.Equal(t, []int{1, 2}, sig.Signers)
+		assert.Empty(t, sig.Bad)
+		assert.Equal(t, pk.CompressedBytes(), sig.PublicKey)
+		assert.Nil(t, operator.VerifySignature(sig.PublicKey, message, sig.Signature))
+		assert.NotNil(t, operator.VerifySignature(sig.PublicKey, []byte("another message"), sig.Signature))
+		if first == nil {
+			first = sig
+		}
+		assert.Equal(t, first.Signature, sig.Signature)
+	}
+
+	// a partial signature that does not verify under the public share of its node is left out
+	tamper := &localnet.Tamper{Label: 1, SignShare: func(in *pb.SignRequestMsg, out *pb.PartialSigMsg) error {
+		out.Partial = tbls.Sign(gmp.NewInt(42), in.GetMessage()).CompressedBytes()
+		return nil
+	}}
+	liar, err := committee.Operator(tamper.Transport(committee.Network.Endpoint("liar")))
+	if !assert.Nil(t, err) {
+		return
+	}
+	sig, err := liar.Sign("", message)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []int{2, 3}, sig.Signers)
+	assert.Contains(t, sig.Bad[1], "does not verify")
+	assert.Equal(t, first.Signature, sig.Signature)
+	var out bytes.Buffer
+	operator.WriteSignature(&out, sig)
+	assert.Contains(t, out.String(), "signers\t2,3\n")
+	assert.Contains(t, out.String(), "bad\tnode 1: ")
+}
@@ -0,0 +1,138 @@
+package operator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+)
+
+// WriteTranscript prints the transcript of an epoch, one entry of the log per line with the commitments it holds
+func WriteTranscript(w io.Writer, t *Transcript) {
+	state := strings.ToLower(t.Status.GetState().String())
+	switch {
+	case t.Status.GetDealt():
+		state += ", dealt"
+	case t.Status.GetDeleted():
+		state += ", deleted"
+	}
+	fmt.Fprintf(w, "epoch\t%d\n", t.Status.GetEpoch())
+	fmt.Fprintf(w, "state\t%s\n", state)
+	if t.Status.GetDealt() || t.Status.GetDeleted() {
+		fmt.Fprintf(w, "secret\t%q\n", t.Status.GetSecret())
+	}
+	fmt.Fprintf(w, "start\t%s\n", time.Unix(0, t.Status.GetStart()).Format(time.RFC3339Nano))
+	if t.Status.GetEnd() != 0 {
+		fmt.Fprintf(w, "took\t%v\n", time.Duration(t.Status.GetEnd()-t.Status.GetStart()))
+	}
+	if root := t.Audit.GetProactivizationRoot(); root != nil {
+		fmt.Fprintf(w, "root\tphase 2 %x\n", root)
+	}
+	if root := t.Audit.GetRoot(); root != nil {
+		fmt.Fprintf(w, "root\t%x over %d entries, head %d %x\n", root, t.Audit.GetSize(), t.Audit.GetSeq(), t.Audit.GetHead())
+	}
+	for _, entry := range t.Entries {
+		fmt.Fprintf(w, "entry\t%d\tphase %d\tnode %d\thash %x\t%s\n", entry.GetSeq(), entry.GetPhase(), entry.GetIndex(), entry.GetHash(), content(entry))
+	}
+}
+
+// The commitments an entry holds
+func content(entry *pb.EntryMsg) string {
+	switch entry.GetPhase() {
+	case 2:
+		msg := &pb.Cmt2Msg{}
+		if err := proto.Unmarshal(entry.GetData(), msg); err != nil {
+			return "corrupted: " + err.Error()
+		}
+		return fmt.Sprintf("secret %q share %x poly %x witness %x signature %x", msg.GetSecret(), msg.GetSharecmt(), msg.GetPolycmt(), msg.GetZerowitness(), msg.GetSignature())
+	case phaseShareDist:
+		msg := &pb.Cmt1Msg{}
+		if err := proto.Unmarshal(entry.GetData(), msg); err != nil {
+			return "corrupted: " + err.Error()
+		}
+		if len(msg.GetSignature()) == 0 {
+			return fmt.Sprintf("secret %q poly %x unsigned", msg.GetSecret(), msg.GetPolycmt())
+		}
+		return fmt.Sprintf("secret %q poly %x signature %x", msg.GetSecret(), msg.GetPolycmt(), msg.GetSignature())
+	}
+	return fmt.Sprintf("data %x", entry.GetData())
+}
+
+// WriteSecrets prints the secrets of the committee, one per line with the epochs that dealt and last refreshed them
+func WriteSecrets(w io.Writer, secrets []SecretInfo) {
+	for _, s := range secrets {
+		refreshed := "never"
+		if s.Refreshed != 0 {
+			refreshed = strconv.FormatInt(s.Refreshed, 10)
+		}
+		fmt.Fprintf(w, "secret	%q	dealt %d	refreshed %s\n", s.ID, s.Dealt, refreshed)
+	}
+}
+
+// WriteShareReport prints which shares of a node match the commitments on the bulletinboard
+func WriteShareReport(w io.Writer, r *ShareReport, counter int) {
+	fmt.Fprintf(w, "node %d epoch %d: %d/%d shares match the commitments\n", r.Label, r.Epoch, counter-len(r.Bad), counter)
+	bad := make([]int, 0, len(r.Bad))
+	for label := range r.Bad {
+		bad = append(bad, label)
+	}
+	sort.Ints(bad)
+	for _, label := range bad {
+		fmt.Fprintf(w, "bad\tpolynomial of node %d: %s\n", label, r.Bad[label])
+	}
+}
+
+// WriteSignature prints a signature of the committee and the nodes that made it
+func WriteSignature(w io.Writer, s *Signature) {
+	fmt.Fprintf(w, "epoch\t%d\n", s.Epoch)
+	fmt.Fprintf(w, "secret\t%q\n", s.Secret)
+	fmt.Fprintf(w, "signature\t%x\n", s.Signature)
+	fmt.Fprintf(w, "public key\t%x\n", s.PublicKey)
+	writeNodes(w, "signers", s.Signers, s.Bad)
+}
+
+// WriteSchnorrSignature prints a Schnorr signature of the committee and the nodes that made it
+func WriteSchnorrSignature(w io.Writer, s *SchnorrSignature) {
+	fmt.Fprintf(w, "signature\t%x\n", s.Signature)
+	fmt.Fprintf(w, "public key\t%x\n", s.PublicKey)
+	writeNodes(w, "signers", s.Signers, s.Bad)
+}
+
+// WriteBeacon prints the value of a round with its proof and the nodes whose evaluations make it
+func WriteBeacon(w io.Writer, b *Beacon) {
+	fmt.Fprintf(w, "round\t%d\n", b.Epoch)
+	fmt.Fprintf(w, "output\t%x\n", b.Output)
+	fmt.Fprintf(w, "proof\t%x\n", b.Proof)
+	fmt.Fprintf(w, "public key\t%x\n", b.PublicKey)
+	writeNodes(w, "signers", b.Signers, b.Bad)
+}
+
+// WritePublicKey prints the public key of the committee and the nodes it was derived from
+func WritePublicKey(w io.Writer, pk *PublicKey) {
+	fmt.Fprintf(w, "epoch\t%d\n", pk.Epoch)
+	fmt.Fprintf(w, "secret\t%q\n", pk.Secret)
+	fmt.Fprintf(w, "public key\t%x\n", pk.Key)
+	writeNodes(w, "nodes", pk.Nodes, pk.Bad)
+}
+
+// Print the labels of the nodes that took part, then why the others were left out
+func writeNodes(w io.Writer, name string, labels []int, bad map[int]string) {
+	list := make([]string, len(labels))
+	for i, label := range labels {
+		list[i] = strconv.Itoa(label)
+	}
+	fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(list, ","))
+	left := make([]int, 0, len(bad))
+	for label := range bad {
+		left = append(left, label)
+	}
+	sort.Ints(left)
+	for _, label := range left {
+		fmt.Fprintf(w, "bad\tnode %d: %s\n", label, bad[label])
+	}
+}
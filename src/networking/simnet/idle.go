@@ -0,0 +1,48 @@
+package simnet
+
+import (
+	"bytes"
+	"runtime"
+)
+
+// States of a goroutine that is doing something, or will shortly without anyone waking it
+var busyStates = map[string]bool{
+	"running":  true,
+	"runnable": true,
+	"syscall":  true,
+	"sleep":    true,
+}
+
+// Whether every other goroutine of the process waits, on the simulator among others.
+// The simulator only moves on once the process is idle, so that the goroutines woken by an event have made all their calls before the next event is chosen.
+func idle() bool {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	// the calling goroutine comes first
+	for i, g := range bytes.Split(buf, []byte("\n\n")) {
+		if i > 0 && busyStates[goroutineState(g)] {
+			return false
+		}
+	}
+	return true
+}
+
+// The state in the header of a goroutine in a stack dump, like "chan receive" in "goroutine 7 [chan receive, 2 minutes]:"
+func goroutineState(g []byte) string {
+	start := bytes.IndexByte(g, '[')
+	if start < 0 {
+		return ""
+	}
+	state := g[start+1:]
+	if end := bytes.IndexAny(state, ",]"); end >= 0 {
+		state = state[:end]
+	}
+	return string(state)
+}
@@ -0,0 +1,149 @@
+package simnet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bl4ck5un/ChuRP/src/networking/adversary"
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+)
+
+// Scenario is a scripted run of a committee on the simulated network
+type Scenario struct {
+	// Seed of every random choice of the network
+	Seed   int64 `toml:"seed"`
+	Nodes  int   `toml:"nodes"`
+	Degree int   `toml:"degree"`
+	Epochs int64 `toml:"epochs"`
+	// Virtual time an epoch may take before the run counts it as stalled
+	Timeout Duration `toml:"timeout"`
+	Network Faults   `toml:"network"`
+	// Crashes, restarts and partitions, at virtual times from the start of the run
+	Events []Event `toml:"events"`
+	// Behaviors of the Byzantine nodes by node name, see adversary.Parse
+	Byzantine map[string][]string `toml:"byzantine"`
+}
+
+// Faults are what the network does to every message between the nodes and the bulletinboard. The clock is not subject to them.
+type Faults struct {
+	// A message takes Delay plus up to Jitter, drawn uniformly, so that messages overtake each other
+	Delay  Duration `toml:"delay"`
+	Jitter Duration `toml:"jitter"`
+	// Chance that a unary call is lost, half of the time before the server sees it and half of the time its reply. The caller gets Unavailable either way.
+	Drop float64 `toml:"drop"`
+	// Chance that a unary call reaches the server a second time
+	Duplicate float64 `toml:"duplicate"`
+}
+
+// Event is something the scenario does to the committee. Exactly one of its actions is set.
+type Event struct {
+	At Duration `toml:"at"`
+	// Stop a node, the messages it sends from then on are lost
+	Crash string `toml:"crash"`
+	// Start a crashed node again from the shares and the log it stored
+	Restart string `toml:"restart"`
+	// Split the network in groups, messages between groups are held until the partition heals. Endpoints in no group form one more group.
+	Partition [][]string `toml:"partition"`
+	// End the partition and deliver what it held
+	Heal bool `toml:"heal"`
+}
+
+// A time.Duration written like "20ms" in a scenario file
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	var err error
+	d.Duration, err = time.ParseDuration(string(text))
+	return err
+}
+
+// DefaultScenario runs one epoch of four nodes on a network that only delays
+func DefaultScenario() Scenario {
+	return Scenario{
+		Seed:    1,
+		Nodes:   4,
+		Degree:  1,
+		Epochs:  1,
+		Timeout: Duration{time.Minute},
+		Network: Faults{
+			Delay:  Duration{time.Millisecond},
+			Jitter: Duration{4 * time.Millisecond},
+		},
+	}
+}
+
+// ReadScenario reads a TOML scenario file, anything it leaves out keeps its default
+func ReadScenario(path string) (Scenario, error) {
+	scenario := DefaultScenario()
+	if _, err := toml.DecodeFile(path, &scenario); err != nil {
+		return Scenario{}, err
+	}
+	if err := scenario.Check(); err != nil {
+		return Scenario{}, err
+	}
+	return scenario, nil
+}
+
+// Check tells whether the scenario can run
+func (s Scenario) Check() error {
+	if s.Degree < 1 || s.Degree >= s.Nodes {
+		return errors.New(fmt.Sprintf("degree must be between 1 and %d, got %d", s.Nodes-1, s.Degree))
+	}
+	if s.Epochs < 1 {
+		return errors.New(fmt.Sprintf("need at least one epoch, got %d", s.Epochs))
+	}
+	if s.Timeout.Duration <= 0 {
+		return errors.New(fmt.Sprintf("timeout must be positive, got %v", s.Timeout.Duration))
+	}
+	if s.Network.Delay.Duration < 0 || s.Network.Jitter.Duration < 0 {
+		return errors.New("delay and jitter cannot be negative")
+	}
+	if s.Network.Drop < 0 || s.Network.Drop >= 1 || s.Network.Duplicate < 0 || s.Network.Duplicate >= 1 {
+		return errors.New("drop and duplicate are chances below 1")
+	}
+	members := map[string]bool{localnet.BoardAddr: true}
+	for i := 1; i <= s.Nodes; i++ {
+		members[localnet.NodeAddr(i)] = true
+	}
+	for i, event := range s.Events {
+		actions := 0
+		for _, set := range []bool{event.Crash != "", event.Restart != "", event.Partition != nil, event.Heal} {
+			if set {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return errors.New(fmt.Sprintf("event %d must do exactly one thing, it does %d", i+1, actions))
+		}
+		if event.At.Duration < 0 {
+			return errors.New(fmt.Sprintf("event %d is before the start", i+1))
+		}
+		for _, name := range []string{event.Crash, event.Restart} {
+			if name != "" && (!members[name] || name == localnet.BoardAddr) {
+				return errors.New(fmt.Sprintf("event %d: %s is not a node", i+1, name))
+			}
+		}
+		for _, group := range event.Partition {
+			for _, name := range group {
+				if !members[name] {
+					return errors.New(fmt.Sprintf("event %d: %s is not in the committee", i+1, name))
+				}
+			}
+		}
+	}
+	for name, specs := range s.Byzantine {
+		if !members[name] || name == localnet.BoardAddr {
+			return errors.New(fmt.Sprintf("byzantine: %s is not a node", name))
+		}
+		for _, spec := range specs {
+			if _, err := adversary.Parse(spec); err != nil {
+				return errors.New(fmt.Sprintf("byzantine: %s: %v", name, err))
+			}
+		}
+	}
+	return nil
+}
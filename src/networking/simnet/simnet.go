@@ -0,0 +1,587 @@
+package simnet
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/adversary"
+	"github.com/bl4ck5un/ChuRP/src/networking/localnet"
+	"github.com/bl4ck5un/ChuRP/src/networking/transport"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Virtual time between two questions of the clock whether the running epoch has completed
+const pollInterval = 10 * time.Millisecond
+
+// Real time the process must stay idle before the simulator takes the next event
+const quietPeriod = time.Millisecond
+
+// What happens to a unary call
+type fate int
+
+const (
+	delivered fate = iota
+	lostRequest
+	lostReply
+)
+
+func (f fate) String() string {
+	switch f {
+	case lostRequest:
+		return "lost"
+	case lostReply:
+		return "reply lost"
+	default:
+		return "delivered"
+	}
+}
+
+// Simulator runs a committee on a simulated network with a virtual clock.
+// It tells from the goroutines of the whole process whether the committee is busy, so only one simulator runs in a process at a time.
+// Every call between the members becomes an event at a virtual time. The simulator takes one event at a time, in the order of their times, and waits until the process is idle before it takes the next one.
+// The delay and fate of a call are drawn from the seed and the identity of the call, its link, method and number on that link, so a run does not depend on how the goroutines of the process are scheduled and is repeated by running it with the same seed.
+type Simulator struct {
+	scenario  Scenario
+	local     *transport.Local
+	committee *localnet.Committee
+	// Adversaries of the Byzantine nodes by label
+	adversaries map[int]*adversary.Adversary
+
+	mutex sync.Mutex
+	// Virtual time since the simulator was created
+	now   time.Duration
+	queue events
+	// Calls so far on every link and method
+	calls map[string]int
+	// Current incarnation of every member, crashed members have none
+	alive        map[string]string
+	incarnations map[string]int
+	// Servers of every incarnation
+	servers map[string][]transport.Server
+	// Group of every member while the network is partitioned, and the calls held by the partition
+	groups map[string]int
+	held   []*event
+	sleeps int
+	trace  []string
+	closed bool
+	// Signalled when an event is scheduled
+	wake chan struct{}
+	// Counts calls and events, so that the simulator notices a call made while it checks whether the process is idle
+	activity uint64
+}
+
+// Result is the outcome of a run
+type Result struct {
+	// What the simulator did, in order, each with its virtual time
+	Trace []string
+	// Status of every epoch the run started
+	Epochs []*pb.EpochStatusMsg
+	// Virtual time the run took
+	Elapsed time.Duration
+}
+
+// New sets up the committee of the scenario in dir on a simulated network
+func New(scenario Scenario, dir string) (*Simulator, error) {
+	if err := scenario.Check(); err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		scenario:     scenario,
+		calls:        make(map[string]int),
+		alive:        make(map[string]string),
+		incarnations: make(map[string]int),
+		servers:      make(map[string][]transport.Server),
+		wake:         make(chan struct{}, 1),
+		adversaries:  make(map[int]*adversary.Adversary),
+	}
+	for name, specs := range scenario.Byzantine {
+		behaviors := make([]adversary.Behavior, len(specs))
+		for i, spec := range specs {
+			behaviors[i], _ = adversary.Parse(spec)
+		}
+		label := labelOf(name)
+		s.adversaries[label] = adversary.New(label, dir, behaviors...)
+	}
+	s.local = transport.NewIntercepted(s)
+	go s.loop()
+	committee, err := localnet.StartOn(adversary.Network(s, s.adversaries), scenario.Degree, scenario.Nodes, dir)
+	if err != nil {
+		s.close()
+		return nil, err
+	}
+	s.committee = committee
+	return s, nil
+}
+
+// Committee returns the simulated committee, to check the shares once a run is over
+func (s *Simulator) Committee() *localnet.Committee {
+	return s.committee
+}
+
+// Adversary returns the adversary of a Byzantine node of the scenario, nil for an honest node
+func (s *Simulator) Adversary(name string) *adversary.Adversary {
+	return s.adversaries[labelOf(name)]
+}
+
+// Run plays the scenario: it starts its epochs one after the other and applies its events on the way.
+// A failed epoch does not stop the run, the committee rolls back and starts the next epoch, but Run fails once it is over. It stops at an epoch that stalls, the result then holds what happened up to there.
+func (s *Simulator) Run() (Result, error) {
+	s.mutex.Lock()
+	start := s.now
+	for i, ev := range s.scenario.Events {
+		ev := ev
+		s.schedule(ev.At.Duration, fmt.Sprintf("~event %03d", i), func() {
+			s.apply(ev)
+		})
+	}
+	s.mutex.Unlock()
+
+	result := Result{}
+	err := s.run(&result)
+	s.mutex.Lock()
+	result.Trace = append([]string{}, s.trace...)
+	result.Elapsed = s.now - start
+	s.mutex.Unlock()
+	return result, err
+}
+
+func (s *Simulator) run(result *Result) error {
+	clock := s.committee.Clock
+	latest, err := clock.Latest()
+	if err != nil {
+		return err
+	}
+	first := latest.GetEpoch() + 1
+	failed := make([]int64, 0)
+	for epoch := first; epoch < first+s.scenario.Epochs; epoch++ {
+		begin := s.Now()
+		if err := clock.ClientStartEpoch(epoch); err != nil {
+			return err
+		}
+		for {
+			msg, err := clock.ClientEpochStatus(epoch)
+			if err != nil {
+				return err
+			}
+			if msg.GetState() == pb.EpochStatusMsg_FAILED {
+				result.Epochs = append(result.Epochs, msg)
+				s.tracef("epoch %d failed", epoch)
+				failed = append(failed, epoch)
+				break
+			}
+			if msg.GetState() == pb.EpochStatusMsg_COMPLETED {
+				result.Epochs = append(result.Epochs, msg)
+				s.tracef("epoch %d completed", epoch)
+				break
+			}
+			if s.Now()-begin > s.scenario.Timeout.Duration {
+				result.Epochs = append(result.Epochs, msg)
+				return errors.New(fmt.Sprintf("epoch %d stalled for %v", epoch, s.scenario.Timeout.Duration))
+			}
+			s.Sleep(pollInterval)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(fmt.Sprintf("epochs %v failed", failed))
+	}
+	return nil
+}
+
+// Stop stops the simulator. The calls still in flight never return.
+func (s *Simulator) Stop() {
+	s.close()
+	if s.committee != nil {
+		s.committee.Stop()
+	}
+}
+
+func (s *Simulator) close() {
+	s.mutex.Lock()
+	s.closed = true
+	s.mutex.Unlock()
+	s.signal()
+}
+
+// Now returns the virtual time
+func (s *Simulator) Now() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.now
+}
+
+// Sleep waits for d of virtual time
+func (s *Simulator) Sleep(d time.Duration) {
+	done := make(chan struct{})
+	s.mutex.Lock()
+	s.sleeps++
+	s.schedule(d, fmt.Sprintf("~sleep %06d", s.sleeps), func() {
+		close(done)
+	})
+	s.mutex.Unlock()
+	<-done
+}
+
+// Endpoint gives a member a new incarnation on the simulated network
+func (s *Simulator) Endpoint(name string) transport.Transport {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.incarnations[name]++
+	tag := name + "." + strconv.Itoa(s.incarnations[name])
+	s.alive[name] = tag
+	return &endpoint{sim: s, tag: tag, tr: s.local.Endpoint(tag)}
+}
+
+type endpoint struct {
+	sim *Simulator
+	tag string
+	tr  transport.Transport
+}
+
+func (e *endpoint) Listen(addr string) (transport.Server, error) {
+	srv, err := e.tr.Listen(addr)
+	if err != nil {
+		return nil, err
+	}
+	e.sim.mutex.Lock()
+	e.sim.servers[e.tag] = append(e.sim.servers[e.tag], srv)
+	e.sim.mutex.Unlock()
+	return srv, nil
+}
+
+func (e *endpoint) Dial(addr string) (transport.Conn, error) {
+	return e.tr.Dial(addr)
+}
+
+// The label of a node from its name
+func labelOf(name string) int {
+	label, _ := strconv.Atoi(strings.TrimPrefix(name, "node"))
+	return label
+}
+
+// The member of an incarnation
+func nameOf(tag string) string {
+	if i := strings.LastIndex(tag, "."); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+type reply struct {
+	out proto.Message
+	err error
+}
+
+func (s *Simulator) Unary(ctx context.Context, call *transport.Call, deliver func(ctx context.Context) (proto.Message, error)) (proto.Message, error) {
+	atomic.AddUint64(&s.activity, 1)
+	replies := make(chan reply, 1)
+	s.mutex.Lock()
+	if s.closed || !s.isAlive(call.From) {
+		s.mutex.Unlock()
+		return hang(ctx)
+	}
+	key, rng := s.draw(call)
+	faults := s.faults(call)
+	f := delivered
+	if r := rng.Float64(); r < faults.Drop/2 {
+		f = lostRequest
+	} else if r < faults.Drop {
+		f = lostReply
+	}
+	duplicate := f == delivered && rng.Float64() < faults.Duplicate
+	var ev *event
+	ev = s.schedule(delay(rng, faults), key, func() {
+		if s.hold(call, ev) {
+			return
+		}
+		s.tracef("%s %v", key, f)
+		if f == lostRequest {
+			replies <- reply{nil, status.Error(codes.Unavailable, "message lost")}
+			return
+		}
+		go func() {
+			out, err := deliver(ctx)
+			if f == lostReply {
+				out, err = nil, status.Error(codes.Unavailable, "reply lost")
+			}
+			replies <- reply{out, err}
+		}()
+	})
+	if duplicate {
+		var dup *event
+		dup = s.schedule(delay(rng, faults), key+" again", func() {
+			if s.hold(call, dup) {
+				return
+			}
+			s.tracef("%s again", key)
+			go deliver(ctx)
+		})
+	}
+	s.mutex.Unlock()
+
+	var r reply
+	select {
+	case r = <-replies:
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, ctx.Err().Error())
+	}
+	s.mutex.Lock()
+	alive := !s.closed && s.isAlive(call.From)
+	s.mutex.Unlock()
+	if !alive {
+		// a crashed member hears nothing any more
+		return hang(ctx)
+	}
+	return r.out, r.err
+}
+
+func (s *Simulator) Open(ctx context.Context, call *transport.Call) error {
+	atomic.AddUint64(&s.activity, 1)
+	opened := make(chan struct{})
+	s.mutex.Lock()
+	if s.closed || !s.isAlive(call.From) {
+		s.mutex.Unlock()
+		_, err := hang(ctx)
+		return err
+	}
+	key, rng := s.draw(call)
+	var ev *event
+	ev = s.schedule(delay(rng, s.faults(call)), key, func() {
+		if s.hold(call, ev) {
+			return
+		}
+		s.tracef("%s opened", key)
+		close(opened)
+	})
+	s.mutex.Unlock()
+	select {
+	case <-opened:
+		return nil
+	case <-ctx.Done():
+		return status.Error(codes.Canceled, ctx.Err().Error())
+	}
+}
+
+// Block until ctx is done, which is never for the calls of the nodes
+func hang(ctx context.Context) (proto.Message, error) {
+	<-ctx.Done()
+	return nil, status.Error(codes.Canceled, ctx.Err().Error())
+}
+
+// Name a call after its link, method and number on that link, and draw its randomness from that name. The caller holds s.mutex.
+func (s *Simulator) draw(call *transport.Call) (string, *rand.Rand) {
+	link := nameOf(call.From) + ">" + call.To + " " + call.Method
+	n := s.calls[link]
+	s.calls[link]++
+	key := fmt.Sprintf("%s #%d", link, n)
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s", s.scenario.Seed, key)
+	return key, rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// The clock drives the run, its calls take no time and never fail
+func (s *Simulator) faults(call *transport.Call) Faults {
+	if nameOf(call.From) == localnet.ClockName {
+		return Faults{}
+	}
+	return s.scenario.Network
+}
+
+func delay(rng *rand.Rand, faults Faults) time.Duration {
+	return faults.Delay.Duration + time.Duration(rng.Int63n(int64(faults.Jitter.Duration)+1))
+}
+
+// The caller holds s.mutex
+func (s *Simulator) isAlive(tag string) bool {
+	name := nameOf(tag)
+	if name == "" || name == localnet.ClockName {
+		return true
+	}
+	return s.alive[name] == tag
+}
+
+// Keep a call that crosses the partition until it heals
+func (s *Simulator) hold(call *transport.Call, ev *event) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.groups == nil || nameOf(call.From) == localnet.ClockName {
+		return false
+	}
+	from, ok := s.groups[nameOf(call.From)]
+	if !ok {
+		from = -1
+	}
+	to, ok := s.groups[call.To]
+	if !ok {
+		to = -1
+	}
+	if from == to {
+		return false
+	}
+	s.held = append(s.held, ev)
+	s.trace = append(s.trace, fmt.Sprintf("%12v %s held", s.now, ev.key))
+	return true
+}
+
+// Carry out an event of the scenario
+func (s *Simulator) apply(ev Event) {
+	switch {
+	case ev.Crash != "":
+		s.mutex.Lock()
+		tag, ok := s.alive[ev.Crash]
+		delete(s.alive, ev.Crash)
+		servers := s.servers[tag]
+		delete(s.servers, tag)
+		s.mutex.Unlock()
+		if !ok {
+			s.tracef("%s is down already", ev.Crash)
+			return
+		}
+		s.tracef("crash %s", ev.Crash)
+		for _, srv := range servers {
+			srv.Stop()
+		}
+	case ev.Restart != "":
+		s.mutex.Lock()
+		_, up := s.alive[ev.Restart]
+		s.mutex.Unlock()
+		if up {
+			s.tracef("%s is up already", ev.Restart)
+			return
+		}
+		s.tracef("restart %s", ev.Restart)
+		label := labelOf(ev.Restart)
+		go func() {
+			if err := s.committee.Restart(label); err != nil {
+				log.Printf("[simnet] failed to restart %s: %v", ev.Restart, err)
+			}
+		}()
+	case ev.Partition != nil:
+		s.mutex.Lock()
+		s.groups = make(map[string]int)
+		for i, group := range ev.Partition {
+			for _, name := range group {
+				s.groups[name] = i
+			}
+		}
+		s.mutex.Unlock()
+		s.tracef("partition %v", ev.Partition)
+	case ev.Heal:
+		s.mutex.Lock()
+		s.groups = nil
+		held := s.held
+		s.held = nil
+		for _, ev := range held {
+			ev.at = s.now
+			heap.Push(&s.queue, ev)
+		}
+		s.mutex.Unlock()
+		s.tracef("heal, %d calls were held", len(held))
+	}
+}
+
+func (s *Simulator) tracef(format string, args ...interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.trace = append(s.trace, fmt.Sprintf("%12v ", s.now)+fmt.Sprintf(format, args...))
+}
+
+// Schedule fire after d of virtual time. The caller holds s.mutex.
+func (s *Simulator) schedule(d time.Duration, key string, fire func()) *event {
+	ev := &event{at: s.now + d, key: key, fire: fire}
+	heap.Push(&s.queue, ev)
+	atomic.AddUint64(&s.activity, 1)
+	s.signal()
+	return ev
+}
+
+func (s *Simulator) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Take the events in order, each once the process is idle
+func (s *Simulator) loop() {
+	for {
+		s.settle()
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			return
+		}
+		if s.queue.Len() == 0 {
+			s.mutex.Unlock()
+			<-s.wake
+			continue
+		}
+		ev := heap.Pop(&s.queue).(*event)
+		s.now = ev.at
+		s.mutex.Unlock()
+		ev.fire()
+	}
+}
+
+// Wait until the process is idle and no call or event came up for a while, or the simulator is stopped
+func (s *Simulator) settle() {
+	for {
+		s.mutex.Lock()
+		closed := s.closed
+		s.mutex.Unlock()
+		if closed {
+			return
+		}
+		before := atomic.LoadUint64(&s.activity)
+		if idle() {
+			time.Sleep(quietPeriod)
+			if atomic.LoadUint64(&s.activity) == before && idle() {
+				return
+			}
+			continue
+		}
+		time.Sleep(quietPeriod / 10)
+	}
+}
+
+// An event at a virtual time. Events at the same time are taken in the order of their keys.
+type event struct {
+	at   time.Duration
+	key  string
+	fire func()
+}
+
+// A heap of events, the next one first
+type events []*event
+
+func (q events) Len() int { return len(q) }
+
+func (q events) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].key < q[j].key
+}
+
+func (q events) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *events) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *events) Pop() interface{} {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ev
+}
@@ -0,0 +1,221 @@
+package simnet
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bl4ck5un/ChuRP/src/networking/nodes"
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/bl4ck5un/ChuRP/src/utils/sharestore"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func run(t *testing.T, scenario Scenario) (Result, error) {
+	return runWith(t, scenario, func(sim *Simulator, err error) error {
+		if err == nil {
+			err = sim.Committee().Verify()
+		}
+		return err
+	})
+}
+
+// Run a scenario and look at the simulator before its directory goes
+func runWith(t *testing.T, scenario Scenario, check func(sim *Simulator, err error) error) (Result, error) {
+	dir, err := ioutil.TempDir("", "simnet")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sim, err := New(scenario, dir)
+	if !assert.Nil(t, err) {
+		return Result{}, err
+	}
+	result, err := sim.Run()
+	sim.Stop()
+	return result, check(sim, err)
+}
+
+func TestScenarios(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*.toml")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, paths)
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			scenario, err := ReadScenario(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			if testing.Short() && (scenario.Nodes > 4 || len(scenario.Events) > 0) {
+				t.Skip("long scenario")
+			}
+			result, err := run(t, scenario)
+			assert.Nil(t, err)
+			assert.Equal(t, int(scenario.Epochs), len(result.Epochs))
+			for _, msg := range result.Epochs {
+				assert.Equal(t, pb.EpochStatusMsg_COMPLETED, msg.GetState())
+			}
+		})
+	}
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+	scenario := DefaultScenario()
+	scenario.Network.Drop = 0.05
+	scenario.Network.Duplicate = 0.05
+
+	first, err := run(t, scenario)
+	assert.Nil(t, err)
+	second, err := run(t, scenario)
+	assert.Nil(t, err)
+	assert.Equal(t, first.Trace, second.Trace)
+	assert.Equal(t, first.Elapsed, second.Elapsed)
+
+	scenario.Seed++
+	other, err := run(t, scenario)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first.Trace, other.Trace)
+}
+
+func TestCheck(t *testing.T) {
+	assert.Nil(t, DefaultScenario().Check())
+
+	for _, change := range []func(s *Scenario){
+		func(s *Scenario) { s.Degree = s.Nodes },
+		func(s *Scenario) { s.Epochs = 0 },
+		func(s *Scenario) { s.Timeout = Duration{} },
+		func(s *Scenario) { s.Network.Drop = 1 },
+		func(s *Scenario) { s.Network.Jitter = Duration{-time.Millisecond} },
+		func(s *Scenario) { s.Events = []Event{{Crash: "node1", Heal: true}} },
+		func(s *Scenario) { s.Events = []Event{{}} },
+		func(s *Scenario) { s.Events = []Event{{Crash: "node9"}} },
+		func(s *Scenario) { s.Events = []Event{{Restart: "bulletinboard"}} },
+		func(s *Scenario) { s.Events = []Event{{Partition: [][]string{{"node1", "clock"}}}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"bulletinboard": {"silent"}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"node1": {"lying"}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"node1": {"equivocate:node2"}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"node1": {"silent:clock"}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"node1": {"bad-new-shares@0"}} },
+		func(s *Scenario) { s.Byzantine = map[string][]string{"node1": {"bad-new-shares@2-1"}} },
+	} {
+		scenario := DefaultScenario()
+		change(&scenario)
+		assert.NotNil(t, scenario.Check())
+	}
+}
+
+// Each behavior of a Byzantine node2 is caught by the honest nodes, or leaves them unharmed
+func TestByzantine(t *testing.T) {
+	honest := []int{1, 3, 4}
+	for _, c := range []struct {
+		behavior string
+		// State the epoch ends in, RUNNING if it stalls
+		state pb.EpochStatusMsg_State
+		// Fault the honest nodes catch, none if phase is 0. The bulletinboard fails an epoch on the first complaint, so every honest node catches it only when the epoch completes.
+		phase   int32
+		culprit int
+		// Check that caught the fault, as the metrics of the node count it
+		check string
+	}{
+		{"inconsistent-shares", pb.EpochStatusMsg_COMPLETED, 1, 2, "point"},
+		{"bad-zero-shares", pb.EpochStatusMsg_FAILED, 2, 0, "zero_sum"},
+		{"bad-zero-witness", pb.EpochStatusMsg_FAILED, 2, 2, "zero_witness"},
+		{"bad-new-shares", pb.EpochStatusMsg_FAILED, 3, 2, "new_share"},
+		{"bad-commitment", pb.EpochStatusMsg_FAILED, 3, 2, "refresh"},
+		{"equivocate", pb.EpochStatusMsg_COMPLETED, 0, 0, ""},
+		{"silent:node1,bulletinboard", pb.EpochStatusMsg_RUNNING, 0, 0, ""},
+	} {
+		t.Run(c.behavior, func(t *testing.T) {
+			scenario := DefaultScenario()
+			scenario.Timeout = Duration{time.Second}
+			scenario.Byzantine = map[string][]string{"node2": {c.behavior}}
+			result, err := runWith(t, scenario, func(sim *Simulator, err error) error {
+				committee := sim.Committee()
+				caught := 0
+				for _, label := range honest {
+					faults := committee.Nodes[label-1].Faults(1)
+					if c.state == pb.EpochStatusMsg_COMPLETED && c.phase != 0 {
+						assert.NotEmpty(t, faults, "node %d", label)
+					}
+					for _, f := range faults {
+						assert.Equal(t, nodes.Fault{Epoch: 1, Phase: c.phase, Culprit: c.culprit, Reason: f.Reason}, f)
+					}
+					if len(faults) > 0 {
+						var out bytes.Buffer
+						assert.Nil(t, committee.Nodes[label-1].Metrics().WriteText(&out))
+						assert.Contains(t, out.String(), fmt.Sprintf("churp_verification_failures_total{check=%q} %d\n", c.check, len(faults)), "node %d", label)
+					}
+					caught += len(faults)
+				}
+				if c.phase != 0 {
+					assert.NotZero(t, caught)
+				}
+				if c.state == pb.EpochStatusMsg_COMPLETED {
+					return committee.Verify()
+				}
+				// the honest nodes keep no shares of an epoch that did not complete
+				for _, label := range honest {
+					_, err := committee.Shares(label)
+					assert.Equal(t, sharestore.ErrNoState, err, "node %d", label)
+				}
+				return err
+			})
+			if c.state == pb.EpochStatusMsg_COMPLETED {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+			if assert.Equal(t, 1, len(result.Epochs)) {
+				assert.Equal(t, c.state, result.Epochs[0].GetState())
+			}
+		})
+	}
+}
+
+// After a Byzantine node2 fails epoch 1, every node rolls back or leaves it, and epoch 2 hands the shares from before it off
+func TestEpochAfterByzantineFailure(t *testing.T) {
+	scenario := DefaultScenario()
+	scenario.Epochs = 2
+	scenario.Byzantine = map[string][]string{"node2": {"bad-new-shares@1"}}
+	result, err := runWith(t, scenario, func(sim *Simulator, err error) error {
+		committee := sim.Committee()
+		for label := 1; label <= scenario.Nodes; label++ {
+			state, err := committee.Shares(label)
+			if assert.Nil(t, err, "node %d", label) {
+				assert.Equal(t, int64(2), state.Epoch, "node %d", label)
+			}
+		}
+		assert.NotEmpty(t, committee.Nodes[0].Faults(1))
+		assert.Empty(t, committee.Nodes[0].Faults(2))
+		assert.NotNil(t, err)
+		return committee.Verify()
+	})
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(result.Epochs)) {
+		assert.Equal(t, pb.EpochStatusMsg_FAILED, result.Epochs[0].GetState())
+		assert.Equal(t, pb.EpochStatusMsg_COMPLETED, result.Epochs[1].GetState())
+	}
+}
+
+// The bulletinboard keeps the first commitment of an equivocating node and refuses the second
+func TestEquivocationRefused(t *testing.T) {
+	scenario := DefaultScenario()
+	scenario.Byzantine = map[string][]string{"node2": {"equivocate"}}
+	_, err := runWith(t, scenario, func(sim *Simulator, err error) error {
+		refused := sim.Adversary("node2").Refused()
+		// one refusal in phase 2 and one in phase 3
+		if assert.Equal(t, 2, len(refused)) {
+			for _, e := range refused {
+				assert.Equal(t, codes.AlreadyExists, status.Code(e))
+			}
+		}
+		assert.Nil(t, sim.Adversary("node1"))
+		return err
+	})
+	assert.Nil(t, err)
+}
@@ -1,15 +0,0 @@
-package main
-
-import (
-	"../clock"
-	"flag"
-)
-
-func main() {
-	metadataPath := flag.String("path", "/mpss/metadata", "Enter the metadata path")
-	flag.Parse()
-
-	clock, _ := clock.New(*metadataPath)
-	clock.Connect()
-	clock.ClientStartEpoch()
-}
@@ -0,0 +1,463 @@
+package transport
+
+import (
+	"context"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// The NodeService of the server at the other end of a local connection
+type nodeClient struct {
+	conn *localConn
+}
+
+func (c nodeClient) call(ctx context.Context, name string, in proto.Message, method func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error)) (proto.Message, error) {
+	return c.conn.call(ctx, name, in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.node == nil {
+			return nil, unimplemented("services.NodeService")
+		}
+		return method(ctx, s.node, in)
+	})
+}
+
+func (c nodeClient) StartPhase1(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "StartPhase1", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.StartPhase1(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) SharePhase1(ctx context.Context, in *pb.PointMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "SharePhase1", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.SharePhase1(ctx, in.(*pb.PointMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) SharePhase2(ctx context.Context, in *pb.ZeroMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "SharePhase2", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.SharePhase2(ctx, in.(*pb.ZeroMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) StartVerifPhase2(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "StartVerifPhase2", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.StartVerifPhase2(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) SharePhase3(ctx context.Context, in *pb.PointMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "SharePhase3", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.SharePhase3(ctx, in.(*pb.PointMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) StartVerifPhase3(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "StartVerifPhase3", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.StartVerifPhase3(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) Resend(ctx context.Context, in *pb.ResendMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "Resend", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Resend(ctx, in.(*pb.ResendMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c nodeClient) Audit(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AuditMsg, error) {
+	out, err := c.call(ctx, "Audit", in, func(ctx context.Context, srv pb.NodeServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Audit(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AuditMsg), nil
+}
+
+// The BulletinBoardService of the server at the other end of a local connection
+type boardClient struct {
+	conn *localConn
+}
+
+func (c boardClient) call(ctx context.Context, name string, in proto.Message, method func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error)) (proto.Message, error) {
+	return c.conn.call(ctx, name, in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.board == nil {
+			return nil, unimplemented("services.BulletinBoardService")
+		}
+		return method(ctx, s.board, in)
+	})
+}
+
+func (c boardClient) stream(ctx context.Context, name string, in proto.Message, method func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error) (clientStream, error) {
+	return c.conn.stream(ctx, name, in, func(s *localServer, in proto.Message, stream serverStream) error {
+		if s.board == nil {
+			return unimplemented("services.BulletinBoardService")
+		}
+		return method(s.board, in, stream)
+	})
+}
+
+func (c boardClient) StartEpoch(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "StartEpoch", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.StartEpoch(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) ReadPhase1(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_ReadPhase1Client, error) {
+	stream, err := c.stream(ctx, "ReadPhase1", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.ReadPhase1(in.(*pb.EpochMsg), cmt1Server{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmt1Client{stream}, nil
+}
+
+func (c boardClient) WritePhase2(ctx context.Context, in *pb.Cmt2Msg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "WritePhase2", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.WritePhase2(ctx, in.(*pb.Cmt2Msg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) ReadPhase2(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_ReadPhase2Client, error) {
+	stream, err := c.stream(ctx, "ReadPhase2", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.ReadPhase2(in.(*pb.EpochMsg), cmt2Server{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmt2Client{stream}, nil
+}
+
+func (c boardClient) WritePhase3(ctx context.Context, in *pb.Cmt1Msg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "WritePhase3", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.WritePhase3(ctx, in.(*pb.Cmt1Msg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) ReadPhase3(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_ReadPhase3Client, error) {
+	stream, err := c.stream(ctx, "ReadPhase3", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.ReadPhase3(in.(*pb.EpochMsg), cmt1Server{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmt1Client{stream}, nil
+}
+
+func (c boardClient) EpochStatus(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.EpochStatusMsg, error) {
+	out, err := c.call(ctx, "EpochStatus", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.EpochStatus(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.EpochStatusMsg), nil
+}
+
+func (c boardClient) EpochHistory(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_EpochHistoryClient, error) {
+	stream, err := c.stream(ctx, "EpochHistory", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.EpochHistory(in.(*pb.EpochMsg), epochStatusServer{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return epochStatusClient{stream}, nil
+}
+
+func (c boardClient) Audit(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.AuditMsg, error) {
+	out, err := c.call(ctx, "Audit", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Audit(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AuditMsg), nil
+}
+
+func (c boardClient) ReadLog(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_ReadLogClient, error) {
+	stream, err := c.stream(ctx, "ReadLog", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.ReadLog(in.(*pb.EpochMsg), entryServer{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entryClient{stream}, nil
+}
+
+func (c boardClient) ReadCommitments(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (pb.BulletinBoardService_ReadCommitmentsClient, error) {
+	stream, err := c.stream(ctx, "ReadCommitments", in, func(srv pb.BulletinBoardServiceServer, in proto.Message, stream serverStream) error {
+		return srv.ReadCommitments(in.(*pb.EpochMsg), cmt1Server{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmt1Client{stream}, nil
+}
+
+func (c boardClient) Deal(ctx context.Context, in *pb.DealMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "Deal", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Deal(ctx, in.(*pb.DealMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) Delete(ctx context.Context, in *pb.DeleteMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "Delete", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Delete(ctx, in.(*pb.DeleteMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) WriteBeacon(ctx context.Context, in *pb.BeaconShareMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "WriteBeacon", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.WriteBeacon(ctx, in.(*pb.BeaconShareMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c boardClient) ReadBeacon(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.BeaconMsg, error) {
+	out, err := c.call(ctx, "ReadBeacon", in, func(ctx context.Context, srv pb.BulletinBoardServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.ReadBeacon(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.BeaconMsg), nil
+}
+
+// The AdminService of the server at the other end of a local connection
+type adminClient struct {
+	conn *localConn
+}
+
+func (c adminClient) Status(ctx context.Context, in *pb.StatusRequestMsg, opts ...grpc.CallOption) (*pb.StatusMsg, error) {
+	out, err := c.conn.call(ctx, "Status", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.admin == nil {
+			return nil, unimplemented("services.AdminService")
+		}
+		return s.admin.Status(ctx, in.(*pb.StatusRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.StatusMsg), nil
+}
+
+// The SecretService of the server at the other end of a local connection
+type secretClient struct {
+	conn *localConn
+}
+
+func (c secretClient) call(ctx context.Context, name string, in proto.Message, method func(ctx context.Context, srv pb.SecretServiceServer, in proto.Message) (proto.Message, error)) (proto.Message, error) {
+	return c.conn.call(ctx, name, in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.secret == nil {
+			return nil, unimplemented("services.SecretService")
+		}
+		return method(ctx, s.secret, in)
+	})
+}
+
+func (c secretClient) Store(ctx context.Context, in *pb.DealtShareMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "Store", in, func(ctx context.Context, srv pb.SecretServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Store(ctx, in.(*pb.DealtShareMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c secretClient) Retrieve(ctx context.Context, in *pb.ShareRequestMsg, opts ...grpc.CallOption) (*pb.SharesMsg, error) {
+	out, err := c.call(ctx, "Retrieve", in, func(ctx context.Context, srv pb.SecretServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Retrieve(ctx, in.(*pb.ShareRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.SharesMsg), nil
+}
+
+func (c secretClient) Delete(ctx context.Context, in *pb.DeleteMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.call(ctx, "Delete", in, func(ctx context.Context, srv pb.SecretServiceServer, in proto.Message) (proto.Message, error) {
+		return srv.Delete(ctx, in.(*pb.DeleteMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+// The SignService of the server at the other end of a local connection
+type signClient struct {
+	conn *localConn
+}
+
+func (c signClient) SignShare(ctx context.Context, in *pb.SignRequestMsg, opts ...grpc.CallOption) (*pb.PartialSigMsg, error) {
+	out, err := c.conn.call(ctx, "SignShare", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.sign == nil {
+			return nil, unimplemented("services.SignService")
+		}
+		return s.sign.SignShare(ctx, in.(*pb.SignRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.PartialSigMsg), nil
+}
+
+// The DecryptService of the server at the other end of a local connection
+type decryptClient struct {
+	conn *localConn
+}
+
+func (c decryptClient) PublicShare(ctx context.Context, in *pb.EpochMsg, opts ...grpc.CallOption) (*pb.PublicShareMsg, error) {
+	out, err := c.conn.call(ctx, "PublicShare", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.decrypt == nil {
+			return nil, unimplemented("services.DecryptService")
+		}
+		return s.decrypt.PublicShare(ctx, in.(*pb.EpochMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.PublicShareMsg), nil
+}
+
+func (c decryptClient) DecryptShare(ctx context.Context, in *pb.DecryptRequestMsg, opts ...grpc.CallOption) (*pb.PartialDecryptionMsg, error) {
+	out, err := c.conn.call(ctx, "DecryptShare", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.decrypt == nil {
+			return nil, unimplemented("services.DecryptService")
+		}
+		return s.decrypt.DecryptShare(ctx, in.(*pb.DecryptRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.PartialDecryptionMsg), nil
+}
+
+// The SchnorrService of the server at the other end of a local connection
+type schnorrClient struct {
+	conn *localConn
+}
+
+func (c schnorrClient) StoreKey(ctx context.Context, in *pb.DealtKeyMsg, opts ...grpc.CallOption) (*pb.AckMsg, error) {
+	out, err := c.conn.call(ctx, "StoreKey", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.schnorr == nil {
+			return nil, unimplemented("services.SchnorrService")
+		}
+		return s.schnorr.StoreKey(ctx, in.(*pb.DealtKeyMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.AckMsg), nil
+}
+
+func (c schnorrClient) CommitNonce(ctx context.Context, in *pb.NonceRequestMsg, opts ...grpc.CallOption) (*pb.NonceCommitMsg, error) {
+	out, err := c.conn.call(ctx, "CommitNonce", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.schnorr == nil {
+			return nil, unimplemented("services.SchnorrService")
+		}
+		return s.schnorr.CommitNonce(ctx, in.(*pb.NonceRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.NonceCommitMsg), nil
+}
+
+func (c schnorrClient) SignSchnorr(ctx context.Context, in *pb.SchnorrRequestMsg, opts ...grpc.CallOption) (*pb.SchnorrShareMsg, error) {
+	out, err := c.conn.call(ctx, "SignSchnorr", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.schnorr == nil {
+			return nil, unimplemented("services.SchnorrService")
+		}
+		return s.schnorr.SignSchnorr(ctx, in.(*pb.SchnorrRequestMsg))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*pb.SchnorrShareMsg), nil
+}
+
+// The health service of the server at the other end of a local connection
+type healthClient struct {
+	conn *localConn
+}
+
+func (c healthClient) Check(ctx context.Context, in *healthpb.HealthCheckRequest, opts ...grpc.CallOption) (*healthpb.HealthCheckResponse, error) {
+	out, err := c.conn.call(ctx, "Check", in, func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error) {
+		if s.health == nil {
+			return nil, unimplemented("grpc.health.v1.Health")
+		}
+		return s.health.Check(ctx, in.(*healthpb.HealthCheckRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*healthpb.HealthCheckResponse), nil
+}
+
+func (c healthClient) Watch(ctx context.Context, in *healthpb.HealthCheckRequest, opts ...grpc.CallOption) (healthpb.Health_WatchClient, error) {
+	stream, err := c.conn.stream(ctx, "Watch", in, func(s *localServer, in proto.Message, stream serverStream) error {
+		if s.health == nil {
+			return unimplemented("grpc.health.v1.Health")
+		}
+		return s.health.Watch(in.(*healthpb.HealthCheckRequest), healthWatchServer{stream})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return healthWatchClient{stream}, nil
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"net"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// The transport of a deployment, gRPC over TCP
+type grpcTransport struct{}
+
+// GRPC returns the transport that serves and dials gRPC over TCP
+func GRPC() Transport {
+	return grpcTransport{}
+}
+
+func (grpcTransport) Listen(addr string) (Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcServer{lis: lis, server: grpc.NewServer()}, nil
+}
+
+func (grpcTransport) Dial(addr string) (Conn, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBackoffMaxDelay(MaxBackoff))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcConn{
+		conn:    conn,
+		node:    pb.NewNodeServiceClient(conn),
+		board:   pb.NewBulletinBoardServiceClient(conn),
+		admin:   pb.NewAdminServiceClient(conn),
+		secret:  pb.NewSecretServiceClient(conn),
+		sign:    pb.NewSignServiceClient(conn),
+		decrypt: pb.NewDecryptServiceClient(conn),
+		schnorr: pb.NewSchnorrServiceClient(conn),
+		health:  healthpb.NewHealthClient(conn),
+	}, nil
+}
+
+type grpcServer struct {
+	lis    net.Listener
+	server *grpc.Server
+}
+
+func (s *grpcServer) RegisterNode(srv pb.NodeServiceServer) {
+	pb.RegisterNodeServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterBulletinBoard(srv pb.BulletinBoardServiceServer) {
+	pb.RegisterBulletinBoardServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterAdmin(srv pb.AdminServiceServer) {
+	pb.RegisterAdminServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterSecret(srv pb.SecretServiceServer) {
+	pb.RegisterSecretServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterSign(srv pb.SignServiceServer) {
+	pb.RegisterSignServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterDecrypt(srv pb.DecryptServiceServer) {
+	pb.RegisterDecryptServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterSchnorr(srv pb.SchnorrServiceServer) {
+	pb.RegisterSchnorrServiceServer(s.server, srv)
+}
+
+func (s *grpcServer) RegisterHealth(srv healthpb.HealthServer) {
+	healthpb.RegisterHealthServer(s.server, srv)
+}
+
+func (s *grpcServer) Serve() error {
+	reflection.Register(s.server)
+	return s.server.Serve(s.lis)
+}
+
+func (s *grpcServer) Stop() {
+	s.server.Stop()
+}
+
+func (s *grpcServer) GracefulStop() {
+	s.server.GracefulStop()
+}
+
+type grpcConn struct {
+	conn    *grpc.ClientConn
+	node    pb.NodeServiceClient
+	board   pb.BulletinBoardServiceClient
+	admin   pb.AdminServiceClient
+	secret  pb.SecretServiceClient
+	sign    pb.SignServiceClient
+	decrypt pb.DecryptServiceClient
+	schnorr pb.SchnorrServiceClient
+	health  healthpb.HealthClient
+}
+
+func (c *grpcConn) Node() pb.NodeServiceClient {
+	return c.node
+}
+
+func (c *grpcConn) BulletinBoard() pb.BulletinBoardServiceClient {
+	return c.board
+}
+
+func (c *grpcConn) Admin() pb.AdminServiceClient {
+	return c.admin
+}
+
+func (c *grpcConn) Secret() pb.SecretServiceClient {
+	return c.secret
+}
+
+func (c *grpcConn) Sign() pb.SignServiceClient {
+	return c.sign
+}
+
+func (c *grpcConn) Decrypt() pb.DecryptServiceClient {
+	return c.decrypt
+}
+
+func (c *grpcConn) Schnorr() pb.SchnorrServiceClient {
+	return c.schnorr
+}
+
+func (c *grpcConn) Health() healthpb.HealthClient {
+	return c.health
+}
+
+func (c *grpcConn) State() string {
+	return c.conn.GetState().String()
+}
+
+func (c *grpcConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Number of calls in a row that did not reach a peer after which it is down
+const DownAfter = 3
+
+// Health follows the calls made to one peer. The peer is down once DownAfter calls in a row did not reach it, and up again as soon as one does.
+type Health struct {
+	mutex    sync.Mutex
+	failures int
+	lastErr  string
+	lastOK   time.Time
+}
+
+// Unreachable tells whether a call failed without an answer from the peer: it was not serving, or did not answer in time.
+// A peer that refuses a call still answered it.
+func Unreachable(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// Record the outcome of a call, and tell whether the peer went down or came back up with it
+func (h *Health) Record(err error) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	down := h.failures >= DownAfter
+	if Unreachable(err) {
+		h.failures++
+		h.lastErr = err.Error()
+	} else {
+		h.failures = 0
+		h.lastErr = ""
+		h.lastOK = time.Now()
+	}
+	return down != (h.failures >= DownAfter)
+}
+
+// Down tells whether the latest calls did not reach the peer
+func (h *Health) Down() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.failures >= DownAfter
+}
+
+// Report fills in the health of a connection status
+func (h *Health) Report(msg *pb.ConnStatusMsg) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	msg.Failures = int32(h.failures)
+	msg.Down = h.failures >= DownAfter
+	msg.Error = h.lastErr
+	if !h.lastOK.IsZero() {
+		msg.LastOk = h.lastOK.UnixNano()
+	}
+}
@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealth(t *testing.T) {
+	h := &Health{}
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	// a peer that answers, even with an error, is up
+	assert.False(t, h.Record(nil))
+	assert.False(t, h.Record(status.Error(codes.FailedPrecondition, "stale epoch")))
+	assert.False(t, h.Record(errors.New("bad commitment")))
+	assert.False(t, h.Down())
+
+	for i := 1; i < DownAfter; i++ {
+		assert.False(t, h.Record(unavailable))
+	}
+	assert.False(t, h.Down())
+	assert.True(t, h.Record(status.Error(codes.DeadlineExceeded, "deadline exceeded")))
+	assert.True(t, h.Down())
+	assert.False(t, h.Record(unavailable))
+
+	msg := &pb.ConnStatusMsg{}
+	h.Report(msg)
+	assert.Equal(t, int32(DownAfter+1), msg.GetFailures())
+	assert.True(t, msg.GetDown())
+	assert.Contains(t, msg.GetError(), "connection refused")
+	assert.NotZero(t, msg.GetLastOk())
+
+	assert.True(t, h.Record(nil))
+	assert.False(t, h.Down())
+	msg = &pb.ConnStatusMsg{}
+	h.Report(msg)
+	assert.Zero(t, msg.GetFailures())
+	assert.Empty(t, msg.GetError())
+}
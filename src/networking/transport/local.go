@@ -0,0 +1,383 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Local is the transport inside one process. A call runs the method of the server on a goroutine of its own, as gRPC would, and the messages are copied on the way in and out, so that neither side shares memory with the other.
+// Addresses are plain names, they only have to be unique within the Local.
+type Local struct {
+	mutex       sync.Mutex
+	servers     map[string]*localServer
+	interceptor Interceptor
+}
+
+// Call is a call on a local transport as an Interceptor sees it
+type Call struct {
+	// Endpoint that made the call, see Endpoint, and the address it called
+	From   string
+	To     string
+	Method string
+	In     proto.Message
+}
+
+// Interceptor decides how the calls of a local transport travel, a simulated network for instance
+type Interceptor interface {
+	// Unary carries a unary call. deliver hands it to the server and returns the reply, it may be called any number of times.
+	Unary(ctx context.Context, call *Call, deliver func(ctx context.Context) (proto.Message, error)) (proto.Message, error)
+	// Open is called before a server-streaming call reaches the server, the call fails with its error. The messages of the stream are not intercepted.
+	Open(ctx context.Context, call *Call) error
+}
+
+// NewLocal returns a transport with no servers
+func NewLocal() *Local {
+	return NewIntercepted(nil)
+}
+
+// NewIntercepted returns a transport with no servers whose calls all go through interceptor
+func NewIntercepted(interceptor Interceptor) *Local {
+	return &Local{
+		servers:     make(map[string]*localServer),
+		interceptor: interceptor,
+	}
+}
+
+// Endpoint returns the transport as seen by one member, whose calls an Interceptor sees coming from name
+func (l *Local) Endpoint(name string) Transport {
+	return endpoint{local: l, name: name}
+}
+
+type endpoint struct {
+	local *Local
+	name  string
+}
+
+func (e endpoint) Listen(addr string) (Server, error) {
+	return e.local.Listen(addr)
+}
+
+func (e endpoint) Dial(addr string) (Conn, error) {
+	return &localConn{local: e.local, from: e.name, addr: addr}, nil
+}
+
+func (l *Local) Listen(addr string) (Server, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, ok := l.servers[addr]; ok {
+		return nil, errors.New(fmt.Sprintf("address %s is in use", addr))
+	}
+	s := &localServer{
+		local:   l,
+		addr:    addr,
+		serving: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	l.servers[addr] = s
+	return s, nil
+}
+
+func (l *Local) Dial(addr string) (Conn, error) {
+	return l.Endpoint("").Dial(addr)
+}
+
+// Close stops every server of the transport
+func (l *Local) Close() {
+	l.mutex.Lock()
+	servers := make([]*localServer, 0, len(l.servers))
+	for _, s := range l.servers {
+		servers = append(servers, s)
+	}
+	l.mutex.Unlock()
+	for _, s := range servers {
+		s.Stop()
+	}
+}
+
+// The server at addr once it serves
+func (l *Local) lookup(ctx context.Context, addr string) (*localServer, error) {
+	l.mutex.Lock()
+	s, ok := l.servers[addr]
+	l.mutex.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "nothing serves at %s", addr)
+	}
+	// like a listening socket, a server that does not serve yet holds the call back
+	select {
+	case <-s.serving:
+		return s, nil
+	case <-s.stopped:
+		return nil, status.Errorf(codes.Unavailable, "server at %s stopped", addr)
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	}
+}
+
+type localServer struct {
+	local   *Local
+	addr    string
+	node    pb.NodeServiceServer
+	board   pb.BulletinBoardServiceServer
+	admin   pb.AdminServiceServer
+	secret  pb.SecretServiceServer
+	sign    pb.SignServiceServer
+	decrypt pb.DecryptServiceServer
+	schnorr pb.SchnorrServiceServer
+	health  healthpb.HealthServer
+	// Closed when the server starts serving, and when it stops
+	serving  chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// Calls whose method runs, and whether the server refuses new ones as it stops
+	mutex    sync.Mutex
+	calls    sync.WaitGroup
+	draining bool
+}
+
+func (s *localServer) RegisterNode(srv pb.NodeServiceServer) {
+	s.node = srv
+}
+
+func (s *localServer) RegisterBulletinBoard(srv pb.BulletinBoardServiceServer) {
+	s.board = srv
+}
+
+func (s *localServer) RegisterAdmin(srv pb.AdminServiceServer) {
+	s.admin = srv
+}
+
+func (s *localServer) RegisterSecret(srv pb.SecretServiceServer) {
+	s.secret = srv
+}
+
+func (s *localServer) RegisterSign(srv pb.SignServiceServer) {
+	s.sign = srv
+}
+
+func (s *localServer) RegisterDecrypt(srv pb.DecryptServiceServer) {
+	s.decrypt = srv
+}
+
+func (s *localServer) RegisterSchnorr(srv pb.SchnorrServiceServer) {
+	s.schnorr = srv
+}
+
+func (s *localServer) RegisterHealth(srv healthpb.HealthServer) {
+	s.health = srv
+}
+
+func (s *localServer) Serve() error {
+	close(s.serving)
+	<-s.stopped
+	return nil
+}
+
+func (s *localServer) Stop() {
+	s.stopOnce.Do(func() {
+		s.local.mutex.Lock()
+		if s.local.servers[s.addr] == s {
+			delete(s.local.servers, s.addr)
+		}
+		s.local.mutex.Unlock()
+		close(s.stopped)
+	})
+}
+
+func (s *localServer) GracefulStop() {
+	s.mutex.Lock()
+	s.draining = true
+	s.mutex.Unlock()
+	s.calls.Wait()
+	s.Stop()
+}
+
+// Count a call whose method is about to run, false once the server is stopping
+func (s *localServer) enter() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.draining {
+		return false
+	}
+	s.calls.Add(1)
+	return true
+}
+
+type localConn struct {
+	local *Local
+	from  string
+	addr  string
+
+	mutex  sync.Mutex
+	closed bool
+}
+
+func (c *localConn) Node() pb.NodeServiceClient {
+	return nodeClient{c}
+}
+
+func (c *localConn) BulletinBoard() pb.BulletinBoardServiceClient {
+	return boardClient{c}
+}
+
+func (c *localConn) Admin() pb.AdminServiceClient {
+	return adminClient{c}
+}
+
+func (c *localConn) Secret() pb.SecretServiceClient {
+	return secretClient{c}
+}
+
+func (c *localConn) Sign() pb.SignServiceClient {
+	return signClient{c}
+}
+
+func (c *localConn) Decrypt() pb.DecryptServiceClient {
+	return decryptClient{c}
+}
+
+func (c *localConn) Schnorr() pb.SchnorrServiceClient {
+	return schnorrClient{c}
+}
+
+func (c *localConn) Health() healthpb.HealthClient {
+	return healthClient{c}
+}
+
+// A local connection is ready while its server serves, it connects while the server is listening but not serving yet
+func (c *localConn) State() string {
+	c.mutex.Lock()
+	closed := c.closed
+	c.mutex.Unlock()
+	if closed {
+		return "SHUTDOWN"
+	}
+	c.local.mutex.Lock()
+	s, ok := c.local.servers[c.addr]
+	c.local.mutex.Unlock()
+	if !ok {
+		return "TRANSIENT_FAILURE"
+	}
+	select {
+	case <-s.serving:
+		return "READY"
+	default:
+		return "CONNECTING"
+	}
+}
+
+func (c *localConn) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *localConn) server(ctx context.Context) (*localServer, error) {
+	c.mutex.Lock()
+	closed := c.closed
+	c.mutex.Unlock()
+	if closed {
+		return nil, status.Error(codes.Canceled, "the connection is closed")
+	}
+	return c.local.lookup(ctx, c.addr)
+}
+
+// Run a unary method on the server and wait for its reply
+func (c *localConn) call(ctx context.Context, name string, in proto.Message, method func(ctx context.Context, s *localServer, in proto.Message) (proto.Message, error)) (proto.Message, error) {
+	deliver := func(ctx context.Context) (proto.Message, error) {
+		s, err := c.server(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !s.enter() {
+			return nil, status.Errorf(codes.Unavailable, "server at %s is stopping", c.addr)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		type result struct {
+			out proto.Message
+			err error
+		}
+		done := make(chan result, 1)
+		in := proto.Clone(in)
+		go func() {
+			defer s.calls.Done()
+			out, err := method(ctx, s, in)
+			done <- result{out, err}
+		}()
+		select {
+		case r := <-done:
+			if r.err != nil {
+				return nil, statusError(r.err)
+			}
+			return proto.Clone(r.out), nil
+		case <-s.stopped:
+			return nil, status.Errorf(codes.Unavailable, "server at %s stopped", c.addr)
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		}
+	}
+	if c.local.interceptor == nil {
+		return deliver(ctx)
+	}
+	return c.local.interceptor.Unary(ctx, &Call{From: c.from, To: c.addr, Method: name, In: in}, deliver)
+}
+
+// Run a server-streaming method on the server, the stream ends when the method returns
+func (c *localConn) stream(ctx context.Context, name string, in proto.Message, method func(s *localServer, in proto.Message, stream serverStream) error) (clientStream, error) {
+	if c.local.interceptor != nil {
+		if err := c.local.interceptor.Open(ctx, &Call{From: c.from, To: c.addr, Method: name, In: in}); err != nil {
+			return clientStream{}, err
+		}
+	}
+	s, err := c.server(ctx)
+	if err != nil {
+		return clientStream{}, err
+	}
+	if !s.enter() {
+		return clientStream{}, status.Errorf(codes.Unavailable, "server at %s is stopping", c.addr)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &pipe{
+		ctx:     ctx,
+		cancel:  cancel,
+		msgs:    make(chan proto.Message),
+		done:    make(chan struct{}),
+		stopped: s.stopped,
+	}
+	in = proto.Clone(in)
+	go func() {
+		defer s.calls.Done()
+		p.err = method(s, in, serverStream{p})
+		close(p.done)
+	}()
+	return clientStream{p}, nil
+}
+
+// A method the server does not have, like calling a service that is not registered
+func unimplemented(service string) error {
+	return status.Errorf(codes.Unimplemented, "unknown service %s", service)
+}
+
+// gRPC hands the client a status whatever the server returns
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// A bulletinboard that acks every start and streams its history. If release is set, a start waits for it.
+type testBoard struct {
+	pb.BulletinBoardServiceServer
+	history []*pb.EpochStatusMsg
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *testBoard) StartEpoch(ctx context.Context, in *pb.EpochMsg) (*pb.AckMsg, error) {
+	if in.GetEpoch() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative epoch")
+	}
+	if b.release != nil {
+		b.entered <- struct{}{}
+		<-b.release
+	}
+	in.Committee = "changed by the server"
+	return &pb.AckMsg{Epoch: in.GetEpoch()}, nil
+}
+
+func (b *testBoard) EpochHistory(in *pb.EpochMsg, stream pb.BulletinBoardService_EpochHistoryServer) error {
+	for _, msg := range b.history {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serve(t *testing.T, l *Local, addr string, board pb.BulletinBoardServiceServer) Server {
+	s, err := l.Listen(addr)
+	assert.Nil(t, err)
+	s.RegisterBulletinBoard(board)
+	go s.Serve()
+	return s
+}
+
+func TestLocalUnary(t *testing.T) {
+	l := NewLocal()
+	defer l.Close()
+	serve(t, l, "bulletinboard", &testBoard{})
+	conn, err := l.Dial("bulletinboard")
+	assert.Nil(t, err)
+
+	in := &pb.EpochMsg{Epoch: 3, Committee: "c"}
+	ack, err := conn.BulletinBoard().StartEpoch(context.Background(), in)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), ack.GetEpoch())
+	// the server works on a copy
+	assert.Equal(t, "c", in.GetCommittee())
+
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{Epoch: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = conn.Node().StartPhase1(context.Background(), &pb.EpochMsg{Epoch: 1})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestLocalStream(t *testing.T) {
+	l := NewLocal()
+	defer l.Close()
+	board := &testBoard{history: []*pb.EpochStatusMsg{{Epoch: 0}, {Epoch: 1}, {Epoch: 2}}}
+	serve(t, l, "bulletinboard", board)
+	conn, _ := l.Dial("bulletinboard")
+
+	stream, err := conn.BulletinBoard().EpochHistory(context.Background(), &pb.EpochMsg{})
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		msg, err := stream.Recv()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(i), msg.GetEpoch())
+	}
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err = conn.BulletinBoard().EpochHistory(ctx, &pb.EpochMsg{})
+	assert.Nil(t, err)
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestLocalUnavailable(t *testing.T) {
+	l := NewLocal()
+	defer l.Close()
+	conn, err := l.Dial("bulletinboard")
+	assert.Nil(t, err)
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	s := serve(t, l, "bulletinboard", &testBoard{})
+	_, err = l.Listen("bulletinboard")
+	assert.NotNil(t, err)
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{})
+	assert.Nil(t, err)
+
+	s.Stop()
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	// the address is free again
+	serve(t, l, "bulletinboard", &testBoard{})
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{})
+	assert.Nil(t, err)
+}
+
+func TestLocalGracefulStop(t *testing.T) {
+	l := NewLocal()
+	defer l.Close()
+	board := &testBoard{entered: make(chan struct{}), release: make(chan struct{})}
+	s := serve(t, l, "bulletinboard", board)
+	conn, _ := l.Dial("bulletinboard")
+
+	inFlight := make(chan error, 1)
+	go func() {
+		_, err := conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{Epoch: 1})
+		inFlight <- err
+	}()
+	<-board.entered
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	// new calls are refused while the one in flight runs on
+	var err error
+	for i := 0; i < 100; i++ {
+		_, err = conn.BulletinBoard().EpochHistory(context.Background(), &pb.EpochMsg{})
+		if err != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	select {
+	case <-stopped:
+		t.Fatal("stopped before the call in flight returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(board.release)
+	assert.Nil(t, <-inFlight)
+	<-stopped
+	_, err = conn.BulletinBoard().StartEpoch(context.Background(), &pb.EpochMsg{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestLocalHealth(t *testing.T) {
+	l := NewLocal()
+	defer l.Close()
+	s := serve(t, l, "bulletinboard", &testBoard{})
+	conn, _ := l.Dial("bulletinboard")
+
+	_, err := conn.Health().Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	srv := health.NewServer()
+	srv.SetServingStatus("ready", healthpb.HealthCheckResponse_NOT_SERVING)
+	s.RegisterHealth(srv)
+	resp, err := conn.Health().Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	resp, err = conn.Health().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "ready"})
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := conn.Health().Watch(ctx, &healthpb.HealthCheckRequest{Service: "ready"})
+	assert.Nil(t, err)
+	resp, err = stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	srv.SetServingStatus("ready", healthpb.HealthCheckResponse_SERVING)
+	resp, err = stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Carries the messages of a server-streaming call from the method to the client. A message is handed over only when the client receives it, so all of them are through once the method returns.
+type pipe struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	msgs   chan proto.Message
+	// Closed when the method returns, err is what it returned
+	done    chan struct{}
+	err     error
+	stopped <-chan struct{}
+}
+
+func (p *pipe) send(msg proto.Message) error {
+	select {
+	case p.msgs <- proto.Clone(msg):
+		return nil
+	case <-p.ctx.Done():
+		return contextError(p.ctx.Err())
+	}
+}
+
+func (p *pipe) recv() (proto.Message, error) {
+	select {
+	case msg := <-p.msgs:
+		return msg, nil
+	case <-p.done:
+		p.cancel()
+		if p.err != nil {
+			return nil, statusError(p.err)
+		}
+		return nil, io.EOF
+	case <-p.stopped:
+		p.cancel()
+		return nil, status.Error(codes.Unavailable, "server stopped")
+	case <-p.ctx.Done():
+		return nil, contextError(p.ctx.Err())
+	}
+}
+
+// The server side of a pipe, a grpc.ServerStream
+type serverStream struct {
+	*pipe
+}
+
+func (s serverStream) SetHeader(metadata.MD) error  { return nil }
+func (s serverStream) SendHeader(metadata.MD) error { return nil }
+func (s serverStream) SetTrailer(metadata.MD)       {}
+func (s serverStream) Context() context.Context     { return s.ctx }
+
+func (s serverStream) SendMsg(m interface{}) error {
+	return s.send(m.(proto.Message))
+}
+
+func (s serverStream) RecvMsg(m interface{}) error {
+	return errors.New("server-streaming call has no more client messages")
+}
+
+// The client side of a pipe, a grpc.ClientStream
+type clientStream struct {
+	*pipe
+}
+
+func (c clientStream) Header() (metadata.MD, error) { return nil, nil }
+func (c clientStream) Trailer() metadata.MD         { return nil }
+func (c clientStream) CloseSend() error             { return nil }
+func (c clientStream) Context() context.Context     { return c.ctx }
+
+func (c clientStream) SendMsg(m interface{}) error {
+	return errors.New("server-streaming call takes no more client messages")
+}
+
+func (c clientStream) RecvMsg(m interface{}) error {
+	msg, err := c.recv()
+	if err != nil {
+		return err
+	}
+	dst := m.(proto.Message)
+	dst.Reset()
+	proto.Merge(dst, msg)
+	return nil
+}
+
+// Typed ends of the streams of the bulletinboard service. One type serves every method that streams the same message.
+
+type cmt1Server struct{ serverStream }
+
+func (s cmt1Server) Send(msg *pb.Cmt1Msg) error { return s.send(msg) }
+
+type cmt1Client struct{ clientStream }
+
+func (c cmt1Client) Recv() (*pb.Cmt1Msg, error) {
+	msg, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.Cmt1Msg), nil
+}
+
+type cmt2Server struct{ serverStream }
+
+func (s cmt2Server) Send(msg *pb.Cmt2Msg) error { return s.send(msg) }
+
+type cmt2Client struct{ clientStream }
+
+func (c cmt2Client) Recv() (*pb.Cmt2Msg, error) {
+	msg, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.Cmt2Msg), nil
+}
+
+type epochStatusServer struct{ serverStream }
+
+func (s epochStatusServer) Send(msg *pb.EpochStatusMsg) error { return s.send(msg) }
+
+type epochStatusClient struct{ clientStream }
+
+func (c epochStatusClient) Recv() (*pb.EpochStatusMsg, error) {
+	msg, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.EpochStatusMsg), nil
+}
+
+type entryServer struct{ serverStream }
+
+func (s entryServer) Send(msg *pb.EntryMsg) error { return s.send(msg) }
+
+type entryClient struct{ clientStream }
+
+func (c entryClient) Recv() (*pb.EntryMsg, error) {
+	msg, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.EntryMsg), nil
+}
+
+// Typed ends of the watch stream of the health service
+
+type healthWatchServer struct{ serverStream }
+
+func (s healthWatchServer) Send(msg *healthpb.HealthCheckResponse) error { return s.send(msg) }
+
+type healthWatchClient struct{ clientStream }
+
+func (c healthWatchClient) Recv() (*healthpb.HealthCheckResponse, error) {
+	msg, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*healthpb.HealthCheckResponse), nil
+}
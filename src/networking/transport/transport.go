@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"time"
+
+	pb "github.com/bl4ck5un/ChuRP/src/services"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Longest wait between two attempts of a connection to reach its server again
+const MaxBackoff = 5 * time.Second
+
+// Transport carries the calls between the nodes, the bulletinboard and their clients.
+// The protocol only sees the service clients and servers, GRPC runs them over TCP and Local inside one process.
+type Transport interface {
+	// Listen returns a server at addr, calls reach it once it serves
+	Listen(addr string) (Server, error)
+	// Dial returns a connection to the server at addr without waiting for it. A call fails with Unavailable while nothing serves at addr.
+	// The connection reaches a server that comes back at addr by itself, trying again with a backoff of at most MaxBackoff.
+	Dial(addr string) (Conn, error)
+}
+
+// Server serves the services registered on it
+type Server interface {
+	RegisterNode(srv pb.NodeServiceServer)
+	RegisterBulletinBoard(srv pb.BulletinBoardServiceServer)
+	RegisterAdmin(srv pb.AdminServiceServer)
+	RegisterSecret(srv pb.SecretServiceServer)
+	RegisterSign(srv pb.SignServiceServer)
+	RegisterDecrypt(srv pb.DecryptServiceServer)
+	RegisterSchnorr(srv pb.SchnorrServiceServer)
+	RegisterHealth(srv healthpb.HealthServer)
+	// Serve blocks until the server stops
+	Serve() error
+	Stop()
+	// GracefulStop refuses new calls, waits for the calls in flight to return and stops
+	GracefulStop()
+}
+
+// State of a connection that was never dialed
+const NotDialed = "NONE"
+
+// Conn is a connection to a server
+type Conn interface {
+	Node() pb.NodeServiceClient
+	BulletinBoard() pb.BulletinBoardServiceClient
+	Admin() pb.AdminServiceClient
+	Secret() pb.SecretServiceClient
+	Sign() pb.SignServiceClient
+	Decrypt() pb.DecryptServiceClient
+	Schnorr() pb.SchnorrServiceClient
+	Health() healthpb.HealthClient
+	// State tells how the connection is doing, as a gRPC connectivity state such as READY or TRANSIENT_FAILURE
+	State() string
+	Close() error
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Interval and number of attempts when the receiver is not in the epoch yet, or is recovering
+	RetryInterval = 50 * time.Millisecond
+	RetryLimit    = 200
+	// Deadline of a call between the services, its retries included. A call that moves a node on runs the phases that follow before it returns, so the deadline bounds a peer that hangs rather than a slow epoch.
+	CallTimeout = 2 * time.Minute
+)
+
+// EpochScoped is a message tagged with the epoch and committee it belongs to
+type EpochScoped interface {
+	GetEpoch() int64
+	GetCommittee() string
+}
+
+// BeaconSecret is the ID of the secret the beacon is keyed on. The operator deals it like any other, and the epoch that first hands it off after a dealing adds a random value of every node to it, so no one knows it.
+const BeaconSecret = "beacon"
+
+// SecretName names the secret with the given ID in logs and errors. The empty ID is the secret the committee started with.
+func SecretName(id string) string {
+	if id == "" {
+		return "the default secret"
+	}
+	return fmt.Sprintf("secret %q", id)
+}
+
+// HeldSecrets returns the IDs of the secrets the committee holds at the end of the epoch.
+// Epochs recorded before the committee could hold more than one secret list none, they hold the default one.
+func (m *EpochStatusMsg) HeldSecrets() []string {
+	if len(m.GetSecrets()) == 0 {
+		return []string{""}
+	}
+	return m.GetSecrets()
+}
+
+// CheckEpoch accepts msg only if it belongs to the given epoch of the given committee.
+// Messages from an earlier epoch fail with FailedPrecondition and must not be retried.
+// Messages from a later epoch fail with Unavailable so the sender can retry once the receiver has caught up.
+func CheckEpoch(msg EpochScoped, epoch int64, committee string) error {
+	if msg.GetCommittee() != committee {
+		return status.Errorf(codes.PermissionDenied, "committee %q is not the current committee %q", msg.GetCommittee(), committee)
+	}
+	if msg.GetEpoch() < epoch {
+		return status.Errorf(codes.FailedPrecondition, "stale epoch %d, current epoch is %d", msg.GetEpoch(), epoch)
+	}
+	if msg.GetEpoch() > epoch {
+		return status.Errorf(codes.Unavailable, "future epoch %d, current epoch is %d", msg.GetEpoch(), epoch)
+	}
+	return nil
+}
+
+// Retry repeats call while the receiver answers Unavailable, which it does for a future epoch or while it recovers from a crash
+func Retry(call func() error) error {
+	var err error
+	for i := 0; i < RetryLimit; i++ {
+		err = call()
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		time.Sleep(RetryInterval)
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Services the nodes and the bulletinboard report on through the standard gRPC health check.
+// A server is live while it serves calls, the empty service name asks the same. It is ready while it can take part in an epoch.
+const (
+	HealthLive  = "liveness"
+	HealthReady = "readiness"
+)
+
+// CheckHealth answers a health check of a server that is live, and ready unless notReady says why it is not
+func CheckHealth(in *healthpb.HealthCheckRequest, notReady string) (*healthpb.HealthCheckResponse, error) {
+	switch in.GetService() {
+	case "", HealthLive:
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	case HealthReady:
+		if notReady != "" {
+			return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+		}
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "unknown service %q", in.GetService())
+}
+
+// The servers answer checks only, watching a service is left to the caller polling it
+var ErrWatchHealth = status.Error(codes.Unimplemented, "watching the health is not supported, check it instead")
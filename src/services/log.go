@@ -0,0 +1,66 @@
+package services
+
+import (
+	"github.com/bl4ck5un/ChuRP/src/utils/merkle"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Committed is a commitment served by the bulletinboard together with the proof that it is an entry of the log
+type Committed interface {
+	GetIndex() int32
+	GetEpoch() int64
+	GetSecret() string
+	GetInclusion() *InclusionMsg
+	// EntryData returns the encoding the bulletinboard keeps as the data of the entry
+	EntryData() []byte
+}
+
+func (m *Cmt1Msg) EntryData() []byte {
+	c := *m
+	c.Inclusion = nil
+	return marshal(&c)
+}
+
+func (m *Cmt2Msg) EntryData() []byte {
+	c := *m
+	c.Inclusion = nil
+	return marshal(&c)
+}
+
+// EntryHash returns the hash that chains entry to the one before it, whose hash is entry.Prev
+func EntryHash(entry *EntryMsg) []byte {
+	c := *entry
+	c.Prev = nil
+	c.Hash = nil
+	return merkle.Link(entry.GetPrev(), marshal(&c))
+}
+
+// LogEntry rebuilds the entry of the log that msg was read from in the given phase
+func LogEntry(msg Committed, phase int32) *EntryMsg {
+	entry := &EntryMsg{
+		Epoch:  msg.GetEpoch(),
+		Phase:  phase,
+		Index:  msg.GetIndex(),
+		Data:   msg.EntryData(),
+		Seq:    msg.GetInclusion().GetSeq(),
+		Prev:   msg.GetInclusion().GetPrev(),
+		Secret: msg.GetSecret(),
+	}
+	entry.Hash = EntryHash(entry)
+	return entry
+}
+
+// VerifyInclusion checks that msg was read from the entry of its node in the given phase of the log
+func VerifyInclusion(msg Committed, phase int32) error {
+	inclusion := msg.GetInclusion()
+	if inclusion == nil {
+		return status.Errorf(codes.DataLoss, "commitment of node %d comes without an inclusion proof", msg.GetIndex())
+	}
+	entry := LogEntry(msg, phase)
+	err := merkle.Verify(inclusion.GetRoot(), entry.GetHash(), int(inclusion.GetLeaf()), int(inclusion.GetSize()), inclusion.GetPath())
+	if err != nil {
+		return status.Errorf(codes.DataLoss, "inclusion proof of the commitment of node %d: %v", msg.GetIndex(), err)
+	}
+	return nil
+}
@@ -22,38 +22,94 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
-type EmptyMsg struct {
+type EpochStatusMsg_State int32
+
+const (
+	EpochStatusMsg_RUNNING   EpochStatusMsg_State = 0
+	EpochStatusMsg_COMPLETED EpochStatusMsg_State = 1
+	EpochStatusMsg_FAILED    EpochStatusMsg_State = 2
+)
+
+var EpochStatusMsg_State_name = map[int32]string{
+	0: "RUNNING",
+	1: "COMPLETED",
+	2: "FAILED",
+}
+
+var EpochStatusMsg_State_value = map[string]int32{
+	"RUNNING":   0,
+	"COMPLETED": 1,
+	"FAILED":    2,
+}
+
+func (x EpochStatusMsg_State) String() string {
+	return proto.EnumName(EpochStatusMsg_State_name, int32(x))
+}
+
+func (EpochStatusMsg_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{2, 0}
+}
+
+// Every message names the epoch and the committee it belongs to.
+// The committee holds many secrets, each under an ID, and messages about one of them name it. The empty ID is the secret the committee started with.
+type EpochMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *EmptyMsg) Reset()         { *m = EmptyMsg{} }
-func (m *EmptyMsg) String() string { return proto.CompactTextString(m) }
-func (*EmptyMsg) ProtoMessage()    {}
-func (*EmptyMsg) Descriptor() ([]byte, []int) {
+func (m *EpochMsg) Reset()         { *m = EpochMsg{} }
+func (m *EpochMsg) String() string { return proto.CompactTextString(m) }
+func (*EpochMsg) ProtoMessage()    {}
+func (*EpochMsg) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8e16ccb8c5307b32, []int{0}
 }
 
-func (m *EmptyMsg) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_EmptyMsg.Unmarshal(m, b)
+func (m *EpochMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochMsg.Unmarshal(m, b)
+}
+func (m *EpochMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochMsg.Marshal(b, m, deterministic)
 }
-func (m *EmptyMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_EmptyMsg.Marshal(b, m, deterministic)
+func (m *EpochMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochMsg.Merge(m, src)
 }
-func (m *EmptyMsg) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EmptyMsg.Merge(m, src)
+func (m *EpochMsg) XXX_Size() int {
+	return xxx_messageInfo_EpochMsg.Size(m)
 }
-func (m *EmptyMsg) XXX_Size() int {
-	return xxx_messageInfo_EmptyMsg.Size(m)
+func (m *EpochMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochMsg proto.InternalMessageInfo
+
+func (m *EpochMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
 }
-func (m *EmptyMsg) XXX_DiscardUnknown() {
-	xxx_messageInfo_EmptyMsg.DiscardUnknown(m)
+
+func (m *EpochMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
 }
 
-var xxx_messageInfo_EmptyMsg proto.InternalMessageInfo
+func (m *EpochMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
 
 type AckMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -84,9 +140,202 @@ func (m *AckMsg) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AckMsg proto.InternalMessageInfo
 
-type Cmt1Msg struct {
+func (m *AckMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *AckMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+// Progress of an epoch as recorded by the bulletinboard, times in unix nanoseconds
+type EpochStatusMsg struct {
+	Epoch     int64                `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee string               `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	State     EpochStatusMsg_State `protobuf:"varint,3,opt,name=state,proto3,enum=services.EpochStatusMsg_State" json:"state,omitempty"`
+	Start     int64                `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+	End       int64                `protobuf:"varint,5,opt,name=end,proto3" json:"end,omitempty"`
+	// Set for an epoch that ran no phases, in which the operator dealt a new secret
+	Dealt bool `protobuf:"varint,6,opt,name=dealt,proto3" json:"dealt,omitempty"`
+	// Set for an epoch that ran no phases, in which the operator deleted a secret
+	Deleted bool `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// The secret the operator dealt or deleted
+	Secret string `protobuf:"bytes,8,opt,name=secret,proto3" json:"secret,omitempty"`
+	// IDs of the secrets the committee holds at the end of the epoch, in order. An epoch with phases hands off every one of them.
+	Secrets              []string `protobuf:"bytes,9,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EpochStatusMsg) Reset()         { *m = EpochStatusMsg{} }
+func (m *EpochStatusMsg) String() string { return proto.CompactTextString(m) }
+func (*EpochStatusMsg) ProtoMessage()    {}
+func (*EpochStatusMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{2}
+}
+
+func (m *EpochStatusMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochStatusMsg.Unmarshal(m, b)
+}
+func (m *EpochStatusMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochStatusMsg.Marshal(b, m, deterministic)
+}
+func (m *EpochStatusMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochStatusMsg.Merge(m, src)
+}
+func (m *EpochStatusMsg) XXX_Size() int {
+	return xxx_messageInfo_EpochStatusMsg.Size(m)
+}
+func (m *EpochStatusMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochStatusMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochStatusMsg proto.InternalMessageInfo
+
+func (m *EpochStatusMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *EpochStatusMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *EpochStatusMsg) GetState() EpochStatusMsg_State {
+	if m != nil {
+		return m.State
+	}
+	return EpochStatusMsg_RUNNING
+}
+
+func (m *EpochStatusMsg) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *EpochStatusMsg) GetEnd() int64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+func (m *EpochStatusMsg) GetDealt() bool {
+	if m != nil {
+		return m.Dealt
+	}
+	return false
+}
+
+func (m *EpochStatusMsg) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *EpochStatusMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EpochStatusMsg) GetSecrets() []string {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+// Asks the receiver to send its message of the given phase to node index again
+type ResendMsg struct {
 	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Polycmt              []byte   `protobuf:"bytes,2,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
+	Phase                int32    `protobuf:"varint,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Epoch                int64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,4,opt,name=committee,proto3" json:"committee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResendMsg) Reset()         { *m = ResendMsg{} }
+func (m *ResendMsg) String() string { return proto.CompactTextString(m) }
+func (*ResendMsg) ProtoMessage()    {}
+func (*ResendMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{3}
+}
+
+func (m *ResendMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResendMsg.Unmarshal(m, b)
+}
+func (m *ResendMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResendMsg.Marshal(b, m, deterministic)
+}
+func (m *ResendMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResendMsg.Merge(m, src)
+}
+func (m *ResendMsg) XXX_Size() int {
+	return xxx_messageInfo_ResendMsg.Size(m)
+}
+func (m *ResendMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResendMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResendMsg proto.InternalMessageInfo
+
+func (m *ResendMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ResendMsg) GetPhase() int32 {
+	if m != nil {
+		return m.Phase
+	}
+	return 0
+}
+
+func (m *ResendMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ResendMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+type Cmt1Msg struct {
+	Index     int32         `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Polycmt   []byte        `protobuf:"bytes,2,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
+	Signature []byte        `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch     int64         `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee string        `protobuf:"bytes,5,opt,name=committee,proto3" json:"committee,omitempty"`
+	Inclusion *InclusionMsg `protobuf:"bytes,6,opt,name=inclusion,proto3" json:"inclusion,omitempty"`
+	Secret    string        `protobuf:"bytes,7,opt,name=secret,proto3" json:"secret,omitempty"`
+	// For the default secret in phase 3, the Feldman commitment to the P-521 key the node refreshed, empty if it holds none
+	Keycmt               [][]byte `protobuf:"bytes,8,rep,name=keycmt,proto3" json:"keycmt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -96,7 +345,7 @@ func (m *Cmt1Msg) Reset()         { *m = Cmt1Msg{} }
 func (m *Cmt1Msg) String() string { return proto.CompactTextString(m) }
 func (*Cmt1Msg) ProtoMessage()    {}
 func (*Cmt1Msg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e16ccb8c5307b32, []int{2}
+	return fileDescriptor_8e16ccb8c5307b32, []int{4}
 }
 
 func (m *Cmt1Msg) XXX_Unmarshal(b []byte) error {
@@ -131,11 +380,61 @@ func (m *Cmt1Msg) GetPolycmt() []byte {
 	return nil
 }
 
+func (m *Cmt1Msg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Cmt1Msg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Cmt1Msg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *Cmt1Msg) GetInclusion() *InclusionMsg {
+	if m != nil {
+		return m.Inclusion
+	}
+	return nil
+}
+
+func (m *Cmt1Msg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Cmt1Msg) GetKeycmt() [][]byte {
+	if m != nil {
+		return m.Keycmt
+	}
+	return nil
+}
+
 type Cmt2Msg struct {
-	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Sharecmt             []byte   `protobuf:"bytes,2,opt,name=sharecmt,proto3" json:"sharecmt,omitempty"`
-	Polycmt              []byte   `protobuf:"bytes,3,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
-	Zerowitness          []byte   `protobuf:"bytes,4,opt,name=zerowitness,proto3" json:"zerowitness,omitempty"`
+	Index       int32         `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Sharecmt    []byte        `protobuf:"bytes,2,opt,name=sharecmt,proto3" json:"sharecmt,omitempty"`
+	Polycmt     []byte        `protobuf:"bytes,3,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
+	Zerowitness []byte        `protobuf:"bytes,4,opt,name=zerowitness,proto3" json:"zerowitness,omitempty"`
+	Signature   []byte        `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch       int64         `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee   string        `protobuf:"bytes,7,opt,name=committee,proto3" json:"committee,omitempty"`
+	Inclusion   *InclusionMsg `protobuf:"bytes,8,opt,name=inclusion,proto3" json:"inclusion,omitempty"`
+	Secret      string        `protobuf:"bytes,9,opt,name=secret,proto3" json:"secret,omitempty"`
+	// For the default secret, the Feldman commitment to the P-521 key the node holds, and the one to its zero polynomial refreshing that key from degree 1 up. Both are empty if the node holds no key.
+	Key                  [][]byte `protobuf:"bytes,10,rep,name=key,proto3" json:"key,omitempty"`
+	Keyzero              [][]byte `protobuf:"bytes,11,rep,name=keyzero,proto3" json:"keyzero,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -145,7 +444,7 @@ func (m *Cmt2Msg) Reset()         { *m = Cmt2Msg{} }
 func (m *Cmt2Msg) String() string { return proto.CompactTextString(m) }
 func (*Cmt2Msg) ProtoMessage()    {}
 func (*Cmt2Msg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e16ccb8c5307b32, []int{3}
+	return fileDescriptor_8e16ccb8c5307b32, []int{5}
 }
 
 func (m *Cmt2Msg) XXX_Unmarshal(b []byte) error {
@@ -194,11 +493,66 @@ func (m *Cmt2Msg) GetZerowitness() []byte {
 	return nil
 }
 
+func (m *Cmt2Msg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Cmt2Msg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Cmt2Msg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *Cmt2Msg) GetInclusion() *InclusionMsg {
+	if m != nil {
+		return m.Inclusion
+	}
+	return nil
+}
+
+func (m *Cmt2Msg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Cmt2Msg) GetKey() [][]byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Cmt2Msg) GetKeyzero() [][]byte {
+	if m != nil {
+		return m.Keyzero
+	}
+	return nil
+}
+
 type PointMsg struct {
-	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	X                    int32    `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y                    []byte   `protobuf:"bytes,3,opt,name=y,proto3" json:"y,omitempty"`
-	Witness              []byte   `protobuf:"bytes,4,opt,name=witness,proto3" json:"witness,omitempty"`
+	Index     int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	X         int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y         []byte `protobuf:"bytes,3,opt,name=y,proto3" json:"y,omitempty"`
+	Witness   []byte `protobuf:"bytes,4,opt,name=witness,proto3" json:"witness,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch     int64  `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee string `protobuf:"bytes,7,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret    string `protobuf:"bytes,8,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Phase the point is sent in, 1 or 3. The signature covers it, so a point of one phase is refused in the other.
+	Phase                int32    `protobuf:"varint,9,opt,name=phase,proto3" json:"phase,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -208,7 +562,7 @@ func (m *PointMsg) Reset()         { *m = PointMsg{} }
 func (m *PointMsg) String() string { return proto.CompactTextString(m) }
 func (*PointMsg) ProtoMessage()    {}
 func (*PointMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e16ccb8c5307b32, []int{4}
+	return fileDescriptor_8e16ccb8c5307b32, []int{6}
 }
 
 func (m *PointMsg) XXX_Unmarshal(b []byte) error {
@@ -257,9 +611,50 @@ func (m *PointMsg) GetWitness() []byte {
 	return nil
 }
 
+func (m *PointMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PointMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *PointMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *PointMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *PointMsg) GetPhase() int32 {
+	if m != nil {
+		return m.Phase
+	}
+	return 0
+}
+
 type ZeroMsg struct {
-	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Share                []byte   `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	Index     int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Share     []byte `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch     int64  `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee string `protobuf:"bytes,5,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret    string `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`
+	// For the default secret, the zero share of the P-521 key refresh for the receiver, empty if the sender holds no key
+	KeyShare             []byte   `protobuf:"bytes,7,opt,name=key_share,json=keyShare,proto3" json:"key_share,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -269,7 +664,7 @@ func (m *ZeroMsg) Reset()         { *m = ZeroMsg{} }
 func (m *ZeroMsg) String() string { return proto.CompactTextString(m) }
 func (*ZeroMsg) ProtoMessage()    {}
 func (*ZeroMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8e16ccb8c5307b32, []int{5}
+	return fileDescriptor_8e16ccb8c5307b32, []int{7}
 }
 
 func (m *ZeroMsg) XXX_Unmarshal(b []byte) error {
@@ -304,603 +699,4518 @@ func (m *ZeroMsg) GetShare() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*EmptyMsg)(nil), "services.EmptyMsg")
-	proto.RegisterType((*AckMsg)(nil), "services.AckMsg")
-	proto.RegisterType((*Cmt1Msg)(nil), "services.Cmt1Msg")
-	proto.RegisterType((*Cmt2Msg)(nil), "services.Cmt2Msg")
-	proto.RegisterType((*PointMsg)(nil), "services.PointMsg")
-	proto.RegisterType((*ZeroMsg)(nil), "services.ZeroMsg")
+func (m *ZeroMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("services.proto", fileDescriptor_8e16ccb8c5307b32) }
+func (m *ZeroMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
 
-var fileDescriptor_8e16ccb8c5307b32 = []byte{
-	// 380 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0xc1, 0x6f, 0xa2, 0x40,
-	0x14, 0xc6, 0xc5, 0x5d, 0x94, 0x3c, 0xcc, 0xc6, 0x9d, 0x78, 0x20, 0x9e, 0x0c, 0x27, 0x4f, 0x66,
-	0x1d, 0x34, 0x9b, 0xf6, 0x56, 0x1b, 0x8f, 0x36, 0x06, 0x13, 0x9b, 0xf4, 0x46, 0x61, 0xaa, 0x93,
-	0x2a, 0x43, 0x66, 0xa6, 0x55, 0xfa, 0xd7, 0x36, 0xe9, 0x3f, 0xd2, 0x30, 0x82, 0x4a, 0x44, 0xa5,
-	0x37, 0xbe, 0x81, 0xef, 0xfd, 0xde, 0xfb, 0xde, 0x04, 0xf8, 0x23, 0x08, 0x7f, 0xa7, 0x3e, 0x11,
-	0xbd, 0x88, 0x33, 0xc9, 0x90, 0x91, 0x69, 0x1b, 0xc0, 0x18, 0xaf, 0x23, 0x19, 0x4f, 0xc4, 0xc2,
-	0x36, 0xa0, 0x76, 0xe7, 0xbf, 0x26, 0x4f, 0x37, 0x50, 0xbf, 0x5f, 0xcb, 0xfe, 0x44, 0x2c, 0x50,
-	0x0b, 0x74, 0x1a, 0x06, 0x64, 0x6b, 0x69, 0x1d, 0xad, 0xab, 0xbb, 0x3b, 0x81, 0x2c, 0xa8, 0x47,
-	0x6c, 0x15, 0xfb, 0x6b, 0x69, 0x55, 0x3b, 0x5a, 0xb7, 0xe1, 0x66, 0xd2, 0xde, 0x28, 0x2b, 0x3e,
-	0x6f, 0x6d, 0x83, 0x21, 0x96, 0x1e, 0x27, 0x07, 0xef, 0x5e, 0x1f, 0x97, 0xfd, 0x95, 0x2b, 0x8b,
-	0x3a, 0x60, 0x7e, 0x10, 0xce, 0x36, 0x54, 0x86, 0x44, 0x08, 0xeb, 0xb7, 0x7a, 0x7b, 0x7c, 0x64,
-	0xcf, 0xc1, 0x98, 0x32, 0x1a, 0xca, 0xf3, 0xe4, 0x06, 0x68, 0x5b, 0x85, 0xd4, 0x5d, 0x4d, 0xa9,
-	0x38, 0xa5, 0x68, 0x71, 0x42, 0xce, 0xd7, 0xce, 0xa4, 0x3d, 0x84, 0xfa, 0x13, 0xe1, 0xec, 0x7c,
-	0xd9, 0x16, 0xe8, 0x6a, 0x80, 0x74, 0x9a, 0x9d, 0xc0, 0x5f, 0x55, 0x68, 0x8d, 0xde, 0x56, 0x2b,
-	0x22, 0x69, 0x38, 0x62, 0x1e, 0x0f, 0x66, 0xbb, 0xc8, 0xd1, 0x00, 0x60, 0x26, 0x3d, 0x2e, 0xc7,
-	0x11, 0xf3, 0x97, 0x08, 0xf5, 0xf6, 0xab, 0xc9, 0xf6, 0xd0, 0x6e, 0x1e, 0xce, 0xd2, 0x7d, 0x54,
-	0xd0, 0x7f, 0x00, 0x97, 0x78, 0xc1, 0x74, 0xe9, 0x09, 0xd2, 0x2f, 0x74, 0xfd, 0x3d, 0x9c, 0xa5,
-	0xbb, 0xb3, 0x2b, 0xff, 0x34, 0x34, 0x00, 0xf3, 0x91, 0x53, 0x49, 0x94, 0x13, 0xa3, 0xfc, 0x57,
-	0xb8, 0x0c, 0x0e, 0x97, 0xc0, 0xe1, 0x42, 0x9c, 0x83, 0x4e, 0x9b, 0xba, 0x8a, 0x73, 0x7e, 0x30,
-	0x1d, 0xfe, 0xac, 0x82, 0xf9, 0xc0, 0x02, 0x92, 0x85, 0x3b, 0x04, 0x53, 0x85, 0x7b, 0x21, 0xa7,
-	0x22, 0x7e, 0x62, 0x4b, 0xb6, 0x76, 0x6a, 0xcb, 0xae, 0x54, 0xa1, 0x6d, 0x70, 0x6c, 0xcb, 0x65,
-	0x9b, 0xde, 0x98, 0x42, 0xd7, 0x2d, 0x34, 0x55, 0x8f, 0x73, 0xc2, 0xe9, 0xcb, 0x85, 0x84, 0xaf,
-	0x36, 0xea, 0x94, 0x6e, 0xf4, 0x14, 0xe9, 0x94, 0x45, 0x3e, 0xd7, 0xd4, 0x2f, 0xc3, 0xf9, 0x0e,
-	0x00, 0x00, 0xff, 0xff, 0x0e, 0x16, 0xd4, 0xda, 0x44, 0x04, 0x00, 0x00,
+func (m *ZeroMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *ZeroMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ZeroMsg) GetKeyShare() []byte {
+	if m != nil {
+		return m.KeyShare
+	}
+	return nil
+}
 
-// BulletinBoardServiceClient is the client API for BulletinBoardService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BulletinBoardServiceClient interface {
-	// Start a epoch
-	StartEpoch(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	// BulletinBoard RPC for recontruction phase
-	ReadPhase1(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase1Client, error)
+// A commitment on the bulletinboard, written by node index in a phase of an epoch for the secret with the given ID.
+// Data is the marshalled Cmt2Msg of phase 2 or Cmt1Msg of phase 3.
+// Entries form a hash-chained log: seq is the position in the log from 1, hash chains the entry to prev, the hash of the entry before it.
+type EntryMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Phase                int32    `protobuf:"varint,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Index                int32    `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Data                 []byte   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Seq                  int64    `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	Prev                 []byte   `protobuf:"bytes,6,opt,name=prev,proto3" json:"prev,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
+	Secret               string   `protobuf:"bytes,8,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EntryMsg) Reset()         { *m = EntryMsg{} }
+func (m *EntryMsg) String() string { return proto.CompactTextString(m) }
+func (*EntryMsg) ProtoMessage()    {}
+func (*EntryMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{8}
+}
+
+func (m *EntryMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntryMsg.Unmarshal(m, b)
+}
+func (m *EntryMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntryMsg.Marshal(b, m, deterministic)
+}
+func (m *EntryMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntryMsg.Merge(m, src)
+}
+func (m *EntryMsg) XXX_Size() int {
+	return xxx_messageInfo_EntryMsg.Size(m)
+}
+func (m *EntryMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntryMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntryMsg proto.InternalMessageInfo
+
+func (m *EntryMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *EntryMsg) GetPhase() int32 {
+	if m != nil {
+		return m.Phase
+	}
+	return 0
+}
+
+func (m *EntryMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *EntryMsg) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *EntryMsg) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *EntryMsg) GetPrev() []byte {
+	if m != nil {
+		return m.Prev
+	}
+	return nil
+}
+
+func (m *EntryMsg) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *EntryMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// A record of the durable bulletinboard, either an entry of the log or the status of an epoch
+type RecordMsg struct {
+	Entry                *EntryMsg       `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Status               *EpochStatusMsg `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RecordMsg) Reset()         { *m = RecordMsg{} }
+func (m *RecordMsg) String() string { return proto.CompactTextString(m) }
+func (*RecordMsg) ProtoMessage()    {}
+func (*RecordMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{9}
+}
+
+func (m *RecordMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecordMsg.Unmarshal(m, b)
+}
+func (m *RecordMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecordMsg.Marshal(b, m, deterministic)
+}
+func (m *RecordMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecordMsg.Merge(m, src)
+}
+func (m *RecordMsg) XXX_Size() int {
+	return xxx_messageInfo_RecordMsg.Size(m)
+}
+func (m *RecordMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecordMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecordMsg proto.InternalMessageInfo
+
+func (m *RecordMsg) GetEntry() *EntryMsg {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *RecordMsg) GetStatus() *EpochStatusMsg {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// Proves that a commitment read from the bulletinboard is an entry of the log.
+// The hash of the entry is leaf number leaf of the Merkle tree over the size entries of the epoch up to the phase read, path leads to its root.
+type InclusionMsg struct {
+	Seq                  int64    `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Prev                 []byte   `protobuf:"bytes,2,opt,name=prev,proto3" json:"prev,omitempty"`
+	Leaf                 int32    `protobuf:"varint,3,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	Size                 int32    `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Path                 [][]byte `protobuf:"bytes,5,rep,name=path,proto3" json:"path,omitempty"`
+	Root                 []byte   `protobuf:"bytes,6,opt,name=root,proto3" json:"root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InclusionMsg) Reset()         { *m = InclusionMsg{} }
+func (m *InclusionMsg) String() string { return proto.CompactTextString(m) }
+func (*InclusionMsg) ProtoMessage()    {}
+func (*InclusionMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{10}
+}
+
+func (m *InclusionMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InclusionMsg.Unmarshal(m, b)
+}
+func (m *InclusionMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InclusionMsg.Marshal(b, m, deterministic)
+}
+func (m *InclusionMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InclusionMsg.Merge(m, src)
+}
+func (m *InclusionMsg) XXX_Size() int {
+	return xxx_messageInfo_InclusionMsg.Size(m)
+}
+func (m *InclusionMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_InclusionMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InclusionMsg proto.InternalMessageInfo
+
+func (m *InclusionMsg) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *InclusionMsg) GetPrev() []byte {
+	if m != nil {
+		return m.Prev
+	}
+	return nil
+}
+
+func (m *InclusionMsg) GetLeaf() int32 {
+	if m != nil {
+		return m.Leaf
+	}
+	return 0
+}
+
+func (m *InclusionMsg) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *InclusionMsg) GetPath() [][]byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *InclusionMsg) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+// The log of an epoch: the Merkle roots over its entries up to phase 2 and up to phase 3, and its last entry, at position seq with hash head
+type AuditMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	ProactivizationRoot  []byte   `protobuf:"bytes,3,opt,name=proactivization_root,json=proactivizationRoot,proto3" json:"proactivization_root,omitempty"`
+	Root                 []byte   `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`
+	Seq                  int64    `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	Head                 []byte   `protobuf:"bytes,6,opt,name=head,proto3" json:"head,omitempty"`
+	Size                 int32    `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditMsg) Reset()         { *m = AuditMsg{} }
+func (m *AuditMsg) String() string { return proto.CompactTextString(m) }
+func (*AuditMsg) ProtoMessage()    {}
+func (*AuditMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{11}
+}
+
+func (m *AuditMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditMsg.Unmarshal(m, b)
+}
+func (m *AuditMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditMsg.Marshal(b, m, deterministic)
+}
+func (m *AuditMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditMsg.Merge(m, src)
+}
+func (m *AuditMsg) XXX_Size() int {
+	return xxx_messageInfo_AuditMsg.Size(m)
+}
+func (m *AuditMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditMsg proto.InternalMessageInfo
+
+func (m *AuditMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *AuditMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *AuditMsg) GetProactivizationRoot() []byte {
+	if m != nil {
+		return m.ProactivizationRoot
+	}
+	return nil
+}
+
+func (m *AuditMsg) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *AuditMsg) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *AuditMsg) GetHead() []byte {
+	if m != nil {
+		return m.Head
+	}
+	return nil
+}
+
+func (m *AuditMsg) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+// Addresses a phase of an epoch. Subscribe starts at the epoch and ignores the phase.
+type ReadMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Phase                int32    `protobuf:"varint,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadMsg) Reset()         { *m = ReadMsg{} }
+func (m *ReadMsg) String() string { return proto.CompactTextString(m) }
+func (*ReadMsg) ProtoMessage()    {}
+func (*ReadMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{12}
+}
+
+func (m *ReadMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadMsg.Unmarshal(m, b)
+}
+func (m *ReadMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadMsg.Marshal(b, m, deterministic)
+}
+func (m *ReadMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadMsg.Merge(m, src)
+}
+func (m *ReadMsg) XXX_Size() int {
+	return xxx_messageInfo_ReadMsg.Size(m)
+}
+func (m *ReadMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadMsg proto.InternalMessageInfo
+
+func (m *ReadMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ReadMsg) GetPhase() int32 {
+	if m != nil {
+		return m.Phase
+	}
+	return 0
+}
+
+// Raft messages between replicas, indexes are positions in the replicated log
+type VoteMsg struct {
+	Term                 int64    `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Candidate            int32    `protobuf:"varint,2,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	LastIndex            int64    `protobuf:"varint,3,opt,name=last_index,json=lastIndex,proto3" json:"last_index,omitempty"`
+	LastTerm             int64    `protobuf:"varint,4,opt,name=last_term,json=lastTerm,proto3" json:"last_term,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteMsg) Reset()         { *m = VoteMsg{} }
+func (m *VoteMsg) String() string { return proto.CompactTextString(m) }
+func (*VoteMsg) ProtoMessage()    {}
+func (*VoteMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{13}
+}
+
+func (m *VoteMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteMsg.Unmarshal(m, b)
+}
+func (m *VoteMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteMsg.Marshal(b, m, deterministic)
+}
+func (m *VoteMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteMsg.Merge(m, src)
+}
+func (m *VoteMsg) XXX_Size() int {
+	return xxx_messageInfo_VoteMsg.Size(m)
+}
+func (m *VoteMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteMsg proto.InternalMessageInfo
+
+func (m *VoteMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteMsg) GetCandidate() int32 {
+	if m != nil {
+		return m.Candidate
+	}
+	return 0
+}
+
+func (m *VoteMsg) GetLastIndex() int64 {
+	if m != nil {
+		return m.LastIndex
+	}
+	return 0
+}
+
+func (m *VoteMsg) GetLastTerm() int64 {
+	if m != nil {
+		return m.LastTerm
+	}
+	return 0
+}
+
+type VoteReplyMsg struct {
+	Term                 int64    `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Granted              bool     `protobuf:"varint,2,opt,name=granted,proto3" json:"granted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteReplyMsg) Reset()         { *m = VoteReplyMsg{} }
+func (m *VoteReplyMsg) String() string { return proto.CompactTextString(m) }
+func (*VoteReplyMsg) ProtoMessage()    {}
+func (*VoteReplyMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{14}
+}
+
+func (m *VoteReplyMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteReplyMsg.Unmarshal(m, b)
+}
+func (m *VoteReplyMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteReplyMsg.Marshal(b, m, deterministic)
+}
+func (m *VoteReplyMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteReplyMsg.Merge(m, src)
+}
+func (m *VoteReplyMsg) XXX_Size() int {
+	return xxx_messageInfo_VoteReplyMsg.Size(m)
+}
+func (m *VoteReplyMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteReplyMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteReplyMsg proto.InternalMessageInfo
+
+func (m *VoteReplyMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteReplyMsg) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+// An entry of the replicated log. A leader opens its term with an entry without content.
+type LogEntryMsg struct {
+	Term                 int64     `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Entry                *EntryMsg `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LogEntryMsg) Reset()         { *m = LogEntryMsg{} }
+func (m *LogEntryMsg) String() string { return proto.CompactTextString(m) }
+func (*LogEntryMsg) ProtoMessage()    {}
+func (*LogEntryMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{15}
+}
+
+func (m *LogEntryMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogEntryMsg.Unmarshal(m, b)
+}
+func (m *LogEntryMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogEntryMsg.Marshal(b, m, deterministic)
+}
+func (m *LogEntryMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogEntryMsg.Merge(m, src)
+}
+func (m *LogEntryMsg) XXX_Size() int {
+	return xxx_messageInfo_LogEntryMsg.Size(m)
+}
+func (m *LogEntryMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogEntryMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogEntryMsg proto.InternalMessageInfo
+
+func (m *LogEntryMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *LogEntryMsg) GetEntry() *EntryMsg {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type AppendEntriesMsg struct {
+	Term                 int64          `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Leader               int32          `protobuf:"varint,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	PrevIndex            int64          `protobuf:"varint,3,opt,name=prev_index,json=prevIndex,proto3" json:"prev_index,omitempty"`
+	PrevTerm             int64          `protobuf:"varint,4,opt,name=prev_term,json=prevTerm,proto3" json:"prev_term,omitempty"`
+	Entries              []*LogEntryMsg `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	Commit               int64          `protobuf:"varint,6,opt,name=commit,proto3" json:"commit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AppendEntriesMsg) Reset()         { *m = AppendEntriesMsg{} }
+func (m *AppendEntriesMsg) String() string { return proto.CompactTextString(m) }
+func (*AppendEntriesMsg) ProtoMessage()    {}
+func (*AppendEntriesMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{16}
+}
+
+func (m *AppendEntriesMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendEntriesMsg.Unmarshal(m, b)
+}
+func (m *AppendEntriesMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendEntriesMsg.Marshal(b, m, deterministic)
+}
+func (m *AppendEntriesMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendEntriesMsg.Merge(m, src)
+}
+func (m *AppendEntriesMsg) XXX_Size() int {
+	return xxx_messageInfo_AppendEntriesMsg.Size(m)
+}
+func (m *AppendEntriesMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendEntriesMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendEntriesMsg proto.InternalMessageInfo
+
+func (m *AppendEntriesMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendEntriesMsg) GetLeader() int32 {
+	if m != nil {
+		return m.Leader
+	}
+	return 0
+}
+
+func (m *AppendEntriesMsg) GetPrevIndex() int64 {
+	if m != nil {
+		return m.PrevIndex
+	}
+	return 0
+}
+
+func (m *AppendEntriesMsg) GetPrevTerm() int64 {
+	if m != nil {
+		return m.PrevTerm
+	}
+	return 0
+}
+
+func (m *AppendEntriesMsg) GetEntries() []*LogEntryMsg {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *AppendEntriesMsg) GetCommit() int64 {
+	if m != nil {
+		return m.Commit
+	}
+	return 0
+}
+
+// Last is the index of the last entry the follower holds that matches the leader
+type AppendEntriesReplyMsg struct {
+	Term                 int64    `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Last                 int64    `protobuf:"varint,3,opt,name=last,proto3" json:"last,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendEntriesReplyMsg) Reset()         { *m = AppendEntriesReplyMsg{} }
+func (m *AppendEntriesReplyMsg) String() string { return proto.CompactTextString(m) }
+func (*AppendEntriesReplyMsg) ProtoMessage()    {}
+func (*AppendEntriesReplyMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{17}
+}
+
+func (m *AppendEntriesReplyMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendEntriesReplyMsg.Unmarshal(m, b)
+}
+func (m *AppendEntriesReplyMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendEntriesReplyMsg.Marshal(b, m, deterministic)
+}
+func (m *AppendEntriesReplyMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendEntriesReplyMsg.Merge(m, src)
+}
+func (m *AppendEntriesReplyMsg) XXX_Size() int {
+	return xxx_messageInfo_AppendEntriesReplyMsg.Size(m)
+}
+func (m *AppendEntriesReplyMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendEntriesReplyMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendEntriesReplyMsg proto.InternalMessageInfo
+
+func (m *AppendEntriesReplyMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendEntriesReplyMsg) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *AppendEntriesReplyMsg) GetLast() int64 {
+	if m != nil {
+		return m.Last
+	}
+	return 0
+}
+
+// A record of the durable state of a replica: its term and vote, and the entries that replace its log from position index on, if any
+type ReplicaRecordMsg struct {
+	Term                 int64          `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	VotedFor             int32          `protobuf:"varint,2,opt,name=voted_for,json=votedFor,proto3" json:"voted_for,omitempty"`
+	Index                int64          `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Entries              []*LogEntryMsg `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ReplicaRecordMsg) Reset()         { *m = ReplicaRecordMsg{} }
+func (m *ReplicaRecordMsg) String() string { return proto.CompactTextString(m) }
+func (*ReplicaRecordMsg) ProtoMessage()    {}
+func (*ReplicaRecordMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{18}
+}
+
+func (m *ReplicaRecordMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicaRecordMsg.Unmarshal(m, b)
+}
+func (m *ReplicaRecordMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicaRecordMsg.Marshal(b, m, deterministic)
+}
+func (m *ReplicaRecordMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicaRecordMsg.Merge(m, src)
+}
+func (m *ReplicaRecordMsg) XXX_Size() int {
+	return xxx_messageInfo_ReplicaRecordMsg.Size(m)
+}
+func (m *ReplicaRecordMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicaRecordMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicaRecordMsg proto.InternalMessageInfo
+
+func (m *ReplicaRecordMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *ReplicaRecordMsg) GetVotedFor() int32 {
+	if m != nil {
+		return m.VotedFor
+	}
+	return 0
+}
+
+func (m *ReplicaRecordMsg) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReplicaRecordMsg) GetEntries() []*LogEntryMsg {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type StatusRequestMsg struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusRequestMsg) Reset()         { *m = StatusRequestMsg{} }
+func (m *StatusRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*StatusRequestMsg) ProtoMessage()    {}
+func (*StatusRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{19}
+}
+
+func (m *StatusRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusRequestMsg.Unmarshal(m, b)
+}
+func (m *StatusRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *StatusRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusRequestMsg.Merge(m, src)
+}
+func (m *StatusRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_StatusRequestMsg.Size(m)
+}
+func (m *StatusRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusRequestMsg proto.InternalMessageInfo
+
+// Status of a node or of the bulletinboard, whichever answers fills in node or board.
+// Label is 0 for the bulletinboard, completed is the latest epoch that completed.
+type StatusMsg struct {
+	Label     int32            `protobuf:"varint,1,opt,name=label,proto3" json:"label,omitempty"`
+	Committee string           `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	Counter   int32            `protobuf:"varint,3,opt,name=counter,proto3" json:"counter,omitempty"`
+	Degree    int32            `protobuf:"varint,4,opt,name=degree,proto3" json:"degree,omitempty"`
+	Epoch     int64            `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Completed int64            `protobuf:"varint,6,opt,name=completed,proto3" json:"completed,omitempty"`
+	Conns     []*ConnStatusMsg `protobuf:"bytes,7,rep,name=conns,proto3" json:"conns,omitempty"`
+	Node      *NodeStatusMsg   `protobuf:"bytes,8,opt,name=node,proto3" json:"node,omitempty"`
+	Board     *BoardStatusMsg  `protobuf:"bytes,9,opt,name=board,proto3" json:"board,omitempty"`
+	// Whether the server can take part in an epoch, and why not if it cannot
+	Ready                bool     `protobuf:"varint,10,opt,name=ready,proto3" json:"ready,omitempty"`
+	NotReady             string   `protobuf:"bytes,11,opt,name=not_ready,json=notReady,proto3" json:"not_ready,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusMsg) Reset()         { *m = StatusMsg{} }
+func (m *StatusMsg) String() string { return proto.CompactTextString(m) }
+func (*StatusMsg) ProtoMessage()    {}
+func (*StatusMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{20}
+}
+
+func (m *StatusMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusMsg.Unmarshal(m, b)
+}
+func (m *StatusMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusMsg.Marshal(b, m, deterministic)
+}
+func (m *StatusMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusMsg.Merge(m, src)
+}
+func (m *StatusMsg) XXX_Size() int {
+	return xxx_messageInfo_StatusMsg.Size(m)
+}
+func (m *StatusMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusMsg proto.InternalMessageInfo
+
+func (m *StatusMsg) GetLabel() int32 {
+	if m != nil {
+		return m.Label
+	}
+	return 0
+}
+
+func (m *StatusMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *StatusMsg) GetCounter() int32 {
+	if m != nil {
+		return m.Counter
+	}
+	return 0
+}
+
+func (m *StatusMsg) GetDegree() int32 {
+	if m != nil {
+		return m.Degree
+	}
+	return 0
+}
+
+func (m *StatusMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *StatusMsg) GetCompleted() int64 {
+	if m != nil {
+		return m.Completed
+	}
+	return 0
+}
+
+func (m *StatusMsg) GetConns() []*ConnStatusMsg {
+	if m != nil {
+		return m.Conns
+	}
+	return nil
+}
+
+func (m *StatusMsg) GetNode() *NodeStatusMsg {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *StatusMsg) GetBoard() *BoardStatusMsg {
+	if m != nil {
+		return m.Board
+	}
+	return nil
+}
+
+func (m *StatusMsg) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *StatusMsg) GetNotReady() string {
+	if m != nil {
+		return m.NotReady
+	}
+	return ""
+}
+
+// Health of the connection to a peer, a gRPC connectivity state such as READY or TRANSIENT_FAILURE, or NONE before the first dial.
+// Failures counts the calls in a row that did not reach the peer, which is down after a few of them until a call reaches it again.
+type ConnStatusMsg struct {
+	Peer                 string   `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Failures             int32    `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`
+	Down                 bool     `protobuf:"varint,5,opt,name=down,proto3" json:"down,omitempty"`
+	Error                string   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	LastOk               int64    `protobuf:"varint,7,opt,name=last_ok,json=lastOk,proto3" json:"last_ok,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnStatusMsg) Reset()         { *m = ConnStatusMsg{} }
+func (m *ConnStatusMsg) String() string { return proto.CompactTextString(m) }
+func (*ConnStatusMsg) ProtoMessage()    {}
+func (*ConnStatusMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{21}
+}
+
+func (m *ConnStatusMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnStatusMsg.Unmarshal(m, b)
+}
+func (m *ConnStatusMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnStatusMsg.Marshal(b, m, deterministic)
+}
+func (m *ConnStatusMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnStatusMsg.Merge(m, src)
+}
+func (m *ConnStatusMsg) XXX_Size() int {
+	return xxx_messageInfo_ConnStatusMsg.Size(m)
+}
+func (m *ConnStatusMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnStatusMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnStatusMsg proto.InternalMessageInfo
+
+func (m *ConnStatusMsg) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *ConnStatusMsg) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ConnStatusMsg) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ConnStatusMsg) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *ConnStatusMsg) GetDown() bool {
+	if m != nil {
+		return m.Down
+	}
+	return false
+}
+
+func (m *ConnStatusMsg) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ConnStatusMsg) GetLastOk() int64 {
+	if m != nil {
+		return m.LastOk
+	}
+	return 0
+}
+
+// Progress of a node through the current epoch.
+// Waiting lists the peers whose message of the current phase has not arrived, a node that waits on no peer waits on the bulletinboard.
+// Poly_cmts are the commitments to the sharing polynomials verified at the end of the latest completed epoch, indexed by label - 1.
+type NodeStatusMsg struct {
+	Phase                string          `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Recovering           bool            `protobuf:"varint,2,opt,name=recovering,proto3" json:"recovering,omitempty"`
+	RecCnt               int32           `protobuf:"varint,3,opt,name=rec_cnt,json=recCnt,proto3" json:"rec_cnt,omitempty"`
+	ZeroCnt              int32           `protobuf:"varint,4,opt,name=zero_cnt,json=zeroCnt,proto3" json:"zero_cnt,omitempty"`
+	ShareCnt             int32           `protobuf:"varint,5,opt,name=share_cnt,json=shareCnt,proto3" json:"share_cnt,omitempty"`
+	Waiting              []int32         `protobuf:"varint,6,rep,packed,name=waiting,proto3" json:"waiting,omitempty"`
+	PolyCmts             [][]byte        `protobuf:"bytes,7,rep,name=poly_cmts,json=polyCmts,proto3" json:"poly_cmts,omitempty"`
+	Timings              []*PhaseTimeMsg `protobuf:"bytes,8,rep,name=timings,proto3" json:"timings,omitempty"`
+	Faults               int32           `protobuf:"varint,9,opt,name=faults,proto3" json:"faults,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *NodeStatusMsg) Reset()         { *m = NodeStatusMsg{} }
+func (m *NodeStatusMsg) String() string { return proto.CompactTextString(m) }
+func (*NodeStatusMsg) ProtoMessage()    {}
+func (*NodeStatusMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{22}
+}
+
+func (m *NodeStatusMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatusMsg.Unmarshal(m, b)
+}
+func (m *NodeStatusMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatusMsg.Marshal(b, m, deterministic)
+}
+func (m *NodeStatusMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatusMsg.Merge(m, src)
+}
+func (m *NodeStatusMsg) XXX_Size() int {
+	return xxx_messageInfo_NodeStatusMsg.Size(m)
+}
+func (m *NodeStatusMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatusMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeStatusMsg proto.InternalMessageInfo
+
+func (m *NodeStatusMsg) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *NodeStatusMsg) GetRecovering() bool {
+	if m != nil {
+		return m.Recovering
+	}
+	return false
+}
+
+func (m *NodeStatusMsg) GetRecCnt() int32 {
+	if m != nil {
+		return m.RecCnt
+	}
+	return 0
+}
+
+func (m *NodeStatusMsg) GetZeroCnt() int32 {
+	if m != nil {
+		return m.ZeroCnt
+	}
+	return 0
+}
+
+func (m *NodeStatusMsg) GetShareCnt() int32 {
+	if m != nil {
+		return m.ShareCnt
+	}
+	return 0
+}
+
+func (m *NodeStatusMsg) GetWaiting() []int32 {
+	if m != nil {
+		return m.Waiting
+	}
+	return nil
+}
+
+func (m *NodeStatusMsg) GetPolyCmts() [][]byte {
+	if m != nil {
+		return m.PolyCmts
+	}
+	return nil
+}
+
+func (m *NodeStatusMsg) GetTimings() []*PhaseTimeMsg {
+	if m != nil {
+		return m.Timings
+	}
+	return nil
+}
+
+func (m *NodeStatusMsg) GetFaults() int32 {
+	if m != nil {
+		return m.Faults
+	}
+	return 0
+}
+
+// How long a phase of the latest completed epoch took, in nanoseconds
+type PhaseTimeMsg struct {
+	Phase                string   `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Took                 int64    `protobuf:"varint,2,opt,name=took,proto3" json:"took,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PhaseTimeMsg) Reset()         { *m = PhaseTimeMsg{} }
+func (m *PhaseTimeMsg) String() string { return proto.CompactTextString(m) }
+func (*PhaseTimeMsg) ProtoMessage()    {}
+func (*PhaseTimeMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{23}
+}
+
+func (m *PhaseTimeMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PhaseTimeMsg.Unmarshal(m, b)
+}
+func (m *PhaseTimeMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PhaseTimeMsg.Marshal(b, m, deterministic)
+}
+func (m *PhaseTimeMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PhaseTimeMsg.Merge(m, src)
+}
+func (m *PhaseTimeMsg) XXX_Size() int {
+	return xxx_messageInfo_PhaseTimeMsg.Size(m)
+}
+func (m *PhaseTimeMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PhaseTimeMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PhaseTimeMsg proto.InternalMessageInfo
+
+func (m *PhaseTimeMsg) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *PhaseTimeMsg) GetTook() int64 {
+	if m != nil {
+		return m.Took
+	}
+	return 0
+}
+
+// Progress of the current epoch on the bulletinboard
+type BoardStatusMsg struct {
+	Status               *EpochStatusMsg    `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Phases               []*PhaseWritersMsg `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *BoardStatusMsg) Reset()         { *m = BoardStatusMsg{} }
+func (m *BoardStatusMsg) String() string { return proto.CompactTextString(m) }
+func (*BoardStatusMsg) ProtoMessage()    {}
+func (*BoardStatusMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{24}
+}
+
+func (m *BoardStatusMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BoardStatusMsg.Unmarshal(m, b)
+}
+func (m *BoardStatusMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BoardStatusMsg.Marshal(b, m, deterministic)
+}
+func (m *BoardStatusMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoardStatusMsg.Merge(m, src)
+}
+func (m *BoardStatusMsg) XXX_Size() int {
+	return xxx_messageInfo_BoardStatusMsg.Size(m)
+}
+func (m *BoardStatusMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoardStatusMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoardStatusMsg proto.InternalMessageInfo
+
+func (m *BoardStatusMsg) GetStatus() *EpochStatusMsg {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *BoardStatusMsg) GetPhases() []*PhaseWritersMsg {
+	if m != nil {
+		return m.Phases
+	}
+	return nil
+}
+
+// Labels of the nodes that wrote their commitment in a phase of the current epoch
+type PhaseWritersMsg struct {
+	Phase                int32    `protobuf:"varint,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Written              []int32  `protobuf:"varint,2,rep,packed,name=written,proto3" json:"written,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PhaseWritersMsg) Reset()         { *m = PhaseWritersMsg{} }
+func (m *PhaseWritersMsg) String() string { return proto.CompactTextString(m) }
+func (*PhaseWritersMsg) ProtoMessage()    {}
+func (*PhaseWritersMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{25}
+}
+
+func (m *PhaseWritersMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PhaseWritersMsg.Unmarshal(m, b)
+}
+func (m *PhaseWritersMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PhaseWritersMsg.Marshal(b, m, deterministic)
+}
+func (m *PhaseWritersMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PhaseWritersMsg.Merge(m, src)
+}
+func (m *PhaseWritersMsg) XXX_Size() int {
+	return xxx_messageInfo_PhaseWritersMsg.Size(m)
+}
+func (m *PhaseWritersMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PhaseWritersMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PhaseWritersMsg proto.InternalMessageInfo
+
+func (m *PhaseWritersMsg) GetPhase() int32 {
+	if m != nil {
+		return m.Phase
+	}
+	return 0
+}
+
+func (m *PhaseWritersMsg) GetWritten() []int32 {
+	if m != nil {
+		return m.Written
+	}
+	return nil
+}
+
+// The commitment to the polynomial of a dealt secret, the bulletinboard records it as the commitment of every node in the given epoch
+type DealMsg struct {
+	Epoch                int64    `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	Polycmt              []byte   `protobuf:"bytes,3,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Secret               string   `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DealMsg) Reset()         { *m = DealMsg{} }
+func (m *DealMsg) String() string { return proto.CompactTextString(m) }
+func (*DealMsg) ProtoMessage()    {}
+func (*DealMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{26}
+}
+
+func (m *DealMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DealMsg.Unmarshal(m, b)
+}
+func (m *DealMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DealMsg.Marshal(b, m, deterministic)
+}
+func (m *DealMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DealMsg.Merge(m, src)
+}
+func (m *DealMsg) XXX_Size() int {
+	return xxx_messageInfo_DealMsg.Size(m)
+}
+func (m *DealMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_DealMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DealMsg proto.InternalMessageInfo
+
+func (m *DealMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *DealMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *DealMsg) GetPolycmt() []byte {
+	if m != nil {
+		return m.Polycmt
+	}
+	return nil
+}
+
+func (m *DealMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *DealMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// Drops the secret with the given ID in an epoch that runs no phases, the secret the committee started with stays
+type DeleteMsg struct {
+	Secret               string   `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	Epoch                int64    `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,3,opt,name=committee,proto3" json:"committee,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteMsg) Reset()         { *m = DeleteMsg{} }
+func (m *DeleteMsg) String() string { return proto.CompactTextString(m) }
+func (*DeleteMsg) ProtoMessage()    {}
+func (*DeleteMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{27}
+}
+
+func (m *DeleteMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteMsg.Unmarshal(m, b)
+}
+func (m *DeleteMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteMsg.Marshal(b, m, deterministic)
+}
+func (m *DeleteMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteMsg.Merge(m, src)
+}
+func (m *DeleteMsg) XXX_Size() int {
+	return xxx_messageInfo_DeleteMsg.Size(m)
+}
+func (m *DeleteMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteMsg proto.InternalMessageInfo
+
+func (m *DeleteMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *DeleteMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *DeleteMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *DeleteMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// The share of node x of a dealt secret, with its witness and the commitment it opens
+type DealtShareMsg struct {
+	X                    int32    `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    []byte   `protobuf:"bytes,2,opt,name=y,proto3" json:"y,omitempty"`
+	Witness              []byte   `protobuf:"bytes,3,opt,name=witness,proto3" json:"witness,omitempty"`
+	Polycmt              []byte   `protobuf:"bytes,4,opt,name=polycmt,proto3" json:"polycmt,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch                int64    `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,7,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,8,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DealtShareMsg) Reset()         { *m = DealtShareMsg{} }
+func (m *DealtShareMsg) String() string { return proto.CompactTextString(m) }
+func (*DealtShareMsg) ProtoMessage()    {}
+func (*DealtShareMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{28}
+}
+
+func (m *DealtShareMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DealtShareMsg.Unmarshal(m, b)
+}
+func (m *DealtShareMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DealtShareMsg.Marshal(b, m, deterministic)
+}
+func (m *DealtShareMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DealtShareMsg.Merge(m, src)
+}
+func (m *DealtShareMsg) XXX_Size() int {
+	return xxx_messageInfo_DealtShareMsg.Size(m)
+}
+func (m *DealtShareMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_DealtShareMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DealtShareMsg proto.InternalMessageInfo
+
+func (m *DealtShareMsg) GetX() int32 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *DealtShareMsg) GetY() []byte {
+	if m != nil {
+		return m.Y
+	}
+	return nil
+}
+
+func (m *DealtShareMsg) GetWitness() []byte {
+	if m != nil {
+		return m.Witness
+	}
+	return nil
+}
+
+func (m *DealtShareMsg) GetPolycmt() []byte {
+	if m != nil {
+		return m.Polycmt
+	}
+	return nil
+}
+
+func (m *DealtShareMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *DealtShareMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *DealtShareMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *DealtShareMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// Asks node index for its shares of the given completed epoch
+type ShareRequestMsg struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch                int64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,4,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShareRequestMsg) Reset()         { *m = ShareRequestMsg{} }
+func (m *ShareRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*ShareRequestMsg) ProtoMessage()    {}
+func (*ShareRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{29}
+}
+
+func (m *ShareRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareRequestMsg.Unmarshal(m, b)
+}
+func (m *ShareRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *ShareRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareRequestMsg.Merge(m, src)
+}
+func (m *ShareRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_ShareRequestMsg.Size(m)
+}
+func (m *ShareRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareRequestMsg proto.InternalMessageInfo
+
+func (m *ShareRequestMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ShareRequestMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *ShareRequestMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ShareRequestMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *ShareRequestMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// The shares of node index on the polynomial of every node, and the commitments to those polynomials, indexed by label - 1
+type SharesMsg struct {
+	Index                int32       `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Shares               []*PointMsg `protobuf:"bytes,2,rep,name=shares,proto3" json:"shares,omitempty"`
+	PolyCmts             [][]byte    `protobuf:"bytes,3,rep,name=poly_cmts,json=polyCmts,proto3" json:"poly_cmts,omitempty"`
+	Epoch                int64       `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string      `protobuf:"bytes,5,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string      `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SharesMsg) Reset()         { *m = SharesMsg{} }
+func (m *SharesMsg) String() string { return proto.CompactTextString(m) }
+func (*SharesMsg) ProtoMessage()    {}
+func (*SharesMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{30}
+}
+
+func (m *SharesMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SharesMsg.Unmarshal(m, b)
+}
+func (m *SharesMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SharesMsg.Marshal(b, m, deterministic)
+}
+func (m *SharesMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SharesMsg.Merge(m, src)
+}
+func (m *SharesMsg) XXX_Size() int {
+	return xxx_messageInfo_SharesMsg.Size(m)
+}
+func (m *SharesMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SharesMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SharesMsg proto.InternalMessageInfo
+
+func (m *SharesMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *SharesMsg) GetShares() []*PointMsg {
+	if m != nil {
+		return m.Shares
+	}
+	return nil
+}
+
+func (m *SharesMsg) GetPolyCmts() [][]byte {
+	if m != nil {
+		return m.PolyCmts
+	}
+	return nil
+}
+
+func (m *SharesMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *SharesMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *SharesMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// Asks a node to sign message with its share of the secret of the given completed epoch
+type SignRequestMsg struct {
+	Message              []byte   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch                int64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,4,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignRequestMsg) Reset()         { *m = SignRequestMsg{} }
+func (m *SignRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*SignRequestMsg) ProtoMessage()    {}
+func (*SignRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{31}
+}
+
+func (m *SignRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignRequestMsg.Unmarshal(m, b)
+}
+func (m *SignRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *SignRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignRequestMsg.Merge(m, src)
+}
+func (m *SignRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_SignRequestMsg.Size(m)
+}
+func (m *SignRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignRequestMsg proto.InternalMessageInfo
+
+func (m *SignRequestMsg) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *SignRequestMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignRequestMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *SignRequestMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *SignRequestMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// The partial signature H(m)^s_j of node index, where s_j is its share of the secret. Evals holds g^f_i(j) for the polynomial f_i of every node, indexed by label - 1, and witnesses the proofs that they open the commitments of the epoch; together they give the public share g^s_j the partial signature is checked against.
+type PartialSigMsg struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Partial              []byte   `protobuf:"bytes,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	PublicShare          []byte   `protobuf:"bytes,3,opt,name=public_share,json=publicShare,proto3" json:"public_share,omitempty"`
+	Evals                [][]byte `protobuf:"bytes,4,rep,name=evals,proto3" json:"evals,omitempty"`
+	Witnesses            [][]byte `protobuf:"bytes,5,rep,name=witnesses,proto3" json:"witnesses,omitempty"`
+	Epoch                int64    `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,7,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,8,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartialSigMsg) Reset()         { *m = PartialSigMsg{} }
+func (m *PartialSigMsg) String() string { return proto.CompactTextString(m) }
+func (*PartialSigMsg) ProtoMessage()    {}
+func (*PartialSigMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{32}
+}
+
+func (m *PartialSigMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartialSigMsg.Unmarshal(m, b)
+}
+func (m *PartialSigMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartialSigMsg.Marshal(b, m, deterministic)
+}
+func (m *PartialSigMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartialSigMsg.Merge(m, src)
+}
+func (m *PartialSigMsg) XXX_Size() int {
+	return xxx_messageInfo_PartialSigMsg.Size(m)
+}
+func (m *PartialSigMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartialSigMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartialSigMsg proto.InternalMessageInfo
+
+func (m *PartialSigMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *PartialSigMsg) GetPartial() []byte {
+	if m != nil {
+		return m.Partial
+	}
+	return nil
+}
+
+func (m *PartialSigMsg) GetPublicShare() []byte {
+	if m != nil {
+		return m.PublicShare
+	}
+	return nil
+}
+
+func (m *PartialSigMsg) GetEvals() [][]byte {
+	if m != nil {
+		return m.Evals
+	}
+	return nil
+}
+
+func (m *PartialSigMsg) GetWitnesses() [][]byte {
+	if m != nil {
+		return m.Witnesses
+	}
+	return nil
+}
+
+func (m *PartialSigMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *PartialSigMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *PartialSigMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// The public share g^s_j of node index. Evals holds g^f_i(j) for the polynomial f_i of every node, indexed by label - 1, and witnesses the proofs that they open the commitments of the epoch.
+type PublicShareMsg struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	PublicShare          []byte   `protobuf:"bytes,2,opt,name=public_share,json=publicShare,proto3" json:"public_share,omitempty"`
+	Evals                [][]byte `protobuf:"bytes,3,rep,name=evals,proto3" json:"evals,omitempty"`
+	Witnesses            [][]byte `protobuf:"bytes,4,rep,name=witnesses,proto3" json:"witnesses,omitempty"`
+	Epoch                int64    `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,6,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,7,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PublicShareMsg) Reset()         { *m = PublicShareMsg{} }
+func (m *PublicShareMsg) String() string { return proto.CompactTextString(m) }
+func (*PublicShareMsg) ProtoMessage()    {}
+func (*PublicShareMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{33}
+}
+
+func (m *PublicShareMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PublicShareMsg.Unmarshal(m, b)
+}
+func (m *PublicShareMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PublicShareMsg.Marshal(b, m, deterministic)
+}
+func (m *PublicShareMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PublicShareMsg.Merge(m, src)
+}
+func (m *PublicShareMsg) XXX_Size() int {
+	return xxx_messageInfo_PublicShareMsg.Size(m)
+}
+func (m *PublicShareMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PublicShareMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PublicShareMsg proto.InternalMessageInfo
+
+func (m *PublicShareMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *PublicShareMsg) GetPublicShare() []byte {
+	if m != nil {
+		return m.PublicShare
+	}
+	return nil
+}
+
+func (m *PublicShareMsg) GetEvals() [][]byte {
+	if m != nil {
+		return m.Evals
+	}
+	return nil
+}
+
+func (m *PublicShareMsg) GetWitnesses() [][]byte {
+	if m != nil {
+		return m.Witnesses
+	}
+	return nil
+}
+
+func (m *PublicShareMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *PublicShareMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *PublicShareMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// Asks a node to decrypt c1 = g^r, the first part of an ElGamal ciphertext, with its share of the secret of the given completed epoch
+type DecryptRequestMsg struct {
+	C1                   []byte   `protobuf:"bytes,1,opt,name=c1,proto3" json:"c1,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Epoch                int64    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string   `protobuf:"bytes,4,opt,name=committee,proto3" json:"committee,omitempty"`
+	Secret               string   `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DecryptRequestMsg) Reset()         { *m = DecryptRequestMsg{} }
+func (m *DecryptRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*DecryptRequestMsg) ProtoMessage()    {}
+func (*DecryptRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{34}
+}
+
+func (m *DecryptRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecryptRequestMsg.Unmarshal(m, b)
+}
+func (m *DecryptRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecryptRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *DecryptRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecryptRequestMsg.Merge(m, src)
+}
+func (m *DecryptRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_DecryptRequestMsg.Size(m)
+}
+func (m *DecryptRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecryptRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DecryptRequestMsg proto.InternalMessageInfo
+
+func (m *DecryptRequestMsg) GetC1() []byte {
+	if m != nil {
+		return m.C1
+	}
+	return nil
+}
+
+func (m *DecryptRequestMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *DecryptRequestMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *DecryptRequestMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *DecryptRequestMsg) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+// The partial decryption c1^s_j of a node, with a Chaum-Pedersen proof that its discrete logarithm to c1 is that of the public share to g
+type PartialDecryptionMsg struct {
+	Share                *PublicShareMsg `protobuf:"bytes,1,opt,name=share,proto3" json:"share,omitempty"`
+	Partial              []byte          `protobuf:"bytes,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	Challenge            []byte          `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Response             []byte          `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *PartialDecryptionMsg) Reset()         { *m = PartialDecryptionMsg{} }
+func (m *PartialDecryptionMsg) String() string { return proto.CompactTextString(m) }
+func (*PartialDecryptionMsg) ProtoMessage()    {}
+func (*PartialDecryptionMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{35}
+}
+
+func (m *PartialDecryptionMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartialDecryptionMsg.Unmarshal(m, b)
+}
+func (m *PartialDecryptionMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartialDecryptionMsg.Marshal(b, m, deterministic)
+}
+func (m *PartialDecryptionMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartialDecryptionMsg.Merge(m, src)
+}
+func (m *PartialDecryptionMsg) XXX_Size() int {
+	return xxx_messageInfo_PartialDecryptionMsg.Size(m)
+}
+func (m *PartialDecryptionMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartialDecryptionMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartialDecryptionMsg proto.InternalMessageInfo
+
+func (m *PartialDecryptionMsg) GetShare() *PublicShareMsg {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+func (m *PartialDecryptionMsg) GetPartial() []byte {
+	if m != nil {
+		return m.Partial
+	}
+	return nil
+}
+
+func (m *PartialDecryptionMsg) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PartialDecryptionMsg) GetResponse() []byte {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+// The share of a P-521 signing key the operator dealt to node x. Commitment is the Feldman commitment to the polynomial of the key, one compressed point per coefficient from the constant one up, so g^share and the public key follow from it.
+type DealtKeyMsg struct {
+	X                    int32    `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Share                []byte   `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	Commitment           [][]byte `protobuf:"bytes,3,rep,name=commitment,proto3" json:"commitment,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Committee            string   `protobuf:"bytes,5,opt,name=committee,proto3" json:"committee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DealtKeyMsg) Reset()         { *m = DealtKeyMsg{} }
+func (m *DealtKeyMsg) String() string { return proto.CompactTextString(m) }
+func (*DealtKeyMsg) ProtoMessage()    {}
+func (*DealtKeyMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{36}
+}
+
+func (m *DealtKeyMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DealtKeyMsg.Unmarshal(m, b)
+}
+func (m *DealtKeyMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DealtKeyMsg.Marshal(b, m, deterministic)
+}
+func (m *DealtKeyMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DealtKeyMsg.Merge(m, src)
+}
+func (m *DealtKeyMsg) XXX_Size() int {
+	return xxx_messageInfo_DealtKeyMsg.Size(m)
+}
+func (m *DealtKeyMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_DealtKeyMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DealtKeyMsg proto.InternalMessageInfo
+
+func (m *DealtKeyMsg) GetX() int32 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *DealtKeyMsg) GetShare() []byte {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+func (m *DealtKeyMsg) GetCommitment() [][]byte {
+	if m != nil {
+		return m.Commitment
+	}
+	return nil
+}
+
+func (m *DealtKeyMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *DealtKeyMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+// Asks a node to commit to fresh nonces for the signing session
+type NonceRequestMsg struct {
+	Session              []byte   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Committee            string   `protobuf:"bytes,3,opt,name=committee,proto3" json:"committee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NonceRequestMsg) Reset()         { *m = NonceRequestMsg{} }
+func (m *NonceRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*NonceRequestMsg) ProtoMessage()    {}
+func (*NonceRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{37}
+}
+
+func (m *NonceRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NonceRequestMsg.Unmarshal(m, b)
+}
+func (m *NonceRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NonceRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *NonceRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NonceRequestMsg.Merge(m, src)
+}
+func (m *NonceRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_NonceRequestMsg.Size(m)
+}
+func (m *NonceRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_NonceRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NonceRequestMsg proto.InternalMessageInfo
+
+func (m *NonceRequestMsg) GetSession() []byte {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *NonceRequestMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *NonceRequestMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+// The commitments g^d and g^e of node index to its nonces for a signing session. Key is the Feldman commitment to the key the node signs with, left out when the commitments are sent back to the signers.
+type NonceCommitMsg struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Session              []byte   `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Hiding               []byte   `protobuf:"bytes,3,opt,name=hiding,proto3" json:"hiding,omitempty"`
+	Binding              []byte   `protobuf:"bytes,4,opt,name=binding,proto3" json:"binding,omitempty"`
+	Key                  [][]byte `protobuf:"bytes,5,rep,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NonceCommitMsg) Reset()         { *m = NonceCommitMsg{} }
+func (m *NonceCommitMsg) String() string { return proto.CompactTextString(m) }
+func (*NonceCommitMsg) ProtoMessage()    {}
+func (*NonceCommitMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{38}
+}
+
+func (m *NonceCommitMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NonceCommitMsg.Unmarshal(m, b)
+}
+func (m *NonceCommitMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NonceCommitMsg.Marshal(b, m, deterministic)
+}
+func (m *NonceCommitMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NonceCommitMsg.Merge(m, src)
+}
+func (m *NonceCommitMsg) XXX_Size() int {
+	return xxx_messageInfo_NonceCommitMsg.Size(m)
+}
+func (m *NonceCommitMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_NonceCommitMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NonceCommitMsg proto.InternalMessageInfo
+
+func (m *NonceCommitMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *NonceCommitMsg) GetSession() []byte {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *NonceCommitMsg) GetHiding() []byte {
+	if m != nil {
+		return m.Hiding
+	}
+	return nil
+}
+
+func (m *NonceCommitMsg) GetBinding() []byte {
+	if m != nil {
+		return m.Binding
+	}
+	return nil
+}
+
+func (m *NonceCommitMsg) GetKey() [][]byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+// Asks a node to sign message in a session, given the nonce commitments of all signers
+type SchnorrRequestMsg struct {
+	Session              []byte            `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Message              []byte            `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Commitments          []*NonceCommitMsg `protobuf:"bytes,3,rep,name=commitments,proto3" json:"commitments,omitempty"`
+	Signature            []byte            `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Committee            string            `protobuf:"bytes,5,opt,name=committee,proto3" json:"committee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SchnorrRequestMsg) Reset()         { *m = SchnorrRequestMsg{} }
+func (m *SchnorrRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*SchnorrRequestMsg) ProtoMessage()    {}
+func (*SchnorrRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{39}
+}
+
+func (m *SchnorrRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SchnorrRequestMsg.Unmarshal(m, b)
+}
+func (m *SchnorrRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SchnorrRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *SchnorrRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchnorrRequestMsg.Merge(m, src)
+}
+func (m *SchnorrRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_SchnorrRequestMsg.Size(m)
+}
+func (m *SchnorrRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchnorrRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SchnorrRequestMsg proto.InternalMessageInfo
+
+func (m *SchnorrRequestMsg) GetSession() []byte {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *SchnorrRequestMsg) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *SchnorrRequestMsg) GetCommitments() []*NonceCommitMsg {
+	if m != nil {
+		return m.Commitments
+	}
+	return nil
+}
+
+func (m *SchnorrRequestMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SchnorrRequestMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+// The response z of node index in a signing session
+type SchnorrShareMsg struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Session              []byte   `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Response             []byte   `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SchnorrShareMsg) Reset()         { *m = SchnorrShareMsg{} }
+func (m *SchnorrShareMsg) String() string { return proto.CompactTextString(m) }
+func (*SchnorrShareMsg) ProtoMessage()    {}
+func (*SchnorrShareMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{40}
+}
+
+func (m *SchnorrShareMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SchnorrShareMsg.Unmarshal(m, b)
+}
+func (m *SchnorrShareMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SchnorrShareMsg.Marshal(b, m, deterministic)
+}
+func (m *SchnorrShareMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchnorrShareMsg.Merge(m, src)
+}
+func (m *SchnorrShareMsg) XXX_Size() int {
+	return xxx_messageInfo_SchnorrShareMsg.Size(m)
+}
+func (m *SchnorrShareMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchnorrShareMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SchnorrShareMsg proto.InternalMessageInfo
+
+func (m *SchnorrShareMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *SchnorrShareMsg) GetSession() []byte {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *SchnorrShareMsg) GetResponse() []byte {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+// The partial evaluation H(r)^s_i of node index for the beacon of the round of a completed epoch, made with its share at the end of the epoch, and the public share it verifies under. The node signs the rest of the message.
+type BeaconShareMsg struct {
+	Index                int32           `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Epoch                int64           `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string          `protobuf:"bytes,3,opt,name=committee,proto3" json:"committee,omitempty"`
+	Partial              []byte          `protobuf:"bytes,4,opt,name=partial,proto3" json:"partial,omitempty"`
+	Share                *PublicShareMsg `protobuf:"bytes,5,opt,name=share,proto3" json:"share,omitempty"`
+	Signature            []byte          `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *BeaconShareMsg) Reset()         { *m = BeaconShareMsg{} }
+func (m *BeaconShareMsg) String() string { return proto.CompactTextString(m) }
+func (*BeaconShareMsg) ProtoMessage()    {}
+func (*BeaconShareMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{41}
+}
+
+func (m *BeaconShareMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BeaconShareMsg.Unmarshal(m, b)
+}
+func (m *BeaconShareMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BeaconShareMsg.Marshal(b, m, deterministic)
+}
+func (m *BeaconShareMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BeaconShareMsg.Merge(m, src)
+}
+func (m *BeaconShareMsg) XXX_Size() int {
+	return xxx_messageInfo_BeaconShareMsg.Size(m)
+}
+func (m *BeaconShareMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_BeaconShareMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BeaconShareMsg proto.InternalMessageInfo
+
+func (m *BeaconShareMsg) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BeaconShareMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *BeaconShareMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *BeaconShareMsg) GetPartial() []byte {
+	if m != nil {
+		return m.Partial
+	}
+	return nil
+}
+
+func (m *BeaconShareMsg) GetShare() *PublicShareMsg {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+func (m *BeaconShareMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// The beacon of the round of an epoch: the random value, its proof H(r)^s and the public key g^s the proof verifies under, combined from the partial evaluations of signers. Shares are all the partial evaluations on the bulletinboard, for anyone to combine again.
+type BeaconMsg struct {
+	Epoch                int64             `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Committee            string            `protobuf:"bytes,2,opt,name=committee,proto3" json:"committee,omitempty"`
+	Output               []byte            `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+	Proof                []byte            `protobuf:"bytes,4,opt,name=proof,proto3" json:"proof,omitempty"`
+	PublicKey            []byte            `protobuf:"bytes,5,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Signers              []int32           `protobuf:"varint,6,rep,packed,name=signers,proto3" json:"signers,omitempty"`
+	Shares               []*BeaconShareMsg `protobuf:"bytes,7,rep,name=shares,proto3" json:"shares,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BeaconMsg) Reset()         { *m = BeaconMsg{} }
+func (m *BeaconMsg) String() string { return proto.CompactTextString(m) }
+func (*BeaconMsg) ProtoMessage()    {}
+func (*BeaconMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e16ccb8c5307b32, []int{42}
+}
+
+func (m *BeaconMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BeaconMsg.Unmarshal(m, b)
+}
+func (m *BeaconMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BeaconMsg.Marshal(b, m, deterministic)
+}
+func (m *BeaconMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BeaconMsg.Merge(m, src)
+}
+func (m *BeaconMsg) XXX_Size() int {
+	return xxx_messageInfo_BeaconMsg.Size(m)
+}
+func (m *BeaconMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_BeaconMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BeaconMsg proto.InternalMessageInfo
+
+func (m *BeaconMsg) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *BeaconMsg) GetCommittee() string {
+	if m != nil {
+		return m.Committee
+	}
+	return ""
+}
+
+func (m *BeaconMsg) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *BeaconMsg) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *BeaconMsg) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *BeaconMsg) GetSigners() []int32 {
+	if m != nil {
+		return m.Signers
+	}
+	return nil
+}
+
+func (m *BeaconMsg) GetShares() []*BeaconShareMsg {
+	if m != nil {
+		return m.Shares
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("services.EpochStatusMsg_State", EpochStatusMsg_State_name, EpochStatusMsg_State_value)
+	proto.RegisterType((*EpochMsg)(nil), "services.EpochMsg")
+	proto.RegisterType((*AckMsg)(nil), "services.AckMsg")
+	proto.RegisterType((*EpochStatusMsg)(nil), "services.EpochStatusMsg")
+	proto.RegisterType((*ResendMsg)(nil), "services.ResendMsg")
+	proto.RegisterType((*Cmt1Msg)(nil), "services.Cmt1Msg")
+	proto.RegisterType((*Cmt2Msg)(nil), "services.Cmt2Msg")
+	proto.RegisterType((*PointMsg)(nil), "services.PointMsg")
+	proto.RegisterType((*ZeroMsg)(nil), "services.ZeroMsg")
+	proto.RegisterType((*EntryMsg)(nil), "services.EntryMsg")
+	proto.RegisterType((*RecordMsg)(nil), "services.RecordMsg")
+	proto.RegisterType((*InclusionMsg)(nil), "services.InclusionMsg")
+	proto.RegisterType((*AuditMsg)(nil), "services.AuditMsg")
+	proto.RegisterType((*ReadMsg)(nil), "services.ReadMsg")
+	proto.RegisterType((*VoteMsg)(nil), "services.VoteMsg")
+	proto.RegisterType((*VoteReplyMsg)(nil), "services.VoteReplyMsg")
+	proto.RegisterType((*LogEntryMsg)(nil), "services.LogEntryMsg")
+	proto.RegisterType((*AppendEntriesMsg)(nil), "services.AppendEntriesMsg")
+	proto.RegisterType((*AppendEntriesReplyMsg)(nil), "services.AppendEntriesReplyMsg")
+	proto.RegisterType((*ReplicaRecordMsg)(nil), "services.ReplicaRecordMsg")
+	proto.RegisterType((*StatusRequestMsg)(nil), "services.StatusRequestMsg")
+	proto.RegisterType((*StatusMsg)(nil), "services.StatusMsg")
+	proto.RegisterType((*ConnStatusMsg)(nil), "services.ConnStatusMsg")
+	proto.RegisterType((*NodeStatusMsg)(nil), "services.NodeStatusMsg")
+	proto.RegisterType((*PhaseTimeMsg)(nil), "services.PhaseTimeMsg")
+	proto.RegisterType((*BoardStatusMsg)(nil), "services.BoardStatusMsg")
+	proto.RegisterType((*PhaseWritersMsg)(nil), "services.PhaseWritersMsg")
+	proto.RegisterType((*DealMsg)(nil), "services.DealMsg")
+	proto.RegisterType((*DeleteMsg)(nil), "services.DeleteMsg")
+	proto.RegisterType((*DealtShareMsg)(nil), "services.DealtShareMsg")
+	proto.RegisterType((*ShareRequestMsg)(nil), "services.ShareRequestMsg")
+	proto.RegisterType((*SharesMsg)(nil), "services.SharesMsg")
+	proto.RegisterType((*SignRequestMsg)(nil), "services.SignRequestMsg")
+	proto.RegisterType((*PartialSigMsg)(nil), "services.PartialSigMsg")
+	proto.RegisterType((*PublicShareMsg)(nil), "services.PublicShareMsg")
+	proto.RegisterType((*DecryptRequestMsg)(nil), "services.DecryptRequestMsg")
+	proto.RegisterType((*PartialDecryptionMsg)(nil), "services.PartialDecryptionMsg")
+	proto.RegisterType((*DealtKeyMsg)(nil), "services.DealtKeyMsg")
+	proto.RegisterType((*NonceRequestMsg)(nil), "services.NonceRequestMsg")
+	proto.RegisterType((*NonceCommitMsg)(nil), "services.NonceCommitMsg")
+	proto.RegisterType((*SchnorrRequestMsg)(nil), "services.SchnorrRequestMsg")
+	proto.RegisterType((*SchnorrShareMsg)(nil), "services.SchnorrShareMsg")
+	proto.RegisterType((*BeaconShareMsg)(nil), "services.BeaconShareMsg")
+	proto.RegisterType((*BeaconMsg)(nil), "services.BeaconMsg")
+}
+
+func init() { proto.RegisterFile("services.proto", fileDescriptor_8e16ccb8c5307b32) }
+
+var fileDescriptor_8e16ccb8c5307b32 = []byte{
+	// 2575 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x1a, 0x5d, 0x6f, 0xdc, 0xc6,
+	0x51, 0xbc, 0x0f, 0xf2, 0x38, 0x77, 0x92, 0x95, 0xb5, 0xa3, 0x5c, 0xce, 0xad, 0xab, 0xf2, 0x49,
+	0x68, 0x11, 0xc7, 0x3a, 0x3b, 0x6d, 0xe1, 0x38, 0x40, 0x64, 0xc9, 0x49, 0x0d, 0xdb, 0xb2, 0x41,
+	0xb9, 0x2e, 0x5a, 0xa0, 0x10, 0x28, 0x72, 0x75, 0x22, 0x74, 0xc7, 0x3d, 0x2f, 0xf7, 0xe4, 0x9c,
+	0x9f, 0xfb, 0x54, 0xa0, 0x0f, 0x05, 0x0a, 0x14, 0xe8, 0x43, 0x7f, 0x44, 0x81, 0x06, 0x28, 0x50,
+	0xa0, 0x40, 0x03, 0x14, 0xc8, 0x1f, 0x08, 0xfa, 0x5c, 0xe4, 0x0f, 0xf4, 0x1f, 0x14, 0x3b, 0xbb,
+	0x3c, 0x2e, 0xa9, 0xe3, 0xe9, 0x23, 0x71, 0xde, 0x76, 0x66, 0x77, 0x76, 0xe7, 0x7b, 0x67, 0x96,
+	0x84, 0x95, 0x94, 0xf2, 0x93, 0x38, 0xa4, 0xe9, 0xcd, 0x31, 0x67, 0x82, 0x91, 0x56, 0x06, 0x7b,
+	0x2f, 0xa0, 0xf5, 0x60, 0xcc, 0xc2, 0xa3, 0x27, 0xe9, 0x80, 0x5c, 0x83, 0x26, 0x95, 0xe3, 0xae,
+	0xb5, 0x6e, 0x6d, 0xd4, 0x7d, 0x05, 0x90, 0xef, 0x81, 0x1b, 0xb2, 0xd1, 0x28, 0x16, 0x82, 0xd2,
+	0x6e, 0x6d, 0xdd, 0xda, 0x70, 0xfd, 0x1c, 0x41, 0xd6, 0xc0, 0x4e, 0x69, 0xc8, 0xa9, 0xe8, 0xd6,
+	0x71, 0x4a, 0x43, 0xde, 0x3d, 0xb0, 0xb7, 0xc2, 0xe3, 0x4b, 0xee, 0xea, 0xfd, 0xad, 0x06, 0x2b,
+	0xc8, 0xd6, 0x9e, 0x08, 0xc4, 0x24, 0xbd, 0x2c, 0x73, 0x77, 0xa0, 0x99, 0x8a, 0x40, 0x50, 0xe4,
+	0x6d, 0xa5, 0x7f, 0xe3, 0xe6, 0x4c, 0x0d, 0xc5, 0xcd, 0x6f, 0xca, 0x11, 0xf5, 0xd5, 0x62, 0x79,
+	0x52, 0x2a, 0x02, 0x2e, 0xba, 0x0d, 0x75, 0x12, 0x02, 0x64, 0x15, 0xea, 0x34, 0x89, 0xba, 0x4d,
+	0xc4, 0xc9, 0xa1, 0x5c, 0x17, 0xd1, 0x60, 0x28, 0xba, 0xf6, 0xba, 0xb5, 0xd1, 0xf2, 0x15, 0x40,
+	0xba, 0xe0, 0x44, 0x74, 0x48, 0x05, 0x8d, 0xba, 0x0e, 0xe2, 0x33, 0xd0, 0x50, 0x55, 0xcb, 0x54,
+	0x95, 0xa4, 0x50, 0xa3, 0xb4, 0xeb, 0xae, 0xd7, 0x37, 0x5c, 0x3f, 0x03, 0xbd, 0xf7, 0xa1, 0x89,
+	0x9c, 0x91, 0x36, 0x38, 0xfe, 0x2f, 0x76, 0x77, 0x1f, 0xee, 0x7e, 0xba, 0xba, 0x44, 0x96, 0xc1,
+	0xdd, 0x7e, 0xfa, 0xe4, 0xd9, 0xe3, 0x07, 0xcf, 0x1f, 0xec, 0xac, 0x5a, 0x04, 0xc0, 0xfe, 0x64,
+	0xeb, 0xe1, 0xe3, 0x07, 0x3b, 0xab, 0x35, 0xef, 0x18, 0x5c, 0x9f, 0xa6, 0x34, 0x89, 0xb4, 0xc6,
+	0xe2, 0x24, 0xa2, 0x9f, 0xa1, 0xc6, 0x9a, 0xbe, 0x02, 0x24, 0x76, 0x7c, 0x14, 0xa4, 0x4a, 0x5b,
+	0x4d, 0x5f, 0x01, 0xb9, 0x76, 0xeb, 0x95, 0xda, 0x6d, 0x94, 0x8d, 0xf4, 0x3f, 0x0b, 0x9c, 0xed,
+	0x91, 0xd8, 0xac, 0x3e, 0xab, 0x0b, 0xce, 0x98, 0x0d, 0xa7, 0xe1, 0x48, 0xe0, 0x69, 0x1d, 0x3f,
+	0x03, 0xe5, 0xce, 0x69, 0x3c, 0x48, 0x02, 0x31, 0xe1, 0xca, 0x3a, 0x1d, 0x3f, 0x47, 0xe4, 0xdc,
+	0x34, 0x2a, 0xb9, 0x69, 0x9e, 0xb6, 0xb5, 0x1b, 0x27, 0xe1, 0x70, 0x92, 0xc6, 0x2c, 0x41, 0x8b,
+	0xb4, 0xfb, 0x6b, 0xb9, 0xbd, 0x1f, 0x66, 0x53, 0x4f, 0xd2, 0x81, 0x9f, 0x2f, 0x34, 0x6c, 0xe2,
+	0x14, 0x6c, 0xb2, 0x06, 0xf6, 0x31, 0x45, 0xc6, 0x5b, 0xeb, 0xf5, 0x8d, 0x8e, 0xaf, 0x21, 0xef,
+	0x8b, 0x1a, 0xca, 0xdc, 0xaf, 0x96, 0xb9, 0x07, 0xad, 0xf4, 0x28, 0xe0, 0x34, 0x17, 0x7a, 0x06,
+	0x9b, 0xfa, 0xa8, 0x17, 0xf5, 0xb1, 0x0e, 0xed, 0xd7, 0x94, 0xb3, 0x57, 0xb1, 0x48, 0x68, 0x9a,
+	0xa2, 0xdc, 0x1d, 0xdf, 0x44, 0x15, 0x35, 0xd6, 0xac, 0xd4, 0x98, 0x5d, 0xa9, 0x31, 0x67, 0xa1,
+	0xc6, 0x5a, 0x17, 0xd7, 0x98, 0x5b, 0xd0, 0xd8, 0x2a, 0xd4, 0x8f, 0xe9, 0xb4, 0x0b, 0xa8, 0x2e,
+	0x39, 0x94, 0xd2, 0x1e, 0xd3, 0xa9, 0x94, 0xa1, 0xdb, 0x46, 0x6c, 0x06, 0x7a, 0x5f, 0x59, 0xd0,
+	0x7a, 0xc6, 0xe2, 0x44, 0x54, 0xab, 0xb1, 0x03, 0xd6, 0x67, 0xda, 0x45, 0x2d, 0x84, 0xa6, 0x5a,
+	0x65, 0x16, 0x6e, 0x5c, 0x54, 0x94, 0xf3, 0xe6, 0x94, 0x54, 0x15, 0xb4, 0xb3, 0x30, 0x72, 0x8d,
+	0x30, 0xf2, 0xfe, 0x61, 0x81, 0xf3, 0x6b, 0xca, 0xd9, 0xc2, 0xf0, 0x43, 0x77, 0xd0, 0xbe, 0xa1,
+	0x80, 0x37, 0x10, 0x0e, 0x39, 0xdf, 0x76, 0x81, 0xef, 0xeb, 0xe0, 0x1e, 0xd3, 0xe9, 0xbe, 0xe2,
+	0xc1, 0x51, 0xfe, 0x79, 0x4c, 0xa7, 0x7b, 0x12, 0xf6, 0xfe, 0x6a, 0x41, 0xeb, 0x41, 0x22, 0xf8,
+	0xb4, 0x3a, 0xe1, 0x56, 0xa6, 0x0f, 0x25, 0x6b, 0xdd, 0x94, 0x95, 0x40, 0x23, 0x0a, 0x44, 0xa0,
+	0x8d, 0x84, 0x63, 0xe9, 0x26, 0x29, 0x7d, 0x99, 0xa5, 0xd1, 0x94, 0xbe, 0x94, 0xab, 0xc6, 0x9c,
+	0x9e, 0x20, 0x9f, 0x1d, 0x1f, 0xc7, 0x12, 0x77, 0x14, 0xa4, 0x47, 0x9a, 0x41, 0x1c, 0x57, 0x59,
+	0xc2, 0x1b, 0xc8, 0x9c, 0x17, 0x32, 0x8e, 0x39, 0x6f, 0x03, 0x9a, 0x54, 0x0a, 0x80, 0x4c, 0xb7,
+	0xfb, 0xc4, 0xc8, 0xf8, 0x5a, 0x2e, 0x5f, 0x2d, 0x20, 0xb7, 0xc0, 0x4e, 0x31, 0xff, 0xa3, 0x24,
+	0xed, 0x7e, 0xb7, 0xea, 0x72, 0xf0, 0xf5, 0x3a, 0xef, 0xb7, 0x16, 0x74, 0xcc, 0xa8, 0xc8, 0x64,
+	0xb1, 0x4e, 0xcb, 0x52, 0x2b, 0xca, 0x32, 0xa4, 0xc1, 0xa1, 0x56, 0x0d, 0x8e, 0x25, 0x2e, 0x8d,
+	0x5f, 0xab, 0x9c, 0xda, 0xf4, 0x71, 0x8c, 0xb4, 0x81, 0x38, 0xea, 0x36, 0x31, 0x56, 0x70, 0x2c,
+	0x71, 0x9c, 0x31, 0x91, 0xe9, 0x46, 0x8e, 0xbd, 0x7f, 0x5a, 0xd0, 0xda, 0x9a, 0x44, 0xb1, 0xb8,
+	0xec, 0xad, 0xb8, 0x09, 0xd7, 0xc6, 0x9c, 0x05, 0xa1, 0x88, 0x4f, 0xe2, 0xd7, 0x81, 0x88, 0x59,
+	0xb2, 0x8f, 0x87, 0x28, 0xbf, 0xbb, 0x5a, 0x9a, 0xf3, 0x19, 0x13, 0x33, 0x3e, 0x1a, 0x39, 0x1f,
+	0xf3, 0x2d, 0x79, 0x44, 0x83, 0x28, 0xe3, 0x56, 0x8e, 0x67, 0x92, 0x3a, 0xb9, 0xa4, 0xde, 0x07,
+	0xe0, 0xf8, 0x34, 0x88, 0x2e, 0xe8, 0x64, 0xde, 0x2b, 0x70, 0x5e, 0x30, 0x41, 0x25, 0x19, 0x81,
+	0x86, 0xa0, 0x7c, 0xa4, 0xa9, 0x70, 0x8c, 0x42, 0x07, 0x49, 0x14, 0x47, 0x81, 0xc8, 0x08, 0x73,
+	0x04, 0xf9, 0x3e, 0xc0, 0x30, 0x48, 0xc5, 0x7e, 0xee, 0xa6, 0x75, 0xdf, 0x95, 0x98, 0x87, 0x12,
+	0x21, 0xc3, 0x02, 0xa7, 0x71, 0x57, 0x15, 0x66, 0x2d, 0x89, 0x78, 0x4e, 0xf9, 0xc8, 0xbb, 0x07,
+	0x1d, 0x79, 0xb0, 0x4f, 0xc7, 0xc3, 0x69, 0xd5, 0xe9, 0x5d, 0x70, 0x06, 0x3c, 0x48, 0xe4, 0xb5,
+	0x5f, 0x53, 0xd7, 0xbe, 0x06, 0xbd, 0x47, 0xd0, 0x7e, 0xcc, 0x06, 0xb3, 0xb0, 0x9a, 0x47, 0x3c,
+	0xf3, 0xda, 0xda, 0x19, 0x5e, 0xeb, 0x7d, 0x61, 0xc1, 0xea, 0xd6, 0x78, 0x4c, 0x93, 0x48, 0xce,
+	0xc4, 0x34, 0xad, 0xda, 0x72, 0x0d, 0xec, 0x21, 0x0d, 0x22, 0xca, 0xb5, 0x2a, 0x34, 0x24, 0xf5,
+	0x20, 0xbd, 0xb2, 0xa8, 0x07, 0x89, 0x99, 0xe9, 0x01, 0xa7, 0x4d, 0x3d, 0x48, 0x84, 0xd4, 0x03,
+	0x79, 0x1f, 0x1c, 0xaa, 0x4e, 0x45, 0x27, 0x6d, 0xf7, 0xdf, 0xce, 0x19, 0x35, 0x44, 0xf4, 0xb3,
+	0x55, 0x92, 0x09, 0xe5, 0x76, 0x3a, 0xe3, 0x6a, 0xc8, 0xfb, 0x15, 0xbc, 0x5d, 0x10, 0xe2, 0x2c,
+	0xcd, 0xa6, 0x93, 0x30, 0xa4, 0x69, 0x9a, 0x69, 0x56, 0x83, 0x18, 0x59, 0x41, 0x2a, 0xb4, 0x14,
+	0x38, 0xf6, 0x7e, 0x67, 0xc1, 0xaa, 0xdc, 0x2e, 0x0e, 0x83, 0x3c, 0x2b, 0xcc, 0xdb, 0xf6, 0x3a,
+	0xb8, 0x27, 0x4c, 0xd0, 0x68, 0xff, 0x90, 0x65, 0x3a, 0x6a, 0x21, 0xe2, 0x13, 0xc6, 0x8b, 0xf9,
+	0xac, 0x9e, 0xe5, 0x33, 0x43, 0xfe, 0xc6, 0x79, 0xe4, 0xf7, 0x08, 0xac, 0xaa, 0x34, 0xe2, 0xd3,
+	0x97, 0x13, 0x9a, 0xca, 0x88, 0xf5, 0xbe, 0xae, 0x81, 0x5b, 0xa8, 0x6a, 0x87, 0xc1, 0x01, 0x1d,
+	0x66, 0x97, 0x04, 0x02, 0x67, 0xc4, 0x6f, 0x17, 0x9c, 0x90, 0x4d, 0x12, 0x41, 0xb9, 0xce, 0x29,
+	0x19, 0x28, 0xf5, 0x1d, 0xd1, 0x01, 0xa7, 0x59, 0x62, 0xd1, 0x50, 0x1e, 0x65, 0xcd, 0xd3, 0x59,
+	0x62, 0xac, 0x6a, 0x55, 0x65, 0xa0, 0x1c, 0x41, 0xde, 0x83, 0x66, 0xc8, 0x92, 0x24, 0xed, 0x3a,
+	0x28, 0xea, 0x3b, 0xb9, 0xa8, 0xdb, 0x2c, 0x49, 0xf2, 0xec, 0xa8, 0x56, 0x91, 0x1f, 0x43, 0x23,
+	0x61, 0x11, 0xd5, 0x75, 0x84, 0xb1, 0x7a, 0x97, 0x45, 0x34, 0x5f, 0x8d, 0x8b, 0xc8, 0x4d, 0x68,
+	0x1e, 0xb0, 0x80, 0x47, 0x5d, 0xb7, 0x9c, 0x7a, 0xef, 0x4b, 0xb4, 0xb1, 0x39, 0x2e, 0x93, 0xfc,
+	0x73, 0x1a, 0x44, 0xb2, 0xba, 0xc0, 0x4a, 0x1b, 0x01, 0x69, 0xc1, 0x84, 0x89, 0x7d, 0x35, 0xd3,
+	0x46, 0x2d, 0xb5, 0x12, 0x26, 0x64, 0x6a, 0x99, 0xca, 0xab, 0x6c, 0xb9, 0xc0, 0x28, 0xe6, 0x57,
+	0x4a, 0x39, 0x6a, 0xda, 0xf5, 0x71, 0x2c, 0x55, 0x19, 0x44, 0x11, 0xcf, 0x7c, 0xcb, 0xf5, 0x33,
+	0x90, 0x5c, 0x33, 0x5b, 0x07, 0x37, 0x6b, 0x0d, 0x7a, 0xd0, 0x3a, 0x0c, 0xe2, 0xe1, 0x84, 0xd3,
+	0x54, 0xab, 0x78, 0x06, 0xe3, 0x6d, 0xc7, 0x5e, 0x25, 0xa8, 0xe3, 0x96, 0x8f, 0x63, 0x54, 0x3c,
+	0xe7, 0x8c, 0xeb, 0x4b, 0x58, 0x01, 0xe4, 0x1d, 0x70, 0x30, 0xd9, 0xb0, 0x63, 0x4c, 0x8b, 0x75,
+	0xdf, 0x96, 0xe0, 0xd3, 0x63, 0xef, 0x4f, 0x35, 0x58, 0x2e, 0xe8, 0x2b, 0xcf, 0x84, 0x8a, 0x6b,
+	0x05, 0x90, 0x1b, 0x00, 0x9c, 0x86, 0xec, 0x84, 0xf2, 0x38, 0x19, 0xe8, 0xa8, 0x30, 0x30, 0xf2,
+	0x00, 0x4e, 0xc3, 0xfd, 0x30, 0x11, 0xda, 0x43, 0x6c, 0x4e, 0xc3, 0xed, 0x44, 0x90, 0x77, 0xa1,
+	0x25, 0x0b, 0x30, 0x9c, 0x51, 0xfc, 0x3b, 0x12, 0x96, 0x53, 0xd7, 0xc1, 0xc5, 0xa2, 0x00, 0xe7,
+	0x9a, 0x4a, 0x36, 0x44, 0xc8, 0x49, 0x59, 0x71, 0x05, 0xb1, 0x90, 0xa7, 0xd9, 0xeb, 0x75, 0x49,
+	0xa6, 0x41, 0x4c, 0x18, 0x6c, 0x38, 0xdd, 0x0f, 0x47, 0x42, 0xb9, 0x4a, 0xc7, 0x6f, 0x49, 0xc4,
+	0xf6, 0x48, 0xa4, 0xe4, 0x16, 0x38, 0x22, 0x1e, 0xc5, 0xc9, 0x20, 0xc5, 0x32, 0xba, 0x50, 0x5f,
+	0x3e, 0x93, 0x92, 0x3c, 0x8f, 0x47, 0x14, 0x23, 0x46, 0x2f, 0x93, 0x1e, 0x7c, 0x18, 0x4c, 0x86,
+	0xd8, 0x0a, 0x21, 0xe3, 0x0a, 0xf2, 0x7e, 0x06, 0x1d, 0x93, 0xa0, 0x42, 0x2f, 0x32, 0xce, 0x19,
+	0x3b, 0xee, 0xd6, 0x74, 0x9c, 0x33, 0x76, 0xec, 0x4d, 0x60, 0xa5, 0xe8, 0x54, 0xc6, 0xcd, 0x6f,
+	0x9d, 0xef, 0xe6, 0x27, 0x9b, 0x60, 0xe3, 0x01, 0xd2, 0x4b, 0xa4, 0x18, 0xef, 0x96, 0xc4, 0xf8,
+	0x25, 0x8f, 0x05, 0xe5, 0x8a, 0x44, 0x2d, 0xf4, 0xb6, 0xe0, 0x4a, 0x69, 0xaa, 0xc8, 0xf3, 0xac,
+	0x74, 0x92, 0xaa, 0xe5, 0x32, 0xb0, 0x93, 0x6e, 0x4d, 0xab, 0x56, 0x81, 0xde, 0xef, 0x2d, 0x70,
+	0x76, 0x68, 0x30, 0xbc, 0xec, 0x3d, 0x5f, 0xdd, 0x6d, 0x14, 0xca, 0xcd, 0x46, 0xb9, 0xdc, 0xcc,
+	0x0b, 0xad, 0x66, 0xa1, 0xd0, 0x9a, 0x80, 0xbb, 0x83, 0xad, 0xac, 0x64, 0x28, 0x5f, 0x64, 0x95,
+	0xeb, 0x62, 0xc5, 0x68, 0xad, 0x92, 0xd1, 0x7a, 0x99, 0xd1, 0x85, 0xec, 0x78, 0x5f, 0x5a, 0xb0,
+	0x2c, 0xd5, 0x20, 0xb0, 0x46, 0x95, 0x67, 0x63, 0x6f, 0x60, 0x15, 0x7a, 0x83, 0xda, 0x9c, 0xde,
+	0xa0, 0x5e, 0xec, 0x0d, 0x0c, 0x75, 0x34, 0x16, 0xa8, 0xe3, 0x4d, 0x76, 0x0d, 0xde, 0x1f, 0x2c,
+	0xb8, 0x82, 0x62, 0xe4, 0x17, 0x42, 0x45, 0x9f, 0x50, 0xe0, 0xa9, 0x56, 0xc9, 0xd3, 0xf9, 0xdb,
+	0xf5, 0x4a, 0xb3, 0x7e, 0x6e, 0x81, 0x8b, 0x3c, 0xa5, 0xd5, 0xdc, 0xfc, 0x08, 0x6c, 0xcc, 0x05,
+	0x59, 0x00, 0x18, 0x15, 0x4a, 0xd6, 0xc7, 0xf9, 0x7a, 0x45, 0x31, 0x23, 0xd4, 0x4b, 0x19, 0xe1,
+	0x5b, 0x6c, 0x65, 0xbc, 0x3f, 0x5a, 0xb0, 0xb2, 0x17, 0x0f, 0x12, 0x43, 0x97, 0x5d, 0x70, 0x46,
+	0x34, 0x4d, 0x83, 0x81, 0x0a, 0xb2, 0x8e, 0x9f, 0x81, 0xdf, 0xa1, 0x3e, 0xff, 0x6b, 0xc1, 0xf2,
+	0xb3, 0x80, 0x8b, 0x38, 0x18, 0xee, 0xc5, 0x83, 0xc5, 0x8f, 0x23, 0x6a, 0xd9, 0xec, 0x71, 0x44,
+	0x81, 0xe4, 0x87, 0xd0, 0x19, 0x4f, 0x0e, 0x86, 0x71, 0xa8, 0xdb, 0x34, 0xe5, 0xc8, 0x6d, 0x85,
+	0x43, 0x53, 0x21, 0xc3, 0x27, 0xc1, 0x50, 0x15, 0x22, 0x1d, 0x5f, 0x01, 0x92, 0x61, 0xed, 0xed,
+	0xba, 0x44, 0xeb, 0xf8, 0x39, 0xe2, 0x5b, 0x75, 0xe4, 0x2f, 0x2d, 0x58, 0x79, 0x96, 0xf3, 0x53,
+	0x2d, 0x65, 0x59, 0x96, 0xda, 0x02, 0x59, 0xea, 0x95, 0xb2, 0x34, 0x2a, 0x65, 0x69, 0x56, 0xca,
+	0x62, 0x57, 0xcb, 0x52, 0x78, 0xeb, 0x91, 0x79, 0xf6, 0xad, 0x1d, 0x1a, 0xf2, 0xe9, 0x58, 0x18,
+	0xae, 0xb4, 0x02, 0xb5, 0x70, 0x53, 0x7b, 0x51, 0x2d, 0xdc, 0xfc, 0x0e, 0x1d, 0xe8, 0xcf, 0x16,
+	0x5c, 0xd3, 0x0e, 0xa4, 0xd9, 0xd2, 0xfd, 0xe6, 0xcd, 0xec, 0xed, 0xe0, 0xd4, 0xbd, 0x55, 0x34,
+	0x45, 0xf6, 0xaa, 0x50, 0xed, 0x61, 0x92, 0xb1, 0xa3, 0x60, 0x38, 0xa4, 0xc9, 0x60, 0xf6, 0xde,
+	0x30, 0x43, 0xc8, 0x2a, 0x87, 0xd3, 0x74, 0xcc, 0x92, 0x34, 0x4b, 0xc7, 0x33, 0x58, 0x2a, 0xab,
+	0x8d, 0xd9, 0xf8, 0x11, 0x9d, 0x9e, 0xce, 0xc5, 0xf3, 0x5f, 0x37, 0x6e, 0x00, 0x28, 0xa9, 0x47,
+	0x14, 0x2b, 0x12, 0x69, 0x4b, 0x03, 0x73, 0xc6, 0x75, 0xb4, 0x30, 0x39, 0x78, 0x03, 0xb8, 0xb2,
+	0xcb, 0x92, 0x90, 0x16, 0x93, 0x40, 0x4a, 0x53, 0x7c, 0xd5, 0xd2, 0x49, 0x40, 0x83, 0x67, 0xd8,
+	0x70, 0xe1, 0x25, 0x25, 0xbb, 0xff, 0x15, 0x3c, 0x69, 0x1b, 0x51, 0x0b, 0xe3, 0x3a, 0x3b, 0xbe,
+	0x56, 0x3c, 0x7e, 0x0d, 0xec, 0xa3, 0x38, 0x92, 0x45, 0x94, 0x52, 0xb9, 0x86, 0x24, 0xc5, 0x41,
+	0x9c, 0xe0, 0x84, 0xbe, 0x99, 0x34, 0x98, 0x3d, 0xaa, 0x35, 0x67, 0x8f, 0x6a, 0xb2, 0xfb, 0x7f,
+	0x6b, 0x2f, 0x3c, 0x4a, 0x18, 0xe7, 0xe7, 0x12, 0xd9, 0xc8, 0x88, 0xb5, 0x62, 0x46, 0xbc, 0x0b,
+	0xed, 0xdc, 0x06, 0x2a, 0xf8, 0x0a, 0x3e, 0x55, 0x14, 0xd6, 0x37, 0x17, 0x7f, 0x23, 0x8b, 0xfd,
+	0x06, 0xae, 0x68, 0x01, 0xce, 0x48, 0x1d, 0xd5, 0x8a, 0x34, 0x1d, 0xb4, 0x5e, 0x72, 0xd0, 0x7f,
+	0x59, 0xb0, 0x72, 0x9f, 0x06, 0x21, 0x4b, 0xce, 0xd8, 0xfe, 0x32, 0x95, 0x8a, 0x11, 0x51, 0x8d,
+	0x62, 0x44, 0xcd, 0x62, 0xb3, 0x79, 0xbe, 0xd8, 0x2c, 0x68, 0xd0, 0x2e, 0xd7, 0x3c, 0xff, 0xb1,
+	0xc0, 0x55, 0x42, 0x7c, 0x83, 0xef, 0x32, 0x6c, 0x22, 0xc6, 0x93, 0xac, 0xf6, 0xd3, 0x10, 0x16,
+	0xa1, 0x9c, 0xb1, 0x43, 0xcd, 0xbf, 0x02, 0xf0, 0x55, 0x40, 0x65, 0x69, 0xe5, 0x6e, 0xc8, 0x8e,
+	0xc2, 0x3c, 0x52, 0x2f, 0xb9, 0x92, 0x37, 0xca, 0xd3, 0xac, 0xfc, 0xd7, 0x20, 0xd6, 0xd2, 0xaa,
+	0x30, 0x70, 0xca, 0xfe, 0x53, 0x34, 0x42, 0x56, 0x1e, 0xf4, 0xbf, 0xb6, 0xe1, 0xda, 0xfd, 0xc9,
+	0x70, 0x48, 0x45, 0x9c, 0xa8, 0xc2, 0x5c, 0x11, 0x90, 0x3b, 0x00, 0x7b, 0x22, 0xe0, 0x02, 0x6b,
+	0x70, 0x42, 0x4a, 0x45, 0xf9, 0x93, 0x74, 0xd0, 0x5b, 0xcd, 0x71, 0xea, 0xdb, 0x92, 0xb7, 0x44,
+	0x7e, 0x0a, 0x20, 0x1b, 0x3e, 0xac, 0xb5, 0x37, 0xe7, 0x52, 0xbd, 0x65, 0x74, 0xae, 0xea, 0x6b,
+	0x85, 0xb7, 0x74, 0xcb, 0x22, 0x77, 0xa0, 0x8d, 0xb5, 0x39, 0x52, 0xf6, 0x49, 0x71, 0x55, 0xff,
+	0x3c, 0xc7, 0xf5, 0xcf, 0x71, 0x5c, 0x7f, 0xee, 0x71, 0xb7, 0xc9, 0x69, 0xa6, 0xce, 0x3c, 0xee,
+	0xf6, 0x45, 0xa4, 0xfb, 0x08, 0xda, 0x46, 0x2f, 0x33, 0x97, 0xb2, 0xb2, 0xed, 0xf1, 0x96, 0xc8,
+	0xc7, 0xd0, 0x41, 0xdc, 0xcf, 0xe3, 0x54, 0x30, 0x3e, 0xbd, 0x28, 0xfd, 0x2d, 0x8b, 0x6c, 0x42,
+	0x13, 0x1f, 0x29, 0xe7, 0x92, 0x1a, 0xb8, 0xec, 0x25, 0xd3, 0x5b, 0x22, 0xfa, 0x59, 0xf0, 0x31,
+	0x1b, 0x9c, 0x45, 0x94, 0xbd, 0xb4, 0xe0, 0x49, 0xf7, 0xe0, 0x8a, 0x24, 0xdb, 0x36, 0xd2, 0xd3,
+	0x05, 0x14, 0xf5, 0x1e, 0x34, 0xe4, 0x75, 0x66, 0x1a, 0x44, 0xf7, 0x5c, 0x73, 0x0d, 0xb2, 0x09,
+	0xb6, 0xea, 0x81, 0xc8, 0x55, 0x93, 0x40, 0x77, 0x45, 0x73, 0x49, 0x3e, 0xd4, 0x96, 0x57, 0xf1,
+	0x40, 0x2a, 0x23, 0x64, 0x91, 0x03, 0x68, 0xda, 0x79, 0x72, 0x5d, 0x2d, 0xef, 0x87, 0x84, 0xfd,
+	0x7f, 0xd7, 0xa1, 0x8d, 0x4f, 0x09, 0x6a, 0x92, 0x7c, 0x00, 0x6d, 0x8c, 0xae, 0x05, 0x81, 0x32,
+	0xef, 0x7c, 0x49, 0x26, 0xf9, 0x3b, 0x4d, 0x96, 0xd5, 0xfd, 0x73, 0xc9, 0xee, 0x98, 0x64, 0x85,
+	0xe0, 0xd2, 0x5f, 0x47, 0xe6, 0x52, 0xdd, 0xc5, 0xe7, 0x32, 0x2e, 0x5e, 0x50, 0x1e, 0x1f, 0x2e,
+	0x08, 0xb1, 0x33, 0x19, 0xbd, 0x7d, 0x6e, 0x46, 0x4f, 0x1f, 0x79, 0xfb, 0xdc, 0x47, 0x6e, 0x82,
+	0xad, 0x3e, 0xb6, 0x9a, 0xbe, 0x30, 0xfb, 0xfc, 0x5a, 0x41, 0x72, 0xd1, 0xa8, 0xe8, 0x3f, 0x82,
+	0xce, 0x56, 0x34, 0x8a, 0x93, 0xcc, 0x90, 0x1f, 0x82, 0xad, 0x83, 0xba, 0x97, 0xaf, 0x2f, 0xbf,
+	0x32, 0xf6, 0xae, 0x96, 0xe7, 0xd4, 0x66, 0x9f, 0x5b, 0xb0, 0xbc, 0x87, 0x55, 0x66, 0x9e, 0x75,
+	0x9b, 0x7b, 0x82, 0x71, 0x4a, 0xde, 0x29, 0x06, 0x80, 0x58, 0xe8, 0x96, 0xf7, 0xa0, 0xe5, 0x53,
+	0xc1, 0x63, 0x7a, 0x42, 0x89, 0xf1, 0x18, 0x52, 0x6a, 0x6d, 0x7b, 0x57, 0x4b, 0x53, 0xe9, 0x65,
+	0x83, 0xa8, 0xff, 0x14, 0xda, 0xb2, 0xd5, 0xcb, 0xb8, 0xfe, 0x18, 0x5c, 0x04, 0x55, 0x99, 0x6b,
+	0x9c, 0x52, 0x68, 0x07, 0x7b, 0x86, 0x4c, 0x85, 0x8e, 0xcc, 0x5b, 0xea, 0xff, 0xc5, 0x82, 0x15,
+	0x5d, 0x5d, 0x67, 0x9b, 0x7e, 0x04, 0x6d, 0xe3, 0xae, 0x3e, 0x2b, 0xe7, 0x15, 0xaf, 0x75, 0x6f,
+	0x89, 0x3c, 0x81, 0x4e, 0xb6, 0x21, 0xd2, 0x5f, 0x37, 0x65, 0x2b, 0x75, 0x17, 0xbd, 0x1b, 0xa7,
+	0x38, 0x2b, 0x94, 0xfa, 0xde, 0x52, 0xff, 0x2b, 0xd9, 0xdd, 0xea, 0x3a, 0x69, 0x16, 0xc3, 0x2d,
+	0xb4, 0x95, 0xbc, 0x92, 0xdf, 0x2e, 0x99, 0x4b, 0x95, 0xe3, 0x73, 0x8d, 0xb5, 0x03, 0x6d, 0x95,
+	0x1c, 0xb1, 0xa2, 0x33, 0xed, 0x55, 0xaa, 0x9c, 0x7b, 0x95, 0xd5, 0x9f, 0xb7, 0x44, 0x3e, 0xd5,
+	0x16, 0x50, 0x2c, 0x99, 0xd2, 0x9d, 0x2a, 0x47, 0x7b, 0xef, 0x9e, 0x9a, 0xcc, 0xf5, 0xd4, 0xff,
+	0x7b, 0x0d, 0x56, 0xf4, 0x0b, 0x7d, 0x26, 0xd8, 0x5d, 0x68, 0x6b, 0x6a, 0xf9, 0x9d, 0xc5, 0x4c,
+	0x17, 0xfa, 0x83, 0x4f, 0x6f, 0xad, 0x88, 0xca, 0x3e, 0x18, 0x78, 0x4b, 0x64, 0x17, 0x96, 0x0b,
+	0xdf, 0x12, 0xcc, 0xb0, 0x28, 0x7f, 0x29, 0xe9, 0xfd, 0xa0, 0x62, 0xce, 0xd8, 0xef, 0x16, 0xd8,
+	0x6a, 0x8a, 0xcc, 0xb9, 0x70, 0x2a, 0x82, 0xba, 0x21, 0x73, 0xb4, 0xc9, 0xb6, 0xfe, 0xbc, 0x55,
+	0x79, 0x67, 0xfd, 0x04, 0xdc, 0xbd, 0xc9, 0x41, 0x1a, 0xf2, 0xf8, 0x80, 0x5e, 0x80, 0xee, 0xc0,
+	0xc6, 0xdf, 0x77, 0x6e, 0xff, 0x7f, 0x00, 0xe5, 0xc3, 0x5d, 0xcb, 0xd0, 0x23, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BulletinBoardServiceClient is the client API for BulletinBoardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BulletinBoardServiceClient interface {
+	// Start a epoch
+	StartEpoch(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// BulletinBoard RPC for recontruction phase
+	ReadPhase1(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase1Client, error)
+	// BulletinBoard RPC for proactivization phase
+	WritePhase2(ctx context.Context, in *Cmt2Msg, opts ...grpc.CallOption) (*AckMsg, error)
+	ReadPhase2(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase2Client, error)
+	// BulletinBoard RPC for share distribution phase
+	WritePhase3(ctx context.Context, in *Cmt1Msg, opts ...grpc.CallOption) (*AckMsg, error)
+	ReadPhase3(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase3Client, error)
+	// BulletinBoard RPC for the clock to follow the progress of epochs
+	EpochStatus(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*EpochStatusMsg, error)
+	EpochHistory(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_EpochHistoryClient, error)
+	// BulletinBoard RPC for auditors to check the log of an epoch
+	Audit(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AuditMsg, error)
+	ReadLog(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadLogClient, error)
+	// BulletinBoard RPC for nodes joining the committee to learn the commitments a completed epoch ended with
+	ReadCommitments(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadCommitmentsClient, error)
+	// BulletinBoard RPC for the operator to record the commitment to a secret it deals as an epoch of its own
+	Deal(ctx context.Context, in *DealMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// BulletinBoard RPC for the operator to record that the committee drops a secret, again as an epoch of its own
+	Delete(ctx context.Context, in *DeleteMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// BulletinBoard RPC for the randomness beacon, whose rounds are the completed epochs
+	WriteBeacon(ctx context.Context, in *BeaconShareMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	ReadBeacon(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*BeaconMsg, error)
+}
+
+type bulletinBoardServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBulletinBoardServiceClient(cc *grpc.ClientConn) BulletinBoardServiceClient {
+	return &bulletinBoardServiceClient{cc}
+}
+
+func (c *bulletinBoardServiceClient) StartEpoch(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/StartEpoch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadPhase1(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[0], "/services.BulletinBoardService/ReadPhase1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceReadPhase1Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_ReadPhase1Client interface {
+	Recv() (*Cmt1Msg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceReadPhase1Client struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceReadPhase1Client) Recv() (*Cmt1Msg, error) {
+	m := new(Cmt1Msg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) WritePhase2(ctx context.Context, in *Cmt2Msg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/WritePhase2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadPhase2(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase2Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[1], "/services.BulletinBoardService/ReadPhase2", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceReadPhase2Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_ReadPhase2Client interface {
+	Recv() (*Cmt2Msg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceReadPhase2Client struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceReadPhase2Client) Recv() (*Cmt2Msg, error) {
+	m := new(Cmt2Msg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) WritePhase3(ctx context.Context, in *Cmt1Msg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/WritePhase3", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadPhase3(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase3Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[2], "/services.BulletinBoardService/ReadPhase3", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceReadPhase3Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_ReadPhase3Client interface {
+	Recv() (*Cmt1Msg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceReadPhase3Client struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceReadPhase3Client) Recv() (*Cmt1Msg, error) {
+	m := new(Cmt1Msg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) EpochStatus(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*EpochStatusMsg, error) {
+	out := new(EpochStatusMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/EpochStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) EpochHistory(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_EpochHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[3], "/services.BulletinBoardService/EpochHistory", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceEpochHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_EpochHistoryClient interface {
+	Recv() (*EpochStatusMsg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceEpochHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceEpochHistoryClient) Recv() (*EpochStatusMsg, error) {
+	m := new(EpochStatusMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) Audit(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AuditMsg, error) {
+	out := new(AuditMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/Audit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadLog(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[4], "/services.BulletinBoardService/ReadLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceReadLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_ReadLogClient interface {
+	Recv() (*EntryMsg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceReadLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceReadLogClient) Recv() (*EntryMsg, error) {
+	m := new(EntryMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadCommitments(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadCommitmentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[5], "/services.BulletinBoardService/ReadCommitments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bulletinBoardServiceReadCommitmentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BulletinBoardService_ReadCommitmentsClient interface {
+	Recv() (*Cmt1Msg, error)
+	grpc.ClientStream
+}
+
+type bulletinBoardServiceReadCommitmentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bulletinBoardServiceReadCommitmentsClient) Recv() (*Cmt1Msg, error) {
+	m := new(Cmt1Msg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bulletinBoardServiceClient) Deal(ctx context.Context, in *DealMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/Deal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) Delete(ctx context.Context, in *DeleteMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) WriteBeacon(ctx context.Context, in *BeaconShareMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/WriteBeacon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulletinBoardServiceClient) ReadBeacon(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*BeaconMsg, error) {
+	out := new(BeaconMsg)
+	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/ReadBeacon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BulletinBoardServiceServer is the server API for BulletinBoardService service.
+type BulletinBoardServiceServer interface {
+	// Start a epoch
+	StartEpoch(context.Context, *EpochMsg) (*AckMsg, error)
+	// BulletinBoard RPC for recontruction phase
+	ReadPhase1(*EpochMsg, BulletinBoardService_ReadPhase1Server) error
 	// BulletinBoard RPC for proactivization phase
-	WritePhase2(ctx context.Context, in *Cmt2Msg, opts ...grpc.CallOption) (*AckMsg, error)
-	ReadPhase2(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase2Client, error)
+	WritePhase2(context.Context, *Cmt2Msg) (*AckMsg, error)
+	ReadPhase2(*EpochMsg, BulletinBoardService_ReadPhase2Server) error
 	// BulletinBoard RPC for share distribution phase
-	WritePhase3(ctx context.Context, in *Cmt1Msg, opts ...grpc.CallOption) (*AckMsg, error)
-	ReadPhase3(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase3Client, error)
+	WritePhase3(context.Context, *Cmt1Msg) (*AckMsg, error)
+	ReadPhase3(*EpochMsg, BulletinBoardService_ReadPhase3Server) error
+	// BulletinBoard RPC for the clock to follow the progress of epochs
+	EpochStatus(context.Context, *EpochMsg) (*EpochStatusMsg, error)
+	EpochHistory(*EpochMsg, BulletinBoardService_EpochHistoryServer) error
+	// BulletinBoard RPC for auditors to check the log of an epoch
+	Audit(context.Context, *EpochMsg) (*AuditMsg, error)
+	ReadLog(*EpochMsg, BulletinBoardService_ReadLogServer) error
+	// BulletinBoard RPC for nodes joining the committee to learn the commitments a completed epoch ended with
+	ReadCommitments(*EpochMsg, BulletinBoardService_ReadCommitmentsServer) error
+	// BulletinBoard RPC for the operator to record the commitment to a secret it deals as an epoch of its own
+	Deal(context.Context, *DealMsg) (*AckMsg, error)
+	// BulletinBoard RPC for the operator to record that the committee drops a secret, again as an epoch of its own
+	Delete(context.Context, *DeleteMsg) (*AckMsg, error)
+	// BulletinBoard RPC for the randomness beacon, whose rounds are the completed epochs
+	WriteBeacon(context.Context, *BeaconShareMsg) (*AckMsg, error)
+	ReadBeacon(context.Context, *EpochMsg) (*BeaconMsg, error)
+}
+
+func RegisterBulletinBoardServiceServer(s *grpc.Server, srv BulletinBoardServiceServer) {
+	s.RegisterService(&_BulletinBoardService_serviceDesc, srv)
+}
+
+func _BulletinBoardService_StartEpoch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).StartEpoch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/StartEpoch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).StartEpoch(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_ReadPhase1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).ReadPhase1(m, &bulletinBoardServiceReadPhase1Server{stream})
+}
+
+type BulletinBoardService_ReadPhase1Server interface {
+	Send(*Cmt1Msg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceReadPhase1Server struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceReadPhase1Server) Send(m *Cmt1Msg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_WritePhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Cmt2Msg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).WritePhase2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/WritePhase2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).WritePhase2(ctx, req.(*Cmt2Msg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_ReadPhase2_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).ReadPhase2(m, &bulletinBoardServiceReadPhase2Server{stream})
+}
+
+type BulletinBoardService_ReadPhase2Server interface {
+	Send(*Cmt2Msg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceReadPhase2Server struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceReadPhase2Server) Send(m *Cmt2Msg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_WritePhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Cmt1Msg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).WritePhase3(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/WritePhase3",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).WritePhase3(ctx, req.(*Cmt1Msg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_ReadPhase3_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).ReadPhase3(m, &bulletinBoardServiceReadPhase3Server{stream})
+}
+
+type BulletinBoardService_ReadPhase3Server interface {
+	Send(*Cmt1Msg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceReadPhase3Server struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceReadPhase3Server) Send(m *Cmt1Msg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_EpochStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).EpochStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/EpochStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).EpochStatus(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_EpochHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).EpochHistory(m, &bulletinBoardServiceEpochHistoryServer{stream})
+}
+
+type BulletinBoardService_EpochHistoryServer interface {
+	Send(*EpochStatusMsg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceEpochHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceEpochHistoryServer) Send(m *EpochStatusMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_Audit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).Audit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/Audit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).Audit(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_ReadLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).ReadLog(m, &bulletinBoardServiceReadLogServer{stream})
+}
+
+type BulletinBoardService_ReadLogServer interface {
+	Send(*EntryMsg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceReadLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceReadLogServer) Send(m *EntryMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_ReadCommitments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EpochMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BulletinBoardServiceServer).ReadCommitments(m, &bulletinBoardServiceReadCommitmentsServer{stream})
+}
+
+type BulletinBoardService_ReadCommitmentsServer interface {
+	Send(*Cmt1Msg) error
+	grpc.ServerStream
+}
+
+type bulletinBoardServiceReadCommitmentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bulletinBoardServiceReadCommitmentsServer) Send(m *Cmt1Msg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BulletinBoardService_Deal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DealMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).Deal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/Deal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).Deal(ctx, req.(*DealMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).Delete(ctx, req.(*DeleteMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_WriteBeacon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeaconShareMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).WriteBeacon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/WriteBeacon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).WriteBeacon(ctx, req.(*BeaconShareMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulletinBoardService_ReadBeacon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulletinBoardServiceServer).ReadBeacon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.BulletinBoardService/ReadBeacon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulletinBoardServiceServer).ReadBeacon(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BulletinBoardService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.BulletinBoardService",
+	HandlerType: (*BulletinBoardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartEpoch",
+			Handler:    _BulletinBoardService_StartEpoch_Handler,
+		},
+		{
+			MethodName: "WritePhase2",
+			Handler:    _BulletinBoardService_WritePhase2_Handler,
+		},
+		{
+			MethodName: "WritePhase3",
+			Handler:    _BulletinBoardService_WritePhase3_Handler,
+		},
+		{
+			MethodName: "EpochStatus",
+			Handler:    _BulletinBoardService_EpochStatus_Handler,
+		},
+		{
+			MethodName: "Audit",
+			Handler:    _BulletinBoardService_Audit_Handler,
+		},
+		{
+			MethodName: "Deal",
+			Handler:    _BulletinBoardService_Deal_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _BulletinBoardService_Delete_Handler,
+		},
+		{
+			MethodName: "WriteBeacon",
+			Handler:    _BulletinBoardService_WriteBeacon_Handler,
+		},
+		{
+			MethodName: "ReadBeacon",
+			Handler:    _BulletinBoardService_ReadBeacon_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadPhase1",
+			Handler:       _BulletinBoardService_ReadPhase1_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadPhase2",
+			Handler:       _BulletinBoardService_ReadPhase2_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadPhase3",
+			Handler:       _BulletinBoardService_ReadPhase3_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "EpochHistory",
+			Handler:       _BulletinBoardService_EpochHistory_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadLog",
+			Handler:       _BulletinBoardService_ReadLog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadCommitments",
+			Handler:       _BulletinBoardService_ReadCommitments_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "services.proto",
+}
+
+// NodeServiceClient is the client API for NodeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NodeServiceClient interface {
+	// Node RPC for reconstruction phase
+	StartPhase1(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	SharePhase1(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// Node RPC for proactivization phase
+	SharePhase2(ctx context.Context, in *ZeroMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	StartVerifPhase2(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// Node RPC for share distribution phase
+	SharePhase3(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	StartVerifPhase3(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// Node RPC for a recovering node to ask for the message of a phase again
+	Resend(ctx context.Context, in *ResendMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// Node RPC for auditors to learn the log of an epoch as the node read it
+	Audit(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AuditMsg, error)
+}
+
+type nodeServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNodeServiceClient(cc *grpc.ClientConn) NodeServiceClient {
+	return &nodeServiceClient{cc}
+}
+
+func (c *nodeServiceClient) StartPhase1(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/StartPhase1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) SharePhase1(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) SharePhase2(ctx context.Context, in *ZeroMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) StartVerifPhase2(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/StartVerifPhase2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) SharePhase3(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase3", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) StartVerifPhase3(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/StartVerifPhase3", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) Resend(ctx context.Context, in *ResendMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/Resend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) Audit(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*AuditMsg, error) {
+	out := new(AuditMsg)
+	err := c.cc.Invoke(ctx, "/services.NodeService/Audit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeServiceServer is the server API for NodeService service.
+type NodeServiceServer interface {
+	// Node RPC for reconstruction phase
+	StartPhase1(context.Context, *EpochMsg) (*AckMsg, error)
+	SharePhase1(context.Context, *PointMsg) (*AckMsg, error)
+	// Node RPC for proactivization phase
+	SharePhase2(context.Context, *ZeroMsg) (*AckMsg, error)
+	StartVerifPhase2(context.Context, *EpochMsg) (*AckMsg, error)
+	// Node RPC for share distribution phase
+	SharePhase3(context.Context, *PointMsg) (*AckMsg, error)
+	StartVerifPhase3(context.Context, *EpochMsg) (*AckMsg, error)
+	// Node RPC for a recovering node to ask for the message of a phase again
+	Resend(context.Context, *ResendMsg) (*AckMsg, error)
+	// Node RPC for auditors to learn the log of an epoch as the node read it
+	Audit(context.Context, *EpochMsg) (*AuditMsg, error)
+}
+
+func RegisterNodeServiceServer(s *grpc.Server, srv NodeServiceServer) {
+	s.RegisterService(&_NodeService_serviceDesc, srv)
+}
+
+func _NodeService_StartPhase1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).StartPhase1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/StartPhase1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).StartPhase1(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_SharePhase1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).SharePhase1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/SharePhase1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).SharePhase1(ctx, req.(*PointMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_SharePhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZeroMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).SharePhase2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/SharePhase2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).SharePhase2(ctx, req.(*ZeroMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_StartVerifPhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).StartVerifPhase2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/StartVerifPhase2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).StartVerifPhase2(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_SharePhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).SharePhase3(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/SharePhase3",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).SharePhase3(ctx, req.(*PointMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_StartVerifPhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).StartVerifPhase3(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/StartVerifPhase3",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).StartVerifPhase3(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_Resend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).Resend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/Resend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).Resend(ctx, req.(*ResendMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_Audit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).Audit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.NodeService/Audit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).Audit(ctx, req.(*EpochMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NodeService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.NodeService",
+	HandlerType: (*NodeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartPhase1",
+			Handler:    _NodeService_StartPhase1_Handler,
+		},
+		{
+			MethodName: "SharePhase1",
+			Handler:    _NodeService_SharePhase1_Handler,
+		},
+		{
+			MethodName: "SharePhase2",
+			Handler:    _NodeService_SharePhase2_Handler,
+		},
+		{
+			MethodName: "StartVerifPhase2",
+			Handler:    _NodeService_StartVerifPhase2_Handler,
+		},
+		{
+			MethodName: "SharePhase3",
+			Handler:    _NodeService_SharePhase3_Handler,
+		},
+		{
+			MethodName: "StartVerifPhase3",
+			Handler:    _NodeService_StartVerifPhase3_Handler,
+		},
+		{
+			MethodName: "Resend",
+			Handler:    _NodeService_Resend_Handler,
+		},
+		{
+			MethodName: "Audit",
+			Handler:    _NodeService_Audit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
 }
 
-type bulletinBoardServiceClient struct {
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	// What the node or the bulletinboard is doing right now, for operators
+	Status(ctx context.Context, in *StatusRequestMsg, opts ...grpc.CallOption) (*StatusMsg, error)
+}
+
+type adminServiceClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewBulletinBoardServiceClient(cc *grpc.ClientConn) BulletinBoardServiceClient {
-	return &bulletinBoardServiceClient{cc}
+func NewAdminServiceClient(cc *grpc.ClientConn) AdminServiceClient {
+	return &adminServiceClient{cc}
 }
 
-func (c *bulletinBoardServiceClient) StartEpoch(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/StartEpoch", in, out, opts...)
+func (c *adminServiceClient) Status(ctx context.Context, in *StatusRequestMsg, opts ...grpc.CallOption) (*StatusMsg, error) {
+	out := new(StatusMsg)
+	err := c.cc.Invoke(ctx, "/services.AdminService/Status", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bulletinBoardServiceClient) ReadPhase1(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase1Client, error) {
-	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[0], "/services.BulletinBoardService/ReadPhase1", opts...)
-	if err != nil {
+// AdminServiceServer is the server API for AdminService service.
+type AdminServiceServer interface {
+	// What the node or the bulletinboard is doing right now, for operators
+	Status(context.Context, *StatusRequestMsg) (*StatusMsg, error)
+}
+
+func RegisterAdminServiceServer(s *grpc.Server, srv AdminServiceServer) {
+	s.RegisterService(&_AdminService_serviceDesc, srv)
+}
+
+func _AdminService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequestMsg)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &bulletinBoardServiceReadPhase1Client{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Status(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.AdminService/Status",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Status(ctx, req.(*StatusRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type BulletinBoardService_ReadPhase1Client interface {
-	Recv() (*Cmt1Msg, error)
-	grpc.ClientStream
+var _AdminService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _AdminService_Status_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
 }
 
-type bulletinBoardServiceReadPhase1Client struct {
-	grpc.ClientStream
+// SecretServiceClient is the client API for SecretService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SecretServiceClient interface {
+	// Take the share of a secret the operator dealt
+	Store(ctx context.Context, in *DealtShareMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// Hand the shares of the latest completed epoch to the operator
+	Retrieve(ctx context.Context, in *ShareRequestMsg, opts ...grpc.CallOption) (*SharesMsg, error)
+	// Drop the shares of a secret the operator deleted
+	Delete(ctx context.Context, in *DeleteMsg, opts ...grpc.CallOption) (*AckMsg, error)
 }
 
-func (x *bulletinBoardServiceReadPhase1Client) Recv() (*Cmt1Msg, error) {
-	m := new(Cmt1Msg)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type secretServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSecretServiceClient(cc *grpc.ClientConn) SecretServiceClient {
+	return &secretServiceClient{cc}
+}
+
+func (c *secretServiceClient) Store(ctx context.Context, in *DealtShareMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.SecretService/Store", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-func (c *bulletinBoardServiceClient) WritePhase2(ctx context.Context, in *Cmt2Msg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/WritePhase2", in, out, opts...)
+func (c *secretServiceClient) Retrieve(ctx context.Context, in *ShareRequestMsg, opts ...grpc.CallOption) (*SharesMsg, error) {
+	out := new(SharesMsg)
+	err := c.cc.Invoke(ctx, "/services.SecretService/Retrieve", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bulletinBoardServiceClient) ReadPhase2(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase2Client, error) {
-	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[1], "/services.BulletinBoardService/ReadPhase2", opts...)
+func (c *secretServiceClient) Delete(ctx context.Context, in *DeleteMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.SecretService/Delete", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &bulletinBoardServiceReadPhase2Client{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	return out, nil
+}
+
+// SecretServiceServer is the server API for SecretService service.
+type SecretServiceServer interface {
+	// Take the share of a secret the operator dealt
+	Store(context.Context, *DealtShareMsg) (*AckMsg, error)
+	// Hand the shares of the latest completed epoch to the operator
+	Retrieve(context.Context, *ShareRequestMsg) (*SharesMsg, error)
+	// Drop the shares of a secret the operator deleted
+	Delete(context.Context, *DeleteMsg) (*AckMsg, error)
+}
+
+func RegisterSecretServiceServer(s *grpc.Server, srv SecretServiceServer) {
+	s.RegisterService(&_SecretService_serviceDesc, srv)
+}
+
+func _SecretService_Store_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DealtShareMsg)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(SecretServiceServer).Store(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.SecretService/Store",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretServiceServer).Store(ctx, req.(*DealtShareMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretService_Retrieve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRequestMsg)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(SecretServiceServer).Retrieve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.SecretService/Retrieve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretServiceServer).Retrieve(ctx, req.(*ShareRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type BulletinBoardService_ReadPhase2Client interface {
-	Recv() (*Cmt2Msg, error)
-	grpc.ClientStream
+func _SecretService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.SecretService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretServiceServer).Delete(ctx, req.(*DeleteMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type bulletinBoardServiceReadPhase2Client struct {
-	grpc.ClientStream
+var _SecretService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.SecretService",
+	HandlerType: (*SecretServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Store",
+			Handler:    _SecretService_Store_Handler,
+		},
+		{
+			MethodName: "Retrieve",
+			Handler:    _SecretService_Retrieve_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _SecretService_Delete_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
 }
 
-func (x *bulletinBoardServiceReadPhase2Client) Recv() (*Cmt2Msg, error) {
-	m := new(Cmt2Msg)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+// SignServiceClient is the client API for SignService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SignServiceClient interface {
+	// Sign a message with the share of the secret of the latest completed epoch, a partial threshold BLS signature
+	SignShare(ctx context.Context, in *SignRequestMsg, opts ...grpc.CallOption) (*PartialSigMsg, error)
 }
 
-func (c *bulletinBoardServiceClient) WritePhase3(ctx context.Context, in *Cmt1Msg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.BulletinBoardService/WritePhase3", in, out, opts...)
+type signServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSignServiceClient(cc *grpc.ClientConn) SignServiceClient {
+	return &signServiceClient{cc}
+}
+
+func (c *signServiceClient) SignShare(ctx context.Context, in *SignRequestMsg, opts ...grpc.CallOption) (*PartialSigMsg, error) {
+	out := new(PartialSigMsg)
+	err := c.cc.Invoke(ctx, "/services.SignService/SignShare", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bulletinBoardServiceClient) ReadPhase3(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (BulletinBoardService_ReadPhase3Client, error) {
-	stream, err := c.cc.NewStream(ctx, &_BulletinBoardService_serviceDesc.Streams[2], "/services.BulletinBoardService/ReadPhase3", opts...)
-	if err != nil {
+// SignServiceServer is the server API for SignService service.
+type SignServiceServer interface {
+	// Sign a message with the share of the secret of the latest completed epoch, a partial threshold BLS signature
+	SignShare(context.Context, *SignRequestMsg) (*PartialSigMsg, error)
+}
+
+func RegisterSignServiceServer(s *grpc.Server, srv SignServiceServer) {
+	s.RegisterService(&_SignService_serviceDesc, srv)
+}
+
+func _SignService_SignShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequestMsg)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &bulletinBoardServiceReadPhase3Client{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(SignServiceServer).SignShare(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.SignService/SignShare",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignServiceServer).SignShare(ctx, req.(*SignRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type BulletinBoardService_ReadPhase3Client interface {
-	Recv() (*Cmt1Msg, error)
-	grpc.ClientStream
+var _SignService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.SignService",
+	HandlerType: (*SignServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignShare",
+			Handler:    _SignService_SignShare_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
 }
 
-type bulletinBoardServiceReadPhase3Client struct {
-	grpc.ClientStream
+// DecryptServiceClient is the client API for DecryptService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DecryptServiceClient interface {
+	// The public share of the node in the latest completed epoch, from which clients derive the public key
+	PublicShare(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*PublicShareMsg, error)
+	// Decrypt the first part of a ciphertext with the share of the secret of the latest completed epoch, for the operator
+	DecryptShare(ctx context.Context, in *DecryptRequestMsg, opts ...grpc.CallOption) (*PartialDecryptionMsg, error)
 }
 
-func (x *bulletinBoardServiceReadPhase3Client) Recv() (*Cmt1Msg, error) {
-	m := new(Cmt1Msg)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type decryptServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDecryptServiceClient(cc *grpc.ClientConn) DecryptServiceClient {
+	return &decryptServiceClient{cc}
+}
+
+func (c *decryptServiceClient) PublicShare(ctx context.Context, in *EpochMsg, opts ...grpc.CallOption) (*PublicShareMsg, error) {
+	out := new(PublicShareMsg)
+	err := c.cc.Invoke(ctx, "/services.DecryptService/PublicShare", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-// BulletinBoardServiceServer is the server API for BulletinBoardService service.
-type BulletinBoardServiceServer interface {
-	// Start a epoch
-	StartEpoch(context.Context, *EmptyMsg) (*AckMsg, error)
-	// BulletinBoard RPC for recontruction phase
-	ReadPhase1(*EmptyMsg, BulletinBoardService_ReadPhase1Server) error
-	// BulletinBoard RPC for proactivization phase
-	WritePhase2(context.Context, *Cmt2Msg) (*AckMsg, error)
-	ReadPhase2(*EmptyMsg, BulletinBoardService_ReadPhase2Server) error
-	// BulletinBoard RPC for share distribution phase
-	WritePhase3(context.Context, *Cmt1Msg) (*AckMsg, error)
-	ReadPhase3(*EmptyMsg, BulletinBoardService_ReadPhase3Server) error
+func (c *decryptServiceClient) DecryptShare(ctx context.Context, in *DecryptRequestMsg, opts ...grpc.CallOption) (*PartialDecryptionMsg, error) {
+	out := new(PartialDecryptionMsg)
+	err := c.cc.Invoke(ctx, "/services.DecryptService/DecryptShare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterBulletinBoardServiceServer(s *grpc.Server, srv BulletinBoardServiceServer) {
-	s.RegisterService(&_BulletinBoardService_serviceDesc, srv)
+// DecryptServiceServer is the server API for DecryptService service.
+type DecryptServiceServer interface {
+	// The public share of the node in the latest completed epoch, from which clients derive the public key
+	PublicShare(context.Context, *EpochMsg) (*PublicShareMsg, error)
+	// Decrypt the first part of a ciphertext with the share of the secret of the latest completed epoch, for the operator
+	DecryptShare(context.Context, *DecryptRequestMsg) (*PartialDecryptionMsg, error)
 }
 
-func _BulletinBoardService_StartEpoch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyMsg)
+func RegisterDecryptServiceServer(s *grpc.Server, srv DecryptServiceServer) {
+	s.RegisterService(&_DecryptService_serviceDesc, srv)
+}
+
+func _DecryptService_PublicShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EpochMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BulletinBoardServiceServer).StartEpoch(ctx, in)
+		return srv.(DecryptServiceServer).PublicShare(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.BulletinBoardService/StartEpoch",
+		FullMethod: "/services.DecryptService/PublicShare",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BulletinBoardServiceServer).StartEpoch(ctx, req.(*EmptyMsg))
+		return srv.(DecryptServiceServer).PublicShare(ctx, req.(*EpochMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BulletinBoardService_ReadPhase1_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EmptyMsg)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _DecryptService_DecryptShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecryptRequestMsg)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(BulletinBoardServiceServer).ReadPhase1(m, &bulletinBoardServiceReadPhase1Server{stream})
+	if interceptor == nil {
+		return srv.(DecryptServiceServer).DecryptShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.DecryptService/DecryptShare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecryptServiceServer).DecryptShare(ctx, req.(*DecryptRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type BulletinBoardService_ReadPhase1Server interface {
-	Send(*Cmt1Msg) error
-	grpc.ServerStream
+var _DecryptService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.DecryptService",
+	HandlerType: (*DecryptServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublicShare",
+			Handler:    _DecryptService_PublicShare_Handler,
+		},
+		{
+			MethodName: "DecryptShare",
+			Handler:    _DecryptService_DecryptShare_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services.proto",
+}
+
+// SchnorrServiceClient is the client API for SchnorrService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SchnorrServiceClient interface {
+	// Take the share of a P-521 signing key the operator dealt, in place of any key before
+	StoreKey(ctx context.Context, in *DealtKeyMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	// First round of a signature: draw fresh nonces for a signing session and commit to them
+	CommitNonce(ctx context.Context, in *NonceRequestMsg, opts ...grpc.CallOption) (*NonceCommitMsg, error)
+	// Second round: sign a message with the nonces of the session, given the nonce commitments of all signers. The nonces are used up either way.
+	SignSchnorr(ctx context.Context, in *SchnorrRequestMsg, opts ...grpc.CallOption) (*SchnorrShareMsg, error)
+}
+
+type schnorrServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSchnorrServiceClient(cc *grpc.ClientConn) SchnorrServiceClient {
+	return &schnorrServiceClient{cc}
+}
+
+func (c *schnorrServiceClient) StoreKey(ctx context.Context, in *DealtKeyMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+	out := new(AckMsg)
+	err := c.cc.Invoke(ctx, "/services.SchnorrService/StoreKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schnorrServiceClient) CommitNonce(ctx context.Context, in *NonceRequestMsg, opts ...grpc.CallOption) (*NonceCommitMsg, error) {
+	out := new(NonceCommitMsg)
+	err := c.cc.Invoke(ctx, "/services.SchnorrService/CommitNonce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schnorrServiceClient) SignSchnorr(ctx context.Context, in *SchnorrRequestMsg, opts ...grpc.CallOption) (*SchnorrShareMsg, error) {
+	out := new(SchnorrShareMsg)
+	err := c.cc.Invoke(ctx, "/services.SchnorrService/SignSchnorr", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type bulletinBoardServiceReadPhase1Server struct {
-	grpc.ServerStream
+// SchnorrServiceServer is the server API for SchnorrService service.
+type SchnorrServiceServer interface {
+	// Take the share of a P-521 signing key the operator dealt, in place of any key before
+	StoreKey(context.Context, *DealtKeyMsg) (*AckMsg, error)
+	// First round of a signature: draw fresh nonces for a signing session and commit to them
+	CommitNonce(context.Context, *NonceRequestMsg) (*NonceCommitMsg, error)
+	// Second round: sign a message with the nonces of the session, given the nonce commitments of all signers. The nonces are used up either way.
+	SignSchnorr(context.Context, *SchnorrRequestMsg) (*SchnorrShareMsg, error)
 }
 
-func (x *bulletinBoardServiceReadPhase1Server) Send(m *Cmt1Msg) error {
-	return x.ServerStream.SendMsg(m)
+func RegisterSchnorrServiceServer(s *grpc.Server, srv SchnorrServiceServer) {
+	s.RegisterService(&_SchnorrService_serviceDesc, srv)
 }
 
-func _BulletinBoardService_WritePhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Cmt2Msg)
+func _SchnorrService_StoreKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DealtKeyMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BulletinBoardServiceServer).WritePhase2(ctx, in)
+		return srv.(SchnorrServiceServer).StoreKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.BulletinBoardService/WritePhase2",
+		FullMethod: "/services.SchnorrService/StoreKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BulletinBoardServiceServer).WritePhase2(ctx, req.(*Cmt2Msg))
+		return srv.(SchnorrServiceServer).StoreKey(ctx, req.(*DealtKeyMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BulletinBoardService_ReadPhase2_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EmptyMsg)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(BulletinBoardServiceServer).ReadPhase2(m, &bulletinBoardServiceReadPhase2Server{stream})
-}
-
-type BulletinBoardService_ReadPhase2Server interface {
-	Send(*Cmt2Msg) error
-	grpc.ServerStream
-}
-
-type bulletinBoardServiceReadPhase2Server struct {
-	grpc.ServerStream
-}
-
-func (x *bulletinBoardServiceReadPhase2Server) Send(m *Cmt2Msg) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _BulletinBoardService_WritePhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Cmt1Msg)
+func _SchnorrService_CommitNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NonceRequestMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BulletinBoardServiceServer).WritePhase3(ctx, in)
+		return srv.(SchnorrServiceServer).CommitNonce(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.BulletinBoardService/WritePhase3",
+		FullMethod: "/services.SchnorrService/CommitNonce",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BulletinBoardServiceServer).WritePhase3(ctx, req.(*Cmt1Msg))
+		return srv.(SchnorrServiceServer).CommitNonce(ctx, req.(*NonceRequestMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BulletinBoardService_ReadPhase3_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EmptyMsg)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _SchnorrService_SignSchnorr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchnorrRequestMsg)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(BulletinBoardServiceServer).ReadPhase3(m, &bulletinBoardServiceReadPhase3Server{stream})
-}
-
-type BulletinBoardService_ReadPhase3Server interface {
-	Send(*Cmt1Msg) error
-	grpc.ServerStream
-}
-
-type bulletinBoardServiceReadPhase3Server struct {
-	grpc.ServerStream
-}
-
-func (x *bulletinBoardServiceReadPhase3Server) Send(m *Cmt1Msg) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(SchnorrServiceServer).SignSchnorr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.SchnorrService/SignSchnorr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchnorrServiceServer).SignSchnorr(ctx, req.(*SchnorrRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var _BulletinBoardService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "services.BulletinBoardService",
-	HandlerType: (*BulletinBoardServiceServer)(nil),
+var _SchnorrService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.SchnorrService",
+	HandlerType: (*SchnorrServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "StartEpoch",
-			Handler:    _BulletinBoardService_StartEpoch_Handler,
-		},
-		{
-			MethodName: "WritePhase2",
-			Handler:    _BulletinBoardService_WritePhase2_Handler,
-		},
-		{
-			MethodName: "WritePhase3",
-			Handler:    _BulletinBoardService_WritePhase3_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "ReadPhase1",
-			Handler:       _BulletinBoardService_ReadPhase1_Handler,
-			ServerStreams: true,
+			MethodName: "StoreKey",
+			Handler:    _SchnorrService_StoreKey_Handler,
 		},
 		{
-			StreamName:    "ReadPhase2",
-			Handler:       _BulletinBoardService_ReadPhase2_Handler,
-			ServerStreams: true,
+			MethodName: "CommitNonce",
+			Handler:    _SchnorrService_CommitNonce_Handler,
 		},
 		{
-			StreamName:    "ReadPhase3",
-			Handler:       _BulletinBoardService_ReadPhase3_Handler,
-			ServerStreams: true,
+			MethodName: "SignSchnorr",
+			Handler:    _SchnorrService_SignSchnorr_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
-// NodeServiceClient is the client API for NodeService service.
+// ReplicaServiceClient is the client API for ReplicaService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type NodeServiceClient interface {
-	// Node RPC for reconstruction phase
-	StartPhase1(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	SharePhase1(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	// Node RPC for proactivization phase
-	SharePhase2(ctx context.Context, in *ZeroMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	StartVerifPhase2(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	// Node RPC for share distribution phase
-	SharePhase3(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error)
-	StartVerifPhase3(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error)
+type ReplicaServiceClient interface {
+	// Replica RPC for the consensus among replicas
+	RequestVote(ctx context.Context, in *VoteMsg, opts ...grpc.CallOption) (*VoteReplyMsg, error)
+	AppendEntries(ctx context.Context, in *AppendEntriesMsg, opts ...grpc.CallOption) (*AppendEntriesReplyMsg, error)
+	// Replica RPC for the bulletinboard, Append and Read are served by the leader only
+	Append(ctx context.Context, in *EntryMsg, opts ...grpc.CallOption) (*AckMsg, error)
+	Read(ctx context.Context, in *ReadMsg, opts ...grpc.CallOption) (ReplicaService_ReadClient, error)
+	Subscribe(ctx context.Context, in *ReadMsg, opts ...grpc.CallOption) (ReplicaService_SubscribeClient, error)
 }
 
-type nodeServiceClient struct {
+type replicaServiceClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewNodeServiceClient(cc *grpc.ClientConn) NodeServiceClient {
-	return &nodeServiceClient{cc}
+func NewReplicaServiceClient(cc *grpc.ClientConn) ReplicaServiceClient {
+	return &replicaServiceClient{cc}
 }
 
-func (c *nodeServiceClient) StartPhase1(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/StartPhase1", in, out, opts...)
+func (c *replicaServiceClient) RequestVote(ctx context.Context, in *VoteMsg, opts ...grpc.CallOption) (*VoteReplyMsg, error) {
+	out := new(VoteReplyMsg)
+	err := c.cc.Invoke(ctx, "/services.ReplicaService/RequestVote", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *nodeServiceClient) SharePhase1(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase1", in, out, opts...)
+func (c *replicaServiceClient) AppendEntries(ctx context.Context, in *AppendEntriesMsg, opts ...grpc.CallOption) (*AppendEntriesReplyMsg, error) {
+	out := new(AppendEntriesReplyMsg)
+	err := c.cc.Invoke(ctx, "/services.ReplicaService/AppendEntries", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *nodeServiceClient) SharePhase2(ctx context.Context, in *ZeroMsg, opts ...grpc.CallOption) (*AckMsg, error) {
+func (c *replicaServiceClient) Append(ctx context.Context, in *EntryMsg, opts ...grpc.CallOption) (*AckMsg, error) {
 	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase2", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/services.ReplicaService/Append", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *nodeServiceClient) StartVerifPhase2(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/StartVerifPhase2", in, out, opts...)
+func (c *replicaServiceClient) Read(ctx context.Context, in *ReadMsg, opts ...grpc.CallOption) (ReplicaService_ReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReplicaService_serviceDesc.Streams[0], "/services.ReplicaService/Read", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *nodeServiceClient) SharePhase3(ctx context.Context, in *PointMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/SharePhase3", in, out, opts...)
-	if err != nil {
+	x := &replicaServiceReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *nodeServiceClient) StartVerifPhase3(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*AckMsg, error) {
-	out := new(AckMsg)
-	err := c.cc.Invoke(ctx, "/services.NodeService/StartVerifPhase3", in, out, opts...)
-	if err != nil {
+	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return x, nil
 }
 
-// NodeServiceServer is the server API for NodeService service.
-type NodeServiceServer interface {
-	// Node RPC for reconstruction phase
-	StartPhase1(context.Context, *EmptyMsg) (*AckMsg, error)
-	SharePhase1(context.Context, *PointMsg) (*AckMsg, error)
-	// Node RPC for proactivization phase
-	SharePhase2(context.Context, *ZeroMsg) (*AckMsg, error)
-	StartVerifPhase2(context.Context, *EmptyMsg) (*AckMsg, error)
-	// Node RPC for share distribution phase
-	SharePhase3(context.Context, *PointMsg) (*AckMsg, error)
-	StartVerifPhase3(context.Context, *EmptyMsg) (*AckMsg, error)
+type ReplicaService_ReadClient interface {
+	Recv() (*EntryMsg, error)
+	grpc.ClientStream
 }
 
-func RegisterNodeServiceServer(s *grpc.Server, srv NodeServiceServer) {
-	s.RegisterService(&_NodeService_serviceDesc, srv)
+type replicaServiceReadClient struct {
+	grpc.ClientStream
 }
 
-func _NodeService_StartPhase1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyMsg)
-	if err := dec(in); err != nil {
+func (x *replicaServiceReadClient) Recv() (*EntryMsg, error) {
+	m := new(EntryMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(NodeServiceServer).StartPhase1(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/services.NodeService/StartPhase1",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).StartPhase1(ctx, req.(*EmptyMsg))
-	}
-	return interceptor(ctx, in, info, handler)
+	return m, nil
 }
 
-func _NodeService_SharePhase1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PointMsg)
-	if err := dec(in); err != nil {
+func (c *replicaServiceClient) Subscribe(ctx context.Context, in *ReadMsg, opts ...grpc.CallOption) (ReplicaService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ReplicaService_serviceDesc.Streams[1], "/services.ReplicaService/Subscribe", opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(NodeServiceServer).SharePhase1(ctx, in)
+	x := &replicaServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/services.NodeService/SharePhase1",
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).SharePhase1(ctx, req.(*PointMsg))
+	return x, nil
+}
+
+type ReplicaService_SubscribeClient interface {
+	Recv() (*EntryMsg, error)
+	grpc.ClientStream
+}
+
+type replicaServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *replicaServiceSubscribeClient) Recv() (*EntryMsg, error) {
+	m := new(EntryMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return interceptor(ctx, in, info, handler)
+	return m, nil
 }
 
-func _NodeService_SharePhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ZeroMsg)
+// ReplicaServiceServer is the server API for ReplicaService service.
+type ReplicaServiceServer interface {
+	// Replica RPC for the consensus among replicas
+	RequestVote(context.Context, *VoteMsg) (*VoteReplyMsg, error)
+	AppendEntries(context.Context, *AppendEntriesMsg) (*AppendEntriesReplyMsg, error)
+	// Replica RPC for the bulletinboard, Append and Read are served by the leader only
+	Append(context.Context, *EntryMsg) (*AckMsg, error)
+	Read(*ReadMsg, ReplicaService_ReadServer) error
+	Subscribe(*ReadMsg, ReplicaService_SubscribeServer) error
+}
+
+func RegisterReplicaServiceServer(s *grpc.Server, srv ReplicaServiceServer) {
+	s.RegisterService(&_ReplicaService_serviceDesc, srv)
+}
+
+func _ReplicaService_RequestVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).SharePhase2(ctx, in)
+		return srv.(ReplicaServiceServer).RequestVote(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.NodeService/SharePhase2",
+		FullMethod: "/services.ReplicaService/RequestVote",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).SharePhase2(ctx, req.(*ZeroMsg))
+		return srv.(ReplicaServiceServer).RequestVote(ctx, req.(*VoteMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_StartVerifPhase2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyMsg)
+func _ReplicaService_AppendEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendEntriesMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).StartVerifPhase2(ctx, in)
+		return srv.(ReplicaServiceServer).AppendEntries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.NodeService/StartVerifPhase2",
+		FullMethod: "/services.ReplicaService/AppendEntries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).StartVerifPhase2(ctx, req.(*EmptyMsg))
+		return srv.(ReplicaServiceServer).AppendEntries(ctx, req.(*AppendEntriesMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_SharePhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PointMsg)
+func _ReplicaService_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntryMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).SharePhase3(ctx, in)
+		return srv.(ReplicaServiceServer).Append(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/services.NodeService/SharePhase3",
+		FullMethod: "/services.ReplicaService/Append",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).SharePhase3(ctx, req.(*PointMsg))
+		return srv.(ReplicaServiceServer).Append(ctx, req.(*EntryMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_StartVerifPhase3_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyMsg)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(NodeServiceServer).StartVerifPhase3(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/services.NodeService/StartVerifPhase3",
+func _ReplicaService_Read_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).StartVerifPhase3(ctx, req.(*EmptyMsg))
+	return srv.(ReplicaServiceServer).Read(m, &replicaServiceReadServer{stream})
+}
+
+type ReplicaService_ReadServer interface {
+	Send(*EntryMsg) error
+	grpc.ServerStream
+}
+
+type replicaServiceReadServer struct {
+	grpc.ServerStream
+}
+
+func (x *replicaServiceReadServer) Send(m *EntryMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ReplicaService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadMsg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(ReplicaServiceServer).Subscribe(m, &replicaServiceSubscribeServer{stream})
 }
 
-var _NodeService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "services.NodeService",
-	HandlerType: (*NodeServiceServer)(nil),
+type ReplicaService_SubscribeServer interface {
+	Send(*EntryMsg) error
+	grpc.ServerStream
+}
+
+type replicaServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *replicaServiceSubscribeServer) Send(m *EntryMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ReplicaService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "services.ReplicaService",
+	HandlerType: (*ReplicaServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "StartPhase1",
-			Handler:    _NodeService_StartPhase1_Handler,
-		},
-		{
-			MethodName: "SharePhase1",
-			Handler:    _NodeService_SharePhase1_Handler,
+			MethodName: "RequestVote",
+			Handler:    _ReplicaService_RequestVote_Handler,
 		},
 		{
-			MethodName: "SharePhase2",
-			Handler:    _NodeService_SharePhase2_Handler,
+			MethodName: "AppendEntries",
+			Handler:    _ReplicaService_AppendEntries_Handler,
 		},
 		{
-			MethodName: "StartVerifPhase2",
-			Handler:    _NodeService_StartVerifPhase2_Handler,
+			MethodName: "Append",
+			Handler:    _ReplicaService_Append_Handler,
 		},
+	},
+	Streams: []grpc.StreamDesc{
 		{
-			MethodName: "SharePhase3",
-			Handler:    _NodeService_SharePhase3_Handler,
+			StreamName:    "Read",
+			Handler:       _ReplicaService_Read_Handler,
+			ServerStreams: true,
 		},
 		{
-			MethodName: "StartVerifPhase3",
-			Handler:    _NodeService_StartVerifPhase3_Handler,
+			StreamName:    "Subscribe",
+			Handler:       _ReplicaService_Subscribe_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Signed is a protocol message carrying the signature of the node named by its index
+type Signed interface {
+	GetIndex() int32
+	GetSignature() []byte
+	// SigningBytes returns the encoding covered by the signature
+	SigningBytes() []byte
+}
+
+// The signed bytes start with a tag naming the type of the message and, for a message of the protocol, the phase it is sent in.
+// A signature on one message then never verifies for another with the same encoding, like a point of phase 1 sent again in phase 3.
+func signingBytes(tag string, msg proto.Message) []byte {
+	return append([]byte("churp/"+tag+"\x00"), marshal(msg)...)
+}
+
+func marshal(msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err.Error())
+	}
+	return data
+}
+
+func (m *Cmt1Msg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	c.Inclusion = nil
+	return signingBytes("Cmt1Msg/phase3", &c)
+}
+
+func (m *Cmt2Msg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	c.Inclusion = nil
+	return signingBytes("Cmt2Msg/phase2", &c)
+}
+
+func (m *PointMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes(fmt.Sprintf("PointMsg/phase%d", m.GetPhase()), &c)
+}
+
+func (m *ZeroMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("ZeroMsg/phase2", &c)
+}
+
+func (m *BeaconShareMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("BeaconShareMsg/beacon", &c)
+}
+
+func (m *DealMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("DealMsg", &c)
+}
+
+func (m *DealtShareMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("DealtShareMsg", &c)
+}
+
+func (m *DeleteMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("DeleteMsg", &c)
+}
+
+func (m *ShareRequestMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("ShareRequestMsg", &c)
+}
+
+func (m *SignRequestMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("SignRequestMsg", &c)
+}
+
+func (m *DecryptRequestMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("DecryptRequestMsg", &c)
+}
+
+func (m *DealtKeyMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("DealtKeyMsg", &c)
+}
+
+func (m *NonceRequestMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("NonceRequestMsg", &c)
+}
+
+func (m *SchnorrRequestMsg) SigningBytes() []byte {
+	c := *m
+	c.Signature = nil
+	return signingBytes("SchnorrRequestMsg", &c)
+}
+
+// Sign returns the signature of id on msg
+func Sign(id *identity.Identity, msg Signed) []byte {
+	sig, err := id.Sign(msg.SigningBytes())
+	if err != nil {
+		panic(err.Error())
+	}
+	return sig
+}
+
+// VerifySigned checks msg against the key of the node it claims to come from.
+// pks[i] is the identity key of node i+1.
+func VerifySigned(pks []*ecdsa.PublicKey, msg Signed) error {
+	index := msg.GetIndex()
+	if index < 1 || int(index) > len(pks) {
+		return status.Errorf(codes.InvalidArgument, "index %d out of range [1, %d]", index, len(pks))
+	}
+	if !identity.Verify(pks[index-1], msg.SigningBytes(), msg.GetSignature()) {
+		return status.Errorf(codes.Unauthenticated, "invalid signature from node %d", index)
+	}
+	return nil
+}
+
+// Operated is a request of the operator, signed with the operator key rather than the key of a node
+type Operated interface {
+	GetSignature() []byte
+	SigningBytes() []byte
+}
+
+// SignOperated returns the signature of the operator key id on msg
+func SignOperated(id *identity.Identity, msg Operated) []byte {
+	sig, err := id.Sign(msg.SigningBytes())
+	if err != nil {
+		panic(err.Error())
+	}
+	return sig
+}
+
+// VerifyOperated checks msg against the operator key pk. Without an operator key no request is accepted.
+func VerifyOperated(pk *ecdsa.PublicKey, msg Operated) error {
+	if pk == nil {
+		return status.Error(codes.PermissionDenied, "no operator key is configured")
+	}
+	if !identity.Verify(pk, msg.SigningBytes(), msg.GetSignature()) {
+		return status.Error(codes.Unauthenticated, "invalid signature of the operator")
+	}
+	return nil
+}
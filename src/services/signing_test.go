@@ -0,0 +1,40 @@
+package services
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/identity"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A signature covers the phase of a point, so a point of phase 1 sent again in phase 3 does not verify
+func TestSignatureCoversPhase(t *testing.T) {
+	id, err := identity.Generate(rand.Reader)
+	if !assert.Nil(t, err) {
+		return
+	}
+	pks := []*ecdsa.PublicKey{id.Public()}
+	point := &PointMsg{Index: 1, X: 1, Y: []byte{7}, Epoch: 2, Phase: 1}
+	point.Signature = Sign(id, point)
+	assert.Nil(t, VerifySigned(pks, point))
+
+	point.Phase = 3
+	assert.Equal(t, codes.Unauthenticated, status.Code(VerifySigned(pks, point)))
+}
+
+// The signed bytes name the type of the message and the phase it is sent in
+func TestSignatureCoversType(t *testing.T) {
+	for tag, msg := range map[string]Signed{
+		"churp/Cmt1Msg/phase3\x00":  &Cmt1Msg{Index: 1},
+		"churp/Cmt2Msg/phase2\x00":  &Cmt2Msg{Index: 1},
+		"churp/ZeroMsg/phase2\x00":  &ZeroMsg{Index: 1},
+		"churp/PointMsg/phase3\x00": &PointMsg{Index: 1, Phase: 3},
+	} {
+		assert.True(t, bytes.HasPrefix(msg.SigningBytes(), []byte(tag)), tag)
+	}
+}
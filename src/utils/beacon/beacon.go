@@ -0,0 +1,54 @@
+// Package beacon derives the public randomness of a committee from its secret, one value per round.
+// The value of round r comes from the threshold BLS signature H(r)^s of the committee on the round, see the tbls package: the node holding the share S(x) evaluates H(r)^S(x), and any t+1 evaluations interpolate to H(r)^s.
+// The signature is the proof of the value, it verifies under the public key g^s and there is exactly one for every round, so no node and no set of t nodes can pick or predict the value. The key stays the same as the shares are refreshed.
+// Rounds hash into the group apart from the messages the committee signs for its operator, who could otherwise learn future values by asking for them.
+package beacon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/conv"
+	"github.com/bl4ck5un/ChuRP/src/utils/ecparam"
+	"github.com/ncw/gmp"
+)
+
+var curve = ecparam.PBC256
+
+// Hashed ahead of the round into the group, and ahead of the proof into the value
+const (
+	roundDomain  = "churp/beacon/round/v1:"
+	outputDomain = "churp/beacon/output/v1:"
+)
+
+// Hash maps the round to an element of G1 nobody knows the discrete logarithm of
+func Hash(round int64) *pbc.Element {
+	h := sha256.New()
+	h.Write([]byte(roundDomain))
+	binary.Write(h, binary.BigEndian, round)
+	return curve.Pairing.NewG1().SetFromHash(h.Sum(nil))
+}
+
+// Evaluate returns the partial evaluation of the round under share, H(round)^share.
+// It verifies under the public share of the node like the proof under the public key, and t+1 of them combine into the proof with tbls.Combine.
+func Evaluate(share *gmp.Int, round int64) *pbc.Element {
+	return curve.Pairing.NewG1().PowBig(Hash(round), conv.GmpInt2BigInt(share))
+}
+
+// Verify checks that proof is the evaluation of the round under the public key pub, that is e(proof, g) == e(H(round), pub).
+// Partial evaluations verify the same way under the public share of the node that made them.
+func Verify(pub *pbc.Element, round int64, proof *pbc.Element) bool {
+	lhs := curve.Pairing.NewGT().Pair(proof, curve.G)
+	rhs := curve.Pairing.NewGT().Pair(Hash(round), pub)
+	return lhs.Equals(rhs)
+}
+
+// Output returns the random value of the round with the given proof, 32 bytes
+func Output(round int64, proof *pbc.Element) []byte {
+	h := sha256.New()
+	h.Write([]byte(outputDomain))
+	binary.Write(h, binary.BigEndian, round)
+	h.Write(proof.CompressedBytes())
+	return h.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package beacon
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/bl4ck5un/ChuRP/src/utils/tbls"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeacon(t *testing.T) {
+	const n, degree = 5, 2
+	poly, err := polyring.NewRand(degree, rand.New(rand.NewSource(11)), curve.Ngmp)
+	assert.Nil(t, err)
+	secret := gmp.NewInt(0)
+	poly.EvalMod(gmp.NewInt(0), curve.Ngmp, secret)
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	pk := dc.NewG1()
+	dc.Commit(pk, secret)
+
+	outputs := make(map[string]bool)
+	for round := int64(1); round <= 3; round++ {
+		partials := make([]*pbc.Element, n)
+		for i := range partials {
+			share := gmp.NewInt(0)
+			poly.EvalMod(gmp.NewInt(int64(i+1)), curve.Ngmp, share)
+			partials[i] = Evaluate(share, round)
+			public := dc.NewG1()
+			dc.Commit(public, share)
+			assert.True(t, Verify(public, round, partials[i]))
+		}
+		// whichever degree+1 nodes combine, the proof and the value are the same
+		var proof *pbc.Element
+		for _, xs := range [][]int{{1, 2, 3}, {2, 4, 5}, {5, 1, 3}} {
+			evals := make([]*pbc.Element, len(xs))
+			for i, x := range xs {
+				evals[i] = partials[x-1]
+			}
+			combined, err := tbls.Combine(xs, evals)
+			assert.Nil(t, err)
+			assert.True(t, Verify(pk, round, combined))
+			assert.False(t, Verify(pk, round+1, combined))
+			if proof == nil {
+				proof = combined
+			}
+			assert.True(t, proof.Equals(combined))
+		}
+		assert.True(t, proof.Equals(Evaluate(secret, round)))
+		output := Output(round, proof)
+		assert.Len(t, output, 32)
+		outputs[string(output)] = true
+	}
+	assert.Len(t, outputs, 3)
+
+	// the operator cannot have the round signed as a message
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, 1)
+	assert.False(t, tbls.Sign(secret, msg).Equals(Evaluate(secret, 1)))
+	assert.False(t, Verify(pk, 1, tbls.Sign(secret, msg)))
+}
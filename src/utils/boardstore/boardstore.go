@@ -0,0 +1,199 @@
+package boardstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Snapshot and log files both start with their magic, followed by one frame per record.
+// A frame is the big-endian length of the record | the big-endian CRC-32 (IEEE) of the record | the record.
+var (
+	snapMagic = []byte("CHURPBBS1")
+	logMagic  = []byte("CHURPBBL1")
+)
+
+const frameHeaderLen = 8
+
+// Store keeps the records of the bulletinboard in a directory: a snapshot holding the state at some point, and a log of the records appended since
+type Store struct {
+	dir string
+	log *os.File
+	// Records in the log
+	logged int
+}
+
+// Open opens the store in dir, creating the directory if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "board.log"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, log: f}, nil
+}
+
+// Load returns the records of the snapshot followed by those of the log.
+// A frame torn by a crash while it was appended ends the log and is cut off, so later appends follow the last complete record.
+func (s *Store) Load() ([][]byte, error) {
+	records := make([][]byte, 0)
+	data, err := ioutil.ReadFile(s.snapPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if !bytes.HasPrefix(data, snapMagic) {
+			return nil, errors.New(fmt.Sprintf("%s is not a bulletinboard snapshot", s.snapPath()))
+		}
+		snap, rest := frames(data[len(snapMagic):])
+		if len(rest) != 0 {
+			return nil, errors.New(fmt.Sprintf("%s is corrupted", s.snapPath()))
+		}
+		records = append(records, snap...)
+	}
+
+	if _, err := s.log.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err = ioutil.ReadAll(s.log)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return records, s.resetLog()
+	}
+	if !bytes.HasPrefix(data, logMagic) {
+		return nil, errors.New(fmt.Sprintf("%s is not a bulletinboard log", s.log.Name()))
+	}
+	logged, rest := frames(data[len(logMagic):])
+	if len(rest) != 0 {
+		if err := s.log.Truncate(int64(len(data) - len(rest))); err != nil {
+			return nil, err
+		}
+		if err := s.log.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.log.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	s.logged = len(logged)
+	return append(records, logged...), nil
+}
+
+// Append adds a record to the log and syncs it before returning
+func (s *Store) Append(record []byte) error {
+	if _, err := s.log.Write(frame(record)); err != nil {
+		return err
+	}
+	s.logged++
+	return s.log.Sync()
+}
+
+// Logged returns the number of records appended since the last snapshot
+func (s *Store) Logged() int {
+	return s.logged
+}
+
+// Snapshot replaces the snapshot by the given records, which must hold everything stored so far, and empties the log.
+// The new snapshot is written and synced next to the old one and renamed over it. A crash before the log is emptied replays the log on top of the new snapshot, so records must be safe to apply twice.
+func (s *Store) Snapshot(records [][]byte) error {
+	f, err := ioutil.TempFile(s.dir, "board.snap.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	data := append([]byte{}, snapMagic...)
+	for _, record := range records {
+		data = append(data, frame(record)...)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.snapPath()); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+	return s.resetLog()
+}
+
+// Close closes the log
+func (s *Store) Close() error {
+	return s.log.Close()
+}
+
+func (s *Store) snapPath() string {
+	return filepath.Join(s.dir, "board.snap")
+}
+
+// Empty the log down to its magic
+func (s *Store) resetLog() error {
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.log.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := s.log.Write(logMagic); err != nil {
+		return err
+	}
+	s.logged = 0
+	return s.log.Sync()
+}
+
+func frame(record []byte) []byte {
+	f := make([]byte, frameHeaderLen, frameHeaderLen+len(record))
+	binary.BigEndian.PutUint32(f, uint32(len(record)))
+	binary.BigEndian.PutUint32(f[4:], crc32.ChecksumIEEE(record))
+	return append(f, record...)
+}
+
+// Split data into the records of its complete frames, and return what follows the last of them
+func frames(data []byte) ([][]byte, []byte) {
+	records := make([][]byte, 0)
+	for len(data) >= frameHeaderLen {
+		size := int(binary.BigEndian.Uint32(data))
+		if len(data)-frameHeaderLen < size {
+			break
+		}
+		record := data[frameHeaderLen : frameHeaderLen+size]
+		if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(data[4:]) {
+			break
+		}
+		records = append(records, record)
+		data = data[frameHeaderLen+size:]
+	}
+	return records, data
+}
+
+// Make the rename durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
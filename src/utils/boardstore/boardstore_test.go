@@ -0,0 +1,89 @@
+package boardstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boardstore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir)
+	assert.Nil(t, err, "Open")
+	records, err := s.Load()
+	assert.Nil(t, err, "Load empty")
+	assert.Equal(t, 0, len(records), "nothing stored yet")
+	assert.Nil(t, s.Append([]byte("a")), "Append")
+	assert.Nil(t, s.Append([]byte{}), "Append empty record")
+	assert.Nil(t, s.Append([]byte("bc")), "Append")
+	assert.Nil(t, s.Close(), "Close")
+
+	s, err = Open(dir)
+	assert.Nil(t, err, "Open again")
+	records, err = s.Load()
+	assert.Nil(t, err, "Load")
+	assert.Equal(t, [][]byte{[]byte("a"), {}, []byte("bc")}, records, "records survive")
+	assert.Equal(t, 3, s.Logged(), "Logged")
+	s.Close()
+}
+
+func TestTornTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boardstore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	s, _ := Open(dir)
+	s.Load()
+	s.Append([]byte("first"))
+	s.Append([]byte("second"))
+	s.Close()
+	path := filepath.Join(dir, "board.log")
+	data, _ := ioutil.ReadFile(path)
+	assert.Nil(t, ioutil.WriteFile(path, data[:len(data)-3], 0600), "tear the last frame")
+
+	s, _ = Open(dir)
+	records, err := s.Load()
+	assert.Nil(t, err, "Load torn log")
+	assert.Equal(t, [][]byte{[]byte("first")}, records, "torn frame dropped")
+	assert.Nil(t, s.Append([]byte("third")), "Append after torn frame")
+	s.Close()
+
+	s, _ = Open(dir)
+	records, _ = s.Load()
+	assert.Equal(t, [][]byte{[]byte("first"), []byte("third")}, records, "append follows the last complete frame")
+	s.Close()
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boardstore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	s, _ := Open(dir)
+	s.Load()
+	s.Append([]byte("a"))
+	s.Append([]byte("b"))
+	assert.Nil(t, s.Snapshot([][]byte{[]byte("ab")}), "Snapshot")
+	assert.Equal(t, 0, s.Logged(), "log emptied")
+	s.Append([]byte("c"))
+	s.Close()
+
+	s, _ = Open(dir)
+	records, err := s.Load()
+	assert.Nil(t, err, "Load")
+	assert.Equal(t, [][]byte{[]byte("ab"), []byte("c")}, records, "snapshot then log")
+	assert.Equal(t, 1, s.Logged(), "Logged")
+	s.Close()
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "board.snap"), []byte("garbage"), 0600), "corrupt snapshot")
+	s, _ = Open(dir)
+	_, err = s.Load()
+	assert.NotNil(t, err, "corrupted snapshot is an error")
+	s.Close()
+}
@@ -157,3 +157,20 @@ func (c *DLPolyCommit) VerifyEval(C *Element, x *Int, polyX *Int, w *Element) bo
 	// fmt.Printf("e1\n%s\ne2\n%s\n", e1.String(), e2.String())
 	return e1.Equals(e2)
 }
+
+// VerifyEvalInExponent checks the correctness of w like VerifyEval, for the value at x given only in the exponent as gPolyX = g^polyring(x)
+func (c *DLPolyCommit) VerifyEvalInExponent(C *Element, x *Int, gPolyX *Element, w *Element) bool {
+	e1 := c.pairing.NewGT()
+	e2 := c.pairing.NewGT()
+	t1 := c.pairing.NewGT()
+	t2 := c.pairing.NewG1()
+	e1.Pair(C, c.pk[0].Source())
+	exp := big.NewInt(0)
+	exp.SetString(x.String(), 10)
+	c.pk[0].PowBig(t2, exp)
+	t2.Div(c.pk[1].Source(), t2)
+	e2.Pair(w, t2)
+	t1.Pair(gPolyX, c.pk[0].Source())
+	e2.Mul(e2, t1)
+	return e1.Equals(e2)
+}
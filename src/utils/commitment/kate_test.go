@@ -78,6 +78,36 @@ func TestDLCommit_Large(test *testing.T) {
 	assert.True(test, c.VerifyEval(C, x, polyOfX, w), "VerifyEval")
 }
 
+func TestDLCommit_VerifyEvalInExponent(test *testing.T) {
+	c := new(DLPolyCommit)
+	const t = 3
+	rnd := rand.New(rand.NewSource(99))
+	c.SetupFix(t)
+
+	poly, err := polyring.NewRand(t, rnd, c.p)
+	assert.Nil(test, err, "NewRand")
+	x := NewInt(5)
+	polyOfX := new(Int)
+	c.polyEval(polyOfX, poly, x)
+
+	C := c.pairing.NewG1()
+	w := c.pairing.NewG1()
+	c.Commit(C, poly)
+	c.CreateWitness(w, poly, x)
+
+	dl := new(DLCommit)
+	dl.SetupFix()
+	gPolyX := dl.NewG1()
+	dl.Commit(gPolyX, polyOfX)
+	assert.True(test, c.VerifyEvalInExponent(C, x, gPolyX, w), "VerifyEvalInExponent")
+
+	// the value at another point does not open the commitment at x
+	other := new(Int)
+	c.polyEval(other, poly, NewInt(6))
+	dl.Commit(gPolyX, other)
+	assert.False(test, c.VerifyEvalInExponent(C, x, gPolyX, w), "VerifyEvalInExponent")
+}
+
 const bigPolyDegree = 100
 
 var rnd = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
@@ -0,0 +1,217 @@
+// Package elgamal implements threshold ElGamal encryption on the group of the commitments.
+// The public key is g^s for the secret s a committee shares on a polynomial of degree t. A ciphertext is g^r with the plaintext sealed under a key derived from pk^r = (g^r)^s.
+// The node at x decrypts its part as (g^r)^S(x) for its share S(x), with a Chaum–Pedersen proof that it used the same exponent as in its public share g^S(x). The parts of any t+1 nodes interpolate in the exponent to (g^r)^s, which opens the plaintext.
+package elgamal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/conv"
+	"github.com/bl4ck5un/ChuRP/src/utils/ecparam"
+	"github.com/bl4ck5un/ChuRP/src/utils/interpolation"
+	"github.com/ncw/gmp"
+)
+
+var curve = ecparam.PBC256
+
+var dc = newDLCommit()
+
+func newDLCommit() *commitment.DLCommit {
+	c := new(commitment.DLCommit)
+	c.SetupFix()
+	return c
+}
+
+// Hashed ahead of the key derivation and of the challenges of the proofs, so neither is mistaken for a hash made elsewhere
+const (
+	keyDomain   = "churp/elgamal/key/v1:"
+	proofDomain = "churp/elgamal/proof/v1:"
+)
+
+// Ciphertext of a plaintext under the public key of a committee
+type Ciphertext struct {
+	// g^r
+	C1 *pbc.Element
+	// The plaintext sealed with AES-GCM under a key derived from pk^r, after its nonce
+	C2 []byte
+}
+
+// Proof that a partial decryption has the same discrete logarithm to C1 as the public share of its node to g
+type Proof struct {
+	Challenge *gmp.Int
+	Response  *gmp.Int
+}
+
+// Bytes returns C1 in compressed form followed by C2
+func (c *Ciphertext) Bytes() []byte {
+	return append(c.C1.CompressedBytes(), c.C2...)
+}
+
+// ParseCiphertext reads a ciphertext written by Bytes
+func ParseCiphertext(b []byte) (*Ciphertext, error) {
+	n := int(curve.Pairing.G1CompressedLength())
+	if len(b) < n {
+		return nil, errors.New(fmt.Sprintf("a ciphertext takes at least %d bytes, got %d", n, len(b)))
+	}
+	c1, err := ParseC1(b[:n])
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{
+		C1: c1,
+		C2: append([]byte{}, b[n:]...),
+	}, nil
+}
+
+// ParseC1 reads the first part of a ciphertext, an element of G1 in compressed form
+func ParseC1(b []byte) (*pbc.Element, error) {
+	if uint(len(b)) != curve.Pairing.G1CompressedLength() {
+		return nil, errors.New(fmt.Sprintf("an element of G1 takes %d bytes, got %d", curve.Pairing.G1CompressedLength(), len(b)))
+	}
+	return curve.Pairing.NewG1().SetCompressedBytes(b), nil
+}
+
+// Encrypt seals plaintext under the public key pk, drawing the randomness from rnd
+func Encrypt(pk *pbc.Element, plaintext []byte, rnd io.Reader) (*Ciphertext, error) {
+	r, err := scalar(rnd)
+	if err != nil {
+		return nil, err
+	}
+	c1 := dc.NewG1()
+	dc.Commit(c1, r)
+	shared := dc.NewG1()
+	shared.PowBig(pk, conv.GmpInt2BigInt(r))
+	aead, err := newAEAD(shared)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rnd, nonce); err != nil {
+		return nil, err
+	}
+	return &Ciphertext{
+		C1: c1,
+		C2: aead.Seal(nonce, nonce, plaintext, c1.CompressedBytes()),
+	}, nil
+}
+
+// Open recovers the plaintext of c given C1^s, the combined partial decryptions
+func Open(c *Ciphertext, shared *pbc.Element) ([]byte, error) {
+	aead, err := newAEAD(shared)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.C2) < aead.NonceSize() {
+		return nil, errors.New("the ciphertext is too short")
+	}
+	nonce, sealed := c.C2[:aead.NonceSize()], c.C2[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, c.C1.CompressedBytes())
+	if err != nil {
+		return nil, errors.New("the ciphertext does not open under the combined decryption")
+	}
+	return plaintext, nil
+}
+
+// PartialDecrypt returns C1^share and the proof that it matches the public share g^share, drawing the nonce of the proof from rnd
+func PartialDecrypt(share *gmp.Int, c1 *pbc.Element, rnd io.Reader) (*pbc.Element, *Proof, error) {
+	exp := conv.GmpInt2BigInt(share)
+	partial := dc.NewG1()
+	partial.PowBig(c1, exp)
+	public := dc.NewG1()
+	dc.Commit(public, share)
+
+	// a1 = g^k, a2 = c1^k, z = k + c * share
+	k, err := scalar(rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	a1 := dc.NewG1()
+	dc.Commit(a1, k)
+	a2 := dc.NewG1()
+	a2.PowBig(c1, conv.GmpInt2BigInt(k))
+	challenge := challenge(public, c1, partial, a1, a2)
+	response := gmp.NewInt(0)
+	response.Mul(challenge, share)
+	response.Add(response, k)
+	response.Mod(response, curve.Ngmp)
+	return partial, &Proof{Challenge: challenge, Response: response}, nil
+}
+
+// VerifyPartial checks the proof that partial is C1 raised to the discrete logarithm of public
+func VerifyPartial(public *pbc.Element, c1 *pbc.Element, partial *pbc.Element, proof *Proof) bool {
+	if proof == nil || proof.Challenge == nil || proof.Response == nil {
+		return false
+	}
+	c := conv.GmpInt2BigInt(proof.Challenge)
+	tmp := dc.NewG1()
+	// a1 = g^z / public^c, a2 = c1^z / partial^c
+	a1 := dc.NewG1()
+	dc.Commit(a1, proof.Response)
+	a1.Div(a1, tmp.PowBig(public, c))
+	a2 := dc.NewG1()
+	a2.PowBig(c1, conv.GmpInt2BigInt(proof.Response))
+	a2.Div(a2, tmp.PowBig(partial, c))
+	return challenge(public, c1, partial, a1, a2).Cmp(proof.Challenge) == 0
+}
+
+// Combine interpolates in the exponent the partial decryptions of the nodes at xs to C1^s
+func Combine(xs []int, partials []*pbc.Element) (*pbc.Element, error) {
+	if len(xs) != len(partials) {
+		return nil, errors.New(fmt.Sprintf("%d partial decryptions for %d nodes", len(partials), len(xs)))
+	}
+	x := make([]*gmp.Int, len(xs))
+	for i, xi := range xs {
+		x[i] = gmp.NewInt(int64(xi))
+	}
+	lambda, err := interpolation.LagrangeCoefficients(x, curve.Ngmp)
+	if err != nil {
+		return nil, err
+	}
+	res := dc.NewG1().Set1()
+	tmp := dc.NewG1()
+	for i, partial := range partials {
+		tmp.PowBig(partial, conv.GmpInt2BigInt(lambda[i]))
+		res.Mul(res, tmp)
+	}
+	return res, nil
+}
+
+// Fiat–Shamir challenge of a proof, over the statement and the commitments of the prover
+func challenge(public, c1, partial, a1, a2 *pbc.Element) *gmp.Int {
+	h := sha256.New()
+	h.Write([]byte(proofDomain))
+	for _, e := range []*pbc.Element{curve.G, public, c1, partial, a1, a2} {
+		h.Write(e.CompressedBytes())
+	}
+	c := gmp.NewInt(0).SetBytes(h.Sum(nil))
+	return c.Mod(c, curve.Ngmp)
+}
+
+// AES-GCM under the key derived from the shared element
+func newAEAD(shared *pbc.Element) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte(keyDomain))
+	h.Write(shared.CompressedBytes())
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// A random exponent
+func scalar(rnd io.Reader) (*gmp.Int, error) {
+	x, err := rand.Int(rnd, curve.Nbig)
+	if err != nil {
+		return nil, err
+	}
+	return conv.BigInt2GmpInt(x), nil
+}
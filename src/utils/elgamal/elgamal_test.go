@@ -0,0 +1,72 @@
+package elgamal
+
+import (
+	"crypto/rand"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdDecryption(t *testing.T) {
+	const n, degree = 5, 2
+	poly, err := polyring.NewRand(degree, mrand.New(mrand.NewSource(7)), curve.Ngmp)
+	assert.Nil(t, err)
+	secret := gmp.NewInt(0)
+	poly.EvalMod(gmp.NewInt(0), curve.Ngmp, secret)
+	pk := dc.NewG1()
+	dc.Commit(pk, secret)
+
+	plaintext := []byte("the key to the vault")
+	c, err := Encrypt(pk, plaintext, rand.Reader)
+	if !assert.Nil(t, err) {
+		return
+	}
+	parsed, err := ParseCiphertext(c.Bytes())
+	assert.Nil(t, err)
+	assert.True(t, parsed.C1.Equals(c.C1))
+	assert.Equal(t, c.C2, parsed.C2)
+
+	partials := make([]*pbc.Element, n)
+	publics := make([]*pbc.Element, n)
+	for i := 0; i < n; i++ {
+		share := gmp.NewInt(0)
+		poly.EvalMod(gmp.NewInt(int64(i+1)), curve.Ngmp, share)
+		publics[i] = dc.NewG1()
+		dc.Commit(publics[i], share)
+		var proof *Proof
+		partials[i], proof, err = PartialDecrypt(share, c.C1, rand.Reader)
+		assert.Nil(t, err)
+		assert.True(t, VerifyPartial(publics[i], c.C1, partials[i], proof))
+		// the proof binds the partial decryption to the public share of its node
+		assert.False(t, VerifyPartial(pk, c.C1, partials[i], proof))
+		wrong, _, err := PartialDecrypt(gmp.NewInt(42), c.C1, rand.Reader)
+		assert.Nil(t, err)
+		assert.False(t, VerifyPartial(publics[i], c.C1, wrong, proof))
+	}
+
+	for _, xs := range [][]int{{1, 2, 3}, {5, 3, 1}, {2, 4, 5}} {
+		chosen := make([]*pbc.Element, len(xs))
+		for i, x := range xs {
+			chosen[i] = partials[x-1]
+		}
+		shared, err := Combine(xs, chosen)
+		assert.Nil(t, err)
+		got, err := Open(c, shared)
+		assert.Nil(t, err)
+		assert.Equal(t, plaintext, got)
+	}
+
+	// too few partial decryptions do not open the ciphertext
+	shared, err := Combine([]int{1, 2}, partials[:2])
+	assert.Nil(t, err)
+	_, err = Open(c, shared)
+	assert.NotNil(t, err)
+	_, err = Combine([]int{1, 1, 2}, partials[:3])
+	assert.NotNil(t, err)
+	_, err = ParseCiphertext(c.Bytes()[:10])
+	assert.NotNil(t, err)
+}
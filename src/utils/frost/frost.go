@@ -0,0 +1,297 @@
+// Package frost implements two-round threshold Schnorr signatures in the style of FROST over P-521, on keys shared with Feldman commitments from the p521 package.
+// A key y shared on a polynomial P of degree t gives the signer at i the share P(i) and the public share g^P(i), and the public key is Y = g^y.
+// In the first round every signer commits to two fresh nonces d and e with D = g^d and E = g^e. In the second, once it sees the commitments of all signers, it binds its nonces to the message and to that set with its binding factor rho,
+// and answers z = d + e*rho + lambda*P(i)*c, where lambda is its Lagrange coefficient among the signers and c the challenge of the group commitment R = Σ D + rho*E.
+// The responses of t+1 signers add up to a plain Schnorr signature (R, z) with g^z = R + c*Y and c = H(R, Y, m), see Verify.
+package frost
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/conv"
+	"github.com/bl4ck5un/ChuRP/src/utils/interpolation"
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/ncw/gmp"
+)
+
+// Hashed ahead of the binding factors and the challenge, so neither is mistaken for the other or for a hash made elsewhere
+const (
+	bindingDomain   = "churp/frost/rho/v1:"
+	challengeDomain = "churp/frost/chal/v1:"
+)
+
+// Order of the group of P-521
+var order = p521.Curve.Params().N
+
+// Nonce is the secret half of the first round of a signer. It must be used for one signature only.
+type Nonce struct {
+	hiding  *big.Int
+	binding *big.Int
+}
+
+// Commitment is the public half of the first round of the signer at Index
+type Commitment struct {
+	Index int
+	// g^d
+	Hiding p521.ECPoint
+	// g^e
+	Binding p521.ECPoint
+}
+
+// Signature is a Schnorr signature, R followed by z
+type Signature struct {
+	R p521.ECPoint
+	Z *big.Int
+}
+
+// Bytes returns R in compressed form followed by z in 66 bytes
+func (s *Signature) Bytes() []byte {
+	z := make([]byte, scalarLen())
+	zb := s.Z.Bytes()
+	copy(z[len(z)-len(zb):], zb)
+	return append(s.R.Bytes(), z...)
+}
+
+// ParseSignature reads a signature written by Bytes
+func ParseSignature(b []byte) (*Signature, error) {
+	n := len(b) - scalarLen()
+	if n <= 0 {
+		return nil, errors.New(fmt.Sprintf("a signature takes more than %d bytes, got %d", scalarLen(), len(b)))
+	}
+	r, err := p521.ParsePoint(b[:n])
+	if err != nil {
+		return nil, err
+	}
+	z := new(big.Int).SetBytes(b[n:])
+	if z.Cmp(order) >= 0 {
+		return nil, errors.New("z is not below the order of the group")
+	}
+	return &Signature{R: r, Z: z}, nil
+}
+
+// ParseCommitment reads the commitment of the signer at index from its compressed points
+func ParseCommitment(index int, hiding []byte, binding []byte) (*Commitment, error) {
+	d, err := p521.ParsePoint(hiding)
+	if err != nil {
+		return nil, err
+	}
+	e, err := p521.ParsePoint(binding)
+	if err != nil {
+		return nil, err
+	}
+	return &Commitment{Index: index, Hiding: d, Binding: e}, nil
+}
+
+// RandomScalar returns a uniformly random exponent drawn from rnd
+func RandomScalar(rnd io.Reader) (*big.Int, error) {
+	return rand.Int(rnd, order)
+}
+
+// Deal shares secret among signers 1 to n on a random polynomial of degree t drawn from rnd. It returns the Feldman commitment to the polynomial and the share of every signer, indexed by label - 1.
+func Deal(secret *big.Int, degree int, n int, rnd io.Reader) (p521.PolyCommit, []*big.Int, error) {
+	poly, err := polyring.New(degree)
+	if err != nil {
+		return p521.PolyCommit{}, nil, err
+	}
+	poly.SetCoefficientBig(0, conv.BigInt2GmpInt(new(big.Int).Mod(secret, order)))
+	for i := 1; i <= degree; i++ {
+		a, err := RandomScalar(rnd)
+		if err != nil {
+			return p521.PolyCommit{}, nil, err
+		}
+		poly.SetCoefficientBig(i, conv.BigInt2GmpInt(a))
+	}
+	shares := make([]*big.Int, n)
+	y := gmp.NewInt(0)
+	for i := range shares {
+		poly.EvalMod(gmp.NewInt(int64(i+1)), conv.BigInt2GmpInt(order), y)
+		shares[i] = conv.GmpInt2BigInt(y)
+	}
+	return p521.NewPolyCommit(poly), shares, nil
+}
+
+// NewNonce draws the nonces of a signer for one signature from rnd, and returns them with their commitment
+func NewNonce(index int, rnd io.Reader) (*Nonce, *Commitment, error) {
+	d, err := RandomScalar(rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, err := RandomScalar(rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Nonce{hiding: d, binding: e}, &Commitment{
+		Index:   index,
+		Hiding:  p521.ScalarBaseMult(d),
+		Binding: p521.ScalarBaseMult(e),
+	}, nil
+}
+
+// Sign is the second round of the signer at index with the given share of the key y, once it knows the commitments of all signers.
+// The commitments must include the one of the signer and name every signer once.
+func Sign(index int, share *big.Int, nonce *Nonce, pub p521.ECPoint, msg []byte, commitments []*Commitment) (*big.Int, error) {
+	signers, err := signerList(commitments)
+	if err != nil {
+		return nil, err
+	}
+	lambda, ok, err := lagrange(index, signers)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("signer %d has no commitment among the signers", index))
+	}
+	rho := bindingFactor(index, msg, commitments)
+	c := Challenge(groupCommitment(msg, commitments), pub, msg)
+
+	// z = d + e*rho + lambda*share*c
+	z := new(big.Int).Mul(nonce.binding, rho)
+	z.Add(z, nonce.hiding)
+	t := new(big.Int).Mul(lambda, share)
+	t.Mul(t, c)
+	z.Add(z, t)
+	return z.Mod(z, order), nil
+}
+
+// VerifyShare checks the response z of the signer at index against its public share, that is g^z = D + rho*E + (lambda*c)*g^P(index)
+func VerifyShare(index int, public p521.ECPoint, z *big.Int, pub p521.ECPoint, msg []byte, commitments []*Commitment) bool {
+	signers, err := signerList(commitments)
+	if err != nil {
+		return false
+	}
+	lambda, ok, err := lagrange(index, signers)
+	if err != nil || !ok {
+		return false
+	}
+	var own *Commitment
+	for _, cmt := range commitments {
+		if cmt.Index == index {
+			own = cmt
+		}
+	}
+	rho := bindingFactor(index, msg, commitments)
+	c := Challenge(groupCommitment(msg, commitments), pub, msg)
+	k := new(big.Int).Mul(lambda, c)
+	want := own.Hiding.Add(own.Binding.ScalarMult(rho)).Add(public.ScalarMult(k))
+	return p521.ScalarBaseMult(z).Equals(want)
+}
+
+// Aggregate adds up the responses of the signers, in the order of their commitments, into the signature on msg
+func Aggregate(msg []byte, commitments []*Commitment, responses []*big.Int) (*Signature, error) {
+	if len(commitments) != len(responses) {
+		return nil, errors.New(fmt.Sprintf("%d responses for %d signers", len(responses), len(commitments)))
+	}
+	if _, err := signerList(commitments); err != nil {
+		return nil, err
+	}
+	z := big.NewInt(0)
+	for _, zi := range responses {
+		z.Add(z, zi)
+	}
+	return &Signature{
+		R: groupCommitment(msg, commitments),
+		Z: z.Mod(z, order),
+	}, nil
+}
+
+// Verify checks a Schnorr signature on msg under the public key pub, g^z = R + c*pub with c = Challenge(R, pub, msg)
+func Verify(pub p521.ECPoint, msg []byte, sig *Signature) bool {
+	if sig == nil || sig.Z == nil || sig.R.IsInfinity() || pub.IsInfinity() {
+		return false
+	}
+	c := Challenge(sig.R, pub, msg)
+	return p521.ScalarBaseMult(sig.Z).Equals(sig.R.Add(pub.ScalarMult(c)))
+}
+
+// Challenge is the Schnorr challenge H(R, Y, m): SHA-512 of the domain, the compressed R and Y and the message, modulo the order of the group
+func Challenge(r p521.ECPoint, pub p521.ECPoint, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write([]byte(challengeDomain))
+	h.Write(r.Bytes())
+	h.Write(pub.Bytes())
+	h.Write(msg)
+	return hashToScalar(h.Sum(nil))
+}
+
+// The binding factor of the signer at index, which ties its nonces to the message and to the commitments of all signers
+func bindingFactor(index int, msg []byte, commitments []*Commitment) *big.Int {
+	h := sha512.New()
+	h.Write([]byte(bindingDomain))
+	binary.Write(h, binary.BigEndian, int64(index))
+	binary.Write(h, binary.BigEndian, int64(len(msg)))
+	h.Write(msg)
+	for _, cmt := range sorted(commitments) {
+		binary.Write(h, binary.BigEndian, int64(cmt.Index))
+		h.Write(cmt.Hiding.Bytes())
+		h.Write(cmt.Binding.Bytes())
+	}
+	return hashToScalar(h.Sum(nil))
+}
+
+// R = Σ D_i + rho_i*E_i over the signers
+func groupCommitment(msg []byte, commitments []*Commitment) p521.ECPoint {
+	r := p521.Infinity()
+	for _, cmt := range commitments {
+		rho := bindingFactor(cmt.Index, msg, commitments)
+		r = r.Add(cmt.Hiding).Add(cmt.Binding.ScalarMult(rho))
+	}
+	return r
+}
+
+// The labels of the signers, each once and none of them 0
+func signerList(commitments []*Commitment) ([]int, error) {
+	seen := make(map[int]bool)
+	signers := make([]int, len(commitments))
+	for i, cmt := range commitments {
+		if cmt == nil || cmt.Index <= 0 || seen[cmt.Index] {
+			return nil, errors.New("every signer must have a positive index and one commitment")
+		}
+		seen[cmt.Index] = true
+		signers[i] = cmt.Index
+	}
+	return signers, nil
+}
+
+// The Lagrange coefficient at 0 of index among the signers, and whether index is one of them
+func lagrange(index int, signers []int) (*big.Int, bool, error) {
+	xs := make([]*gmp.Int, len(signers))
+	at := -1
+	for i, s := range signers {
+		xs[i] = gmp.NewInt(int64(s))
+		if s == index {
+			at = i
+		}
+	}
+	if at < 0 {
+		return nil, false, nil
+	}
+	lambda, err := interpolation.LagrangeCoefficients(xs, conv.BigInt2GmpInt(order))
+	if err != nil {
+		return nil, false, err
+	}
+	return conv.GmpInt2BigInt(lambda[at]), true, nil
+}
+
+// The commitments ordered by the index of their signer
+func sorted(commitments []*Commitment) []*Commitment {
+	out := append([]*Commitment{}, commitments...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Index < out[j].Index })
+	return out
+}
+
+func hashToScalar(digest []byte) *big.Int {
+	k := new(big.Int).SetBytes(digest)
+	return k.Mod(k, order)
+}
+
+func scalarLen() int {
+	return (order.BitLen() + 7) / 8
+}
@@ -0,0 +1,107 @@
+package frost
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/bl4ck5un/ChuRP/src/utils/polycommit/p521"
+	"github.com/stretchr/testify/assert"
+)
+
+// Two rounds of the signers xs on msg with the shares dealt to them
+func sign(t *testing.T, xs []int, shares []*big.Int, pub p521.ECPoint, msg []byte) (*Signature, []*Commitment, []*big.Int) {
+	nonces := make([]*Nonce, len(xs))
+	commitments := make([]*Commitment, len(xs))
+	for i, x := range xs {
+		var err error
+		nonces[i], commitments[i], err = NewNonce(x, rand.Reader)
+		assert.Nil(t, err)
+	}
+	responses := make([]*big.Int, len(xs))
+	for i, x := range xs {
+		var err error
+		responses[i], err = Sign(x, shares[x-1], nonces[i], pub, msg, commitments)
+		assert.Nil(t, err)
+	}
+	sig, err := Aggregate(msg, commitments, responses)
+	assert.Nil(t, err)
+	return sig, commitments, responses
+}
+
+func TestThresholdSchnorr(t *testing.T) {
+	const n, degree = 5, 2
+	secret, err := RandomScalar(rand.Reader)
+	assert.Nil(t, err)
+	comm, shares, err := Deal(secret, degree, n, rand.Reader)
+	if !assert.Nil(t, err) {
+		return
+	}
+	pub := comm.EvalInExponent(big.NewInt(0))
+	assert.True(t, pub.Equals(p521.ScalarBaseMult(secret)))
+	for i, share := range shares {
+		assert.True(t, comm.VerifyEval(big.NewInt(int64(i+1)), share))
+	}
+
+	msg := []byte("pay 5 to bob")
+	for _, xs := range [][]int{{1, 2, 3}, {5, 3, 1}, {2, 3, 4, 5}} {
+		sig, commitments, responses := sign(t, xs, shares, pub, msg)
+		for i, x := range xs {
+			assert.True(t, VerifyShare(x, comm.EvalInExponent(big.NewInt(int64(x))), responses[i], pub, msg, commitments))
+		}
+		assert.True(t, Verify(pub, msg, sig))
+		assert.False(t, Verify(pub, []byte("pay 50 to bob"), sig))
+		parsed, err := ParseSignature(sig.Bytes())
+		assert.Nil(t, err)
+		assert.True(t, Verify(pub, msg, parsed))
+		assert.True(t, standardVerify(pub, msg, sig.Bytes()))
+	}
+
+	// a response under a wrong share is caught before it spoils the signature
+	xs := []int{1, 2, 3}
+	wrong := append([]*big.Int{}, shares...)
+	wrong[1] = big.NewInt(42)
+	sig, commitments, responses := sign(t, xs, wrong, pub, msg)
+	assert.False(t, VerifyShare(2, comm.EvalInExponent(big.NewInt(2)), responses[1], pub, msg, commitments))
+	assert.True(t, VerifyShare(1, comm.EvalInExponent(big.NewInt(1)), responses[0], pub, msg, commitments))
+	assert.False(t, Verify(pub, msg, sig))
+
+	// too few signers do not make a signature
+	sig, _, _ = sign(t, []int{1, 2}, shares, pub, msg)
+	assert.False(t, Verify(pub, msg, sig))
+}
+
+func TestSignChecksCommitments(t *testing.T) {
+	nonce, own, err := NewNonce(1, rand.Reader)
+	assert.Nil(t, err)
+	_, other, err := NewNonce(2, rand.Reader)
+	assert.Nil(t, err)
+	pub := p521.ScalarBaseMult(big.NewInt(7))
+	_, err = Sign(1, big.NewInt(7), nonce, pub, []byte("m"), []*Commitment{other})
+	assert.NotNil(t, err)
+	_, err = Sign(1, big.NewInt(7), nonce, pub, []byte("m"), []*Commitment{own, own})
+	assert.NotNil(t, err)
+	_, err = ParseSignature([]byte{1, 2, 3})
+	assert.NotNil(t, err)
+}
+
+// A Schnorr verifier written from the textbook equation against crypto/elliptic alone: g^z = R + H(R || Y || m)*Y, with the hash prefixed by the domain of the challenge
+func standardVerify(pub p521.ECPoint, msg []byte, sig []byte) bool {
+	curve := p521.Curve
+	n := (curve.Params().BitSize+7)/8 + 1
+	rx, ry := p521.Unmarshal(curve, sig[:n])
+	z := sig[n:]
+	yx, yy := p521.Unmarshal(curve, pub.Bytes())
+	h := sha512.New()
+	h.Write([]byte("churp/frost/chal/v1:"))
+	h.Write(sig[:n])
+	h.Write(pub.Bytes())
+	h.Write(msg)
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	c.Mod(c, curve.Params().N)
+	lx, ly := curve.ScalarBaseMult(z)
+	cx, cy := curve.ScalarMult(yx, yy, c.Bytes())
+	sx, sy := curve.Add(rx, ry, cx, cy)
+	return lx.Cmp(sx) == 0 && ly.Cmp(sy) == 0
+}
@@ -0,0 +1,226 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Curve used for the long-term identity keys of committee members
+var Curve = elliptic.P256()
+
+// byte length of r and s in a signature
+const scalarLen = 32
+
+// Identity is the long-term signing key of a committee member
+type Identity struct {
+	sk *ecdsa.PrivateKey
+}
+
+// Generate returns a fresh identity drawing randomness from rnd
+func Generate(rnd io.Reader) (*Identity, error) {
+	sk, err := ecdsa.GenerateKey(Curve, rnd)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{sk: sk}, nil
+}
+
+// Public returns the verification key of id
+func (id *Identity) Public() *ecdsa.PublicKey {
+	return &id.sk.PublicKey
+}
+
+// Sign returns a fixed-length r||s signature on sha256(data)
+func (id *Identity) Sign(data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, id.sk, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 2*scalarLen)
+	rBytes := r.Bytes()
+	sBytes := s.Bytes()
+	copy(sig[scalarLen-len(rBytes):scalarLen], rBytes)
+	copy(sig[2*scalarLen-len(sBytes):], sBytes)
+	return sig, nil
+}
+
+// Verify checks that sig is a signature on data under pk
+func Verify(pk *ecdsa.PublicKey, data []byte, sig []byte) bool {
+	if pk == nil || len(sig) != 2*scalarLen {
+		return false
+	}
+	digest := sha256.Sum256(data)
+	r := new(big.Int).SetBytes(sig[:scalarLen])
+	s := new(big.Int).SetBytes(sig[scalarLen:])
+	return ecdsa.Verify(pk, digest[:], r, s)
+}
+
+// MarshalPublicKey encodes pk in PKIX form
+func MarshalPublicKey(pk *ecdsa.PublicKey) ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(pk)
+}
+
+// ParsePublicKey decodes a PKIX encoded identity key
+func ParsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an ecdsa public key")
+	}
+	return pk, nil
+}
+
+// CommitteeID names the committee formed by the nodes holding pks, in label order
+func CommitteeID(pks []*ecdsa.PublicKey) string {
+	h := sha256.New()
+	for _, pk := range pks {
+		der, err := MarshalPublicKey(pk)
+		if err != nil {
+			panic(err.Error())
+		}
+		h.Write(der)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// KeyPath returns the file holding the private key of node label
+func KeyPath(metadataPath string, label int) string {
+	return metadataPath + "/sk" + strconv.Itoa(label)
+}
+
+// Save writes the private key of id to path in PEM form
+func (id *Identity) Save(path string) error {
+	der, err := x509.MarshalECPrivateKey(id.sk)
+	if err != nil {
+		return err
+	}
+	block := &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	return ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600)
+}
+
+// Load reads a private key written by Save
+func Load(path string) (*Identity, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New(fmt.Sprintf("no PEM block in %s", path))
+	}
+	sk, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{sk: sk}, nil
+}
+
+// WritePkList writes the public keys one per line, in label order, to the pk_list file
+func WritePkList(metadataPath string, pks []*ecdsa.PublicKey) error {
+	lines := make([]string, len(pks))
+	for i, pk := range pks {
+		der, err := MarshalPublicKey(pk)
+		if err != nil {
+			return err
+		}
+		lines[i] = hex.EncodeToString(der)
+	}
+	return ioutil.WriteFile(metadataPath+"/pk_list", []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// ReadPkList returns the public keys of the first counter nodes in the pk_list file
+func ReadPkList(metadataPath string, counter int) ([]*ecdsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(metadataPath + "/pk_list")
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < counter {
+		return nil, errors.New(fmt.Sprintf("pk_list has %d keys, need %d", len(lines), counter))
+	}
+	pks := make([]*ecdsa.PublicKey, counter)
+	for i := 0; i < counter; i++ {
+		der, err := hex.DecodeString(strings.TrimSpace(lines[i]))
+		if err != nil {
+			return nil, err
+		}
+		pks[i], err = ParsePublicKey(der)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pks, nil
+}
+
+// OperatorKeyPath returns the file holding the private key of the operator
+func OperatorKeyPath(metadataPath string) string {
+	return metadataPath + "/sk_operator"
+}
+
+// GenerateOperator creates the identity of the operator under metadataPath and publishes its public key in the pk_operator file
+func GenerateOperator(metadataPath string) error {
+	id, err := Generate(rand.Reader)
+	if err != nil {
+		return err
+	}
+	if err := id.Save(OperatorKeyPath(metadataPath)); err != nil {
+		return err
+	}
+	der, err := MarshalPublicKey(id.Public())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(metadataPath+"/pk_operator", []byte(hex.EncodeToString(der)+"\n"), 0644)
+}
+
+// ReadOperatorPk returns the public key in the pk_operator file, nil if there is no such file
+func ReadOperatorPk(metadataPath string) (*ecdsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(metadataPath + "/pk_operator")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(der)
+}
+
+// GenerateAll creates identities for nodes 1..counter and for the operator under metadataPath, and publishes their public keys
+func GenerateAll(counter int, metadataPath string) error {
+	pks := make([]*ecdsa.PublicKey, counter)
+	for i := 0; i < counter; i++ {
+		id, err := Generate(rand.Reader)
+		if err != nil {
+			return err
+		}
+		if err := id.Save(KeyPath(metadataPath, i+1)); err != nil {
+			return err
+		}
+		pks[i] = id.Public()
+	}
+	if err := GenerateOperator(metadataPath); err != nil {
+		return err
+	}
+	return WritePkList(metadataPath, pks)
+}
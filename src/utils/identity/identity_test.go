@@ -0,0 +1,74 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	id, err := Generate(rand.Reader)
+	assert.Nil(t, err, "Generate")
+
+	data := []byte("phase 2 commitment")
+	sig, err := id.Sign(data)
+	assert.Nil(t, err, "Sign")
+
+	assert.True(t, Verify(id.Public(), data, sig), "valid signature")
+	assert.False(t, Verify(id.Public(), []byte("phase 3 commitment"), sig), "wrong message")
+
+	other, _ := Generate(rand.Reader)
+	assert.False(t, Verify(other.Public(), data, sig), "wrong key")
+	assert.False(t, Verify(id.Public(), data, sig[1:]), "truncated signature")
+}
+
+func TestGenerateAll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	const counter = 3
+	assert.Nil(t, GenerateAll(counter, dir), "GenerateAll")
+
+	pks, err := ReadPkList(dir, counter)
+	assert.Nil(t, err, "ReadPkList")
+	assert.Equal(t, counter, len(pks))
+
+	for label := 1; label <= counter; label++ {
+		id, err := Load(KeyPath(dir, label))
+		assert.Nil(t, err, "Load")
+		sig, _ := id.Sign([]byte("msg"))
+		for j := range pks {
+			assert.Equal(t, j == label-1, Verify(pks[j], []byte("msg"), sig))
+		}
+	}
+
+	assert.Equal(t, CommitteeID(pks), CommitteeID(pks))
+	assert.NotEqual(t, CommitteeID(pks), CommitteeID(pks[:counter-1]), "committee of a subset")
+	assert.NotEqual(t, CommitteeID(pks), CommitteeID([]*ecdsa.PublicKey{pks[1], pks[0], pks[2]}), "reordered committee")
+
+	_, err = ReadPkList(dir, counter+1)
+	assert.NotNil(t, err, "too few keys")
+}
+
+func TestOperator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	pk, err := ReadOperatorPk(dir)
+	assert.Nil(t, err, "no operator key is not an error")
+	assert.Nil(t, pk)
+
+	assert.Nil(t, GenerateAll(2, dir), "GenerateAll")
+	pk, err = ReadOperatorPk(dir)
+	assert.Nil(t, err, "ReadOperatorPk")
+	id, err := Load(OperatorKeyPath(dir))
+	assert.Nil(t, err, "Load")
+	sig, _ := id.Sign([]byte("deal"))
+	assert.True(t, Verify(pk, []byte("deal"), sig))
+}
@@ -84,3 +84,31 @@ func LagrangeInterpolate(degree int, x []*gmp.Int, y []*gmp.Int, mod *gmp.Int) (
 
 	return resultPoly, nil
 }
+
+// LagrangeCoefficients returns the lambda[i] such that the sum of lambda[i] * y[i] is the value at 0 of the polynomial of degree len(x)-1 that passes through all points in x and y, for any y
+func LagrangeCoefficients(x []*gmp.Int, mod *gmp.Int) ([]*gmp.Int, error) {
+	lambda := make([]*gmp.Int, len(x))
+	diff := gmp.NewInt(0)
+	for i := range x {
+		numerator := gmp.NewInt(1)
+		denominator := gmp.NewInt(1)
+		for j := range x {
+			if j == i {
+				continue
+			}
+			// lambda_i = prod x[j] / (x[j] - x[i])
+			numerator.Mul(numerator, x[j])
+			numerator.Mod(numerator, mod)
+			diff.Sub(x[j], x[i])
+			denominator.Mul(denominator, diff)
+			denominator.Mod(denominator, mod)
+		}
+		if 0 == denominator.CmpInt32(0) {
+			return nil, errors.New("internal error: check duplication in x[]")
+		}
+		denominator.ModInverse(denominator, mod)
+		lambda[i] = numerator.Mul(numerator, denominator)
+		lambda[i].Mod(lambda[i], mod)
+	}
+	return lambda, nil
+}
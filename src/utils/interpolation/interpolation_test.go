@@ -63,3 +63,36 @@ func TestLagrangeInterpolate(t *testing.T) {
 	//reconstructedPoly.Print()
 	assert.True(t, reconstructedPoly.IsSame(originalPoly))
 }
+
+func TestLagrangeCoefficients(t *testing.T) {
+	p := gmp.NewInt(0)
+	gen_prime(p, 256)
+	r := rand.New(rand.NewSource(RAND_SEED))
+
+	const degree = 10
+	originalPoly, err := NewRand(degree, r, p)
+	assert.Nil(t, err, "New")
+	x := make([]*gmp.Int, degree+1)
+	y := make([]*gmp.Int, degree+1)
+	VecInit(x)
+	VecInit(y)
+	for i := range x {
+		x[i].SetInt64(int64(3*i + 1))
+	}
+	originalPoly.EvalModArray(x, p, y)
+
+	lambda, err := LagrangeCoefficients(x, p)
+	assert.Nil(t, err, "LagrangeCoefficients")
+	sum := gmp.NewInt(0)
+	for i := range lambda {
+		sum.Add(sum, gmp.NewInt(0).Mul(lambda[i], y[i]))
+	}
+	sum.Mod(sum, p)
+	constant, err := originalPoly.GetCoefficient(0)
+	assert.Nil(t, err, "GetCoefficient")
+	assert.Equal(t, 0, sum.Cmp(&constant))
+
+	x[1].Set(x[0])
+	_, err = LagrangeCoefficients(x, p)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,47 @@
+// Package logging sets up the structured logger the node, the bulletinboard and the clock share.
+// They log through logrus with the fields node, epoch, phase, peer and rpc wherever those apply.
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats Setup accepts
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup sets the lowest level the process logs, like debug or warning, and the format of its lines, text or json.
+// What is still printed through the standard logger goes through logrus at info level.
+func Setup(level string, format string) error {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	case FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return errors.New(fmt.Sprintf("unknown log format %q, use %s or %s", format, FormatText, FormatJSON))
+	}
+	logrus.SetLevel(l)
+	log.SetFlags(0)
+	log.SetOutput(stdWriter{})
+	return nil
+}
+
+// Writer of the standard logger, every line becomes an entry of logrus
+type stdWriter struct{}
+
+func (stdWriter) Write(p []byte) (int, error) {
+	logrus.Info(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
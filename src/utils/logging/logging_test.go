@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	defer logrus.SetOutput(os.Stderr)
+	defer log.SetOutput(os.Stderr)
+	var out bytes.Buffer
+
+	assert.NotNil(t, Setup("loud", FormatText))
+	assert.NotNil(t, Setup("info", "xml"))
+
+	assert.Nil(t, Setup("warning", FormatJSON))
+	logrus.SetOutput(&out)
+	entry := logrus.WithFields(logrus.Fields{"node": 2, "epoch": 3})
+	entry.Info("dropped below the level")
+	entry.WithField("phase", 1).Warn("reject point message")
+	var line map[string]interface{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "reject point message", line["msg"])
+	assert.Equal(t, "warning", line["level"])
+	assert.Equal(t, float64(2), line["node"])
+	assert.Equal(t, float64(3), line["epoch"])
+	assert.Equal(t, float64(1), line["phase"])
+
+	// the standard logger goes through logrus
+	assert.Nil(t, Setup("debug", FormatText))
+	out.Reset()
+	logrus.SetOutput(&out)
+	log.Print("from the standard logger")
+	assert.Contains(t, out.String(), "level=info")
+	assert.Contains(t, out.String(), "from the standard logger")
+}
@@ -0,0 +1,119 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Domain separation between the hashes of leaves, inner nodes and log links, as in RFC 6962
+const (
+	leafPrefix byte = 0
+	nodePrefix byte = 1
+	linkPrefix byte = 2
+)
+
+// Link returns the hash of a log entry chained to the hash of the entry before it, prev is empty for the first entry
+func Link(prev []byte, data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{linkPrefix})
+	h.Write(prev)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// Root returns the root of the Merkle tree over the given leaves
+func Root(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+	if len(leaves) == 1 {
+		return leafHash(leaves[0])
+	}
+	k := split(len(leaves))
+	return nodeHash(Root(leaves[:k]), Root(leaves[k:]))
+}
+
+// Proof returns the hashes that lead from the leaf at index to the root, bottom up
+func Proof(leaves [][]byte, index int) ([][]byte, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("leaf %d out of range [0, %d)", index, len(leaves))
+	}
+	if len(leaves) == 1 {
+		return [][]byte{}, nil
+	}
+	k := split(len(leaves))
+	if index < k {
+		path, err := Proof(leaves[:k], index)
+		return append(path, Root(leaves[k:])), err
+	}
+	path, err := Proof(leaves[k:], index-k)
+	return append(path, Root(leaves[:k])), err
+}
+
+// Verify checks that leaf is at index in the tree of size leaves with the given root
+func Verify(root []byte, leaf []byte, index int, size int, proof [][]byte) error {
+	if index < 0 || index >= size {
+		return fmt.Errorf("leaf %d out of range [0, %d)", index, size)
+	}
+	computed, err := rootFromPath(leafHash(leaf), index, size, proof)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(computed, root) {
+		return errors.New("proof does not lead to the root")
+	}
+	return nil
+}
+
+func rootFromPath(hash []byte, index int, size int, proof [][]byte) ([]byte, error) {
+	if size == 1 {
+		if len(proof) != 0 {
+			return nil, errors.New("proof is too long")
+		}
+		return hash, nil
+	}
+	if len(proof) == 0 {
+		return nil, errors.New("proof is too short")
+	}
+	last := len(proof) - 1
+	k := split(size)
+	if index < k {
+		left, err := rootFromPath(hash, index, k, proof[:last])
+		if err != nil {
+			return nil, err
+		}
+		return nodeHash(left, proof[last]), nil
+	}
+	right, err := rootFromPath(hash, index-k, size-k, proof[:last])
+	if err != nil {
+		return nil, err
+	}
+	return nodeHash(proof[last], right), nil
+}
+
+// The largest power of two smaller than n, for n > 1
+func split(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func leafHash(leaf []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(leaf)
+	return h.Sum(nil)
+}
+
+func nodeHash(left []byte, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
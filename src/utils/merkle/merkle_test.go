@@ -0,0 +1,51 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = []byte{byte(i), byte(i * 7)}
+	}
+	return leaves
+}
+
+func TestRootVector(t *testing.T) {
+	// the single leaf and two leaf trees of RFC 6962 over the empty leaf and the leaf 0x00
+	assert.Equal(t, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		hex.EncodeToString(Root([][]byte{{}})), "one empty leaf")
+	assert.Equal(t, "fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		hex.EncodeToString(Root([][]byte{{}, {0}})), "two leaves")
+}
+
+func TestProofVerify(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := testLeaves(n)
+		root := Root(leaves)
+		for i := 0; i < n; i++ {
+			proof, err := Proof(leaves, i)
+			assert.Nil(t, err, "Proof")
+			assert.Nil(t, Verify(root, leaves[i], i, n, proof), "leaf %d of %d", i, n)
+			if n > 1 {
+				assert.NotNil(t, Verify(root, leaves[(i+1)%n], i, n, proof), "other leaf %d of %d", i, n)
+				assert.NotNil(t, Verify(root, leaves[i], (i+1)%n, n, proof), "other index %d of %d", i, n)
+				assert.NotNil(t, Verify(root, leaves[i], i, n, proof[1:]), "short proof %d of %d", i, n)
+			}
+		}
+	}
+	_, err := Proof(testLeaves(3), 3)
+	assert.NotNil(t, err, "index out of range")
+}
+
+func TestLink(t *testing.T) {
+	first := Link(nil, []byte("a"))
+	second := Link(first, []byte("b"))
+	assert.Equal(t, second, Link(first, []byte("b")), "deterministic")
+	assert.NotEqual(t, second, Link(Link(nil, []byte("c")), []byte("b")), "depends on prev")
+	assert.NotEqual(t, Root([][]byte{[]byte("a")}), first, "separated from leaves")
+}
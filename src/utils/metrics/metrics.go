@@ -0,0 +1,330 @@
+// Package metrics keeps counters, gauges and histograms and writes them in the Prometheus text exposition format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets of DurationBuckets span the phases of an epoch, from milliseconds for small committees to minutes for large ones
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Registry holds the metrics of one node or bulletinboard. Every metric has a family of series, one for each combination of values of its labels.
+type Registry struct {
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	values []string
+	// Value of a counter or a gauge
+	value float64
+	// Observations of a histogram in each bucket, not cumulative, the last one above every bucket
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Counter is a value that only goes up, like the bytes a node received
+type Counter struct {
+	registry *Registry
+	family   *family
+}
+
+// Gauge is a value that goes up and down, like the current epoch
+type Gauge struct {
+	registry *Registry
+	family   *family
+}
+
+// Histogram counts observations in buckets, like the durations of a phase
+type Histogram struct {
+	registry *Registry
+	family   *family
+}
+
+// Counter registers a counter with the given labels. A name registers once, registering it again is a programming error.
+func (r *Registry) Counter(name string, help string, labels ...string) *Counter {
+	return &Counter{registry: r, family: r.register(name, help, "counter", labels, nil)}
+}
+
+// Gauge registers a gauge with the given labels
+func (r *Registry) Gauge(name string, help string, labels ...string) *Gauge {
+	return &Gauge{registry: r, family: r.register(name, help, "gauge", labels, nil)}
+}
+
+// Histogram registers a histogram with the given upper bounds of its buckets, in increasing order, and labels
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{registry: r, family: r.register(name, help, "histogram", labels, buckets)}
+}
+
+func (r *Registry) register(name string, help string, kind string, labels []string, buckets []float64) *family {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.families[name]; ok {
+		panic(fmt.Sprintf("metric %s registered twice", name))
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// The series of the given label values, created on first use. The caller holds the mutex of the registry.
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s has labels %v, got values %v", f.name, f.labels, values))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: append([]string{}, values...)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets)+1)
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Inc adds one to the series of the label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the series of the label values
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %s cannot go down by %v", c.family.name, v))
+	}
+	c.registry.mutex.Lock()
+	defer c.registry.mutex.Unlock()
+	c.family.with(values).value += v
+}
+
+// Value returns the series of the label values
+func (c *Counter) Value(values ...string) float64 {
+	c.registry.mutex.Lock()
+	defer c.registry.mutex.Unlock()
+	return c.family.with(values).value
+}
+
+// Total returns the sum of all series of the counter
+func (c *Counter) Total() float64 {
+	c.registry.mutex.Lock()
+	defer c.registry.mutex.Unlock()
+	total := 0.0
+	for _, s := range c.family.series {
+		total += s.value
+	}
+	return total
+}
+
+// Set sets the series of the label values to v
+func (g *Gauge) Set(v float64, values ...string) {
+	g.registry.mutex.Lock()
+	defer g.registry.mutex.Unlock()
+	g.family.with(values).value = v
+}
+
+// Value returns the series of the label values
+func (g *Gauge) Value(values ...string) float64 {
+	g.registry.mutex.Lock()
+	defer g.registry.mutex.Unlock()
+	return g.family.with(values).value
+}
+
+// Observe counts v in the series of the label values
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.registry.mutex.Lock()
+	defer h.registry.mutex.Unlock()
+	s := h.family.with(values)
+	i := sort.SearchFloat64s(h.family.buckets, v)
+	s.counts[i]++
+	s.sum += v
+	s.count++
+}
+
+// Count returns how many observations the series of the label values has
+func (h *Histogram) Count(values ...string) uint64 {
+	h.registry.mutex.Lock()
+	defer h.registry.mutex.Unlock()
+	return h.family.with(values).count
+}
+
+// Sum returns the sum of the observations of the series of the label values
+func (h *Histogram) Sum(values ...string) float64 {
+	h.registry.mutex.Lock()
+	defer h.registry.mutex.Unlock()
+	return h.family.with(values).sum
+}
+
+// WriteText writes every metric in the text exposition format, the families ordered by name and their series by label values
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := bufio.NewWriter(w)
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(out, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(out, "# TYPE %s %s\n", f.name, f.kind)
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != "histogram" {
+				fmt.Fprintf(out, "%s%s %s\n", f.name, labelText(f.labels, s.values, "", ""), formatValue(s.value))
+				continue
+			}
+			cumulative := uint64(0)
+			for i, bound := range f.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(out, "%s_bucket%s %d\n", f.name, labelText(f.labels, s.values, "le", formatValue(bound)), cumulative)
+			}
+			fmt.Fprintf(out, "%s_bucket%s %d\n", f.name, labelText(f.labels, s.values, "le", "+Inf"), s.count)
+			fmt.Fprintf(out, "%s_sum%s %s\n", f.name, labelText(f.labels, s.values, "", ""), formatValue(s.sum))
+			fmt.Fprintf(out, "%s_count%s %d\n", f.name, labelText(f.labels, s.values, "", ""), s.count)
+		}
+	}
+	return out.Flush()
+}
+
+// ServeHTTP answers a scrape with the text exposition format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// Serve serves the registry at /metrics on addr until the listener fails
+func Serve(addr string, r *Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	return http.ListenAndServe(addr, mux)
+}
+
+// Phases times the phases of an epoch and observes how long each took in a histogram with a phase label
+type Phases struct {
+	histogram *Histogram
+	mutex     sync.Mutex
+	started   map[string]time.Time
+	took      map[string]time.Duration
+}
+
+// NewPhases returns the timer of phases that observes in h
+func NewPhases(h *Histogram) *Phases {
+	return &Phases{
+		histogram: h,
+		started:   make(map[string]time.Time),
+		took:      make(map[string]time.Duration),
+	}
+}
+
+// Reset forgets the phases of the previous epoch
+func (p *Phases) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.started = make(map[string]time.Time)
+	p.took = make(map[string]time.Duration)
+}
+
+// Start starts timing a phase
+func (p *Phases) Start(phase string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.started[phase] = time.Now()
+}
+
+// End stops timing a phase and observes how long it took. A phase that was not started, like one a restarted node only replayed, is not observed.
+func (p *Phases) End(phase string) {
+	p.mutex.Lock()
+	start, ok := p.started[phase]
+	if !ok {
+		p.mutex.Unlock()
+		return
+	}
+	delete(p.started, phase)
+	took := time.Since(start)
+	p.took[phase] = took
+	p.mutex.Unlock()
+	p.histogram.Observe(took.Seconds(), phase)
+}
+
+// Took returns how long a phase of the current epoch took, 0 if it has not ended
+func (p *Phases) Took(phase string) time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.took[phase]
+}
+
+func labelText(labels []string, values []string, extra string, extraValue string) string {
+	pairs := make([]string, 0, len(labels)+1)
+	for i, label := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label, escapeValue(values[i])))
+	}
+	if extra != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extra, extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var helpEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+var valueEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\"", "\\\"")
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeValue(s string) string {
+	return valueEscaper.Replace(s)
+}
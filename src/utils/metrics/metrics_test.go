@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	r := New()
+	received := r.Counter("churp_received_bytes_total", "Bytes received", "rpc", "peer")
+	epoch := r.Gauge("churp_epoch", "Current epoch")
+	duration := r.Histogram("churp_phase_duration_seconds", "How long a phase took", []float64{0.5, 1}, "phase")
+
+	received.Add(10, "SharePhase1", "node2")
+	received.Add(5, "SharePhase1", "node2")
+	received.Inc("WritePhase2", `a "quoted"`+"\nvalue")
+	epoch.Set(3)
+	duration.Observe(0.2, "sharedist")
+	duration.Observe(1, "sharedist")
+	duration.Observe(7, "sharedist")
+
+	var out bytes.Buffer
+	assert.Nil(t, r.WriteText(&out))
+	assert.Equal(t, `# HELP churp_epoch Current epoch
+# TYPE churp_epoch gauge
+churp_epoch 3
+# HELP churp_phase_duration_seconds How long a phase took
+# TYPE churp_phase_duration_seconds histogram
+churp_phase_duration_seconds_bucket{phase="sharedist",le="0.5"} 1
+churp_phase_duration_seconds_bucket{phase="sharedist",le="1"} 2
+churp_phase_duration_seconds_bucket{phase="sharedist",le="+Inf"} 3
+churp_phase_duration_seconds_sum{phase="sharedist"} 8.2
+churp_phase_duration_seconds_count{phase="sharedist"} 3
+# HELP churp_received_bytes_total Bytes received
+# TYPE churp_received_bytes_total counter
+churp_received_bytes_total{rpc="SharePhase1",peer="node2"} 15
+churp_received_bytes_total{rpc="WritePhase2",peer="a \"quoted\"\nvalue"} 1
+`, out.String())
+
+	assert.Equal(t, 16.0, received.Total())
+	assert.Equal(t, uint64(3), duration.Count("sharedist"))
+}
+
+func TestMisuse(t *testing.T) {
+	r := New()
+	c := r.Counter("c", "A counter", "label")
+	assert.Panics(t, func() { r.Gauge("c", "The same name") })
+	assert.Panics(t, func() { c.Inc() }, "missing label value")
+	assert.Panics(t, func() { c.Add(-1, "x") }, "counter going down")
+}
+
+func TestPhases(t *testing.T) {
+	r := New()
+	h := r.Histogram("d", "Durations", DurationBuckets, "phase")
+	p := NewPhases(h)
+	p.Start("epoch")
+	time.Sleep(time.Millisecond)
+	p.End("epoch")
+	p.End("reconstruction")
+	assert.True(t, p.Took("epoch") >= time.Millisecond)
+	assert.Equal(t, time.Duration(0), p.Took("reconstruction"), "a phase that never started")
+	assert.Equal(t, uint64(1), h.Count("epoch"))
+	assert.Equal(t, uint64(0), h.Count("reconstruction"))
+
+	p.Reset()
+	assert.Equal(t, time.Duration(0), p.Took("epoch"))
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := New()
+	r.Gauge("churp_epoch", "Current epoch").Set(1)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := ioutil.ReadAll(w.Body)
+	assert.Contains(t, w.Header().Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, string(body), "churp_epoch 1\n")
+}
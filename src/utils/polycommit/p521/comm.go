@@ -159,24 +159,7 @@ func (comm PolyCommit) Verify(poly polyring.Polynomial) bool {
 }
 
 func (comm PolyCommit) VerifyEval(x *big.Int, y *big.Int) bool {
-	gYRef := NewECPoint(Curve.ScalarBaseMult(y.Bytes()))
-
-	xx := big.NewInt(1)
-
-	gPxx := big.NewInt(0)
-	gPxy := big.NewInt(0)
-	for i := range comm.c {
-		px, py := Curve.ScalarMult(comm.c[i].x, comm.c[i].y, xx.Bytes())
-
-		gPxx, gPxy = Curve.Add(gPxx, gPxy, px, py)
-
-		xx.Mul(xx, x)
-		xx.Mod(xx, Curve.Params().N)
-	}
-
-	gPx := NewECPoint(gPxx, gPxy)
-
-	return gPx.Equals(gYRef)
+	return comm.EvalInExponent(x).Equals(ScalarBaseMult(y))
 }
 
 // return a commitment to Q+R
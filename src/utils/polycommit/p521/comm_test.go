@@ -119,3 +119,50 @@ func TestAdditiveHomomorphism(t *testing.T) {
 	comm3 := AdditiveHomomorphism(com1, com2)
 	assert.True(t, comm3.Verify(poly3))
 }
+
+func TestPolyCommit_Compressed(t *testing.T) {
+	comm := NewPolyCommit(poly2)
+	parsed, err := ParsePolyCommit(comm.Compressed())
+	assert.Nil(t, err)
+	assert.True(t, parsed.Equals(comm))
+
+	// g^P(0) is the commitment to the constant coefficient
+	assert.True(t, comm.EvalInExponent(big.NewInt(0)).Equals(ScalarBaseMult(big.NewInt(11))))
+	assert.False(t, comm.VerifyEval(big.NewInt(2), big.NewInt(11)))
+
+	// the point at infinity and bytes off the curve are not points
+	_, err = ParsePoint(Infinity().Bytes())
+	assert.NotNil(t, err)
+	bad := ScalarBaseMult(big.NewInt(5)).Bytes()
+	_, err = ParsePoint(bad[1:])
+	assert.NotNil(t, err)
+	for i := 1; i < len(bad); i++ {
+		bad[i] = 0xff
+	}
+	_, err = ParsePoint(bad)
+	assert.NotNil(t, err)
+
+	// the group law holds on the parsed points
+	p, err := ParsePoint(ScalarBaseMult(big.NewInt(5)).Bytes())
+	assert.Nil(t, err)
+	assert.True(t, p.Add(p).Equals(ScalarBaseMult(big.NewInt(10))))
+	assert.True(t, p.ScalarMult(big.NewInt(3)).Equals(ScalarBaseMult(big.NewInt(15))))
+	assert.True(t, p.Add(Infinity()).Equals(p))
+}
+
+func TestParseZeroPolyCommit(t *testing.T) {
+	// poly is zero at 0, its commitment goes without the constant one
+	comm := NewPolyCommit(poly)
+	zero, err := ParseZeroPolyCommit(comm.CompressedZero())
+	assert.Nil(t, err)
+	assert.Equal(t, comm.Degree(), zero.Degree())
+	assert.True(t, zero.Verify(poly))
+	assert.True(t, zero.EvalInExponent(big.NewInt(0)).IsInfinity())
+
+	// refreshing a commitment with it keeps the constant commitment
+	refreshed := AdditiveHomomorphism(NewPolyCommit(poly2), zero)
+	assert.True(t, refreshed.EvalInExponent(big.NewInt(0)).Equals(ScalarBaseMult(big.NewInt(11))))
+
+	_, err = ParseZeroPolyCommit(nil)
+	assert.NotNil(t, err)
+}
@@ -27,11 +27,19 @@ func Marshal(curve elliptic.Curve, x, y *big.Int) []byte {
 
 // Unmarshal decodes an ECC Point from any representation
 func Unmarshal(curve elliptic.Curve, data []byte) (x, y *big.Int) {
+	x, y, ok := decompress(curve, data)
+	if !ok {
+		// If this happens then you're dealing with an invalid point.
+		panic("Invalid point")
+	}
+	return x, y
+}
+
+// decompress decodes a point written by Marshal, ok is false if data holds no point of the curve
+func decompress(curve elliptic.Curve, data []byte) (x, y *big.Int, ok bool) {
 	// handle infinity points specially
 	if data[0] == 0xff {
-		x = big.NewInt(0)
-		y = big.NewInt(0)
-		return
+		return big.NewInt(0), big.NewInt(0), true
 	}
 
 	// Split the sign byte from the rest
@@ -66,10 +74,7 @@ func Unmarshal(curve elliptic.Curve, data []byte) (x, y *big.Int) {
 	// This is where Go's big int library redeems itself.
 	y = big.NewInt(0).ModSqrt(y_squared, c.P)
 	if y == nil {
-		// If this happens then you're dealing with an invalid point.
-		// Panic, return an error, whatever you want.
-		panic("Invalid point")
-		return
+		return nil, nil, false
 	}
 
 	// Finally, check if you have the correct root. If not you want
@@ -79,5 +84,5 @@ func Unmarshal(curve elliptic.Curve, data []byte) (x, y *big.Int) {
 		y.Mod(y, c.P)
 	}
 
-	return x, y
+	return x, y, true
 }
@@ -0,0 +1,116 @@
+package p521
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Group operations on points of Curve, with the point at infinity as (0, 0)
+
+// Infinity returns the neutral element of the group
+func Infinity() ECPoint {
+	return NewECPoint(big.NewInt(0), big.NewInt(0))
+}
+
+// ScalarBaseMult returns k times the base point
+func ScalarBaseMult(k *big.Int) ECPoint {
+	return NewECPoint(Curve.ScalarBaseMult(scalarBytes(k)))
+}
+
+// ScalarMult returns k times ecp
+func (ecp ECPoint) ScalarMult(k *big.Int) ECPoint {
+	if ecp.IsInfinity() {
+		return Infinity()
+	}
+	return NewECPoint(Curve.ScalarMult(ecp.x, ecp.y, scalarBytes(k)))
+}
+
+// Add returns the sum of ecp and other
+func (ecp ECPoint) Add(other ECPoint) ECPoint {
+	return NewECPoint(Curve.Add(ecp.x, ecp.y, other.x, other.y))
+}
+
+// IsInfinity tells whether ecp is the neutral element
+func (ecp ECPoint) IsInfinity() bool {
+	return ecp.x.Sign() == 0 && ecp.y.Sign() == 0
+}
+
+// Bytes returns the compressed form of ecp
+func (ecp ECPoint) Bytes() []byte {
+	return Marshal(Curve, ecp.x, ecp.y)
+}
+
+// ParsePoint reads a point in compressed form, which must be on Curve and not the point at infinity
+func ParsePoint(b []byte) (ECPoint, error) {
+	byteLen := (Curve.Params().BitSize + 7) >> 3
+	if len(b) != 1+byteLen || (b[0] != 2 && b[0] != 3) {
+		return ECPoint{}, errors.New(fmt.Sprintf("a point of P-521 takes %d bytes after a 2 or a 3", byteLen))
+	}
+	x, y, ok := decompress(Curve, b)
+	if !ok || !Curve.IsOnCurve(x, y) {
+		return ECPoint{}, errors.New("not a point of P-521")
+	}
+	return NewECPoint(x, y), nil
+}
+
+// The big-endian bytes of k modulo the order of the group
+func scalarBytes(k *big.Int) []byte {
+	return new(big.Int).Mod(k, Curve.Params().N).Bytes()
+}
+
+// EvalInExponent returns g^P(x) for the polynomial P committed to
+func (comm PolyCommit) EvalInExponent(x *big.Int) ECPoint {
+	res := Infinity()
+	xx := big.NewInt(1)
+	for i := range comm.c {
+		res = res.Add(comm.c[i].ScalarMult(xx))
+		xx.Mul(xx, x)
+		xx.Mod(xx, Curve.Params().N)
+	}
+	return res
+}
+
+// Degree returns the degree of the polynomial committed to
+func (comm PolyCommit) Degree() int {
+	return len(comm.c) - 1
+}
+
+// Compressed returns the compressed commitments to the coefficients, from the constant one up
+func (comm PolyCommit) Compressed() [][]byte {
+	out := make([][]byte, len(comm.c))
+	for i := range comm.c {
+		out[i] = comm.c[i].Bytes()
+	}
+	return out
+}
+
+// ParsePolyCommit reads a commitment written by Compressed
+func ParsePolyCommit(b [][]byte) (PolyCommit, error) {
+	if len(b) == 0 {
+		return PolyCommit{}, errors.New("a commitment holds at least one point")
+	}
+	comm := PolyCommit{c: make([]ECPoint, len(b))}
+	for i := range b {
+		point, err := ParsePoint(b[i])
+		if err != nil {
+			return PolyCommit{}, err
+		}
+		comm.c[i] = point
+	}
+	return comm, nil
+}
+
+// CompressedZero returns the compressed commitments to the coefficients from degree 1 up, for a polynomial that is zero at 0 and whose constant commitment is the point at infinity
+func (comm PolyCommit) CompressedZero() [][]byte {
+	return comm.Compressed()[1:]
+}
+
+// ParseZeroPolyCommit reads a commitment written by CompressedZero, which can only commit to a polynomial that is zero at 0
+func ParseZeroPolyCommit(b [][]byte) (PolyCommit, error) {
+	tail, err := ParsePolyCommit(b)
+	if err != nil {
+		return PolyCommit{}, err
+	}
+	return PolyCommit{c: append([]ECPoint{Infinity()}, tail.c...)}, nil
+}
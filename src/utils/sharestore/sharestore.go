@@ -0,0 +1,351 @@
+package sharestore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Share file layout: magic | salt | nonce | AES-256-GCM ciphertext of the JSON encoded State.
+// The magic and the salt are authenticated as additional data.
+var magic = []byte("CHURPSHR1")
+
+// Log file layout: walMagic | salt, followed by one frame per record.
+// A frame is the big-endian length of the rest of the frame | nonce | ciphertext of the JSON encoded Record.
+var walMagic = []byte("CHURPWAL1")
+
+const (
+	saltLen = 16
+	keyLen  = 32
+	// scrypt cost parameters for deriving the storage key from the operator passphrase
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrNoState is returned by Load when nothing has been saved yet
+var ErrNoState = errors.New("no share state saved")
+
+// Point is one evaluation of a sharing polynomial together with its witness
+type Point struct {
+	X       int32
+	Y       []byte
+	Witness []byte
+}
+
+// State is what a node needs to rejoin the committee after a restart
+type State struct {
+	// Last epoch whose share distribution the node verified
+	Epoch int64
+	// Committee ID, label and size of the committee the shares belong to
+	Committee string
+	Label     int
+	Counter   int
+	// Share of the node on every polynomial, indexed by label - 1
+	Shares []Point
+	// Compressed commitments to every polynomial, indexed by label - 1
+	PolyCmts [][]byte
+	// Set when the operator dealt the shares, the commitments to them on the bulletinboard then carry no signature
+	Dealt bool
+	// Share of the P-521 signing key, nil if there is none
+	Schnorr *SchnorrKey
+	// Secrets the committee holds besides the one it started with, whose shares are the ones above
+	Secrets []Secret
+	// Shares the node held before Epoch, kept until the bulletinboard confirms that Epoch completed, nil once it did
+	Previous *State
+}
+
+// Secret is the share state of a node for a secret the operator created under an ID
+type Secret struct {
+	ID       string
+	Shares   []Point
+	PolyCmts [][]byte
+	Dealt    bool
+}
+
+// SchnorrKey is the share of a node of a P-521 signing key, with the Feldman commitment to the polynomial of the key
+type SchnorrKey struct {
+	Share      []byte
+	Commitment [][]byte
+	// Set while the share is the one the operator dealt, no epoch has refreshed it yet
+	Dealt bool
+}
+
+// Record is an entry of the write-ahead log a node keeps during an epoch
+type Record struct {
+	Epoch int64
+	Kind  string
+	Data  []byte
+}
+
+// Store keeps the share state and the write-ahead log of one node encrypted at rest
+type Store struct {
+	path string
+	salt []byte
+	aead cipher.AEAD
+}
+
+// Path returns the file holding the share state of node label
+func Path(metadataPath string, label int) string {
+	return metadataPath + "/share" + strconv.Itoa(label)
+}
+
+// Open derives the storage key from passphrase. The salt is kept from the share file or the log if either exists, otherwise a fresh one is drawn.
+func Open(path string, passphrase []byte) (*Store, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	salt, err := readSalt(path, magic)
+	if err == nil && salt == nil {
+		salt, err = readSalt(path+".wal", walMagic)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		salt = make([]byte, saltLen)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+	}
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: path, salt: salt, aead: aead}, nil
+}
+
+// Salt of an existing file, nil if there is no file
+func readSalt(path string, m []byte) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(m)+saltLen || !bytes.Equal(data[:len(m)], m) {
+		return nil, errors.New(fmt.Sprintf("%s is not a share file", path))
+	}
+	return data[len(m) : len(m)+saltLen], nil
+}
+
+func (s *Store) header() []byte {
+	return append(append([]byte{}, magic...), s.salt...)
+}
+
+func (s *Store) walHeader() []byte {
+	return append(append([]byte{}, walMagic...), s.salt...)
+}
+
+// Save replaces the stored state. The new file is written and synced next to the old one and renamed over it, so a crash leaves either the old or the new state.
+func (s *Store) Save(state *State) error {
+	plain, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	header := s.header()
+	data := append(append(header, nonce...), s.aead.Seal(nil, nonce, plain, header)...)
+
+	dir := filepath.Dir(s.path)
+	f, err := ioutil.TempFile(dir, filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(dir)
+}
+
+// Load returns the stored state, or ErrNoState if none was saved
+func (s *Store) Load() (*State, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoState
+	}
+	if err != nil {
+		return nil, err
+	}
+	header := s.header()
+	nonceLen := s.aead.NonceSize()
+	if len(data) < len(header)+nonceLen || !bytes.Equal(data[:len(header)], header) {
+		return nil, errors.New(fmt.Sprintf("%s is not a share file", s.path))
+	}
+	nonce := data[len(header) : len(header)+nonceLen]
+	plain, err := s.aead.Open(nil, nonce, data[len(header)+nonceLen:], header)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("cannot decrypt %s: wrong passphrase or corrupted file", s.path))
+	}
+	state := &State{}
+	if err := json.Unmarshal(plain, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// AppendLog adds rec to the write-ahead log and syncs it before returning
+func (s *Store) AppendLog(rec *Record) error {
+	plain, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	header := s.walHeader()
+	sealed := s.aead.Seal(nonce, nonce, plain, header)
+	frame := make([]byte, 4, 4+len(sealed))
+	binary.BigEndian.PutUint32(frame, uint32(len(sealed)))
+	frame = append(frame, sealed...)
+
+	f, err := os.OpenFile(s.path+".wal", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		frame = append(header, frame...)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := f.Write(frame); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// ReadLog returns the records appended since the log was last truncated.
+// A frame torn by a crash while it was appended ends the log and is cut off, so later appends follow the last complete record.
+func (s *Store) ReadLog() ([]*Record, error) {
+	data, err := ioutil.ReadFile(s.path + ".wal")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	header := s.walHeader()
+	if len(data) < len(header) || !bytes.Equal(data[:len(header)], header) {
+		return nil, errors.New(fmt.Sprintf("%s.wal is not a log of this share file", s.path))
+	}
+	size := len(data)
+	data = data[len(header):]
+	records := make([]*Record, 0)
+	nonceLen := s.aead.NonceSize()
+	for len(data) >= 4 {
+		size := int(binary.BigEndian.Uint32(data))
+		if len(data)-4 < size {
+			break
+		}
+		sealed := data[4 : 4+size]
+		data = data[4+size:]
+		if size < nonceLen {
+			return nil, errors.New(fmt.Sprintf("%s.wal: short record", s.path))
+		}
+		plain, err := s.aead.Open(nil, sealed[:nonceLen], sealed[nonceLen:], header)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("cannot decrypt %s.wal: wrong passphrase or corrupted file", s.path))
+		}
+		rec := &Record{}
+		if err := json.Unmarshal(plain, rec); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if len(data) > 0 {
+		if err := s.cutLog(int64(size - len(data))); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// Cut the log off at size bytes
+func (s *Store) cutLog(size int64) error {
+	f, err := os.OpenFile(s.path+".wal", os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// TruncateLog drops every record, once the state they lead to has been saved
+func (s *Store) TruncateLog() error {
+	f, err := os.OpenFile(s.path+".wal", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(s.walHeader()); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Make the rename durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,177 @@
+package sharestore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testState(epoch int64) *State {
+	return &State{
+		Epoch:     epoch,
+		Committee: "0011223344556677",
+		Label:     2,
+		Counter:   3,
+		Shares: []Point{
+			{X: 2, Y: []byte{1, 2, 3}, Witness: []byte{4, 5}},
+			{X: 2, Y: []byte{6}, Witness: []byte{7, 8}},
+			{X: 2, Y: []byte{9, 10}, Witness: []byte{11}},
+		},
+		PolyCmts: [][]byte{{12}, {13, 14}, {15}},
+		Secrets: []Secret{
+			{
+				ID: "signing",
+				Shares: []Point{
+					{X: 2, Y: []byte{16}, Witness: []byte{17}},
+					{X: 2, Y: []byte{16}, Witness: []byte{17}},
+					{X: 2, Y: []byte{16}, Witness: []byte{17}},
+				},
+				PolyCmts: [][]byte{{18}, {18}, {18}},
+				Dealt:    true,
+			},
+		},
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sharestore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+	path := Path(dir, 2)
+
+	s, err := Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "Open")
+	_, err = s.Load()
+	assert.Equal(t, ErrNoState, err, "nothing saved yet")
+
+	assert.Nil(t, s.Save(testState(1)), "Save epoch 1")
+	assert.Nil(t, s.Save(testState(2)), "Save epoch 2")
+	state, err := s.Load()
+	assert.Nil(t, err, "Load")
+	assert.Equal(t, testState(2), state, "latest state")
+
+	// reopening keeps the salt, so the same passphrase derives the same key
+	s, err = Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "reopen")
+	state, err = s.Load()
+	assert.Nil(t, err, "Load after reopen")
+	assert.Equal(t, testState(2), state, "state after reopen")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	assert.Equal(t, []string{path}, files, "no temporary files left behind")
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "file mode")
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sharestore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+	path := Path(dir, 1)
+
+	s, _ := Open(path, []byte("correct horse"))
+	assert.Nil(t, s.Save(testState(1)), "Save")
+
+	s, err = Open(path, []byte("battery staple"))
+	assert.Nil(t, err, "Open with another passphrase")
+	_, err = s.Load()
+	assert.NotNil(t, err, "wrong passphrase")
+
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-1] ^= 1
+	ioutil.WriteFile(path, data, 0600)
+	s, _ = Open(path, []byte("correct horse"))
+	_, err = s.Load()
+	assert.NotNil(t, err, "tampered file")
+
+	_, err = Open(path, nil)
+	assert.NotNil(t, err, "empty passphrase")
+}
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sharestore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+	path := Path(dir, 3)
+
+	s, err := Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "Open")
+	records, err := s.ReadLog()
+	assert.Nil(t, err, "ReadLog without a log")
+	assert.Equal(t, 0, len(records), "no records yet")
+
+	want := []*Record{
+		{Epoch: 1, Kind: "enter"},
+		{Epoch: 1, Kind: "point1", Data: []byte{1, 2, 3}},
+		{Epoch: 1, Kind: "zero", Data: []byte{4}},
+	}
+	for _, rec := range want {
+		assert.Nil(t, s.AppendLog(rec), "AppendLog")
+	}
+
+	// the log alone keeps the salt before any state was saved
+	s, err = Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "reopen")
+	records, err = s.ReadLog()
+	assert.Nil(t, err, "ReadLog")
+	assert.Equal(t, want, records, "records after reopen")
+
+	// a frame torn by a crash ends the log
+	f, _ := os.OpenFile(path+".wal", os.O_APPEND|os.O_WRONLY, 0600)
+	f.Write([]byte{0, 0, 1, 0, 9, 9})
+	f.Close()
+	records, err = s.ReadLog()
+	assert.Nil(t, err, "ReadLog with a torn frame")
+	assert.Equal(t, want, records, "torn frame dropped")
+
+	other, err := Open(path, []byte("battery staple"))
+	assert.Nil(t, err, "Open with another passphrase")
+	_, err = other.ReadLog()
+	assert.NotNil(t, err, "wrong passphrase")
+
+	assert.Nil(t, s.TruncateLog(), "TruncateLog")
+	records, err = s.ReadLog()
+	assert.Nil(t, err, "ReadLog after truncate")
+	assert.Equal(t, 0, len(records), "no records after truncate")
+}
+
+// A node that crashes twice in one epoch, each time while it appends a record, replays every complete record after either crash
+func TestLogSurvivesTwoCrashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sharestore")
+	assert.Nil(t, err, "TempDir")
+	defer os.RemoveAll(dir)
+	path := Path(dir, 1)
+	tear := func() {
+		f, _ := os.OpenFile(path+".wal", os.O_APPEND|os.O_WRONLY, 0600)
+		f.Write([]byte{0, 0, 1, 0, 9, 9})
+		f.Close()
+	}
+
+	s, err := Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "Open")
+	want := []*Record{{Epoch: 2, Kind: "enter"}, {Epoch: 2, Kind: "point1", Data: []byte{1}}}
+	for _, rec := range want {
+		assert.Nil(t, s.AppendLog(rec), "AppendLog")
+	}
+	tear()
+
+	// the first restart replays the log and goes on with the epoch
+	s, err = Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "Open after the first crash")
+	records, err := s.ReadLog()
+	assert.Nil(t, err, "ReadLog after the first crash")
+	assert.Equal(t, want, records, "records after the first crash")
+	next := &Record{Epoch: 2, Kind: "zero", Data: []byte{2}}
+	assert.Nil(t, s.AppendLog(next), "AppendLog after the first crash")
+	want = append(want, next)
+	tear()
+
+	s, err = Open(path, []byte("correct horse"))
+	assert.Nil(t, err, "Open after the second crash")
+	records, err = s.ReadLog()
+	assert.Nil(t, err, "ReadLog after the second crash")
+	assert.Equal(t, want, records, "records after the second crash")
+}
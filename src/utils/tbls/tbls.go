@@ -0,0 +1,113 @@
+// Package tbls implements threshold BLS signatures on the pairing of the commitments.
+// A secret s shared on a polynomial S of degree t gives the node at x the share S(x). Its partial signature on a message m is H(m)^S(x), which verifies under its public share g^S(x) like a signature under a public key.
+// The partial signatures of any t+1 nodes combine into H(m)^s by interpolation in the exponent, which verifies under the public key g^s. The key stays the same as long as the secret does, however the shares are refreshed.
+package tbls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/conv"
+	"github.com/bl4ck5un/ChuRP/src/utils/ecparam"
+	"github.com/bl4ck5un/ChuRP/src/utils/interpolation"
+	"github.com/ncw/gmp"
+)
+
+var curve = ecparam.PBC256
+
+// Hashed ahead of every message, so that signatures of the committee cannot be taken for hashes into the group made elsewhere
+const domain = "churp/tbls/v1:"
+
+// Hash maps msg to an element of G1 nobody knows the discrete logarithm of
+func Hash(msg []byte) *pbc.Element {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	h.Write(msg)
+	return curve.Pairing.NewG1().SetFromHash(h.Sum(nil))
+}
+
+// Parse reads an element of G1 in compressed form
+func Parse(b []byte) (*pbc.Element, error) {
+	if uint(len(b)) != curve.Pairing.G1CompressedLength() {
+		return nil, errors.New(fmt.Sprintf("an element of G1 takes %d bytes, got %d", curve.Pairing.G1CompressedLength(), len(b)))
+	}
+	return curve.Pairing.NewG1().SetCompressedBytes(b), nil
+}
+
+// Lagrange returns the coefficients that interpolate the values at xs to the value at 0, modulo the order of the group
+func Lagrange(xs []int) ([]*gmp.Int, error) {
+	x := make([]*gmp.Int, len(xs))
+	for i, xi := range xs {
+		x[i] = gmp.NewInt(int64(xi))
+	}
+	return interpolation.LagrangeCoefficients(x, curve.Ngmp)
+}
+
+// Weighted returns the product of the elems raised to the matching coeffs
+func Weighted(elems []*pbc.Element, coeffs []*gmp.Int) *pbc.Element {
+	res := curve.Pairing.NewG1().Set1()
+	tmp := curve.Pairing.NewG1()
+	for i, elem := range elems {
+		tmp.PowBig(elem, conv.GmpInt2BigInt(coeffs[i]))
+		res.Mul(res, tmp)
+	}
+	return res
+}
+
+// Combine interpolates in the exponent the elems given for the xs to the one at 0.
+// From t+1 partial signatures it makes the signature, from t+1 public shares the public key.
+func Combine(xs []int, elems []*pbc.Element) (*pbc.Element, error) {
+	if len(xs) != len(elems) {
+		return nil, errors.New(fmt.Sprintf("%d elements for %d points", len(elems), len(xs)))
+	}
+	lambda, err := Lagrange(xs)
+	if err != nil {
+		return nil, err
+	}
+	return Weighted(elems, lambda), nil
+}
+
+// PublicShare returns the public share g^S(x) of the node at x from its values evals[i] = g^f_i(x) on the polynomials f_i the committee shares S over, with S = Σ lambda[i] f_i.
+// Every value must open the compressed commitment polyCmts[i] to f_i with the matching witness.
+func PublicShare(dpc *commitment.DLPolyCommit, x int, polyCmts [][]byte, evals [][]byte, witnesses [][]byte, lambda []*gmp.Int) (*pbc.Element, error) {
+	if len(evals) != len(polyCmts) || len(witnesses) != len(polyCmts) {
+		return nil, errors.New(fmt.Sprintf("gave %d values and %d witnesses for %d polynomials", len(evals), len(witnesses), len(polyCmts)))
+	}
+	values := make([]*pbc.Element, len(evals))
+	for i := range values {
+		eval, err := Parse(evals[i])
+		if err != nil {
+			return nil, err
+		}
+		witness, err := Parse(witnesses[i])
+		if err != nil {
+			return nil, err
+		}
+		cmt, err := Parse(polyCmts[i])
+		if err != nil {
+			return nil, err
+		}
+		if !dpc.VerifyEvalInExponent(cmt, gmp.NewInt(int64(x)), eval, witness) {
+			return nil, errors.New(fmt.Sprintf("the value on the polynomial of node %d does not match the commitment", i+1))
+		}
+		values[i] = eval
+	}
+	return Weighted(values, lambda), nil
+}
+
+// Sign returns the signature on msg under share, H(msg)^share
+func Sign(share *gmp.Int, msg []byte) *pbc.Element {
+	sig := curve.Pairing.NewG1()
+	return sig.PowBig(Hash(msg), conv.GmpInt2BigInt(share))
+}
+
+// Verify checks that sig is the signature on msg under the public key pub, that is e(sig, g) == e(H(msg), pub).
+// Partial signatures verify the same way under the public share of the node that made them.
+func Verify(pub *pbc.Element, msg []byte, sig *pbc.Element) bool {
+	lhs := curve.Pairing.NewGT().Pair(sig, curve.G)
+	rhs := curve.Pairing.NewGT().Pair(Hash(msg), pub)
+	return lhs.Equals(rhs)
+}
@@ -0,0 +1,143 @@
+package tbls
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Nik-U/pbc"
+	"github.com/bl4ck5un/ChuRP/src/utils/commitment"
+	"github.com/bl4ck5un/ChuRP/src/utils/polyring"
+	"github.com/ncw/gmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdSignature(t *testing.T) {
+	const n, degree = 5, 2
+	rnd := rand.New(rand.NewSource(7))
+	poly, err := polyring.NewRand(degree, rnd, curve.Ngmp)
+	assert.Nil(t, err)
+	secret := gmp.NewInt(0)
+	poly.EvalMod(gmp.NewInt(0), curve.Ngmp, secret)
+
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	pk := dc.NewG1()
+	dc.Commit(pk, secret)
+
+	msg := []byte("epoch 3")
+	partials := make([]*pbc.Element, n)
+	publics := make([]*pbc.Element, n)
+	for i := 0; i < n; i++ {
+		share := gmp.NewInt(0)
+		poly.EvalMod(gmp.NewInt(int64(i+1)), curve.Ngmp, share)
+		partials[i] = Sign(share, msg)
+		publics[i] = dc.NewG1()
+		dc.Commit(publics[i], share)
+		assert.True(t, Verify(publics[i], msg, partials[i]))
+	}
+
+	// any degree+1 partial signatures make the same signature
+	for _, xs := range [][]int{{1, 2, 3}, {5, 3, 1}, {2, 4, 5}} {
+		sigs := make([]*pbc.Element, len(xs))
+		pubs := make([]*pbc.Element, len(xs))
+		for i, x := range xs {
+			sigs[i] = partials[x-1]
+			pubs[i] = publics[x-1]
+		}
+		sig, err := Combine(xs, sigs)
+		assert.Nil(t, err)
+		assert.True(t, sig.Equals(Sign(secret, msg)))
+		assert.True(t, Verify(pk, msg, sig))
+		assert.False(t, Verify(pk, []byte("epoch 4"), sig))
+		pub, err := Combine(xs, pubs)
+		assert.Nil(t, err)
+		assert.True(t, pub.Equals(pk))
+	}
+
+	// too few partials or one from the wrong share do not
+	sig, err := Combine([]int{1, 2}, partials[:2])
+	assert.Nil(t, err)
+	assert.False(t, Verify(pk, msg, sig))
+	bad := Sign(gmp.NewInt(42), msg)
+	assert.False(t, Verify(publics[2], msg, bad))
+	sig, err = Combine([]int{1, 2, 3}, []*pbc.Element{partials[0], partials[1], bad})
+	assert.Nil(t, err)
+	assert.False(t, Verify(pk, msg, sig))
+}
+
+func TestLagrange(t *testing.T) {
+	lambda, err := Lagrange([]int{1, 2, 3})
+	assert.Nil(t, err)
+	// 3, -3 and 1 interpolate the values at 1, 2 and 3 to the one at 0
+	minus3 := gmp.NewInt(0).Sub(curve.Ngmp, gmp.NewInt(3))
+	assert.Equal(t, 0, lambda[0].Cmp(gmp.NewInt(3)))
+	assert.Equal(t, 0, lambda[1].Cmp(minus3))
+	assert.Equal(t, 0, lambda[2].Cmp(gmp.NewInt(1)))
+
+	_, err = Lagrange([]int{1, 2, 1})
+	assert.NotNil(t, err)
+	_, err = Combine([]int{1, 2}, []*pbc.Element{curve.G})
+	assert.NotNil(t, err)
+}
+
+func TestParse(t *testing.T) {
+	sig := Sign(gmp.NewInt(5), []byte("m"))
+	parsed, err := Parse(sig.CompressedBytes())
+	assert.Nil(t, err)
+	assert.True(t, parsed.Equals(sig))
+	_, err = Parse(sig.CompressedBytes()[1:])
+	assert.NotNil(t, err)
+}
+
+func TestPublicShare(t *testing.T) {
+	const counter, degree = 3, 1
+	rnd := rand.New(rand.NewSource(9))
+	dpc := commitment.DLPolyCommit{}
+	dpc.SetupFix(counter)
+	dc := commitment.DLCommit{}
+	dc.SetupFix()
+	lambda, err := Lagrange([]int{1, 2, 3})
+	assert.Nil(t, err)
+
+	// the node at 2 holds a point on each of the polynomials of the committee
+	const x = 2
+	cmts := make([][]byte, counter)
+	evals := make([][]byte, counter)
+	witnesses := make([][]byte, counter)
+	share := gmp.NewInt(0)
+	for i := 0; i < counter; i++ {
+		poly, err := polyring.NewRand(degree, rnd, curve.Ngmp)
+		assert.Nil(t, err)
+		cmt := dpc.NewG1()
+		dpc.Commit(cmt, poly)
+		cmts[i] = cmt.CompressedBytes()
+		y := gmp.NewInt(0)
+		poly.EvalMod(gmp.NewInt(x), curve.Ngmp, y)
+		eval := dc.NewG1()
+		dc.Commit(eval, y)
+		evals[i] = eval.CompressedBytes()
+		witness := dpc.NewG1()
+		dpc.CreateWitness(witness, poly, gmp.NewInt(x))
+		witnesses[i] = witness.CompressedBytes()
+		y.Mul(y, lambda[i])
+		share.Add(share, y)
+		share.Mod(share, curve.Ngmp)
+	}
+	public, err := PublicShare(&dpc, x, cmts, evals, witnesses, lambda)
+	if assert.Nil(t, err) {
+		want := dc.NewG1()
+		dc.Commit(want, share)
+		assert.True(t, public.Equals(want))
+	}
+
+	// a value for another node, a swapped value or a missing one does not pass
+	_, err = PublicShare(&dpc, x+1, cmts, evals, witnesses, lambda)
+	assert.NotNil(t, err)
+	swapped := [][]byte{evals[1], evals[0], evals[2]}
+	_, err = PublicShare(&dpc, x, cmts, swapped, witnesses, lambda)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "polynomial of node 1")
+	}
+	_, err = PublicShare(&dpc, x, cmts, evals[:2], witnesses, lambda)
+	assert.NotNil(t, err)
+}
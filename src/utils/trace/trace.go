@@ -0,0 +1,248 @@
+// Package trace records spans around the phases of an epoch and the RPCs they make, and exports them to a collector.
+// Every process derives the trace of an epoch from the committee and the epoch number, so the spans of all nodes, the bulletinboard and the clock in one epoch share a trace without passing it along.
+package trace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Span is a timed step, like a phase of a node or an RPC to a peer
+type Span struct {
+	Trace  string                 `json:"trace"`
+	ID     string                 `json:"id"`
+	Parent string                 `json:"parent,omitempty"`
+	Name   string                 `json:"name"`
+	Start  time.Time              `json:"start"`
+	End    time.Time              `json:"end"`
+	Took   time.Duration          `json:"took_ns"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+
+	once sync.Once
+}
+
+// Exporter takes the spans that finished
+type Exporter interface {
+	Export(span *Span)
+}
+
+var (
+	mutex    sync.RWMutex
+	exporter Exporter
+)
+
+// SetExporter sends the spans of the process to e, nil stops tracing
+func SetExporter(e Exporter) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	exporter = e
+}
+
+func current() Exporter {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return exporter
+}
+
+// Epoch returns the trace of an epoch of a committee
+func Epoch(committee string, epoch int64) string {
+	h := sha256.New()
+	h.Write([]byte(committee))
+	binary.Write(h, binary.BigEndian, epoch)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Start starts a span of the trace under parent, or at the top of the trace if parent is nil.
+// Without an exporter it returns nil, and every method of a nil span does nothing.
+func Start(trace string, parent *Span, name string, fields map[string]interface{}) *Span {
+	if current() == nil {
+		return nil
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	span := &Span{
+		Trace:  trace,
+		ID:     hex.EncodeToString(id),
+		Name:   name,
+		Start:  time.Now(),
+		Fields: fields,
+	}
+	if parent != nil {
+		span.Parent = parent.ID
+	}
+	return span
+}
+
+// Finish ends the span with the error of the step, if any, and exports it. Only the first call counts.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.End = time.Now()
+		s.Took = s.End.Sub(s.Start)
+		if err != nil {
+			s.Error = err.Error()
+		}
+		if e := current(); e != nil {
+			e.Export(s)
+		}
+	})
+}
+
+// File is the local collector, it appends every span to a file as a line of JSON.
+// Processes on one machine may share the file, every span is written at once.
+type File struct {
+	mutex sync.Mutex
+	f     *os.File
+}
+
+// NewFile opens the file at path to append spans to it
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &File{f: f}, nil
+}
+
+// ToFile exports the spans of the process to the file at path
+func ToFile(path string) error {
+	f, err := NewFile(path)
+	if err != nil {
+		return err
+	}
+	SetExporter(f)
+	return nil
+}
+
+func (c *File) Export(span *Span) {
+	line, err := json.Marshal(span)
+	if err != nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.f.Write(append(line, '\n'))
+}
+
+// Close closes the file
+func (c *File) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.f.Close()
+}
+
+// Recorder keeps the spans in memory, for tests
+type Recorder struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+func (r *Recorder) Export(span *Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+// Spans returns the spans exported so far, in the order they finished
+func (r *Recorder) Spans() []*Span {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*Span{}, r.spans...)
+}
+
+// Abandoned is the error of the steps an epoch left open when the next one began
+var Abandoned = errors.New("abandoned")
+
+// Steps keeps the spans of the steps of one epoch under a span of the whole epoch, the way metrics.Phases times them.
+// The calls a step makes are spans under the step started last.
+type Steps struct {
+	mutex  sync.Mutex
+	fields map[string]interface{}
+	epoch  *Span
+	open   map[string]*Span
+	last   *Span
+}
+
+// NewSteps returns the steps of a process, fields go on every span, like the label of a node
+func NewSteps(fields map[string]interface{}) *Steps {
+	return &Steps{fields: fields, open: make(map[string]*Span)}
+}
+
+func (s *Steps) with(fields map[string]interface{}) map[string]interface{} {
+	all := make(map[string]interface{}, len(s.fields)+len(fields))
+	for k, v := range s.fields {
+		all[k] = v
+	}
+	for k, v := range fields {
+		all[k] = v
+	}
+	return all
+}
+
+// Begin starts the span of an epoch and abandons whatever the previous epoch left open
+func (s *Steps) Begin(committee string, epoch int64) {
+	s.Finish(Abandoned)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.epoch = Start(Epoch(committee, epoch), nil, "epoch", s.with(map[string]interface{}{"epoch": epoch}))
+}
+
+// Finish ends every open step and the epoch with err
+func (s *Steps) Finish(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, span := range s.open {
+		span.Finish(err)
+		delete(s.open, name)
+	}
+	s.epoch.Finish(err)
+	s.epoch = nil
+	s.last = nil
+}
+
+// Start starts a step of the epoch
+func (s *Steps) Start(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.epoch == nil {
+		return
+	}
+	span := Start(s.epoch.Trace, s.epoch, name, s.with(map[string]interface{}{"phase": name}))
+	s.open[name] = span
+	s.last = span
+}
+
+// End ends a step that was started
+func (s *Steps) End(name string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.open[name].Finish(err)
+	delete(s.open, name)
+}
+
+// Call runs f in a span under the step started last, fields tell the call apart, like the RPC and the peer
+func (s *Steps) Call(name string, fields map[string]interface{}, f func() error) error {
+	s.mutex.Lock()
+	parent := s.last
+	if parent == nil {
+		parent = s.epoch
+	}
+	s.mutex.Unlock()
+	if parent == nil {
+		return f()
+	}
+	span := Start(parent.Trace, parent, name, s.with(fields))
+	err := f()
+	span.Finish(err)
+	return err
+}
@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoExporter(t *testing.T) {
+	SetExporter(nil)
+	span := Start(Epoch("committee", 1), nil, "epoch", nil)
+	assert.Nil(t, span)
+	// a nil span takes every call
+	span.Finish(errors.New("ignored"))
+
+	steps := NewSteps(nil)
+	steps.Begin("committee", 1)
+	steps.Start("reconstruction")
+	called := false
+	assert.Nil(t, steps.Call("ReadPhase1", nil, func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+	steps.End("reconstruction", nil)
+	steps.Finish(nil)
+}
+
+func TestEpoch(t *testing.T) {
+	assert.Equal(t, Epoch("committee", 1), Epoch("committee", 1))
+	assert.NotEqual(t, Epoch("committee", 1), Epoch("committee", 2))
+	assert.NotEqual(t, Epoch("committee", 1), Epoch("other", 1))
+	assert.Len(t, Epoch("committee", 1), 32)
+}
+
+func TestSteps(t *testing.T) {
+	r := &Recorder{}
+	SetExporter(r)
+	defer SetExporter(nil)
+
+	steps := NewSteps(map[string]interface{}{"node": 1})
+	steps.Begin("committee", 1)
+	steps.Start("reconstruction")
+	failed := errors.New("unavailable")
+	assert.Equal(t, failed, steps.Call("ReadPhase1", map[string]interface{}{"peer": "bulletinboard"}, func() error {
+		return failed
+	}))
+	steps.End("reconstruction", nil)
+	steps.Start("proactivization")
+	// the next epoch abandons what this one left open
+	steps.Begin("committee", 2)
+	steps.Finish(nil)
+
+	spans := r.Spans()
+	if !assert.Len(t, spans, 5) {
+		return
+	}
+	call, reconstruction, proactivization, epoch1, epoch2 := spans[0], spans[1], spans[2], spans[3], spans[4]
+	assert.Equal(t, "ReadPhase1", call.Name)
+	assert.Equal(t, reconstruction.ID, call.Parent)
+	assert.Equal(t, "unavailable", call.Error)
+	assert.Equal(t, "bulletinboard", call.Fields["peer"])
+	assert.Equal(t, 1, call.Fields["node"])
+
+	assert.Equal(t, epoch1.ID, reconstruction.Parent)
+	assert.Equal(t, "", reconstruction.Error)
+	assert.Equal(t, "reconstruction", reconstruction.Fields["phase"])
+	assert.Equal(t, Abandoned.Error(), proactivization.Error)
+	assert.Equal(t, Abandoned.Error(), epoch1.Error)
+	assert.Equal(t, Epoch("committee", 1), epoch1.Trace)
+	assert.Equal(t, "", epoch1.Parent)
+	assert.Equal(t, int64(1), epoch1.Fields["epoch"])
+
+	assert.Equal(t, Epoch("committee", 2), epoch2.Trace)
+	assert.Equal(t, "", epoch2.Error)
+	for _, span := range spans {
+		assert.True(t, span.Took >= 0)
+		assert.Equal(t, span.End.Sub(span.Start), span.Took)
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans")
+
+	f, err := NewFile(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	SetExporter(f)
+	defer SetExporter(nil)
+	parent := Start("trace", nil, "epoch", nil)
+	Start("trace", parent, "SharePhase1", map[string]interface{}{"peer": "node2"}).Finish(nil)
+	parent.Finish(errors.New("failed"))
+	// a second finish is ignored
+	parent.Finish(nil)
+	assert.Nil(t, f.Close())
+
+	file, err := os.Open(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer file.Close()
+	spans := make([]*Span, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		span := &Span{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), span))
+		spans = append(spans, span)
+	}
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+	assert.Equal(t, "SharePhase1", spans[0].Name)
+	assert.Equal(t, spans[1].ID, spans[0].Parent)
+	assert.Equal(t, "node2", spans[0].Fields["peer"])
+	assert.Equal(t, "failed", spans[1].Error)
+}